@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,9 +24,14 @@ import (
 	postgresRepo "github.com/gaston-garcia-cegid/gonsgarage/internal/repository/postgres"
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/handler"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/middleware"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/filestore"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pdf"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/sqlxdb"
 	redisRepo "github.com/gaston-garcia-cegid/gonsgarage/internal/repository/redis"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/appointment"
@@ -34,6 +40,8 @@ import (
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/car"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/employee"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/invoice"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/labour"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/notification"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/part"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/received_invoice"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/repair"
@@ -132,12 +140,34 @@ func main() {
 		&domain.ServiceJob{},
 		&domain.ServiceJobReception{},
 		&domain.ServiceJobHandover{},
+		&domain.ServiceJobStatusEvent{},
+		&domain.ServiceJobPromiseRevision{},
+		&domain.ChecklistTemplate{},
+		&domain.InspectionFinding{},
+		&domain.Estimate{},
+		&domain.EstimateLine{},
+		&domain.OBDSession{},
+		&domain.WorkHour{},
+		&domain.RepairLine{},
+		&domain.RepairStatusEvent{},
+		&domain.RepairTechnician{},
+		&domain.RepairWarranty{},
+		&domain.LabourOperation{},
+		&domain.LabourOperationVehicle{},
+		&domain.LabourOperationPart{},
+		&domain.ServiceJobMessage{},
+		&domain.ServiceJobMessageAttachment{},
 		&domain.Appointment{},
+		&domain.AppointmentDayLock{},
+		&domain.CalendarFeedKey{},
+		&domain.EmployeeLeave{},
+		&domain.WaitlistEntry{},
 		&domain.PartItem{},
 		&domain.Supplier{},
 		&domain.ReceivedInvoice{},
 		&domain.BillingDocument{},
 		&domain.Invoice{},
+		&domain.OutboundNotification{},
 	}
 
 	for _, model := range models {
@@ -157,6 +187,12 @@ func main() {
 	if err := ensureRepairsServiceJobIDColumn(db); err != nil {
 		log.Fatalf("repairs.service_job_id schema: %v", err)
 	}
+	if err := ensureRepairsReworkOfRepairIDColumn(db); err != nil {
+		log.Fatalf("repairs.rework_of_repair_id schema: %v", err)
+	}
+	if err := ensureAppointmentsSequenceColumn(db); err != nil {
+		log.Fatalf("appointments.sequence schema: %v", err)
+	}
 
 	// Create indexes manually if they don't exist
 	if err := createIndexes(db); err != nil {
@@ -198,13 +234,27 @@ func main() {
 	employeeRepo := postgresRepo.NewPostgresEmployeeRepository(db)
 	carRepo := postgresRepo.NewPostgresCarRepository(db)
 	appointmentRepo := postgresRepo.NewPostgresAppointmentRepository(db)
+	employeeLeaveRepo := postgresRepo.NewPostgresEmployeeLeaveRepository(db)
+	waitlistRepo := postgresRepo.NewPostgresWaitlistRepository(db)
+	calendarFeedKeyRepo := postgresRepo.NewPostgresCalendarFeedKeyRepository(db)
 	repairRepo := postgresRepo.NewPostgresRepairRepository(db)
 	serviceJobRepo := postgresRepo.NewPostgresServiceJobRepository(db)
+	checklistTemplateRepo := postgresRepo.NewPostgresChecklistTemplateRepository(db)
+	inspectionFindingRepo := postgresRepo.NewPostgresInspectionFindingRepository(db)
+	estimateRepo := postgresRepo.NewPostgresEstimateRepository(db)
+	obdSessionRepo := postgresRepo.NewPostgresOBDSessionRepository(db)
+	workHourRepo := postgresRepo.NewPostgresWorkHourRepository(db)
+	repairLineRepo := postgresRepo.NewPostgresRepairLineRepository(db)
+	repairTechRepo := postgresRepo.NewPostgresRepairTechnicianRepository(db)
+	repairWarrantyRepo := postgresRepo.NewPostgresRepairWarrantyRepository(db)
+	messageRepo := postgresRepo.NewPostgresServiceJobMessageRepository(db)
 	supplierRepo := postgresRepo.NewPostgresSupplierRepository(db)
 	receivedInvoiceRepo := postgresRepo.NewPostgresReceivedInvoiceRepository(db)
 	billingDocRepo := postgresRepo.NewPostgresBillingDocumentRepository(db)
 	invoiceRepo := postgresRepo.NewPostgresInvoiceRepository(db)
 	partItemRepo := postgresRepo.NewPostgresPartItemRepository(db)
+	labourOpRepo := postgresRepo.NewPostgresLabourOperationRepository(db)
+	outboxRepo := postgresRepo.NewPostgresOutboundNotificationRepository(db)
 	log.Printf("Repositories initialized")
 
 	// Initialize use cases
@@ -217,17 +267,76 @@ func main() {
 	authService := auth.NewAuthService(userRepo, jwtSecret, 24)
 	employeeService := employee.NewEmployeeService(employeeRepo, cacheRepo)
 	carService := car.NewCarService(carRepo, userRepo, cacheRepo)
-	appointmentService := appointment.NewAppointmentService(appointmentRepo, userRepo, carRepo)
-	repairService := repair.NewRepairService(repairRepo, carRepo, userRepo)
-	serviceJobService := servicejob.NewService(serviceJobRepo, carRepo, userRepo, repairRepo)
+	linkSigner := signedlink.New(jwtSecret)
+	notificationService := notification.NewService(outboxRepo)
+	appointmentService := appointment.NewAppointmentService(appointmentRepo, userRepo, carRepo,
+		appointment.WithEmployeeLeaveRepository(employeeLeaveRepo),
+		appointment.WithLinkSigner(linkSigner),
+		appointment.WithCalendarFeedKeyRepository(calendarFeedKeyRepo),
+		appointment.WithNotifier(notificationService),
+		appointment.WithCalendarLocation(workshopLocation()),
+		appointment.WithWaitlistRepository(waitlistRepo),
+		appointment.WithPublicBaseURL(publicAppURL()),
+		appointment.WithChangePolicy(appointmentChangePolicy()))
+	boardHub := pubsub.New()
+	repairService := repair.NewRepairService(repairRepo, carRepo, userRepo,
+		repair.WithEstimateRepository(estimateRepo),
+		repair.WithRepairLineRepository(repairLineRepo),
+		repair.WithEmployeeRepository(employeeRepo),
+		repair.WithPartItemRepository(partItemRepo),
+		repair.WithRepairTechnicianRepository(repairTechRepo),
+		repair.WithLabourOperationRepository(labourOpRepo),
+		repair.WithRepairWarrantyRepository(repairWarrantyRepo),
+		repair.WithBoardHub(boardHub))
+	serviceJobService := servicejob.NewService(serviceJobRepo, carRepo, userRepo, repairRepo,
+		servicejob.WithAppointmentRepository(appointmentRepo),
+		servicejob.WithChecklistTemplateRepository(checklistTemplateRepo),
+		servicejob.WithInspectionFindingRepository(inspectionFindingRepo),
+		servicejob.WithEstimateRepository(estimateRepo),
+		servicejob.WithLabourOperationRepository(labourOpRepo),
+		servicejob.WithOBDSessionRepository(obdSessionRepo),
+		servicejob.WithWorkHourRepository(workHourRepo),
+		servicejob.WithMessageRepository(messageRepo),
+		servicejob.WithBoardHub(boardHub),
+		servicejob.WithPromiseAtRiskWindow(promiseAtRiskWindow()),
+		servicejob.WithLocation(workshopLocation()),
+		servicejob.WithBranding(workshopBranding()),
+		servicejob.WithFileStorage(fileStorage()),
+		servicejob.WithHandoverSignatureRequired(os.Getenv("SERVICE_JOB_REQUIRE_HANDOVER_SIGNATURE") == "true"),
+		servicejob.WithLinkSigner(linkSigner),
+		servicejob.WithNotifier(notificationService),
+		servicejob.WithPublicBaseURL(publicAppURL()))
 	supplierService := supplier.NewSupplierService(supplierRepo, userRepo)
 	receivedInvoiceService := received_invoice.NewReceivedInvoiceService(receivedInvoiceRepo, userRepo)
 	billingDocumentService := billing_document.NewBillingDocumentService(billingDocRepo, userRepo)
 	invoiceService := invoice.NewInvoiceService(invoiceRepo, userRepo)
 	partService := part.NewPartService(partItemRepo, userRepo)
+	labourService := labour.NewLabourOperationService(labourOpRepo, partItemRepo, userRepo)
 
 	log.Printf("Use cases initialized")
 
+	// Background workers: reminder scheduler fills the outbox, dispatcher delivers it.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	reminderOffsets := appointment.DefaultReminderOffsets
+	if raw := strings.TrimSpace(os.Getenv("APPOINTMENT_REMINDER_OFFSETS")); raw != "" {
+		parsed, err := appointment.ParseReminderOffsets(raw)
+		if err != nil {
+			log.Printf("Warning: invalid APPOINTMENT_REMINDER_OFFSETS %q: %v (using defaults)", raw, err)
+		} else {
+			reminderOffsets = parsed
+		}
+	}
+	reminderScheduler := appointment.NewReminderScheduler(appointmentService, notificationService, appointment.ReminderConfig{
+		Offsets:       reminderOffsets,
+		Channel:       strings.TrimSpace(os.Getenv("APPOINTMENT_REMINDER_CHANNEL")),
+		PublicBaseURL: publicAppURL(),
+	})
+	dispatcher := notification.NewDispatcher(outboxRepo, notificationSenders())
+	go dispatcher.Run(workersCtx, 30*time.Second)
+	go reminderScheduler.Run(workersCtx, time.Minute)
+	go appointmentService.RunWaitlist(workersCtx, time.Minute)
+
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtSecret)
 
@@ -239,13 +348,20 @@ func main() {
 
 	// Initialize appointment handler
 	appointmentHandler := handler.NewAppointmentHandler(appointmentService)
+	employeeLeaveHandler := handler.NewEmployeeLeaveHandler(appointmentService)
+	publicAppointmentHandler := handler.NewPublicAppointmentHandler(appointmentService)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(appointmentService, os.Getenv("PUBLIC_API_URL"))
+	waitlistHandler := handler.NewWaitlistHandler(appointmentService)
 	repairHandler := handler.NewRepairHandler(repairService)
 	serviceJobHandler := handler.NewServiceJobHandler(serviceJobService)
+	checklistTemplateHandler := handler.NewChecklistTemplateHandler(serviceJobService)
+	estimateHandler := handler.NewEstimateHandler(serviceJobService, publicAppURL())
 	supplierHandler := handler.NewSupplierHandler(supplierService)
 	receivedInvoiceHandler := handler.NewReceivedInvoiceHandler(receivedInvoiceService)
 	billingDocumentHandler := handler.NewBillingDocumentHandler(billingDocumentService)
 	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
 	partHandler := handler.NewPartHandler(partService)
+	labourOperationHandler := handler.NewLabourOperationHandler(labourService)
 
 	log.Printf("Handlers initialized")
 
@@ -254,15 +370,19 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.Default()
+	// Client IPs are recorded as evidence (estimate approvals), so X-Forwarded-For is only honoured from known proxies.
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// CORS middleware
 	router.Use(corsMiddleware())
 
 	// Setup routes
-	setupRoutes(router, authHandler, adminUserHandler, employeeHandler, carHandler, appointmentHandler, repairHandler, serviceJobHandler,
-		supplierHandler, receivedInvoiceHandler, billingDocumentHandler, invoiceHandler, partHandler,
-		authMiddleware, sqlxDB)
+	setupRoutes(router, authHandler, adminUserHandler, employeeHandler, employeeLeaveHandler, carHandler, appointmentHandler, publicAppointmentHandler, calendarFeedHandler, waitlistHandler, repairHandler, serviceJobHandler,
+		checklistTemplateHandler, estimateHandler, supplierHandler, receivedInvoiceHandler, billingDocumentHandler, invoiceHandler, partHandler,
+		labourOperationHandler, authMiddleware, sqlxDB)
 
 	log.Printf("Routes set up")
 
@@ -325,6 +445,24 @@ func ensureRepairsServiceJobIDColumn(db *gorm.DB) error {
 	return nil
 }
 
+// ensureRepairsReworkOfRepairIDColumn adds the warranty rework link (sqlx SELECT includes rework_of_repair_id).
+func ensureRepairsReworkOfRepairIDColumn(db *gorm.DB) error {
+	const q = `ALTER TABLE repairs ADD COLUMN IF NOT EXISTS rework_of_repair_id uuid`
+	if err := db.Exec(q).Error; err != nil {
+		return fmt.Errorf("%s: %w", q, err)
+	}
+	return nil
+}
+
+// ensureAppointmentsSequenceColumn adds the edit counter written as the iCal SEQUENCE (sqlx SELECT includes sequence).
+func ensureAppointmentsSequenceColumn(db *gorm.DB) error {
+	const q = `ALTER TABLE appointments ADD COLUMN IF NOT EXISTS sequence integer NOT NULL DEFAULT 0`
+	if err := db.Exec(q).Error; err != nil {
+		return fmt.Errorf("%s: %w", q, err)
+	}
+	return nil
+}
+
 // Create indexes manually
 func createIndexes(db *gorm.DB) error {
 	indexes := []string{
@@ -339,12 +477,22 @@ func createIndexes(db *gorm.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_repairs_technician_id ON repairs(technician_id)",
 		"CREATE INDEX IF NOT EXISTS idx_repairs_deleted_at ON repairs(deleted_at)",
 		"CREATE INDEX IF NOT EXISTS idx_repairs_service_job_id ON repairs(service_job_id)",
+		"CREATE INDEX IF NOT EXISTS idx_repairs_rework_of_repair_id ON repairs(rework_of_repair_id)",
 		"CREATE INDEX IF NOT EXISTS idx_appointments_customer_id ON appointments(customer_id)",
 		"CREATE INDEX IF NOT EXISTS idx_appointments_car_id ON appointments(car_id)",
 		"CREATE INDEX IF NOT EXISTS idx_appointments_deleted_at ON appointments(deleted_at)",
 		"CREATE INDEX IF NOT EXISTS idx_service_jobs_car_id ON service_jobs(car_id)",
 		"CREATE INDEX IF NOT EXISTS idx_service_jobs_opened_by_user_id ON service_jobs(opened_by_user_id)",
 		"CREATE INDEX IF NOT EXISTS idx_service_jobs_deleted_at ON service_jobs(deleted_at)",
+		// One visit per appointment: a concurrent second check-in fails on insert.
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_service_jobs_appointment_id_unique ON service_jobs(appointment_id) WHERE appointment_id IS NOT NULL AND deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_appointments_scheduled_at ON appointments(scheduled_at)",
+		"CREATE INDEX IF NOT EXISTS idx_appointments_employee_id_scheduled_at ON appointments(employee_id, scheduled_at)",
+		"CREATE INDEX IF NOT EXISTS idx_employee_leaves_user_id_starts_at ON employee_leaves(user_id, starts_at)",
+		"CREATE INDEX IF NOT EXISTS idx_waitlist_entries_day_status_created_at ON waitlist_entries(day, status, created_at)",
+		// Reminder ticks may race; the outbox keeps one row per dedupe key.
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_outbound_notifications_dedupe_key_unique ON outbound_notifications(dedupe_key) WHERE dedupe_key <> ''",
+		"CREATE INDEX IF NOT EXISTS idx_outbound_notifications_status_send_after ON outbound_notifications(status, send_after)",
 	}
 
 	for _, idx := range indexes {
@@ -355,6 +503,148 @@ func createIndexes(db *gorm.DB) error {
 	return nil
 }
 
+// publicAppURL is the frontend origin used in links sent to clients (PUBLIC_APP_URL).
+func publicAppURL() string {
+	if v := strings.TrimSpace(os.Getenv("PUBLIC_APP_URL")); v != "" {
+		return v
+	}
+	return "http://localhost:3000"
+}
+
+// trustedProxies reads TRUSTED_PROXIES (comma-separated IPs or CIDRs of the reverse proxies in front of the API).
+// Unset trusts none: the client IP is the connection's address.
+func trustedProxies() []string {
+	var out []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// workshopBranding reads the identity printed on job cards and handover reports from WORKSHOP_* env.
+func workshopBranding() servicejob.Branding {
+	b := servicejob.DefaultBranding
+	if name := strings.TrimSpace(os.Getenv("WORKSHOP_NAME")); name != "" {
+		b.Name = name
+	}
+	b.Address = strings.TrimSpace(os.Getenv("WORKSHOP_ADDRESS"))
+	b.Phone = strings.TrimSpace(os.Getenv("WORKSHOP_PHONE"))
+	b.Email = strings.TrimSpace(os.Getenv("WORKSHOP_EMAIL"))
+	b.TaxID = strings.TrimSpace(os.Getenv("WORKSHOP_TAX_ID"))
+	if raw := strings.TrimSpace(os.Getenv("WORKSHOP_BRAND_COLOR")); raw != "" {
+		c, err := pdf.ParseHexColor(raw)
+		if err != nil {
+			log.Printf("Warning: invalid WORKSHOP_BRAND_COLOR %q (using default)", raw)
+		} else {
+			b.Color = c
+		}
+	}
+	return b
+}
+
+// fileStorage opens the local upload directory (FILE_STORAGE_DIR, default ./data/files). Without it,
+// signatures and message attachments cannot be stored; the rest of the API still works.
+func fileStorage() external.FileStorage {
+	dir := strings.TrimSpace(os.Getenv("FILE_STORAGE_DIR"))
+	if dir == "" {
+		dir = "./data/files"
+	}
+	fs, err := filestore.NewLocal(dir)
+	if err != nil {
+		log.Printf("Warning: file storage disabled: %v", err)
+		return nil
+	}
+	return fs
+}
+
+// promiseAtRiskWindow reads SERVICE_JOB_PROMISE_AT_RISK (e.g. "90m"): how long before the promised
+// time a visit still in the shop is flagged at risk.
+func promiseAtRiskWindow() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("SERVICE_JOB_PROMISE_AT_RISK"))
+	if raw == "" {
+		return servicejob.DefaultPromiseAtRiskWindow
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid SERVICE_JOB_PROMISE_AT_RISK %q (using default)", raw)
+		return servicejob.DefaultPromiseAtRiskWindow
+	}
+	return d
+}
+
+// appointmentChangePolicy reads the client cancel / reschedule rules from APPOINTMENT_* env,
+// keeping the default for unset or invalid values.
+func appointmentChangePolicy() appointment.ChangePolicy {
+	p := appointment.DefaultChangePolicy
+	durations := map[string]*time.Duration{
+		"APPOINTMENT_CANCEL_MIN_NOTICE":     &p.CancelMinNotice,
+		"APPOINTMENT_RESCHEDULE_MIN_NOTICE": &p.RescheduleMinNotice,
+	}
+	for key, dst := range durations {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d < 0 {
+				log.Printf("Warning: invalid %s %q (using default)", key, raw)
+				continue
+			}
+			*dst = d
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("APPOINTMENT_NO_SHOW_APPROVAL_THRESHOLD")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			log.Printf("Warning: invalid APPOINTMENT_NO_SHOW_APPROVAL_THRESHOLD %q (using default)", raw)
+		} else {
+			p.NoShowApprovalThreshold = n
+		}
+	}
+	return p
+}
+
+// workshopLocation is the zone written into calendar feeds: the server's TZ by name when set,
+// so calendar apps see e.g. "Europe/Lisbon" instead of "Local".
+func workshopLocation() *time.Location {
+	if name := strings.TrimSpace(os.Getenv("TZ")); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// notificationSenders builds one sender per channel from NOTIFY_* env. Unset channels keep their messages
+// pending until configured; NOTIFY_LOG_ONLY=1 (development) marks them sent and only logs the recipient.
+func notificationSenders() map[string]external.NotificationSender {
+	var fallback external.NotificationSender = notification.UnconfiguredSender{}
+	if os.Getenv("NOTIFY_LOG_ONLY") == "1" {
+		log.Printf("Warning: NOTIFY_LOG_ONLY=1; notifications on unconfigured channels are logged, not delivered.")
+		fallback = notification.LogSender{}
+	}
+	senders := map[string]external.NotificationSender{
+		domain.NotificationChannelEmail:    fallback,
+		domain.NotificationChannelSMS:      fallback,
+		domain.NotificationChannelWhatsApp: fallback,
+	}
+	if addr := strings.TrimSpace(os.Getenv("NOTIFY_SMTP_ADDR")); addr != "" {
+		senders[domain.NotificationChannelEmail] = &notification.SMTPSender{
+			Addr:     addr,
+			Username: os.Getenv("NOTIFY_SMTP_USER"),
+			Password: os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			From:     os.Getenv("NOTIFY_SMTP_FROM"),
+		}
+	}
+	token := os.Getenv("NOTIFY_WEBHOOK_TOKEN")
+	if u := strings.TrimSpace(os.Getenv("NOTIFY_SMS_WEBHOOK_URL")); u != "" {
+		senders[domain.NotificationChannelSMS] = &notification.WebhookSender{URL: u, Token: token}
+	}
+	if u := strings.TrimSpace(os.Getenv("NOTIFY_WHATSAPP_WEBHOOK_URL")); u != "" {
+		senders[domain.NotificationChannelWhatsApp] = &notification.WebhookSender{URL: u, Token: token}
+	}
+	return senders
+}
+
 // corsExtraOrigins parses CORS_ORIGINS (comma-separated) for GIN_MODE=release (e.g. LAN deploy).
 func corsExtraOrigins() []string {
 	v := strings.TrimSpace(os.Getenv("CORS_ORIGINS"))
@@ -417,15 +707,22 @@ func setupRoutes(
 	authHandler *handler.AuthHandler,
 	adminUserHandler *handler.AdminUserHandler,
 	employeeHandler *handler.EmployeeHandler,
+	employeeLeaveHandler *handler.EmployeeLeaveHandler,
 	carHandler *handler.CarHandler,
 	appointmentHandler *handler.AppointmentHandler,
+	publicAppointmentHandler *handler.PublicAppointmentHandler,
+	calendarFeedHandler *handler.CalendarFeedHandler,
+	waitlistHandler *handler.WaitlistHandler,
 	repairHandler *handler.RepairHandler,
 	serviceJobHandler *handler.ServiceJobHandler,
+	checklistTemplateHandler *handler.ChecklistTemplateHandler,
+	estimateHandler *handler.EstimateHandler,
 	supplierHandler *handler.SupplierHandler,
 	receivedInvoiceHandler *handler.ReceivedInvoiceHandler,
 	billingDocumentHandler *handler.BillingDocumentHandler,
 	invoiceHandler *handler.InvoiceHandler,
 	partHandler *handler.PartHandler,
+	labourOperationHandler *handler.LabourOperationHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	sqlxDB *sqlx.DB,
 ) {
@@ -461,11 +758,36 @@ func setupRoutes(
 		auth.POST("/login", authHandler.Login)
 	}
 
+	// Public confirm/cancel links from appointment reminders (signed token, no login)
+	publicAppointments := api.Group("/public/appointments")
+	{
+		publicAppointments.GET("/rsvp", publicAppointmentHandler.GetRSVP)
+		publicAppointments.POST("/rsvp/confirm", publicAppointmentHandler.ConfirmRSVP)
+		publicAppointments.POST("/rsvp/cancel", publicAppointmentHandler.CancelRSVP)
+		publicAppointments.GET("/waitlist/claim", publicAppointmentHandler.GetWaitlistOffer)
+		publicAppointments.POST("/waitlist/claim", publicAppointmentHandler.ClaimWaitlistOffer)
+	}
+
+	// Public estimate approval links (signed token, no login)
+	publicEstimates := api.Group("/public/estimates")
+	{
+		publicEstimates.GET("", estimateHandler.GetPublicEstimate)
+		publicEstimates.POST("/decision", estimateHandler.DecidePublicEstimate)
+	}
+
+	// Public visit tracker links (signed token, no login)
+	api.GET("/public/visits", serviceJobHandler.GetPublicVisitTracker)
+
+	// iCalendar subscriptions (token in the URL: calendar apps cannot send a bearer header)
+	api.GET("/public/calendar/:feed", calendarFeedHandler.GetCalendarFeed)
+
 	// Protected routes
 	protected := api.Group("/")
 	protected.Use(middleware.GinBearerJWT(authMiddleware))
 	{
 		protected.GET("/auth/me", authHandler.Me)
+		protected.GET("/calendar-feeds", calendarFeedHandler.ListCalendarFeeds)
+		protected.POST("/calendar-feeds/rotate", calendarFeedHandler.RotateCalendarFeeds)
 
 		adminUsers := protected.Group("/admin")
 		adminUsers.Use(middleware.RequireStaffManagers())
@@ -484,6 +806,14 @@ func setupRoutes(
 			employees.DELETE("/:id", employeeHandler.DeleteEmployee)
 		}
 
+		employeeLeaves := protected.Group("/employee-leaves")
+		employeeLeaves.Use(middleware.RequireStaffManagers())
+		{
+			employeeLeaves.POST("", employeeLeaveHandler.CreateEmployeeLeave)
+			employeeLeaves.GET("", employeeLeaveHandler.ListEmployeeLeaves)
+			employeeLeaves.DELETE("/:id", employeeLeaveHandler.DeleteEmployeeLeave)
+		}
+
 		parts := protected.Group("/parts")
 		parts.Use(middleware.RequireStaffManagers())
 		{
@@ -494,6 +824,18 @@ func setupRoutes(
 			parts.DELETE("/:id", partHandler.DeletePartItem)
 		}
 
+		// Flat-rate labour catalog: staff read it, managers maintain it (the service checks).
+		labourOps := protected.Group("/labour-operations")
+		labourOps.Use(middleware.RequireWorkshopStaff())
+		{
+			labourOps.POST("", labourOperationHandler.CreateLabourOperation)
+			labourOps.GET("", labourOperationHandler.ListLabourOperations)
+			labourOps.POST("/import", labourOperationHandler.ImportLabourOperations)
+			labourOps.GET("/:id", labourOperationHandler.GetLabourOperation)
+			labourOps.PUT("/:id", labourOperationHandler.UpdateLabourOperation)
+			labourOps.DELETE("/:id", labourOperationHandler.DeleteLabourOperation)
+		}
+
 		// Car routes would go here
 		cars := protected.Group("/cars")
 		{
@@ -509,30 +851,106 @@ func setupRoutes(
 		{
 			appointments.POST("", appointmentHandler.CreateAppointment)
 			appointments.GET("", appointmentHandler.ListAppointments)
+			appointments.GET("/mine", middleware.RequireWorkshopStaff(), appointmentHandler.ListMyAppointments)
+			appointments.GET("/no-shows", middleware.RequireWorkshopStaff(), appointmentHandler.GetCustomerNoShows)
+			appointments.POST("/waitlist", waitlistHandler.JoinWaitlist)
+			appointments.GET("/waitlist", waitlistHandler.ListWaitlist)
+			appointments.DELETE("/waitlist/:id", waitlistHandler.LeaveWaitlist)
 			appointments.GET("/:id", appointmentHandler.GetAppointment)
 			appointments.PUT("/:id", appointmentHandler.UpdateAppointment)
+			appointments.PUT("/:id/assignee", middleware.RequireWorkshopStaff(), appointmentHandler.AssignTechnician)
+			appointments.PUT("/:id/no-show", middleware.RequireWorkshopStaff(), appointmentHandler.MarkNoShow)
 			appointments.DELETE("/:id", appointmentHandler.DeleteAppointment)
 		}
 
 		repairs := protected.Group("/repairs")
 		{
 			repairs.GET("/car/:carId", repairHandler.ListRepairsByCar)
+			repairs.GET("/mine", repairHandler.ListMyRepairs)
+			repairs.GET("", middleware.RequireStaffManagers(), repairHandler.ListRepairs)
+			repairs.GET("/export", middleware.RequireStaffManagers(), repairHandler.ExportRepairs)
+			repairs.GET("/rework-stats", middleware.RequireStaffManagers(), repairHandler.GetReworkStats)
+			repairs.GET("/car/:carId/warranties", repairHandler.ListCarWarranties)
 			repairs.POST("", repairHandler.GinCreateRepair)
 			repairs.GET("/:id", repairHandler.GinGetRepair)
 			repairs.PUT("/:id", repairHandler.GinUpdateRepair)
 			repairs.DELETE("/:id", repairHandler.GinDeleteRepair)
+			repairs.POST("/:id/lines", repairHandler.AddRepairLine)
+			repairs.POST("/:id/operations", repairHandler.AddRepairOperation)
+			repairs.DELETE("/:id/lines/:lineId", repairHandler.RemoveRepairLine)
+			repairs.POST("/:id/status", repairHandler.TransitionRepair)
+			repairs.GET("/:id/status-history", repairHandler.GetRepairStatusHistory)
+			repairs.PUT("/:id/technicians", repairHandler.AssignRepairTechnicians)
+			repairs.PUT("/:id/warranty", repairHandler.SetRepairWarranty)
 		}
 
+		// Visit pages (detail, tracker, messages, findings, OBD sessions, status history, a car's visits) are also open to the car's owner; the service checks ownership.
 		svcJobs := protected.Group("/service-jobs")
-		svcJobs.Use(middleware.RequireWorkshopStaff())
+		staff := middleware.RequireWorkshopStaff()
 		{
-			svcJobs.POST("", serviceJobHandler.CreateServiceJob)
-			svcJobs.GET("", serviceJobHandler.ListServiceJobsByOpenedOn)
+			svcJobs.POST("", staff, serviceJobHandler.CreateServiceJob)
+			svcJobs.GET("", staff, serviceJobHandler.ListServiceJobsByOpenedOn)
+			svcJobs.POST("/check-in", staff, serviceJobHandler.CheckInAppointment)
+			svcJobs.GET("/arrivals", staff, serviceJobHandler.ListArrivals)
+			svcJobs.GET("/board", staff, serviceJobHandler.GetBoard)
+			svcJobs.GET("/board/events", staff, serviceJobHandler.StreamBoard)
+			svcJobs.GET("/promise-alerts", staff, serviceJobHandler.ListPromiseAlerts)
 			svcJobs.GET("/car/:carId", serviceJobHandler.ListServiceJobsByCar)
-			svcJobs.GET("/:id/obd", serviceJobHandler.StubOBD)
+			svcJobs.GET("/:id/obd", serviceJobHandler.ListOBD)
+			svcJobs.POST("/:id/obd", staff, serviceJobHandler.UploadOBD)
 			svcJobs.GET("/:id", serviceJobHandler.GetServiceJob)
-			svcJobs.PUT("/:id/reception", serviceJobHandler.PutReception)
-			svcJobs.PUT("/:id/handover", serviceJobHandler.PutHandover)
+			svcJobs.PUT("/:id/reception", staff, serviceJobHandler.PutReception)
+			svcJobs.PUT("/:id/handover", staff, serviceJobHandler.PutHandover)
+			svcJobs.POST("/:id/cancel", staff, serviceJobHandler.CancelServiceJob)
+			svcJobs.POST("/:id/reopen", staff, serviceJobHandler.ReopenServiceJob)
+			svcJobs.POST("/:id/status", staff, serviceJobHandler.TransitionServiceJob)
+			svcJobs.GET("/:id/status-history", serviceJobHandler.ListStatusHistory)
+			svcJobs.PUT("/:id/promise", staff, serviceJobHandler.PutPromise)
+			svcJobs.GET("/:id/promise-history", serviceJobHandler.ListPromiseHistory)
+			svcJobs.GET("/:id/job-card.pdf", serviceJobHandler.JobCardPDF)
+			svcJobs.GET("/:id/handover.pdf", serviceJobHandler.HandoverPDF)
+			svcJobs.GET("/:id/signatures/:stage", serviceJobHandler.GetSignature)
+			svcJobs.GET("/:id/labour", staff, serviceJobHandler.GetLabour)
+			svcJobs.GET("/:id/tracker", serviceJobHandler.GetVisitTracker)
+			svcJobs.POST("/:id/tracker-link", staff, serviceJobHandler.CreateTrackerLink)
+			svcJobs.GET("/:id/messages", serviceJobHandler.ListMessages)
+			svcJobs.POST("/:id/messages", serviceJobHandler.PostMessage)
+			svcJobs.POST("/:id/messages/read", serviceJobHandler.MarkMessagesRead)
+			svcJobs.GET("/:id/messages/attachments/:attachmentId", serviceJobHandler.GetMessageAttachment)
+			svcJobs.GET("/:id/findings", serviceJobHandler.ListFindings)
+			svcJobs.POST("/:id/findings", staff, serviceJobHandler.AddFinding)
+			svcJobs.PUT("/:id/findings/:findingId", staff, serviceJobHandler.UpdateFinding)
+			svcJobs.DELETE("/:id/findings/:findingId", staff, serviceJobHandler.DeleteFinding)
+			svcJobs.POST("/:id/findings/:findingId/repair", staff, serviceJobHandler.ProposeRepairFromFinding)
+			svcJobs.GET("/:id/estimates", estimateHandler.ListEstimates)
+			svcJobs.POST("/:id/estimates", staff, estimateHandler.CreateEstimate)
+		}
+
+		// Estimates: staff draft and send; the car's owner decides (ownership checked in the service).
+		estimates := protected.Group("/estimates")
+		{
+			estimates.GET("/:id", estimateHandler.GetEstimate)
+			estimates.PUT("/:id", staff, estimateHandler.UpdateEstimate)
+			estimates.POST("/:id/send", staff, estimateHandler.SendEstimate)
+			estimates.POST("/:id/decision", estimateHandler.DecideEstimate)
+		}
+
+		// Technician timers on visits and repairs; hours land in the visit's labour summary.
+		workHours := protected.Group("/work-hours")
+		workHours.Use(middleware.RequireWorkshopStaff())
+		{
+			workHours.POST("/clock-in", serviceJobHandler.ClockIn)
+			workHours.POST("/clock-out", serviceJobHandler.ClockOut)
+			workHours.GET("/running", serviceJobHandler.GetRunningTimer)
+		}
+
+		checklistTemplates := protected.Group("/checklist-templates")
+		checklistTemplates.Use(middleware.RequireWorkshopStaff())
+		{
+			checklistTemplates.GET("", checklistTemplateHandler.ListChecklistTemplates)
+			checklistTemplates.POST("", checklistTemplateHandler.SaveChecklistTemplate)
+			checklistTemplates.GET("/versions", checklistTemplateHandler.ListChecklistTemplateVersions)
+			checklistTemplates.GET("/:id", checklistTemplateHandler.GetChecklistTemplate)
 		}
 
 		suppliers := protected.Group("/suppliers")
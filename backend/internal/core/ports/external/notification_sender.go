@@ -0,0 +1,12 @@
+package external
+
+import (
+	"context"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// NotificationSender delivers one queued message over a single channel (SMS gateway, WhatsApp API, SMTP...).
+type NotificationSender interface {
+	Send(ctx context.Context, n *domain.OutboundNotification) error
+}
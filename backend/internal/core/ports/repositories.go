@@ -112,10 +112,40 @@ type RepairRepository interface {
 	Create(ctx context.Context, repair *domain.Repair) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Repair, error)
 	Update(ctx context.Context, repair *domain.Repair) error
+	// Delete soft-deletes the repair and puts its part lines back in stock, atomically.
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByCarID(ctx context.Context, carID uuid.UUID) ([]*domain.Repair, error)
 	// ListIDsByServiceJobID returns repair row IDs linked to a visit; empty if none.
 	ListIDsByServiceJobID(ctx context.Context, serviceJobID uuid.UUID) ([]uuid.UUID, error)
+	// ListByServiceJobIDs returns the repairs linked to any of the visits, oldest first.
+	ListByServiceJobIDs(ctx context.Context, serviceJobIDs []uuid.UUID) ([]*domain.Repair, error)
+	// ChangeStatus stores the status and timestamps of r (after r.Apply(ev)) while the stored status is still
+	// ev.FromStatus, and records ev, atomically; otherwise domain.ErrRepairStatusConflict. Cancelling puts the
+	// part lines back in stock; reopening a cancelled repair takes them out again (domain.ErrInsufficientStock).
+	ChangeStatus(ctx context.Context, r *domain.Repair, ev *domain.RepairStatusEvent) error
+	// ListStatusEvents returns the repair's status history, oldest first.
+	ListStatusEvents(ctx context.Context, repairID uuid.UUID) ([]*domain.RepairStatusEvent, error)
+	// ListByTechnician returns the repairs the user leads or is assigned to, on any car, newest first;
+	// an empty status means any status.
+	ListByTechnician(ctx context.Context, userID uuid.UUID, status domain.RepairStatus, limit, offset int) ([]*domain.Repair, error)
+	// List returns the repairs matching filters across the workshop, newest first, with the total match count.
+	List(ctx context.Context, filters *RepairFilters) ([]*domain.Repair, int64, error)
+}
+
+// RepairFilters drives the workshop-wide repair search; nil and zero fields do not filter.
+type RepairFilters struct {
+	Status       *domain.RepairStatus
+	TechnicianID *uuid.UUID // leads or is assigned to the repair
+	ServiceJobID *uuid.UUID
+	// CreatedFrom / CreatedTo bound created_at to [from, to) when set.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MinCost     *float64
+	MaxCost     *float64
+	Search      string // case-insensitive text in the description
+	Rework      *bool  // true: only warranty rework; false: leave it out
+	Limit       int
+	Offset      int
 }
 
 // ServiceJobRepository persists workshop visits (service jobs) and 1:1 reception/handover.
@@ -126,10 +156,91 @@ type ServiceJobRepository interface {
 	ListByCarID(ctx context.Context, carID uuid.UUID) ([]*domain.ServiceJob, error)
 	// ListByOpenedOn returns visits whose OpenedAt falls in [day 00:00 UTC, next day 00:00 UTC). Day is normalized to UTC date (year, month, day only).
 	ListByOpenedOn(ctx context.Context, day time.Time) ([]*domain.ServiceJob, error)
-	SaveReception(ctx context.Context, r *domain.ServiceJobReception) error
+	// ListActive returns every open or in-progress visit, oldest first.
+	ListActive(ctx context.Context) ([]*domain.ServiceJob, error)
+	// GetByAppointmentID returns the visit opened from an appointment at check-in, or nil, nil if none.
+	GetByAppointmentID(ctx context.Context, appointmentID uuid.UUID) (*domain.ServiceJob, error)
+	// ListByAppointmentIDs returns the visits opened from any of the appointments, in no particular order.
+	ListByAppointmentIDs(ctx context.Context, appointmentIDs []uuid.UUID) ([]*domain.ServiceJob, error)
+	// CreateForAppointment inserts the visit opened at check-in and moves its appointment (job.AppointmentID)
+	// from scheduled or confirmed to checked_in, atomically. Returns domain.ErrAppointmentAlreadyCheckedIn when
+	// the appointment is already checked in, domain.ErrAppointmentNotCheckInable when it is in any other status.
+	CreateForAppointment(ctx context.Context, job *domain.ServiceJob) error
+	// SaveReception upserts the reception; a non-nil ev changes the visit's status in the same transaction (see ChangeStatus).
+	SaveReception(ctx context.Context, r *domain.ServiceJobReception, ev *domain.ServiceJobStatusEvent) error
 	GetReception(ctx context.Context, serviceJobID uuid.UUID) (*domain.ServiceJobReception, error)
-	SaveHandover(ctx context.Context, h *domain.ServiceJobHandover) error
+	// SaveHandover upserts the handover; a non-nil ev changes the visit's status in the same transaction (see ChangeStatus).
+	SaveHandover(ctx context.Context, h *domain.ServiceJobHandover, ev *domain.ServiceJobStatusEvent) error
 	GetHandover(ctx context.Context, serviceJobID uuid.UUID) (*domain.ServiceJobHandover, error)
+	// ChangeStatus moves the visit from ev.FromStatus to ev.ToStatus and records ev, atomically; returns
+	// domain.ErrServiceJobStatusConflict when the stored status is no longer ev.FromStatus.
+	ChangeStatus(ctx context.Context, ev *domain.ServiceJobStatusEvent) error
+	// ListStatusEvents returns the visit's status history, oldest first.
+	ListStatusEvents(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobStatusEvent, error)
+	// SetPromise stores rev.PromisedAt on an open or in-progress visit and records rev, atomically; returns
+	// domain.ErrServiceJobStatusConflict when the visit is no longer active.
+	SetPromise(ctx context.Context, rev *domain.ServiceJobPromiseRevision) error
+	// ListPromiseRevisions returns the visit's promised-time changes, oldest first.
+	ListPromiseRevisions(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobPromiseRevision, error)
+}
+
+// ChecklistTemplateRepository persists immutable checklist template versions.
+type ChecklistTemplateRepository interface {
+	// Create inserts a version; returns domain.ErrChecklistVersionConflict if (code, kind, version) exists.
+	Create(ctx context.Context, t *domain.ChecklistTemplate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ChecklistTemplate, error)
+	// GetLatest returns the highest version of code for kind, or nil, nil if none.
+	GetLatest(ctx context.Context, code string, kind domain.ChecklistKind) (*domain.ChecklistTemplate, error)
+	// ListLatest returns the highest version of every template of kind (all kinds when empty), ordered by code.
+	ListLatest(ctx context.Context, kind domain.ChecklistKind) ([]*domain.ChecklistTemplate, error)
+	// ListVersions returns every version of code for kind, newest first.
+	ListVersions(ctx context.Context, code string, kind domain.ChecklistKind) ([]*domain.ChecklistTemplate, error)
+}
+
+// InspectionFindingRepository persists DVI findings of service jobs.
+type InspectionFindingRepository interface {
+	Create(ctx context.Context, f *domain.InspectionFinding) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.InspectionFinding, error)
+	// ListByServiceJob returns a visit's findings, red first, then amber, then green; oldest first within a severity.
+	ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.InspectionFinding, error)
+	Update(ctx context.Context, f *domain.InspectionFinding) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetRepair links repairID only while the finding has none; ok is false if another request linked one first.
+	SetRepair(ctx context.Context, id uuid.UUID, repairID *uuid.UUID, onlyIfUnset bool) (ok bool, err error)
+}
+
+// EstimateRepository persists visit estimates with their lines.
+type EstimateRepository interface {
+	// Create stores the estimate and its lines.
+	Create(ctx context.Context, e *domain.Estimate) error
+	// GetByID returns the estimate with lines in Position order.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Estimate, error)
+	ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.Estimate, error)
+	// Save replaces header and lines while the stored status is still from; otherwise ErrEstimateStatusConflict.
+	Save(ctx context.Context, e *domain.Estimate, from domain.EstimateStatus) error
+	// HasApprovedLine reports whether any approved line of a decided estimate authorises the repair.
+	HasApprovedLine(ctx context.Context, repairID uuid.UUID) (bool, error)
+}
+
+// OBDSessionRepository persists uploaded OBD-II scanner sessions of service jobs.
+type OBDSessionRepository interface {
+	Create(ctx context.Context, s *domain.OBDSession) error
+	// ListByServiceJob returns a visit's sessions, oldest first.
+	ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.OBDSession, error)
+}
+
+// WorkHourRepository persists the time technicians clock on visits and repairs.
+type WorkHourRepository interface {
+	// Start stores a running timer (EndTime nil) and links it to the technician's employee record, if any;
+	// returns domain.ErrWorkTimerRunning when the technician already has one.
+	Start(ctx context.Context, w *domain.WorkHour) error
+	// Stop ends the technician's running timer at end, stores its hours and adds them to the employee's
+	// hours worked, atomically; returns domain.ErrNoWorkTimerRunning when none runs.
+	Stop(ctx context.Context, technicianID uuid.UUID, end time.Time) (*domain.WorkHour, error)
+	// GetRunning returns the technician's running timer, or nil, nil.
+	GetRunning(ctx context.Context, technicianID uuid.UUID) (*domain.WorkHour, error)
+	// ListByServiceJob returns the time clocked on a visit, including its repairs, oldest first.
+	ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.WorkHour, error)
 }
 
 // InvoiceRepository persists invoices (customer-scoped access enforced in InvoiceService).
@@ -203,6 +314,69 @@ type AppointmentRepository interface {
 	List(ctx context.Context, filters *AppointmentFilters) ([]*domain.Appointment, int64, error)
 	// CountNonCancelledBetween counts appointments with scheduled_at in [start, end) (UTC), excluding cancelled, optionally excluding an id (e.g. current row on update).
 	CountNonCancelledBetween(ctx context.Context, start, end time.Time, excludeID *uuid.UUID) (int64, error)
+	// CreateWithinDayCap inserts the appointment only while fewer than limit non-cancelled appointments
+	// fall in [dayStart, dayEnd); check and insert run under a per-day lock, so concurrent bookings
+	// cannot overshoot. Returns domain.ErrAppointmentDailyCapReached when the day is full.
+	CreateWithinDayCap(ctx context.Context, appointment *domain.Appointment, dayStart, dayEnd time.Time, limit int64) error
+	// UpdateWithinDayCap is Update with the same locked check; the appointment itself is not counted.
+	UpdateWithinDayCap(ctx context.Context, appointment *domain.Appointment, dayStart, dayEnd time.Time, limit int64) error
+}
+
+// EmployeeLeaveRepository persists staff leave windows used by appointment assignment.
+type EmployeeLeaveRepository interface {
+	Create(ctx context.Context, leave *domain.EmployeeLeave) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.EmployeeLeave, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListOverlapping returns leaves intersecting [start, end); userID nil means every staff member.
+	ListOverlapping(ctx context.Context, userID *uuid.UUID, start, end time.Time) ([]*domain.EmployeeLeave, error)
+}
+
+// WaitlistRepository stores the per-day waitlist. Status changes are conditional on the current
+// status so concurrent offers, claims and expiries cannot both win.
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *domain.WaitlistEntry) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WaitlistEntry, error)
+	// ListByCustomer returns the customer's waiting and offered entries, oldest first.
+	ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*domain.WaitlistEntry, error)
+	// ListByDay returns every entry for the local day starting at day, oldest first.
+	ListByDay(ctx context.Context, day time.Time) ([]*domain.WaitlistEntry, error)
+	// NextWaiting returns the oldest waiting entry for the day, or nil, nil when none.
+	NextWaiting(ctx context.Context, day time.Time) (*domain.WaitlistEntry, error)
+	// Transition moves id from one status to another; false when the entry was not in `from`.
+	// Non-nil patch fields (offer slot and expiry, appointment) are written in the same update.
+	Transition(ctx context.Context, id uuid.UUID, from, to domain.WaitlistStatus, patch WaitlistPatch) (bool, error)
+	// ListExpiredOffers returns offered entries whose OfferExpiresAt is before now.
+	ListExpiredOffers(ctx context.Context, now time.Time, limit int) ([]*domain.WaitlistEntry, error)
+}
+
+// WaitlistPatch carries the optional columns written by WaitlistRepository.Transition.
+type WaitlistPatch struct {
+	OfferedSlot    *time.Time
+	OfferExpiresAt *time.Time
+	AppointmentID  *uuid.UUID
+}
+
+// OutboundNotificationRepository is the notification outbox drained by the dispatcher.
+type OutboundNotificationRepository interface {
+	// Enqueue stores n; with a non-empty DedupeKey already present it stores nothing and returns false.
+	Enqueue(ctx context.Context, n *domain.OutboundNotification) (bool, error)
+	// ListDue returns pending rows with send_after <= now, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.OutboundNotification, error)
+	MarkSent(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkFailed records an attempt error; retryAt nil marks the row failed for good.
+	MarkFailed(ctx context.Context, id uuid.UUID, lastErr string, retryAt *time.Time) error
+	// Postpone keeps the row pending until `until` without counting an attempt (its channel cannot deliver yet).
+	Postpone(ctx context.Context, id uuid.UUID, until time.Time, reason string) error
+}
+
+// CalendarFeedKeyRepository stores one calendar feed key per user.
+type CalendarFeedKeyRepository interface {
+	// Ensure returns the user's key, creating it on first use.
+	Ensure(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error)
+	// GetByKey returns domain.ErrCalendarFeedInvalid when no user holds key.
+	GetByKey(ctx context.Context, key uuid.UUID) (*domain.CalendarFeedKey, error)
+	// Rotate gives the user a new key; feed tokens signed with the old one stop resolving.
+	Rotate(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error)
 }
 
 // AppointmentFilters represents filters for listing appointments
@@ -211,10 +385,78 @@ type AppointmentFilters struct {
 	EmployeeID  *uuid.UUID
 	CarID       *uuid.UUID
 	ScheduledAt *time.Time
-	Reason      *string
-	Status      *string
-	SortBy      string
-	SortOrder   string
-	Limit       int
-	Offset      int
+	// ScheduledFrom / ScheduledTo bound scheduled_at to [from, to) when set.
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+	Reason        *string
+	Status        *string
+	SortBy        string
+	SortOrder     string
+	Limit         int
+	Offset        int
+}
+
+// ServiceJobMessageRepository persists the message thread of each visit.
+type ServiceJobMessageRepository interface {
+	// Create stores the message and its attachments.
+	Create(ctx context.Context, m *domain.ServiceJobMessage) error
+	// ListByServiceJob returns the visit's thread with attachments, oldest first.
+	ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobMessage, error)
+	// MarkRead stamps every unread message of the visit sent by the given side as read by readerID at at,
+	// and returns how many it stamped.
+	MarkRead(ctx context.Context, serviceJobID uuid.UUID, sentBy domain.MessageSide, readerID uuid.UUID, at time.Time) (int64, error)
+	// GetAttachment returns an attachment of a message on the visit, or domain.ErrMessageAttachmentNotFound.
+	GetAttachment(ctx context.Context, serviceJobID, attachmentID uuid.UUID) (*domain.ServiceJobMessageAttachment, error)
+}
+
+// RepairLineRepository persists the labour and parts lines of repairs. Adding or removing a line keeps the
+// repair's cost equal to its line total and moves part stock, in one transaction.
+type RepairLineRepository interface {
+	// Add stores the line; a part line takes its quantity from stock, or fails with domain.ErrInsufficientStock.
+	// Returns the repair's new cost.
+	Add(ctx context.Context, line *domain.RepairLine) (cost float64, err error)
+	// Remove deletes the line and puts a part line's quantity back in stock. Returns the repair's new cost.
+	Remove(ctx context.Context, repairID, lineID uuid.UUID) (cost float64, err error)
+	// ListByRepair returns the repair's lines, oldest first.
+	ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairLine, error)
+}
+
+// RepairTechnicianRepository persists who works on each repair.
+type RepairTechnicianRepository interface {
+	// CreateRepair stores a new repair and its technician set ts atomically.
+	CreateRepair(ctx context.Context, repair *domain.Repair, ts []domain.RepairTechnician) error
+	// Replace swaps the repair's technician set for ts and points repairs.technician_id at the lead, atomically.
+	Replace(ctx context.Context, repairID uuid.UUID, ts []domain.RepairTechnician) error
+	// ListByRepair returns the repair's technicians, lead first.
+	ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairTechnician, error)
+}
+
+// RepairWarrantyRepository persists the warranties given on completed repairs and their fitted parts.
+type RepairWarrantyRepository interface {
+	// Replace swaps the repair's warranties for ws atomically.
+	Replace(ctx context.Context, repairID uuid.UUID, ws []domain.RepairWarranty) error
+	ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairWarranty, error)
+	// ListByCar returns the warranties on the car's (not deleted) repairs, newest first.
+	ListByCar(ctx context.Context, carID uuid.UUID) ([]domain.RepairWarranty, error)
+}
+
+// LabourOperationListFilters drives listing the labour catalog. Make/Model keep the operations that apply to
+// that car (including those for every car).
+type LabourOperationListFilters struct {
+	Search string // code or description
+	Make   string
+	Model  string
+	Limit  int
+	Offset int
+}
+
+// LabourOperationRepository persists the labour-operation catalog with its vehicles and parts kit.
+type LabourOperationRepository interface {
+	Create(ctx context.Context, op *domain.LabourOperation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.LabourOperation, error)
+	GetByCode(ctx context.Context, code string) (*domain.LabourOperation, error)
+	// Update stores the operation and replaces its vehicles and kit.
+	Update(ctx context.Context, op *domain.LabourOperation) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, f LabourOperationListFilters) ([]*domain.LabourOperation, int64, error)
 }
@@ -148,6 +148,21 @@ type AppointmentService interface {
 	DeleteAppointment(ctx context.Context, appointmentID uuid.UUID, requestingUserID uuid.UUID) error
 	// ListAppointments lists appointments with optional filters and authorization checks
 	ListAppointments(ctx context.Context, requestingUserID uuid.UUID, filters *AppointmentFilters) ([]*domain.Appointment, int64, error)
+	// AssignTechnician sets or clears (nil) the assigned technician; staff only
+	AssignTechnician(ctx context.Context, appointmentID uuid.UUID, employeeID *uuid.UUID, requestingUserID uuid.UUID) (*domain.Appointment, error)
+	// ListAssignedOn lists the requesting technician's appointments for one local day
+	ListAssignedOn(ctx context.Context, day time.Time, requestingUserID uuid.UUID) ([]*domain.Appointment, error)
+	// MarkNoShow records that the client did not turn up; staff only
+	MarkNoShow(ctx context.Context, appointmentID uuid.UUID, requestingUserID uuid.UUID) (*domain.Appointment, error)
+	// CustomerNoShows returns a customer's no-show record; staff only
+	CustomerNoShows(ctx context.Context, customerID uuid.UUID, requestingUserID uuid.UUID) (*NoShowSummary, error)
+}
+
+// NoShowSummary is a customer's no-show record as seen by staff.
+type NoShowSummary struct {
+	CustomerID       uuid.UUID `json:"customerId"`
+	NoShows          int64     `json:"noShows"`
+	RequiresApproval bool      `json:"requiresApproval"` // new bookings go to pending_approval
 }
 
 // InvoiceService customer invoices (client: own invoices only for read/update notes).
@@ -187,6 +202,30 @@ type SupplierService interface {
 	Delete(ctx context.Context, id uuid.UUID, requestingUserID uuid.UUID) error
 }
 
+// LabourOperationImport summarises a catalog CSV import; rows with errors are skipped.
+type LabourOperationImport struct {
+	Created int                          `json:"created"`
+	Updated int                          `json:"updated"`
+	Errors  []LabourOperationImportError `json:"errors"`
+}
+
+// LabourOperationImportError explains why one CSV row was skipped (Row counts the header as row 1).
+type LabourOperationImportError struct {
+	Row   int    `json:"row"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error"`
+}
+
+// LabourOperationService manages the labour-operation catalog (staff read; managers edit and import).
+type LabourOperationService interface {
+	Create(ctx context.Context, op *domain.LabourOperation, requestingUserID uuid.UUID) (*domain.LabourOperation, error)
+	Get(ctx context.Context, id uuid.UUID, requestingUserID uuid.UUID) (*domain.LabourOperation, error)
+	List(ctx context.Context, filters LabourOperationListFilters, requestingUserID uuid.UUID) ([]*domain.LabourOperation, int64, error)
+	Update(ctx context.Context, op *domain.LabourOperation, requestingUserID uuid.UUID) (*domain.LabourOperation, error)
+	Delete(ctx context.Context, id uuid.UUID, requestingUserID uuid.UUID) error
+	ImportCSV(ctx context.Context, data []byte, requestingUserID uuid.UUID) (*LabourOperationImport, error)
+}
+
 // PartService manages spare-parts inventory (authorization in HTTP layer).
 type PartService interface {
 	Create(ctx context.Context, item *domain.PartItem, requestingUserID uuid.UUID) (*domain.PartItem, error)
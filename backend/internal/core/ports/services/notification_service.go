@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
 )
@@ -16,7 +17,14 @@ type NotificationService interface {
 }
 
 type NotificationRequest struct {
-	Type    string `json:"type"` // "sms" or "whatsapp"
+	Type    string `json:"type"` // "sms", "whatsapp" or "email"
 	To      string `json:"to"`
+	Subject string `json:"subject,omitempty"` // email only
 	Message string `json:"message"`
+	// SendAt delays delivery; nil means as soon as possible.
+	SendAt *time.Time `json:"send_at,omitempty"`
+	// DedupeKey makes queueing idempotent: a second request with the same key is dropped.
+	DedupeKey string `json:"dedupe_key,omitempty"`
+	// Attachments are only accepted on the email channel.
+	Attachments []domain.NotificationAttachment `json:"attachments,omitempty"`
 }
@@ -9,10 +9,13 @@ import (
 type AppointmentStatus string
 
 const (
-	AppointmentStatusScheduled AppointmentStatus = "scheduled"
-	AppointmentStatusConfirmed AppointmentStatus = "confirmed"
-	AppointmentStatusCompleted AppointmentStatus = "completed"
-	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+	AppointmentStatusScheduled       AppointmentStatus = "scheduled"
+	AppointmentStatusConfirmed       AppointmentStatus = "confirmed"
+	AppointmentStatusCheckedIn       AppointmentStatus = "checked_in" // car arrived; a service job was opened from it
+	AppointmentStatusCompleted       AppointmentStatus = "completed"
+	AppointmentStatusCancelled       AppointmentStatus = "cancelled"
+	AppointmentStatusNoShow          AppointmentStatus = "no_show"          // the client did not turn up
+	AppointmentStatusPendingApproval AppointmentStatus = "pending_approval" // booked by a repeat no-show client; staff must confirm
 )
 
 type Appointment struct {
@@ -23,6 +26,8 @@ type Appointment struct {
 	Status      AppointmentStatus `json:"status" gorm:"not null;default:'scheduled'"`
 	ScheduledAt time.Time         `json:"scheduled_at" gorm:"column:scheduled_at;not null"`
 	Notes       string            `json:"notes"`
+	EmployeeID  *uuid.UUID        `json:"employee_id,omitempty" gorm:"type:uuid;column:employee_id;index"` // assigned technician (staff user ID); nil = unassigned
+	Sequence    int               `json:"sequence" gorm:"column:sequence;not null;default:0"`              // edit counter; the repository bumps it on every update (iCal SEQUENCE)
 	CreatedAt   time.Time         `json:"createdAt" gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt   time.Time         `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
 	DeletedAt   *time.Time        `json:"deleted_at,omitempty" gorm:"column:deleted_at;index"`
@@ -40,9 +45,22 @@ func (Appointment) TableName() string {
 // ValidateAppointmentStatus checks if appointment status is valid
 func ValidateAppointmentStatus(status AppointmentStatus) bool {
 	switch status {
-	case AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusCancelled, AppointmentStatusCompleted:
+	case AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusCheckedIn, AppointmentStatusCancelled, AppointmentStatusCompleted,
+		AppointmentStatusNoShow, AppointmentStatusPendingApproval:
 		return true
 	default:
 		return false
 	}
 }
+
+// AppointmentDayLock is one row per booked day. Writers that check the daily capacity upsert the
+// day's row first inside their transaction, so bookings for the same day are serialized.
+type AppointmentDayLock struct {
+	Day      string    `gorm:"primaryKey;size:32"` // UTC start of the workshop day, RFC 3339
+	LockedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for GORM
+func (AppointmentDayLock) TableName() string {
+	return "appointment_day_locks"
+}
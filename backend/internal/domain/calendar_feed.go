@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedKey is the per-user secret behind calendar feed URLs. Feed tokens carry Key rather than
+// the user ID, so replacing Key revokes every feed URL the user has handed out.
+type CalendarFeedKey struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Key       uuid.UUID `json:"-" gorm:"type:uuid;not null;uniqueIndex"`
+	RotatedAt time.Time `json:"rotated_at" gorm:"not null"`
+}
+
+func (CalendarFeedKey) TableName() string {
+	return "calendar_feed_keys"
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChecklistKind is the visit step a checklist template is filled in at.
+type ChecklistKind string
+
+const (
+	ChecklistKindReception ChecklistKind = "reception"
+	ChecklistKindHandover  ChecklistKind = "handover"
+)
+
+// ChecklistItemType says how an item is answered.
+type ChecklistItemType string
+
+const (
+	ChecklistItemBoolean ChecklistItemType = "boolean" // answered with Bool
+	ChecklistItemLevel   ChecklistItemType = "level"   // Text, one of the item's Levels
+	ChecklistItemNumber  ChecklistItemType = "number"  // Number, within Min / Max when set
+	ChecklistItemText    ChecklistItemType = "text"    // Text
+	ChecklistItemPhoto   ChecklistItemType = "photo"   // Text holds the stored photo's URL or key
+)
+
+// Checklist schema versions of ServiceJobReception / ServiceJobHandover rows.
+const (
+	ChecklistSchemaFixed    = 1 // legacy fixed columns only
+	ChecklistSchemaTemplate = 2 // ChecklistAnswers against ChecklistTemplateID
+)
+
+// DefaultChecklistLevels is used by level items that do not list their own.
+var DefaultChecklistLevels = []string{"empty", "low", "ok", "full"}
+
+// ChecklistItem is one line of a template.
+type ChecklistItem struct {
+	Key      string            `json:"key"`
+	Label    string            `json:"label"`
+	Type     ChecklistItemType `json:"type"`
+	Required bool              `json:"required,omitempty"`
+	Levels   []string          `json:"levels,omitempty"` // level items
+	Unit     string            `json:"unit,omitempty"`   // number items, e.g. "km", "mm", "V"
+	Min      *float64          `json:"min,omitempty"`
+	Max      *float64          `json:"max,omitempty"`
+}
+
+// ChecklistTemplate is one immutable version of a checklist. Editing a template creates the next
+// Version under the same Code, so visits keep pointing at the exact items they were filled in with.
+type ChecklistTemplate struct {
+	ID              uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
+	Code            string          `json:"code" gorm:"type:varchar(64);not null;uniqueIndex:idx_checklist_templates_code_kind_version"`
+	Kind            ChecklistKind   `json:"kind" gorm:"type:varchar(16);not null;uniqueIndex:idx_checklist_templates_code_kind_version"`
+	Version         int             `json:"version" gorm:"not null;uniqueIndex:idx_checklist_templates_code_kind_version"`
+	Name            string          `json:"name" gorm:"not null"`
+	Items           []ChecklistItem `json:"items" gorm:"type:text;serializer:json"`
+	CreatedByUserID uuid.UUID       `json:"created_by_user_id" gorm:"type:uuid;not null"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (ChecklistTemplate) TableName() string { return "checklist_templates" }
+
+// ChecklistAnswer is the value recorded for one template item; which field is set depends on the item type.
+type ChecklistAnswer struct {
+	Key    string   `json:"key"`
+	Bool   *bool    `json:"bool,omitempty"`
+	Number *float64 `json:"number,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// ValidateChecklistKind returns true for known kinds.
+func ValidateChecklistKind(k ChecklistKind) bool {
+	return k == ChecklistKindReception || k == ChecklistKindHandover
+}
+
+// Validate checks the template definition (not the version bookkeeping).
+func (t *ChecklistTemplate) Validate() error {
+	if strings.TrimSpace(t.Code) == "" || strings.TrimSpace(t.Name) == "" || !ValidateChecklistKind(t.Kind) || len(t.Items) == 0 {
+		return ErrInvalidChecklistTemplate
+	}
+	seen := make(map[string]bool, len(t.Items))
+	for _, it := range t.Items {
+		if strings.TrimSpace(it.Key) == "" || seen[it.Key] || strings.TrimSpace(it.Label) == "" {
+			return fmt.Errorf("%w: item %q", ErrInvalidChecklistTemplate, it.Key)
+		}
+		seen[it.Key] = true
+		switch it.Type {
+		case ChecklistItemBoolean, ChecklistItemLevel, ChecklistItemText, ChecklistItemPhoto:
+		case ChecklistItemNumber:
+			if it.Min != nil && it.Max != nil && *it.Min > *it.Max {
+				return fmt.Errorf("%w: item %q min above max", ErrInvalidChecklistTemplate, it.Key)
+			}
+		default:
+			return fmt.Errorf("%w: item %q has unknown type %q", ErrInvalidChecklistTemplate, it.Key, it.Type)
+		}
+	}
+	return nil
+}
+
+// LevelsOf returns the allowed values of a level item.
+func (it ChecklistItem) LevelsOf() []string {
+	if len(it.Levels) > 0 {
+		return it.Levels
+	}
+	return DefaultChecklistLevels
+}
+
+// ValidateAnswers checks answers against the template: known keys, one answer per key, values of
+// the item's type, and every required item answered.
+func (t *ChecklistTemplate) ValidateAnswers(answers []ChecklistAnswer) error {
+	items := make(map[string]ChecklistItem, len(t.Items))
+	for _, it := range t.Items {
+		items[it.Key] = it
+	}
+	answered := make(map[string]bool, len(answers))
+	for _, a := range answers {
+		it, ok := items[a.Key]
+		if !ok || answered[a.Key] {
+			return fmt.Errorf("%w: unknown or repeated item %q", ErrInvalidChecklistAnswers, a.Key)
+		}
+		if !answerMatches(it, a) {
+			return fmt.Errorf("%w: item %q expects a %s value", ErrInvalidChecklistAnswers, a.Key, it.Type)
+		}
+		answered[a.Key] = true
+	}
+	for _, it := range t.Items {
+		if it.Required && !answered[it.Key] {
+			return fmt.Errorf("%w: item %q is required", ErrInvalidChecklistAnswers, it.Key)
+		}
+	}
+	return nil
+}
+
+func answerMatches(it ChecklistItem, a ChecklistAnswer) bool {
+	switch it.Type {
+	case ChecklistItemBoolean:
+		return a.Bool != nil && a.Number == nil && a.Text == ""
+	case ChecklistItemNumber:
+		if a.Number == nil || a.Bool != nil || a.Text != "" {
+			return false
+		}
+		return (it.Min == nil || *a.Number >= *it.Min) && (it.Max == nil || *a.Number <= *it.Max)
+	case ChecklistItemLevel:
+		if a.Bool != nil || a.Number != nil {
+			return false
+		}
+		for _, l := range it.LevelsOf() {
+			if a.Text == l {
+				return true
+			}
+		}
+		return false
+	case ChecklistItemText, ChecklistItemPhoto:
+		return a.Bool == nil && a.Number == nil && strings.TrimSpace(a.Text) != ""
+	default:
+		return false
+	}
+}
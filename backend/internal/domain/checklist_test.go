@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evTemplate() *ChecklistTemplate {
+	minSoC, maxSoC := 0.0, 100.0
+	return &ChecklistTemplate{Code: "ev", Kind: ChecklistKindReception, Name: "Eléctrico", Items: []ChecklistItem{
+		{Key: "charge_cable", Label: "Cable de carga", Type: ChecklistItemBoolean, Required: true},
+		{Key: "soc", Label: "Carga batería", Type: ChecklistItemNumber, Unit: "%", Min: &minSoC, Max: &maxSoC},
+		{Key: "coolant", Label: "Refrigerante", Type: ChecklistItemLevel},
+		{Key: "dashboard", Label: "Foto tablero", Type: ChecklistItemPhoto},
+		{Key: "notes", Label: "Observaciones", Type: ChecklistItemText},
+	}}
+}
+
+func TestChecklistTemplate_Validate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, evTemplate().Validate())
+
+	dup := evTemplate()
+	dup.Items = append(dup.Items, ChecklistItem{Key: "soc", Label: "Otra", Type: ChecklistItemText})
+	assert.ErrorIs(t, dup.Validate(), ErrInvalidChecklistTemplate)
+
+	badType := evTemplate()
+	badType.Items[0].Type = "colour"
+	assert.ErrorIs(t, badType.Validate(), ErrInvalidChecklistTemplate)
+
+	badKind := evTemplate()
+	badKind.Kind = "inspection"
+	assert.ErrorIs(t, badKind.Validate(), ErrInvalidChecklistTemplate)
+}
+
+func TestChecklistTemplate_ValidateAnswers(t *testing.T) {
+	t.Parallel()
+	tmpl := evTemplate()
+	yes, soc, over := true, 64.0, 120.0
+
+	assert.NoError(t, tmpl.ValidateAnswers([]ChecklistAnswer{
+		{Key: "charge_cable", Bool: &yes},
+		{Key: "soc", Number: &soc},
+		{Key: "coolant", Text: "ok"},
+		{Key: "dashboard", Text: "uploads/jobs/1/dashboard.jpg"},
+	}))
+
+	cases := map[string][]ChecklistAnswer{
+		"required missing": {{Key: "soc", Number: &soc}},
+		"unknown key":      {{Key: "charge_cable", Bool: &yes}, {Key: "tyres", Text: "ok"}},
+		"repeated key":     {{Key: "charge_cable", Bool: &yes}, {Key: "charge_cable", Bool: &yes}},
+		"wrong type":       {{Key: "charge_cable", Text: "yes"}},
+		"out of range":     {{Key: "charge_cable", Bool: &yes}, {Key: "soc", Number: &over}},
+		"unknown level":    {{Key: "charge_cable", Bool: &yes}, {Key: "coolant", Text: "half"}},
+		"empty photo":      {{Key: "charge_cable", Bool: &yes}, {Key: "dashboard"}},
+	}
+	for name, answers := range cases {
+		assert.ErrorIs(t, tmpl.ValidateAnswers(answers), ErrInvalidChecklistAnswers, name)
+	}
+}
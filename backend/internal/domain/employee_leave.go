@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmployeeLeave blocks a workshop staff member from appointment assignment for [StartsAt, EndsAt).
+type EmployeeLeave struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID          uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	StartsAt        time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt          time.Time `json:"ends_at" gorm:"not null"`
+	Reason          string    `json:"reason,omitempty" gorm:"type:text"`
+	CreatedByUserID uuid.UUID `json:"created_by_user_id" gorm:"type:uuid;not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (EmployeeLeave) TableName() string { return "employee_leaves" }
+
+// Overlaps reports whether the leave intersects [start, end).
+func (l *EmployeeLeave) Overlaps(start, end time.Time) bool {
+	return l.StartsAt.Before(end) && start.Before(l.EndsAt)
+}
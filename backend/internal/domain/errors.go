@@ -17,6 +17,32 @@ var ErrAppointmentAlreadyExists = errors.New("appointment with the given ID alre
 var ErrInvalidAppointmentData = errors.New("invalid appointment data")
 var ErrAppointmentOutsideBusinessHours = errors.New("appointment outside business hours")
 var ErrAppointmentDailyCapReached = errors.New("maximum appointments per day reached")
+var ErrAppointmentAlreadyCheckedIn = errors.New("appointment already checked in")
+var ErrAppointmentNotCheckInable = errors.New("appointment cannot be checked in in its current status")
+var ErrAssigneeNotActiveEmployee = errors.New("assignee is not an active workshop employee")
+var ErrAssigneeDoubleBooked = errors.New("assignee already has an overlapping appointment")
+var ErrAssigneeOnLeave = errors.New("assignee is on leave at the appointment time")
+var ErrEmployeeLeaveNotFound = errors.New("employee leave not found")
+var ErrInvalidEmployeeLeaveData = errors.New("invalid employee leave data")
+var ErrInvalidNotification = errors.New("invalid notification")
+var ErrAppointmentLinkInvalid = errors.New("appointment link is invalid or expired")
+var ErrAppointmentNotRespondable = errors.New("appointment can no longer be confirmed or cancelled")
+var ErrCalendarFeedInvalid = errors.New("calendar feed link is invalid or expired")
+var ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+var ErrInvalidWaitlistData = errors.New("invalid waitlist data")
+var ErrWaitlistDuplicate = errors.New("already on the waitlist for that day")
+var ErrWaitlistDayNotFull = errors.New("the day still has free slots")
+var ErrWaitlistOfferUnavailable = errors.New("waitlist offer is invalid, expired or already claimed")
+
+// Appointment change-policy violations carry a Code the frontend maps to an explanation.
+var (
+	ErrAppointmentCancelTooLate         = NewError("appointment is too close to be cancelled online", "appointment_cancel_too_late")
+	ErrAppointmentRescheduleTooLate     = NewError("appointment is too close to be rescheduled online", "appointment_reschedule_too_late")
+	ErrAppointmentNotChangeable         = NewError("appointment can no longer be changed", "appointment_not_changeable")
+	ErrAppointmentStatusChangeForbidden = NewError("clients may only cancel an appointment", "appointment_status_change_forbidden")
+	ErrAppointmentNoShowNotApplicable   = NewError("only a past scheduled or confirmed appointment can be marked as no-show", "appointment_no_show_not_applicable")
+)
+
 var ErrWorkshopNotFound = errors.New("workshop not found")
 var ErrInvalidWorkshopData = errors.New("invalid workshop data")
 var ErrAccountingEntryNotFound = errors.New("accounting entry not found")
@@ -31,3 +57,49 @@ var ErrBillingDocumentNotFound = errors.New("billing document not found")
 var ErrServiceJobNotFound = errors.New("service job not found")
 var ErrInvalidServiceJobData = errors.New("invalid service job data")
 var ErrReceptionRequiredBeforeHandover = errors.New("reception must be completed before handover")
+var ErrChecklistTemplateNotFound = errors.New("checklist template not found")
+var ErrInvalidChecklistTemplate = errors.New("invalid checklist template")
+var ErrChecklistTemplateKindMismatch = errors.New("checklist template is for a different visit step")
+var ErrInvalidChecklistAnswers = errors.New("invalid checklist answers")
+var ErrChecklistVersionConflict = errors.New("checklist template version already exists")
+var ErrInspectionFindingNotFound = errors.New("inspection finding not found")
+var ErrInvalidInspectionFinding = errors.New("invalid inspection finding")
+var ErrFindingAlreadyConverted = errors.New("inspection finding already has a proposed repair")
+var ErrEstimateNotFound = errors.New("estimate not found")
+var ErrInvalidEstimate = errors.New("invalid estimate")
+var ErrEstimateNotEditable = errors.New("only draft estimates can be changed or sent")
+var ErrEstimateNotDecidable = errors.New("estimate is not awaiting the customer's decision")
+var ErrEstimateStatusConflict = errors.New("estimate was changed meanwhile")
+var ErrEstimateLinkInvalid = errors.New("estimate link is invalid or expired")
+var ErrRepairEstimateNotApproved = errors.New("repair has no approved estimate line")
+var ErrInvalidOBDLog = errors.New("obd log could not be read")
+var ErrOBDLogTooLarge = errors.New("obd log is too large")
+var ErrServiceJobTransitionNotAllowed = errors.New("service job status change not allowed")
+var ErrServiceJobReasonRequired = errors.New("a reason is required for this status change")
+var ErrServiceJobStatusConflict = errors.New("service job status was changed meanwhile")
+var ErrInvalidPromisedTime = errors.New("promised time must be in the future")
+var ErrHandoverNotRecorded = errors.New("visit has no handover yet")
+var ErrInvalidSignature = errors.New("signature is missing its image or signer, or the image is not a valid SVG path or PNG")
+var ErrSignatureRequired = errors.New("the client's signature is required to hand the car over")
+var ErrFileNotFound = errors.New("file not found")
+var ErrWorkTimerRunning = errors.New("technician already has a running timer")
+var ErrNoWorkTimerRunning = errors.New("technician has no running timer")
+var ErrInvalidWorkTarget = errors.New("clock in on exactly one open visit or active repair")
+var ErrVisitLinkInvalid = errors.New("visit tracking link is invalid or expired")
+var ErrInvalidMessage = errors.New("invalid message")
+var ErrMessageAttachmentNotFound = errors.New("message attachment not found")
+var ErrInvalidRepairLine = errors.New("invalid repair line")
+var ErrRepairLineNotFound = errors.New("repair line not found")
+var ErrInsufficientStock = errors.New("not enough stock of the part")
+var ErrRepairNotEditable = errors.New("repair is completed or cancelled")
+var ErrRepairTransitionNotAllowed = errors.New("repair status change not allowed")
+var ErrRepairStatusConflict = errors.New("repair status was changed meanwhile")
+var ErrRepairReasonRequired = errors.New("a reason is required to reopen a repair")
+var ErrInvalidRepairTechnicians = errors.New("a repair needs exactly one lead technician, each staff member once, with hours not negative")
+var ErrLabourOperationNotFound = errors.New("labour operation not found")
+var ErrInvalidLabourOperation = errors.New("labour operation needs a code, a description and flat-rate hours; vehicles need a make; kit parts need a part, quantity and price")
+var ErrLabourOperationCodeTaken = errors.New("labour operation code already exists")
+var ErrLabourOperationNotApplicable = errors.New("labour operation does not apply to this car")
+var ErrInvalidRepairWarranty = errors.New("a warranty needs months and/or km (not negative) and fitted parts must be part lines of the repair")
+var ErrRepairNotCompleted = errors.New("repair is not completed")
+var ErrNoActiveWarranty = errors.New("the original repair is not a completed repair of this car under active warranty")
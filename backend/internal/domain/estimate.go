@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EstimateStatus is the customer sign-off lifecycle of a quote.
+type EstimateStatus string
+
+const (
+	EstimateStatusDraft             EstimateStatus = "draft"
+	EstimateStatusSent              EstimateStatus = "sent"
+	EstimateStatusApproved          EstimateStatus = "approved"
+	EstimateStatusPartiallyApproved EstimateStatus = "partially_approved"
+	EstimateStatusRejected          EstimateStatus = "rejected"
+)
+
+// EstimateLineKind separates labour from parts on a quote.
+type EstimateLineKind string
+
+const (
+	EstimateLineLabour EstimateLineKind = "labour"
+	EstimateLinePart   EstimateLineKind = "part"
+)
+
+// Estimate approval channels.
+const (
+	EstimateApprovedViaClient     = "client"      // logged-in client
+	EstimateApprovedViaSignedLink = "signed_link" // public link sent to the client
+)
+
+// Estimate is a quote on a visit that the customer approves, fully or line by line, before work starts.
+type Estimate struct {
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID    uuid.UUID      `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	Status          EstimateStatus `json:"status" gorm:"type:varchar(24);not null;default:'draft'"`
+	Notes           string         `json:"notes,omitempty" gorm:"type:text"`
+	CreatedByUserID uuid.UUID      `json:"created_by_user_id" gorm:"type:uuid;not null"`
+	SentAt          *time.Time     `json:"sent_at,omitempty"`
+	DecidedAt       *time.Time     `json:"decided_at,omitempty"`
+	DecidedByUserID *uuid.UUID     `json:"decided_by_user_id,omitempty" gorm:"type:uuid"` // nil when decided from a signed link
+	DecidedVia      string         `json:"decided_via,omitempty" gorm:"type:varchar(16)"`
+	DecisionIP      string         `json:"decision_ip,omitempty" gorm:"type:varchar(64)"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+
+	Lines []EstimateLine `json:"lines" gorm:"foreignKey:EstimateID"`
+}
+
+func (Estimate) TableName() string { return "estimates" }
+
+// EstimateLine is one labour or parts line. RepairID ties it to the repair it authorises.
+type EstimateLine struct {
+	ID          uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey"`
+	EstimateID  uuid.UUID        `json:"estimate_id" gorm:"type:uuid;not null;index"`
+	Position    int              `json:"position" gorm:"not null"`
+	Kind        EstimateLineKind `json:"kind" gorm:"type:varchar(16);not null"`
+	Description string           `json:"description" gorm:"type:text;not null"`
+	Quantity    float64          `json:"quantity" gorm:"type:decimal(10,2);not null"` // hours for labour, units for parts
+	UnitPrice   float64          `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+	RepairID    *uuid.UUID       `json:"repair_id,omitempty" gorm:"type:uuid;index"`
+	Approved    *bool            `json:"approved,omitempty"` // nil until the customer decides
+}
+
+func (EstimateLine) TableName() string { return "estimate_lines" }
+
+// Amount is Quantity × UnitPrice rounded to cents.
+func (l EstimateLine) Amount() float64 {
+	return math.Round(l.Quantity*l.UnitPrice*100) / 100
+}
+
+// Total sums every line.
+func (e *Estimate) Total() float64 {
+	var t float64
+	for _, l := range e.Lines {
+		t += l.Amount()
+	}
+	return math.Round(t*100) / 100
+}
+
+// ApprovedTotal sums the lines the customer approved.
+func (e *Estimate) ApprovedTotal() float64 {
+	var t float64
+	for _, l := range e.Lines {
+		if l.Approved != nil && *l.Approved {
+			t += l.Amount()
+		}
+	}
+	return math.Round(t*100) / 100
+}
+
+// Validate checks the lines staff entered.
+func (e *Estimate) Validate() error {
+	if len(e.Lines) == 0 {
+		return ErrInvalidEstimate
+	}
+	for _, l := range e.Lines {
+		if l.Kind != EstimateLineLabour && l.Kind != EstimateLinePart {
+			return ErrInvalidEstimate
+		}
+		if strings.TrimSpace(l.Description) == "" || l.Quantity <= 0 || l.UnitPrice < 0 {
+			return ErrInvalidEstimate
+		}
+	}
+	return nil
+}
+
+// ApplyDecision marks each line approved or rejected and sets the resulting status.
+// Line IDs that are not on the estimate are rejected with ErrInvalidEstimate.
+func (e *Estimate) ApplyDecision(approvedLineIDs []uuid.UUID) error {
+	approve := make(map[uuid.UUID]bool, len(approvedLineIDs))
+	for _, id := range approvedLineIDs {
+		approve[id] = true
+	}
+	matched := 0
+	for i := range e.Lines {
+		ok := approve[e.Lines[i].ID]
+		if ok {
+			matched++
+		}
+		e.Lines[i].Approved = &ok
+	}
+	if matched != len(approve) {
+		return ErrInvalidEstimate
+	}
+	switch {
+	case matched == 0:
+		e.Status = EstimateStatusRejected
+	case matched == len(e.Lines):
+		e.Status = EstimateStatusApproved
+	default:
+		e.Status = EstimateStatusPartiallyApproved
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func brakeEstimate() *Estimate {
+	return &Estimate{ID: uuid.New(), Status: EstimateStatusSent, Lines: []EstimateLine{
+		{ID: uuid.New(), Kind: EstimateLineLabour, Description: "Cambio pastillas", Quantity: 1.5, UnitPrice: 45},
+		{ID: uuid.New(), Kind: EstimateLinePart, Description: "Pastillas delanteras", Quantity: 1, UnitPrice: 62.4},
+		{ID: uuid.New(), Kind: EstimateLinePart, Description: "Líquido de frenos", Quantity: 2, UnitPrice: 9.99},
+	}}
+}
+
+func TestEstimate_ValidateAndTotal(t *testing.T) {
+	t.Parallel()
+	e := brakeEstimate()
+	require.NoError(t, e.Validate())
+	assert.InDelta(t, 67.5+62.4+19.98, e.Total(), 0.001)
+
+	zeroQty := brakeEstimate()
+	zeroQty.Lines[0].Quantity = 0
+	assert.ErrorIs(t, zeroQty.Validate(), ErrInvalidEstimate)
+
+	badKind := brakeEstimate()
+	badKind.Lines[1].Kind = "fee"
+	assert.ErrorIs(t, badKind.Validate(), ErrInvalidEstimate)
+
+	assert.ErrorIs(t, (&Estimate{}).Validate(), ErrInvalidEstimate)
+}
+
+func TestEstimate_ApplyDecision(t *testing.T) {
+	t.Parallel()
+	all := brakeEstimate()
+	require.NoError(t, all.ApplyDecision([]uuid.UUID{all.Lines[0].ID, all.Lines[1].ID, all.Lines[2].ID}))
+	assert.Equal(t, EstimateStatusApproved, all.Status)
+	assert.Equal(t, all.Total(), all.ApprovedTotal())
+
+	some := brakeEstimate()
+	require.NoError(t, some.ApplyDecision([]uuid.UUID{some.Lines[0].ID, some.Lines[1].ID}))
+	assert.Equal(t, EstimateStatusPartiallyApproved, some.Status)
+	require.NotNil(t, some.Lines[2].Approved)
+	assert.False(t, *some.Lines[2].Approved)
+	assert.InDelta(t, 67.5+62.4, some.ApprovedTotal(), 0.001)
+
+	none := brakeEstimate()
+	require.NoError(t, none.ApplyDecision(nil))
+	assert.Equal(t, EstimateStatusRejected, none.Status)
+	assert.Zero(t, none.ApprovedTotal())
+
+	unknown := brakeEstimate()
+	assert.ErrorIs(t, unknown.ApplyDecision([]uuid.UUID{uuid.New()}), ErrInvalidEstimate)
+}
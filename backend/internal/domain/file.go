@@ -3,11 +3,13 @@ package domain
 import "time"
 
 type File struct {
-	ID        string
-	Name      string
-	Size      int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          string
+	Name        string
+	ContentType string
+	Size        int64
+	Data        []byte // content; empty in listings
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 func NewFile(id, name string, size int64) *File {
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FindingSeverity is the traffic-light rating of a digital vehicle inspection (DVI) finding.
+type FindingSeverity string
+
+const (
+	FindingSeverityGreen FindingSeverity = "green" // checked, fine
+	FindingSeverityAmber FindingSeverity = "amber" // needs attention soon
+	FindingSeverityRed   FindingSeverity = "red"   // needs attention now (safety or imminent failure)
+)
+
+// InspectionFinding is a problem (or an all-clear) a technician records on a visit, beyond the
+// work the client booked.
+type InspectionFinding struct {
+	ID               uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID     uuid.UUID       `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	Severity         FindingSeverity `json:"severity" gorm:"type:varchar(8);not null"`
+	Description      string          `json:"description" gorm:"type:text;not null"`
+	PhotoURL         string          `json:"photo_url,omitempty" gorm:"type:text"` // stored photo URL or key
+	EstimatedCost    *float64        `json:"estimated_cost,omitempty" gorm:"type:decimal(10,2)"`
+	RepairID         *uuid.UUID      `json:"repair_id,omitempty" gorm:"type:uuid"` // proposed repair created from this finding
+	RecordedByUserID uuid.UUID       `json:"recorded_by_user_id" gorm:"type:uuid;not null"`
+	CreatedAt        time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt        time.Time       `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt        *time.Time      `json:"deleted_at,omitempty" gorm:"column:deleted_at;index"`
+}
+
+func (InspectionFinding) TableName() string { return "inspection_findings" }
+
+// ValidateFindingSeverity returns true for known severities.
+func ValidateFindingSeverity(s FindingSeverity) bool {
+	switch s {
+	case FindingSeverityGreen, FindingSeverityAmber, FindingSeverityRed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the fields a technician fills in.
+func (f *InspectionFinding) Validate() error {
+	if !ValidateFindingSeverity(f.Severity) || strings.TrimSpace(f.Description) == "" {
+		return ErrInvalidInspectionFinding
+	}
+	if f.EstimatedCost != nil && *f.EstimatedCost < 0 {
+		return ErrInvalidInspectionFinding
+	}
+	return nil
+}
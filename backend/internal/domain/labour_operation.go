@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LabourOperation is a catalog job with a flat-rate time, so the same job is quoted and billed the same way
+// whoever types it in.
+type LabourOperation struct {
+	ID            uuid.UUID                `json:"id" gorm:"type:uuid;primaryKey"`
+	Code          string                   `json:"code" gorm:"type:varchar(32);not null;uniqueIndex"`
+	Description   string                   `json:"description" gorm:"type:text;not null"`
+	FlatRateHours float64                  `json:"flat_rate_hours" gorm:"type:decimal(6,2);not null"`
+	Vehicles      []LabourOperationVehicle `json:"vehicles" gorm:"foreignKey:OperationID"` // empty: applies to every car
+	Parts         []LabourOperationPart    `json:"parts" gorm:"foreignKey:OperationID"`    // default parts kit
+	CreatedAt     time.Time                `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time                `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (LabourOperation) TableName() string { return "labour_operations" }
+
+// LabourOperationVehicle limits an operation to a make, or to one model of it.
+type LabourOperationVehicle struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	OperationID uuid.UUID `json:"operation_id" gorm:"type:uuid;not null;index"`
+	Make        string    `json:"make" gorm:"type:varchar(64);not null"`
+	Model       string    `json:"model,omitempty" gorm:"type:varchar(64)"` // empty: every model of the make
+}
+
+func (LabourOperationVehicle) TableName() string { return "labour_operation_vehicles" }
+
+// LabourOperationPart is one inventory item of an operation's parts kit, at its catalog price.
+type LabourOperationPart struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	OperationID uuid.UUID `json:"operation_id" gorm:"type:uuid;not null;index"`
+	PartItemID  uuid.UUID `json:"part_item_id" gorm:"type:uuid;not null"`
+	Description string    `json:"description" gorm:"type:text;not null"` // the part's name when the kit was saved
+	Quantity    float64   `json:"quantity" gorm:"type:decimal(10,2);not null"`
+	UnitPrice   float64   `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+}
+
+func (LabourOperationPart) TableName() string { return "labour_operation_parts" }
+
+// Validate trims and checks an operation before it is stored; codes are kept upper case.
+func (o *LabourOperation) Validate() error {
+	o.Code = strings.ToUpper(strings.TrimSpace(o.Code))
+	o.Description = strings.TrimSpace(o.Description)
+	if o.Code == "" || len(o.Code) > 32 || o.Description == "" || o.FlatRateHours <= 0 {
+		return ErrInvalidLabourOperation
+	}
+	for i := range o.Vehicles {
+		v := &o.Vehicles[i]
+		v.Make = strings.TrimSpace(v.Make)
+		v.Model = strings.TrimSpace(v.Model)
+		if v.Make == "" {
+			return ErrInvalidLabourOperation
+		}
+	}
+	for _, p := range o.Parts {
+		if p.PartItemID == uuid.Nil || p.Quantity <= 0 || p.UnitPrice < 0 {
+			return ErrInvalidLabourOperation
+		}
+	}
+	return nil
+}
+
+// AppliesTo reports whether the operation can be done on a car of that make and model (case-insensitive).
+func (o *LabourOperation) AppliesTo(make, model string) bool {
+	if len(o.Vehicles) == 0 {
+		return true
+	}
+	for _, v := range o.Vehicles {
+		if strings.EqualFold(v.Make, strings.TrimSpace(make)) && (v.Model == "" || strings.EqualFold(v.Model, strings.TrimSpace(model))) {
+			return true
+		}
+	}
+	return false
+}
+
+// LineDescription is how the operation reads on a repair or estimate line.
+func (o *LabourOperation) LineDescription() string {
+	return o.Code + " · " + o.Description
+}
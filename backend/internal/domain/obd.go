@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OBDTroubleCode is a diagnostic trouble code read during a scanner session. Status is stored,
+// pending, permanent, or cleared (read and then erased in the same session).
+type OBDTroubleCode struct {
+	Code        string `json:"code"`
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+}
+
+// OBDReading is a decoded mode 01/02 PID value.
+type OBDReading struct {
+	PID   string  `json:"pid"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
+// OBDFreezeFrame is the snapshot an ECU stored when a code was set.
+type OBDFreezeFrame struct {
+	Frame    int          `json:"frame"`
+	DTC      string       `json:"dtc,omitempty"`
+	Readings []OBDReading `json:"readings"`
+}
+
+// OBDSession is one uploaded scanner session (ELM327 log or CSV export) on a visit, kept with what
+// was parsed from it so cleared and pending codes stay part of the visit record.
+type OBDSession struct {
+	ID               uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID     uuid.UUID        `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	FileName         string           `json:"file_name" gorm:"type:varchar(255)"`
+	Format           string           `json:"format" gorm:"type:varchar(16);not null"` // elm327 | csv
+	MILOn            *bool            `json:"mil_on,omitempty"`                        // check-engine lamp, when the log read it
+	TroubleCodes     []OBDTroubleCode `json:"trouble_codes" gorm:"type:text;serializer:json"`
+	FreezeFrames     []OBDFreezeFrame `json:"freeze_frames" gorm:"type:text;serializer:json"`
+	Readings         []OBDReading     `json:"readings" gorm:"type:text;serializer:json"`
+	RawLog           string           `json:"-" gorm:"type:text;not null"` // original upload, for re-parsing or audit
+	UploadedByUserID uuid.UUID        `json:"uploaded_by_user_id" gorm:"type:uuid;not null"`
+	CreatedAt        time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (OBDSession) TableName() string { return "obd_sessions" }
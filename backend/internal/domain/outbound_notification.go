@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification channels accepted by the outbound queue.
+const (
+	NotificationChannelSMS      = "sms"
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelEmail    = "email"
+)
+
+// NotificationAttachment is a file sent with an email (e.g. an .ics invite).
+type NotificationAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+// OutboundNotificationStatus is the delivery state of a queued message.
+type OutboundNotificationStatus string
+
+const (
+	OutboundNotificationPending OutboundNotificationStatus = "pending"
+	OutboundNotificationSent    OutboundNotificationStatus = "sent"
+	OutboundNotificationFailed  OutboundNotificationStatus = "failed" // gave up after max attempts
+)
+
+// OutboundNotification is one queued SMS / WhatsApp / email (transactional outbox).
+// DedupeKey, when set, makes enqueueing idempotent (e.g. one reminder per appointment and offset).
+type OutboundNotification struct {
+	ID        uuid.UUID                  `json:"id" gorm:"type:uuid;primaryKey"`
+	Channel   string                     `json:"channel" gorm:"type:varchar(16);not null"`
+	Recipient string                     `json:"recipient" gorm:"type:varchar(255);not null"`
+	Subject   string                     `json:"subject,omitempty" gorm:"type:varchar(255)"`
+	Body      string                     `json:"body" gorm:"type:text;not null"`
+	DedupeKey string                     `json:"dedupe_key,omitempty" gorm:"type:varchar(255)"`
+	Status    OutboundNotificationStatus `json:"status" gorm:"type:varchar(16);not null;default:'pending';index"`
+	SendAfter time.Time                  `json:"send_after" gorm:"not null;index"`
+	Attempts  int                        `json:"attempts" gorm:"not null;default:0"`
+	LastError string                     `json:"last_error,omitempty" gorm:"type:text"`
+	// Attachments are email-only; stored as JSON alongside the message.
+	Attachments []NotificationAttachment `json:"attachments,omitempty" gorm:"type:text;serializer:json"`
+	SentAt      *time.Time               `json:"sent_at,omitempty"`
+	CreatedAt   time.Time                `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time                `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (OutboundNotification) TableName() string { return "outbound_notifications" }
+
+// ValidNotificationChannel reports whether ch is a supported channel.
+func ValidNotificationChannel(ch string) bool {
+	switch ch {
+	case NotificationChannelSMS, NotificationChannelWhatsApp, NotificationChannelEmail:
+		return true
+	default:
+		return false
+	}
+}
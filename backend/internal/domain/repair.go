@@ -22,16 +22,23 @@ type Repair struct {
 	ServiceJobID  *uuid.UUID   `json:"service_job_id,omitempty" gorm:"type:uuid;index"` // visit (nil = not linked to a service job)
 	Description  string        `json:"description" gorm:"not null"`
 	Status       RepairStatus  `json:"status" gorm:"not null;default:'pending'"`
-	Cost         float64       `json:"cost" gorm:"type:decimal(10,2);default:0"`
+	Cost         float64       `json:"cost" gorm:"type:decimal(10,2);default:0"` // sum of Lines once the repair has any
 	StartedAt    *time.Time    `json:"started_at,omitempty" gorm:"column:started_at"`
 	CompletedAt  *time.Time    `json:"completed_at,omitempty" gorm:"column:completed_at"`
 	CreatedAt    time.Time     `json:"created_at" gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt    time.Time     `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
 	DeletedAt    *time.Time    `json:"deleted_at,omitempty" gorm:"column:deleted_at;index"`
+	// Warranty rework of an earlier repair of the car: not billed to the customer, counted in rework figures.
+	ReworkOfRepairID *uuid.UUID `json:"rework_of_repair_id,omitempty" gorm:"type:uuid;index"`
 
 	// Relationships - these will be ignored by GORM for auto-migration
-	Car        Car  `json:"car,omitempty" gorm:"-"`
-	Technician User `json:"technician,omitempty" gorm:"-"`
+	Car        Car          `json:"car,omitempty" gorm:"-"`
+	Technician User         `json:"technician,omitempty" gorm:"-"`
+	Lines      []RepairLine `json:"lines,omitempty" gorm:"-"`
+	// Technicians working on the repair (lead first); empty for repairs from before shared work was recorded.
+	Technicians []RepairTechnician `json:"technicians,omitempty" gorm:"-"`
+	// Warranties given on the repair's work and fitted parts once completed.
+	Warranties []RepairWarranty `json:"warranties,omitempty" gorm:"-"`
 }
 
 // TableName specifies the table name for GORM
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RepairLineKind says whether a repair line bills time or material.
+type RepairLineKind string
+
+const (
+	RepairLineLabour RepairLineKind = "labour" // Quantity is hours
+	RepairLinePart   RepairLineKind = "part"   // Quantity is units of PartItemID, taken from stock
+)
+
+// RepairLine is one labour or parts line of a repair. A repair that has lines costs their sum.
+type RepairLine struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	RepairID    uuid.UUID      `json:"repair_id" gorm:"type:uuid;not null;index"`
+	Kind        RepairLineKind `json:"kind" gorm:"type:varchar(16);not null"`
+	Description string         `json:"description" gorm:"type:text;not null"`
+	Quantity    float64        `json:"quantity" gorm:"type:decimal(10,2);not null"`
+	UnitPrice   float64        `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+	EmployeeID  *uuid.UUID     `json:"employee_id,omitempty" gorm:"type:uuid"`        // labour: whose hourly rate priced the line
+	PartItemID  *uuid.UUID     `json:"part_item_id,omitempty" gorm:"type:uuid;index"` // part: inventory item consumed
+	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (RepairLine) TableName() string { return "repair_lines" }
+
+// Amount is Quantity × UnitPrice rounded to cents.
+func (l RepairLine) Amount() float64 {
+	return math.Round(l.Quantity*l.UnitPrice*100) / 100
+}
+
+// Validate checks a line before it is stored.
+func (l *RepairLine) Validate() error {
+	if strings.TrimSpace(l.Description) == "" || l.Quantity <= 0 || l.UnitPrice < 0 {
+		return ErrInvalidRepairLine
+	}
+	switch l.Kind {
+	case RepairLineLabour:
+		if l.PartItemID != nil {
+			return ErrInvalidRepairLine
+		}
+	case RepairLinePart:
+		if l.PartItemID == nil {
+			return ErrInvalidRepairLine
+		}
+	default:
+		return ErrInvalidRepairLine
+	}
+	return nil
+}
+
+// RepairLinesTotal sums the lines, rounded to cents.
+func RepairLinesTotal(lines []RepairLine) float64 {
+	var t float64
+	for _, l := range lines {
+		t += l.Amount()
+	}
+	return math.Round(t*100) / 100
+}
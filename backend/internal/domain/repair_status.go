@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RepairStatusEvent is one entry of a repair's status history.
+type RepairStatusEvent struct {
+	ID              uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey"`
+	RepairID        uuid.UUID    `json:"repair_id" gorm:"type:uuid;not null;index"`
+	FromStatus      RepairStatus `json:"from_status" gorm:"type:varchar(16);not null"`
+	ToStatus        RepairStatus `json:"to_status" gorm:"type:varchar(16);not null"`
+	Reason          string       `json:"reason,omitempty" gorm:"type:text"`
+	ChangedByUserID uuid.UUID    `json:"changed_by_user_id" gorm:"type:uuid;not null"`
+	ChangedAt       time.Time    `json:"changed_at" gorm:"not null"`
+}
+
+func (RepairStatusEvent) TableName() string { return "repair_status_events" }
+
+// repairTransitions lists the status changes allowed from each status. Leaving completed or cancelled
+// is a reopen (see RepairTransitionIsReopen).
+var repairTransitions = map[RepairStatus][]RepairStatus{
+	RepairStatusPending:    {RepairStatusInProgress, RepairStatusCancelled},
+	RepairStatusInProgress: {RepairStatusCompleted, RepairStatusCancelled},
+	RepairStatusCompleted:  {RepairStatusInProgress},
+	RepairStatusCancelled:  {RepairStatusPending},
+}
+
+// CanTransitionRepair reports whether a repair may move from one status to another.
+func CanTransitionRepair(from, to RepairStatus) bool {
+	for _, s := range repairTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RepairTransitionIsReopen is true when the repair leaves a final status; only managers may, with a reason.
+func RepairTransitionIsReopen(from RepairStatus) bool {
+	return from == RepairStatusCompleted || from == RepairStatusCancelled
+}
+
+// Apply sets the repair's status and timestamps as the event describes: StartedAt is the first start,
+// CompletedAt follows the completed status.
+func (r *Repair) Apply(ev *RepairStatusEvent) {
+	r.Status = ev.ToStatus
+	r.UpdatedAt = ev.ChangedAt
+	switch {
+	case ev.ToStatus == RepairStatusInProgress && r.StartedAt == nil:
+		at := ev.ChangedAt
+		r.StartedAt = &at
+	case ev.ToStatus == RepairStatusCompleted:
+		at := ev.ChangedAt
+		r.CompletedAt = &at
+	}
+	if ev.FromStatus == RepairStatusCompleted {
+		r.CompletedAt = nil
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RepairTechnicianRole is what a technician does on a shared repair.
+type RepairTechnicianRole string
+
+const (
+	RepairTechnicianLead      RepairTechnicianRole = "lead"      // answers for the repair; mirrored in Repair.TechnicianID
+	RepairTechnicianAssistant RepairTechnicianRole = "assistant" // apprentice, or a specialist brought in for part of the job
+)
+
+// RepairTechnician assigns a staff user to a repair with the hours they put in.
+type RepairTechnician struct {
+	RepairID   uuid.UUID            `json:"repair_id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID            `json:"user_id" gorm:"type:uuid;primaryKey;index"`
+	Role       RepairTechnicianRole `json:"role" gorm:"type:varchar(16);not null"`
+	Hours      float64              `json:"hours" gorm:"type:decimal(6,2);not null;default:0"`
+	AssignedAt time.Time            `json:"assigned_at" gorm:"not null"`
+}
+
+func (RepairTechnician) TableName() string { return "repair_technicians" }
+
+// ValidateRepairTechnicians checks a repair's technician set: exactly one lead, each user once, hours not negative.
+func ValidateRepairTechnicians(ts []RepairTechnician) error {
+	leads := 0
+	seen := make(map[uuid.UUID]bool, len(ts))
+	for _, t := range ts {
+		if t.UserID == uuid.Nil || seen[t.UserID] || t.Hours < 0 {
+			return ErrInvalidRepairTechnicians
+		}
+		seen[t.UserID] = true
+		switch t.Role {
+		case RepairTechnicianLead:
+			leads++
+		case RepairTechnicianAssistant:
+		default:
+			return ErrInvalidRepairTechnicians
+		}
+	}
+	if leads != 1 {
+		return ErrInvalidRepairTechnicians
+	}
+	return nil
+}
+
+// LeadTechnician returns the user leading the repair, or uuid.Nil.
+func LeadTechnician(ts []RepairTechnician) uuid.UUID {
+	for _, t := range ts {
+		if t.Role == RepairTechnicianLead {
+			return t.UserID
+		}
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RepairWarranty is the warranty given on a completed repair's work (RepairLineID nil) or on one fitted part.
+// It runs from the repair's completion for Months and/or KM driven and ends at whichever limit comes first;
+// a zero limit does not apply.
+type RepairWarranty struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	RepairID        uuid.UUID  `json:"repair_id" gorm:"type:uuid;not null;index"`
+	RepairLineID    *uuid.UUID `json:"repair_line_id,omitempty" gorm:"type:uuid"` // part line; nil: the repair's work
+	Months          int        `json:"months" gorm:"not null;default:0"`
+	KM              int        `json:"km" gorm:"not null;default:0"`
+	StartsAt        time.Time  `json:"starts_at" gorm:"not null"`         // the repair's completion
+	StartOdometerKM int        `json:"start_odometer_km" gorm:"not null"` // odometer when the car left
+	CreatedAt       time.Time  `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+func (RepairWarranty) TableName() string { return "repair_warranties" }
+
+// Validate checks the terms before they are stored.
+func (w *RepairWarranty) Validate() error {
+	if w.Months < 0 || w.KM < 0 || (w.Months == 0 && w.KM == 0) || w.StartOdometerKM < 0 || w.StartsAt.IsZero() {
+		return ErrInvalidRepairWarranty
+	}
+	return nil
+}
+
+// ExpiresAt is the last moment covered by time, or nil without a time limit.
+func (w RepairWarranty) ExpiresAt() *time.Time {
+	if w.Months == 0 {
+		return nil
+	}
+	t := w.StartsAt.AddDate(0, w.Months, 0)
+	return &t
+}
+
+// ExpiresAtKM is the odometer reading that ends the warranty, or nil without a distance limit.
+func (w RepairWarranty) ExpiresAtKM() *int {
+	if w.KM == 0 {
+		return nil
+	}
+	km := w.StartOdometerKM + w.KM
+	return &km
+}
+
+// ActiveAt reports whether the warranty still covers the car at that moment and odometer reading.
+func (w RepairWarranty) ActiveAt(at time.Time, odometerKM int) bool {
+	if t := w.ExpiresAt(); t != nil && at.After(*t) {
+		return false
+	}
+	if km := w.ExpiresAtKM(); km != nil && odometerKM > *km {
+		return false
+	}
+	return !at.Before(w.StartsAt)
+}
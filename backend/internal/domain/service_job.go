@@ -24,6 +24,7 @@ type ServiceJob struct {
 	OpenedByUserID uuid.UUID        `json:"opened_by_user_id" gorm:"type:uuid;not null;index"`
 	OpenedAt       time.Time        `json:"opened_at" gorm:"not null"`
 	ClosedAt       *time.Time       `json:"closed_at,omitempty"`
+	PromisedAt     *time.Time       `json:"promised_at,omitempty"` // completion time promised to the client, if any
 	AppointmentID  *uuid.UUID       `json:"appointment_id,omitempty" gorm:"type:uuid;index"`
 	CreatedAt      time.Time        `json:"created_at" gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt      time.Time        `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
@@ -53,19 +54,28 @@ type ServiceJobReception struct {
 	RecordedByUserID uuid.UUID `json:"recorded_by_user_id" gorm:"type:uuid;not null"`
 	RecordedAt       time.Time `json:"recorded_at" gorm:"not null"`
 	SchemaVersion    int       `json:"schema_version" gorm:"not null;default:1"`
+	// Template-based checklist (SchemaVersion 2): the exact template version and its answers.
+	ChecklistTemplateID *uuid.UUID        `json:"checklist_template_id,omitempty" gorm:"type:uuid"`
+	ChecklistAnswers    []ChecklistAnswer `json:"checklist_answers,omitempty" gorm:"type:text;serializer:json"`
+	// Client's signature accepting the car's condition at drop-off; nil when not captured.
+	Signature *CustomerSignature `json:"signature,omitempty" gorm:"embedded;embeddedPrefix:signature_"`
 }
 
 func (ServiceJobReception) TableName() string { return "service_job_receptions" }
 
 // ServiceJobHandover is 1:1 delivery checklist (close visit).
 type ServiceJobHandover struct {
-	ServiceJobID     uuid.UUID `json:"service_job_id" gorm:"type:uuid;primaryKey"`
-	OdometerKM       int       `json:"odometer_km" gorm:"not null"`
-	TiresNote        string    `json:"tires_note,omitempty" gorm:"type:text"`
-	GeneralNotes     string    `json:"general_notes,omitempty" gorm:"type:text"`
-	RecordedByUserID uuid.UUID `json:"recorded_by_user_id" gorm:"type:uuid;not null"`
-	RecordedAt       time.Time `json:"recorded_at" gorm:"not null"`
-	SchemaVersion    int       `json:"schema_version" gorm:"not null;default:1"`
+	ServiceJobID        uuid.UUID         `json:"service_job_id" gorm:"type:uuid;primaryKey"`
+	OdometerKM          int               `json:"odometer_km" gorm:"not null"`
+	TiresNote           string            `json:"tires_note,omitempty" gorm:"type:text"`
+	GeneralNotes        string            `json:"general_notes,omitempty" gorm:"type:text"`
+	RecordedByUserID    uuid.UUID         `json:"recorded_by_user_id" gorm:"type:uuid;not null"`
+	RecordedAt          time.Time         `json:"recorded_at" gorm:"not null"`
+	SchemaVersion       int               `json:"schema_version" gorm:"not null;default:1"`
+	ChecklistTemplateID *uuid.UUID        `json:"checklist_template_id,omitempty" gorm:"type:uuid"`
+	ChecklistAnswers    []ChecklistAnswer `json:"checklist_answers,omitempty" gorm:"type:text;serializer:json"`
+	// Client's signature accepting the car at pickup; nil when not captured.
+	Signature *CustomerSignature `json:"signature,omitempty" gorm:"embedded;embeddedPrefix:signature_"`
 }
 
 func (ServiceJobHandover) TableName() string { return "service_job_handovers" }
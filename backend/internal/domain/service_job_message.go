@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageSide says which side of the counter wrote a visit message.
+type MessageSide string
+
+const (
+	MessageSideStaff  MessageSide = "staff"
+	MessageSideClient MessageSide = "client"
+)
+
+// ServiceJobMessage is one message of the thread between the workshop and the client about a visit.
+// ReadAt is set when someone on the other side first opens the thread after it was sent.
+type ServiceJobMessage struct {
+	ID           uuid.UUID                     `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID uuid.UUID                     `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	SenderUserID uuid.UUID                     `json:"sender_user_id" gorm:"type:uuid;not null"`
+	SenderName   string                        `json:"sender_name" gorm:"type:varchar(200)"`
+	SenderSide   MessageSide                   `json:"sender_side" gorm:"type:varchar(8);not null"`
+	Body         string                        `json:"body" gorm:"type:text"`
+	ReadAt       *time.Time                    `json:"read_at,omitempty"`
+	ReadByUserID *uuid.UUID                    `json:"read_by_user_id,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time                     `json:"created_at" gorm:"column:created_at;index"`
+	Attachments  []ServiceJobMessageAttachment `json:"attachments" gorm:"foreignKey:MessageID"`
+}
+
+func (ServiceJobMessage) TableName() string { return "service_job_messages" }
+
+// ServiceJobMessageAttachment is a file sent with a message; the content lives in file storage under FileID.
+type ServiceJobMessageAttachment struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	MessageID   uuid.UUID `json:"message_id" gorm:"type:uuid;not null;index"`
+	FileID      string    `json:"-" gorm:"type:varchar(255);not null"`
+	FileName    string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+}
+
+func (ServiceJobMessageAttachment) TableName() string { return "service_job_message_attachments" }
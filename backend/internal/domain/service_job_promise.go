@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceJobPromiseRevision records one change of the completion time promised to the client.
+type ServiceJobPromiseRevision struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID    uuid.UUID  `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	PreviousAt      *time.Time `json:"previous_at,omitempty"` // nil for the first promise
+	PromisedAt      time.Time  `json:"promised_at" gorm:"not null"`
+	Reason          string     `json:"reason,omitempty" gorm:"type:text"`
+	ChangedByUserID uuid.UUID  `json:"changed_by_user_id" gorm:"type:uuid;not null"`
+	ChangedAt       time.Time  `json:"changed_at" gorm:"not null"`
+}
+
+func (ServiceJobPromiseRevision) TableName() string { return "service_job_promise_revisions" }
+
+// PromiseState flags how an active visit stands against its promised time.
+type PromiseState string
+
+const (
+	PromiseStateNone    PromiseState = ""         // no promise, or the visit is no longer in the shop
+	PromiseStateOnTrack PromiseState = "on_track" // more than the at-risk window left
+	PromiseStateAtRisk  PromiseState = "at_risk"  // promise falls within the at-risk window
+	PromiseStateOverdue PromiseState = "overdue"  // promise has passed
+)
+
+// PromiseState reports whether the visit is on track, at risk (promise due within window) or overdue at now.
+// Closed and cancelled visits, and visits without a promise, have no state.
+func (j *ServiceJob) PromiseState(now time.Time, window time.Duration) PromiseState {
+	if j.PromisedAt == nil || (j.Status != ServiceJobStatusOpen && j.Status != ServiceJobStatusInProgress) {
+		return PromiseStateNone
+	}
+	switch left := j.PromisedAt.Sub(now); {
+	case left <= 0:
+		return PromiseStateOverdue
+	case left <= window:
+		return PromiseStateAtRisk
+	default:
+		return PromiseStateOnTrack
+	}
+}
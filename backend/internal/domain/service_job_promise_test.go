@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceJob_PromiseState(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { v := now.Add(d); return &v }
+	cases := []struct {
+		name     string
+		status   ServiceJobStatus
+		promised *time.Time
+		want     PromiseState
+	}{
+		{"no promise", ServiceJobStatusInProgress, nil, PromiseStateNone},
+		{"plenty of time", ServiceJobStatusOpen, at(3 * time.Hour), PromiseStateOnTrack},
+		{"inside window", ServiceJobStatusInProgress, at(time.Hour), PromiseStateAtRisk},
+		{"passed", ServiceJobStatusInProgress, at(-time.Minute), PromiseStateOverdue},
+		{"due right now", ServiceJobStatusOpen, at(0), PromiseStateOverdue},
+		{"closed visits are never late", ServiceJobStatusClosed, at(-time.Hour), PromiseStateNone},
+		{"cancelled", ServiceJobStatusCancelled, at(-time.Hour), PromiseStateNone},
+	}
+	for _, tc := range cases {
+		j := &ServiceJob{Status: tc.status, PromisedAt: tc.promised}
+		assert.Equal(t, tc.want, j.PromiseState(now, 2*time.Hour), tc.name)
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Signature image formats accepted at reception and handover.
+const (
+	SignatureContentTypeSVG = "image/svg+xml"
+	SignatureContentTypePNG = "image/png"
+)
+
+// CustomerSignature is the client's signature on a reception or handover: the image lives in file
+// storage, the row keeps its SHA-256 so a later copy can be checked against what was signed.
+type CustomerSignature struct {
+	FileID      string    `json:"file_id" gorm:"type:text"`
+	ContentType string    `json:"content_type" gorm:"type:text"`
+	SHA256      string    `json:"sha256" gorm:"type:text"`
+	SignerName  string    `json:"signer_name" gorm:"type:text"`
+	SignedAt    time.Time `json:"signed_at"`
+}
+
+// Moments of a visit at which the client signs.
+const (
+	SignatureStageReception = "reception"
+	SignatureStageHandover  = "handover"
+)
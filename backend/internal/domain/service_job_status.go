@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceJobStatusEvent is one entry of a visit's status history.
+type ServiceJobStatusEvent struct {
+	ID              uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey"`
+	ServiceJobID    uuid.UUID        `json:"service_job_id" gorm:"type:uuid;not null;index"`
+	FromStatus      ServiceJobStatus `json:"from_status" gorm:"type:varchar(16);not null"`
+	ToStatus        ServiceJobStatus `json:"to_status" gorm:"type:varchar(16);not null"`
+	Reason          string           `json:"reason,omitempty" gorm:"type:text"`
+	ChangedByUserID uuid.UUID        `json:"changed_by_user_id" gorm:"type:uuid;not null"`
+	ChangedAt       time.Time        `json:"changed_at" gorm:"not null"`
+}
+
+func (ServiceJobStatusEvent) TableName() string { return "service_job_status_events" }
+
+// serviceJobTransitions lists the manual status changes allowed from each status. Closing normally
+// happens through the handover checklist; a manual close needs a recorded handover (see the service).
+var serviceJobTransitions = map[ServiceJobStatus][]ServiceJobStatus{
+	ServiceJobStatusOpen:       {ServiceJobStatusInProgress, ServiceJobStatusCancelled},
+	ServiceJobStatusInProgress: {ServiceJobStatusOpen, ServiceJobStatusClosed, ServiceJobStatusCancelled},
+	ServiceJobStatusClosed:     {ServiceJobStatusInProgress},
+	ServiceJobStatusCancelled:  {ServiceJobStatusOpen},
+}
+
+// CanTransitionServiceJob reports whether a visit may move from one status to another.
+func CanTransitionServiceJob(from, to ServiceJobStatus) bool {
+	for _, s := range serviceJobTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceJobTransitionNeedsReason is true for cancelling and for reopening a closed or cancelled visit.
+func ServiceJobTransitionNeedsReason(from, to ServiceJobStatus) bool {
+	return to == ServiceJobStatusCancelled || from == ServiceJobStatusClosed || from == ServiceJobStatusCancelled
+}
+
+// Apply sets the visit's status fields as the event describes (ClosedAt follows the closed status).
+func (j *ServiceJob) Apply(ev *ServiceJobStatusEvent) {
+	j.Status = ev.ToStatus
+	j.UpdatedAt = ev.ChangedAt
+	switch {
+	case ev.ToStatus == ServiceJobStatusClosed:
+		at := ev.ChangedAt
+		j.ClosedAt = &at
+	case ev.FromStatus == ServiceJobStatusClosed:
+		j.ClosedAt = nil
+	}
+}
+
+// ServiceJobChangeEvent is the live-feed event type carrying a ServiceJobChange.
+const ServiceJobChangeEvent = "service_job"
+
+// ServiceJobChange kinds.
+const (
+	ServiceJobChangeCreated   = "created"
+	ServiceJobChangeStatus    = "status"
+	ServiceJobChangeReception = "reception"
+	ServiceJobChangeRepairs   = "repairs"
+	ServiceJobChangePromise   = "promise"
+	ServiceJobChangeMessage   = "message" // a client wrote on the visit thread
+)
+
+// ServiceJobChange tells live views (the workshop board) that a visit changed; they reload what they show.
+type ServiceJobChange struct {
+	ServiceJobID uuid.UUID        `json:"service_job_id"`
+	Kind         string           `json:"kind"`
+	Status       ServiceJobStatus `json:"status,omitempty"`
+	At           time.Time        `json:"at"`
+}
@@ -117,6 +117,11 @@ func (u *User) IsEmployee() bool {
 	return u.Role == RoleEmployee || u.Role == RoleManager || u.Role == RoleAdmin
 }
 
+// IsManager returns true if user is manager or admin
+func (u *User) IsManager() bool {
+	return u.Role == RoleManager || u.Role == RoleAdmin
+}
+
 // CanManageUsers returns true if user can manage other users
 func (u *User) CanManageUsers() bool {
 	return u.Role == RoleAdmin || u.Role == RoleManager
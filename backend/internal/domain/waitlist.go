@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistStatus is the lifecycle of a waitlist entry.
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "waiting"
+	WaitlistStatusOffered   WaitlistStatus = "offered"   // a freed slot is held for this client until OfferExpiresAt
+	WaitlistStatusClaimed   WaitlistStatus = "claimed"   // the offer became AppointmentID
+	WaitlistStatusExpired   WaitlistStatus = "expired"   // the offer lapsed; the slot moved to the next client
+	WaitlistStatusCancelled WaitlistStatus = "cancelled" // the client left the list
+)
+
+// WaitlistEntry queues a client for a fully booked day (FIFO by CreatedAt).
+// Day is the start of the local calendar day.
+type WaitlistEntry struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
+	CustomerID     uuid.UUID      `json:"customer_id" gorm:"type:uuid;not null;index"`
+	CarID          uuid.UUID      `json:"car_id" gorm:"type:uuid;not null"`
+	ServiceType    string         `json:"service_type" gorm:"not null"`
+	Day            time.Time      `json:"day" gorm:"not null"`
+	Status         WaitlistStatus `json:"status" gorm:"type:varchar(16);not null;default:'waiting'"`
+	OfferedSlot    *time.Time     `json:"offered_slot,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+	AppointmentID  *uuid.UUID     `json:"appointment_id,omitempty" gorm:"type:uuid"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (WaitlistEntry) TableName() string { return "waitlist_entries" }
@@ -1,42 +1,68 @@
 package domain
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// WorkHour representa as horas trabalhadas de um funcionário
+// WorkHour representa as horas trabalhadas de um funcionário: um intervalo cronometrado por um técnico
+// numa visita (ServiceJob) ou numa reparação. EndTime nil = cronómetro em curso.
 type WorkHour struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-	EmployeeID  uuid.UUID `json:"employee_id" gorm:"type:uuid;not null"`
-	Date        time.Time `json:"date" gorm:"type:date;not null"`
-	StartTime   time.Time `json:"start_time" gorm:"type:time;not null"`
-	EndTime     time.Time `json:"end_time" gorm:"type:time"`
-	Hours       float64   `json:"hours" gorm:"type:decimal(5,2)"`
-	Description string    `json:"description" gorm:"type:text"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-
-	// Relacionamento
-	Employee *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	TechnicianID uuid.UUID  `json:"technician_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_work_hours_one_running,where:end_time IS NULL"`
+	EmployeeID   *uuid.UUID `json:"employee_id,omitempty" gorm:"type:uuid;index"` // ficha de funcionário do técnico, se existir
+	ServiceJobID *uuid.UUID `json:"service_job_id,omitempty" gorm:"type:uuid;index"`
+	RepairID     *uuid.UUID `json:"repair_id,omitempty" gorm:"type:uuid;index"`
+	Date         time.Time  `json:"date" gorm:"type:date;not null"`
+	StartTime    time.Time  `json:"start_time" gorm:"not null"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Hours        float64    `json:"hours" gorm:"type:decimal(6,2)"`
+	Description  string     `json:"description" gorm:"type:text"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relacionamento (ignorado pelo GORM na migração, como em Repair)
+	Employee *Employee `json:"employee,omitempty" gorm:"-"`
 }
 
+func (WorkHour) TableName() string { return "work_hours" }
+
 // NewWorkHour cria um novo registro de horas trabalhadas
-func NewWorkHour(employeeID uuid.UUID, date, startTime time.Time) *WorkHour {
+func NewWorkHour(technicianID uuid.UUID, date, startTime time.Time) *WorkHour {
 	return &WorkHour{
-		ID:         uuid.New(),
-		EmployeeID: employeeID,
-		Date:       date,
-		StartTime:  startTime,
+		ID:           uuid.New(),
+		TechnicianID: technicianID,
+		Date:         date,
+		StartTime:    startTime,
 	}
 }
 
+// Running indica se o cronómetro ainda não foi parado.
+func (w *WorkHour) Running() bool {
+	return w.EndTime == nil
+}
+
 // CalculateHours calcula as horas trabalhadas
 func (w *WorkHour) CalculateHours() float64 {
-	if w.EndTime.IsZero() {
+	if w.EndTime == nil {
 		return 0
 	}
-	duration := w.EndTime.Sub(w.StartTime)
-	return duration.Hours()
+	return roundHours(w.EndTime.Sub(w.StartTime).Hours())
+}
+
+// HoursAt é CalculateHours, contando um cronómetro em curso até now.
+func (w *WorkHour) HoursAt(now time.Time) float64 {
+	if w.EndTime == nil {
+		if now.Before(w.StartTime) {
+			return 0
+		}
+		return roundHours(now.Sub(w.StartTime).Hours())
+	}
+	return w.CalculateHours()
+}
+
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
 }
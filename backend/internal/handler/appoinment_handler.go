@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -78,6 +79,7 @@ type AppointmentResponse struct {
 	Time       string  `json:"time"`
 	Status     string  `json:"status"`
 	Notes      string  `json:"notes"`
+	EmployeeID *string `json:"employeeID,omitempty"`
 	CreatedAt  string  `json:"createdAt"`
 	UpdatedAt  string  `json:"updatedAt"`
 	DeletedAt  *string `json:"deletedAt,omitempty"`
@@ -149,6 +151,14 @@ func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
 		Notes:       req.Notes,
 		ServiceType: req.ServiceType,
 	}
+	if eid := strings.TrimSpace(req.EmployeeID); eid != "" {
+		id, perr := uuid.Parse(eid)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeID"})
+			return
+		}
+		appointment.EmployeeID = &id
+	}
 
 	createdAppointment, err := h.appointmentService.CreateAppointment(c.Request.Context(), appointment, userID)
 	if err != nil {
@@ -161,13 +171,17 @@ func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
 			return
 		}
 		if err == domain.ErrAppointmentDailyCapReached {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Ya hay 8 turnos agendados ese día; elegí otra fecha u horario."})
+			// waitlist: the client may join the day's waitlist (POST /appointments/waitlist).
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Ya hay 8 turnos agendados ese día; elegí otra fecha u horario o anotate en la lista de espera.", "waitlist": true})
 			return
 		}
 		if err == domain.ErrAppointmentAlreadyExists {
 			c.JSON(http.StatusConflict, gin.H{"error": "appointment with this ID already exists"})
 			return
 		}
+		if writeAssigneeError(c, err) {
+			return
+		}
 		if err == domain.ErrInvalidAppointmentData {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment data"})
 			return
@@ -246,6 +260,7 @@ func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
 // @Produce     json
 // @Param       customerId query string false "Filtro UUID cliente (staff)"
 // @Param       carId query string false "Filtro UUID coche"
+// @Param       employeeId query string false "Filtro UUID técnico asignado"
 // @Param       status query string false "Estado (scheduled, confirmed, ...)"
 // @Param       limit query int false "Límite (default 10)"
 // @Param       offset query int false "Offset"
@@ -285,6 +300,14 @@ func (h *AppointmentHandler) ListAppointments(c *gin.Context) {
 		}
 		filters.CarID = &id
 	}
+	if eid := c.Query("employeeId"); eid != "" {
+		id, perr := uuid.Parse(eid)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		filters.EmployeeID = &id
+	}
 	if st := c.Query("status"); st != "" {
 		filters.Status = &st
 	}
@@ -393,6 +416,14 @@ func (h *AppointmentHandler) UpdateAppointment(c *gin.Context) {
 	if req.ServiceType != "" {
 		patch.ServiceType = req.ServiceType
 	}
+	if eid := strings.TrimSpace(req.EmployeeID); eid != "" {
+		id, perr := uuid.Parse(eid)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeID"})
+			return
+		}
+		patch.EmployeeID = &id
+	}
 
 	appointment, err := h.appointmentService.UpdateAppointment(c.Request.Context(), patch, userID)
 	if err != nil {
@@ -412,6 +443,12 @@ func (h *AppointmentHandler) UpdateAppointment(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Ya hay 8 turnos agendados ese día; elegí otra fecha u horario."})
 			return
 		}
+		if writeAssigneeError(c, err) {
+			return
+		}
+		if writeAppointmentPolicyError(c, err) {
+			return
+		}
 		if err == domain.ErrInvalidAppointmentData {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment data"})
 			return
@@ -473,6 +510,9 @@ func (h *AppointmentHandler) DeleteAppointment(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
+		if writeAppointmentPolicyError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
@@ -480,6 +520,227 @@ func (h *AppointmentHandler) DeleteAppointment(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+type assignTechnicianRequest struct {
+	EmployeeID *string `json:"employeeID"` // null / omitted clears the assignment
+}
+
+// AssignTechnician asigna (o quita) el técnico de una cita.
+// @Summary     Asignar técnico a una cita
+// @Tags        appointments
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID de la cita"
+// @Param       body body assignTechnicianRequest true "employeeID (UUID de usuario del taller) o null"
+// @Success     200 {object} AppointmentResponse
+// @Failure     400 {object} SwaggerMessage
+// @Failure     403 {object} SwaggerMessage
+// @Failure     404 {object} SwaggerMessage
+// @Failure     409 {object} SwaggerMessage
+// @Router      /api/v1/appointments/{id}/assignee [put]
+func (h *AppointmentHandler) AssignTechnician(c *gin.Context) {
+	userID, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	appointmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return
+	}
+	var req assignTechnicianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	var employeeID *uuid.UUID
+	if req.EmployeeID != nil && strings.TrimSpace(*req.EmployeeID) != "" {
+		id, perr := uuid.Parse(strings.TrimSpace(*req.EmployeeID))
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeID"})
+			return
+		}
+		employeeID = &id
+	}
+
+	appointment, err := h.appointmentService.AssignTechnician(c.Request.Context(), appointmentID, employeeID, userID)
+	if err != nil {
+		if err == domain.ErrAppointmentNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if writeAssigneeError(c, err) {
+			return
+		}
+		if err == domain.ErrInvalidAppointmentData {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment data"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, h.toAppointmentResponse(appointment))
+}
+
+// ListMyAppointments lista las citas asignadas al técnico autenticado para un día.
+// @Summary     Mis citas del día
+// @Tags        appointments
+// @Security    BearerAuth
+// @Produce     json
+// @Param       day query string false "Día local (YYYY-MM-DD); por defecto hoy"
+// @Success     200 {array} AppointmentResponse
+// @Failure     400 {object} SwaggerMessage
+// @Failure     403 {object} SwaggerMessage
+// @Router      /api/v1/appointments/mine [get]
+func (h *AppointmentHandler) ListMyAppointments(c *gin.Context) {
+	userID, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	day := time.Now()
+	if q := strings.TrimSpace(c.Query("day")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid day"})
+			return
+		}
+		day = d
+	}
+	appointments, err := h.appointmentService.ListAssignedOn(c.Request.Context(), day, userID)
+	if err != nil {
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if err == domain.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	responses := make([]AppointmentResponse, 0, len(appointments))
+	for _, appointment := range appointments {
+		responses = append(responses, h.toAppointmentResponse(appointment))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// writeAssigneeError maps technician-assignment errors; returns false when err is not one of them.
+func writeAssigneeError(c *gin.Context, err error) bool {
+	switch err {
+	case domain.ErrAssigneeNotActiveEmployee:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El técnico asignado no es un empleado activo."})
+	case domain.ErrAssigneeDoubleBooked:
+		c.JSON(http.StatusConflict, gin.H{"error": "El técnico ya tiene otra cita en ese horario."})
+	case domain.ErrAssigneeOnLeave:
+		c.JSON(http.StatusConflict, gin.H{"error": "El técnico está de licencia en ese horario."})
+	default:
+		return false
+	}
+	return true
+}
+
+// appointmentPolicyMessages explains change-policy violations (domain.Error codes) to clients.
+var appointmentPolicyMessages = map[string]string{
+	"appointment_cancel_too_late":         "Falta muy poco para el turno para cancelarlo online; comunicate con el taller.",
+	"appointment_reschedule_too_late":     "Falta muy poco para el turno para cambiarlo online; comunicate con el taller.",
+	"appointment_not_changeable":          "Este turno ya no se puede modificar.",
+	"appointment_status_change_forbidden": "Solo podés cancelar el turno; el resto de los estados los cambia el taller.",
+	"appointment_no_show_not_applicable":  "Solo un turno pasado, agendado o confirmado, puede marcarse como ausente.",
+}
+
+// writeAppointmentPolicyError maps change-policy errors to 409 with a stable "code"; returns false otherwise.
+func writeAppointmentPolicyError(c *gin.Context, err error) bool {
+	var de *domain.Error
+	if !errors.As(err, &de) {
+		return false
+	}
+	msg, ok := appointmentPolicyMessages[de.Code]
+	if !ok {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": msg, "code": de.Code})
+	return true
+}
+
+// MarkNoShow marca una cita pasada como ausente (el cliente no vino).
+// @Summary     Marcar cita como ausente
+// @Tags        appointments
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "UUID de la cita"
+// @Success     200 {object} AppointmentResponse
+// @Failure     400 {object} SwaggerMessage
+// @Failure     403 {object} SwaggerMessage
+// @Failure     404 {object} SwaggerMessage
+// @Failure     409 {object} SwaggerMessage
+// @Router      /api/v1/appointments/{id}/no-show [put]
+func (h *AppointmentHandler) MarkNoShow(c *gin.Context) {
+	userID, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	appointmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return
+	}
+	appointment, err := h.appointmentService.MarkNoShow(c.Request.Context(), appointmentID, userID)
+	if err != nil {
+		if err == domain.ErrAppointmentNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if writeAppointmentPolicyError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, h.toAppointmentResponse(appointment))
+}
+
+// GetCustomerNoShows devuelve las ausencias de un cliente.
+// @Summary     Ausencias de un cliente
+// @Tags        appointments
+// @Security    BearerAuth
+// @Produce     json
+// @Param       customerId query string true "UUID del cliente"
+// @Success     200 {object} ports.NoShowSummary
+// @Failure     400 {object} SwaggerMessage
+// @Failure     403 {object} SwaggerMessage
+// @Router      /api/v1/appointments/no-shows [get]
+func (h *AppointmentHandler) GetCustomerNoShows(c *gin.Context) {
+	userID, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	customerID, err := uuid.Parse(strings.TrimSpace(c.Query("customerId")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customerId"})
+		return
+	}
+	summary, err := h.appointmentService.CustomerNoShows(c.Request.Context(), customerID, userID)
+	if err != nil {
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
 // Helper methods
 
 func (h *AppointmentHandler) toAppointmentResponse(appointment *domain.Appointment) AppointmentResponse {
@@ -498,6 +759,11 @@ func (h *AppointmentHandler) toAppointmentResponse(appointment *domain.Appointme
 		s := appointment.DeletedAt.Format("2006-01-02T15:04:05Z07:00")
 		deletedAt = &s
 	}
+	var employeeID *string
+	if appointment.EmployeeID != nil {
+		s := appointment.EmployeeID.String()
+		employeeID = &s
+	}
 
 	return AppointmentResponse{
 		ID:         appointment.ID.String(),
@@ -508,6 +774,7 @@ func (h *AppointmentHandler) toAppointmentResponse(appointment *domain.Appointme
 		Time:       time,
 		Status:     string(appointment.Status),
 		Notes:      appointment.Notes,
+		EmployeeID: employeeID,
 		CreatedAt:  appointment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:  appointment.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		DeletedAt:  deletedAt,
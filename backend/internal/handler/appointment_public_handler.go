@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/appointment"
+)
+
+// PublicAppointmentHandler serves the signed confirm/cancel links sent in reminders; no login required.
+type PublicAppointmentHandler struct {
+	svc *appointment.AppointmentService
+}
+
+func NewPublicAppointmentHandler(svc *appointment.AppointmentService) *PublicAppointmentHandler {
+	return &PublicAppointmentHandler{svc: svc}
+}
+
+type rsvpTokenJSON struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// rsvpAppointmentJSON is the minimal view shown on the public page (no customer or car data).
+type rsvpAppointmentJSON struct {
+	ID          uuid.UUID                `json:"id"`
+	ServiceType string                   `json:"serviceType"`
+	ScheduledAt time.Time                `json:"scheduledAt"`
+	Status      domain.AppointmentStatus `json:"status"`
+}
+
+func toRSVPAppointmentJSON(a *domain.Appointment) rsvpAppointmentJSON {
+	return rsvpAppointmentJSON{ID: a.ID, ServiceType: a.ServiceType, ScheduledAt: a.ScheduledAt, Status: a.Status}
+}
+
+// GetRSVP GET /api/v1/public/appointments/rsvp?token=
+// @Summary     Ver turno desde enlace firmado
+// @Tags        appointments
+// @Param       token query string true "Token del recordatorio"
+// @Success     200 {object} rsvpAppointmentJSON
+// @Failure     400,410
+// @Router      /api/v1/public/appointments/rsvp [get]
+func (h *PublicAppointmentHandler) GetRSVP(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token required"})
+		return
+	}
+	appt, err := h.svc.GetByRSVPToken(c.Request.Context(), token)
+	if err != nil {
+		writeRSVPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRSVPAppointmentJSON(appt))
+}
+
+// ConfirmRSVP POST /api/v1/public/appointments/rsvp/confirm
+// @Summary     Confirmar turno desde enlace firmado
+// @Tags        appointments
+// @Accept      json
+// @Param       body body rsvpTokenJSON true "token"
+// @Success     200 {object} rsvpAppointmentJSON
+// @Failure     400,409,410
+// @Router      /api/v1/public/appointments/rsvp/confirm [post]
+func (h *PublicAppointmentHandler) ConfirmRSVP(c *gin.Context) {
+	var req rsvpTokenJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	appt, err := h.svc.ConfirmByToken(c.Request.Context(), strings.TrimSpace(req.Token))
+	if err != nil {
+		writeRSVPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRSVPAppointmentJSON(appt))
+}
+
+// CancelRSVP POST /api/v1/public/appointments/rsvp/cancel
+// @Summary     Cancelar turno desde enlace firmado
+// @Tags        appointments
+// @Accept      json
+// @Param       body body rsvpTokenJSON true "token"
+// @Success     200 {object} rsvpAppointmentJSON
+// @Failure     400,409,410
+// @Router      /api/v1/public/appointments/rsvp/cancel [post]
+func (h *PublicAppointmentHandler) CancelRSVP(c *gin.Context) {
+	var req rsvpTokenJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	appt, err := h.svc.CancelByToken(c.Request.Context(), strings.TrimSpace(req.Token))
+	if err != nil {
+		writeRSVPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toRSVPAppointmentJSON(appt))
+}
+
+// waitlistOfferJSON is the public view of a held waitlist slot.
+type waitlistOfferJSON struct {
+	ServiceType    string                `json:"serviceType"`
+	OfferedSlot    *time.Time            `json:"offeredSlot,omitempty"`
+	OfferExpiresAt *time.Time            `json:"offerExpiresAt,omitempty"`
+	Status         domain.WaitlistStatus `json:"status"`
+}
+
+// GetWaitlistOffer GET /api/v1/public/appointments/waitlist/claim?token=
+// @Summary     Ver turno ofrecido desde la lista de espera
+// @Tags        appointments
+// @Param       token query string true "Token de la oferta"
+// @Success     200 {object} waitlistOfferJSON
+// @Failure     400,410
+// @Router      /api/v1/public/appointments/waitlist/claim [get]
+func (h *PublicAppointmentHandler) GetWaitlistOffer(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token required"})
+		return
+	}
+	e, err := h.svc.GetWaitlistOffer(c.Request.Context(), token)
+	if err != nil {
+		writeRSVPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, waitlistOfferJSON{ServiceType: e.ServiceType, OfferedSlot: e.OfferedSlot, OfferExpiresAt: e.OfferExpiresAt, Status: e.Status})
+}
+
+// ClaimWaitlistOffer POST /api/v1/public/appointments/waitlist/claim
+// @Summary     Tomar el turno ofrecido desde la lista de espera
+// @Tags        appointments
+// @Accept      json
+// @Param       body body rsvpTokenJSON true "token"
+// @Success     201 {object} rsvpAppointmentJSON
+// @Failure     400,410
+// @Router      /api/v1/public/appointments/waitlist/claim [post]
+func (h *PublicAppointmentHandler) ClaimWaitlistOffer(c *gin.Context) {
+	var req rsvpTokenJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	appt, err := h.svc.ClaimWaitlistOffer(c.Request.Context(), strings.TrimSpace(req.Token))
+	if err != nil {
+		writeRSVPError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toRSVPAppointmentJSON(appt))
+}
+
+func writeRSVPError(c *gin.Context, err error) {
+	if writeAppointmentPolicyError(c, err) {
+		return
+	}
+	switch err {
+	case domain.ErrAppointmentLinkInvalid:
+		c.JSON(http.StatusGone, gin.H{"error": "el enlace no es válido o ya venció"})
+	case domain.ErrWaitlistOfferUnavailable:
+		c.JSON(http.StatusGone, gin.H{"error": "la oferta ya no está disponible"})
+	case domain.ErrAppointmentNotRespondable:
+		c.JSON(http.StatusConflict, gin.H{"error": "el turno ya no se puede confirmar ni cancelar"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
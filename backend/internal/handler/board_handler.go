@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+// boardHeartbeat keeps idle board streams alive through proxies that drop silent connections.
+const boardHeartbeat = 25 * time.Second
+
+// GetBoard GET /api/v1/service-jobs/board
+// @Summary     Tablero del taller: visitas abiertas y en curso por estado
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Success     200 {object} servicejob.Board
+// @Failure     401,403,500
+// @Router      /api/v1/service-jobs/board [get]
+func (h *ServiceJobHandler) GetBoard(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.Board(c.Request.Context(), uid)
+	if err != nil {
+		writeBoardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// StreamBoard GET /api/v1/service-jobs/board/events
+// Server-sent events. The stream opens with a "board" event holding the full board, then sends a
+// "service_job" event (domain.ServiceJobChange) whenever a visit changes; clients reload on each one.
+// If the stream closes (e.g. the client fell behind) the client reconnects and gets a fresh board.
+// @Summary     Tablero del taller en vivo (server-sent events)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     text/event-stream
+// @Success     200 {object} domain.ServiceJobChange
+// @Failure     401,403,500,503
+// @Router      /api/v1/service-jobs/board/events [get]
+func (h *ServiceJobHandler) StreamBoard(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	// Subscribe before loading the board so no change falls between the snapshot and the feed.
+	events, cancel, err := h.svc.SubscribeBoard(ctx, uid)
+	if err != nil {
+		writeBoardError(c, err)
+		return
+	}
+	defer cancel()
+	board, err := h.svc.Board(ctx, uid)
+	if err != nil {
+		writeBoardError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("board", board)
+	heartbeat := time.NewTicker(boardHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case ev, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(ev.Type, ev.Data)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+func writeBoardError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, servicejob.ErrBoardUpdatesNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
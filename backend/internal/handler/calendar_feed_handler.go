@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/appointment"
+)
+
+// CalendarFeedHandler issues and serves token-authenticated iCalendar subscriptions.
+type CalendarFeedHandler struct {
+	svc *appointment.AppointmentService
+	// publicAPIURL prefixes feed URLs (e.g. https://api.example.com); empty uses the request host.
+	publicAPIURL string
+}
+
+func NewCalendarFeedHandler(svc *appointment.AppointmentService, publicAPIURL string) *CalendarFeedHandler {
+	return &CalendarFeedHandler{svc: svc, publicAPIURL: strings.TrimRight(publicAPIURL, "/")}
+}
+
+type calendarFeedJSON struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+// ListCalendarFeeds GET /api/v1/calendar-feeds
+// URLs the user can add to a calendar app (client: own appointments; staff: assigned; managers: whole workshop).
+// @Summary     Enlaces de calendario (iCal)
+// @Tags        appointments
+// @Security    BearerAuth
+// @Success     200 {array} calendarFeedJSON
+// @Failure     401,404,500,503
+// @Router      /api/v1/calendar-feeds [get]
+func (h *CalendarFeedHandler) ListCalendarFeeds(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	tokens, err := h.svc.CalendarFeedTokens(c.Request.Context(), uid)
+	if err != nil {
+		writeCalendarFeedError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.feedURLs(c, tokens))
+}
+
+// RotateCalendarFeeds POST /api/v1/calendar-feeds/rotate
+// Revokes every calendar URL the user has handed out and returns new ones.
+// @Summary     Renovar enlaces de calendario (revoca los anteriores)
+// @Tags        appointments
+// @Security    BearerAuth
+// @Success     200 {array} calendarFeedJSON
+// @Failure     401,404,500,503
+// @Router      /api/v1/calendar-feeds/rotate [post]
+func (h *CalendarFeedHandler) RotateCalendarFeeds(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	tokens, err := h.svc.RotateCalendarFeeds(c.Request.Context(), uid)
+	if err != nil {
+		writeCalendarFeedError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.feedURLs(c, tokens))
+}
+
+func (h *CalendarFeedHandler) feedURLs(c *gin.Context, tokens map[appointment.CalendarFeedKind]string) []calendarFeedJSON {
+	base := h.baseURL(c)
+	out := make([]calendarFeedJSON, 0, len(tokens))
+	for _, kind := range []appointment.CalendarFeedKind{appointment.CalendarFeedClient, appointment.CalendarFeedTechnician, appointment.CalendarFeedWorkshop} {
+		token, ok := tokens[kind]
+		if !ok {
+			continue
+		}
+		out = append(out, calendarFeedJSON{
+			Kind: string(kind),
+			URL:  base + "/api/v1/public/calendar/" + string(kind) + ".ics?token=" + url.QueryEscape(token),
+		})
+	}
+	return out
+}
+
+func writeCalendarFeedError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, appointment.ErrCalendarFeedsNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+// GetCalendarFeed GET /api/v1/public/calendar/:feed (client.ics | technician.ics | workshop.ics)
+// @Summary     Feed iCalendar de turnos
+// @Tags        appointments
+// @Produce     text/calendar
+// @Param       feed path string true "client.ics, technician.ics o workshop.ics"
+// @Param       token query string true "Token del enlace"
+// @Success     200 {string} string "text/calendar"
+// @Failure     404,500
+// @Router      /api/v1/public/calendar/{feed} [get]
+func (h *CalendarFeedHandler) GetCalendarFeed(c *gin.Context) {
+	kind := appointment.CalendarFeedKind(strings.TrimSuffix(c.Param("feed"), ".ics"))
+	body, err := h.svc.CalendarFeed(c.Request.Context(), kind, c.Query("token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarFeedInvalid) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "calendar feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="`+string(kind)+`.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+func (h *CalendarFeedHandler) baseURL(c *gin.Context) string {
+	if h.publicAPIURL != "" {
+		return h.publicAPIURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+// ChecklistTemplateHandler manages versioned reception / handover checklist templates.
+type ChecklistTemplateHandler struct {
+	svc *servicejob.Service
+}
+
+func NewChecklistTemplateHandler(svc *servicejob.Service) *ChecklistTemplateHandler {
+	return &ChecklistTemplateHandler{svc: svc}
+}
+
+type saveChecklistTemplateJSON struct {
+	Code  string                 `json:"code" binding:"required"` // e.g. "motorbike", "ev", "pre_itv"
+	Kind  string                 `json:"kind" binding:"required"` // reception | handover
+	Name  string                 `json:"name" binding:"required"`
+	Items []domain.ChecklistItem `json:"items" binding:"required"`
+}
+
+// SaveChecklistTemplate POST /api/v1/checklist-templates
+// Creates version 1 of a new code, or the next version of an existing one; earlier versions stay as they were.
+// @Summary     Crear plantilla de checklist (o nueva versión)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       body body saveChecklistTemplateJSON true "code, kind, name, items"
+// @Success     201 {object} domain.ChecklistTemplate
+// @Failure     400,401,403,409,500,503
+// @Router      /api/v1/checklist-templates [post]
+func (h *ChecklistTemplateHandler) SaveChecklistTemplate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var req saveChecklistTemplateJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.SaveChecklistTemplate(c.Request.Context(), servicejob.ChecklistTemplateInput{
+		Code:  req.Code,
+		Kind:  domain.ChecklistKind(strings.TrimSpace(req.Kind)),
+		Name:  req.Name,
+		Items: req.Items,
+	}, uid)
+	if err != nil {
+		writeChecklistTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListChecklistTemplates GET /api/v1/checklist-templates?kind=reception
+// Current version of every template.
+// @Summary     Listar plantillas de checklist
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       kind query string false "reception o handover"
+// @Success     200 {array} domain.ChecklistTemplate
+// @Failure     400,401,403,500
+// @Router      /api/v1/checklist-templates [get]
+func (h *ChecklistTemplateHandler) ListChecklistTemplates(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	list, err := h.svc.ListChecklistTemplates(c.Request.Context(), domain.ChecklistKind(strings.TrimSpace(c.Query("kind"))), uid)
+	if err != nil {
+		writeChecklistTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// ListChecklistTemplateVersions GET /api/v1/checklist-templates/versions?code=ev&kind=reception
+// @Summary     Historial de versiones de una plantilla
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       code query string true "Código de la plantilla"
+// @Param       kind query string true "reception o handover"
+// @Success     200 {array} domain.ChecklistTemplate
+// @Failure     400,401,403,500
+// @Router      /api/v1/checklist-templates/versions [get]
+func (h *ChecklistTemplateHandler) ListChecklistTemplateVersions(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	code := strings.TrimSpace(c.Query("code"))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+	list, err := h.svc.ListChecklistTemplateVersions(c.Request.Context(), code, domain.ChecklistKind(strings.TrimSpace(c.Query("kind"))), uid)
+	if err != nil {
+		writeChecklistTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetChecklistTemplate GET /api/v1/checklist-templates/:id
+// @Summary     Obtener una versión de plantilla
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID de la versión"
+// @Success     200 {object} domain.ChecklistTemplate
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/checklist-templates/{id} [get]
+func (h *ChecklistTemplateHandler) GetChecklistTemplate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.GetChecklistTemplate(c.Request.Context(), id, uid)
+	if err != nil {
+		writeChecklistTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeChecklistTemplateError(c *gin.Context, err error) {
+	switch {
+	case err == domain.ErrUnauthorizedAccess:
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case err == domain.ErrChecklistTemplateNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "checklist template not found"})
+	case err == domain.ErrChecklistVersionConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": "checklist template was changed meanwhile; reload and retry"})
+	case errors.Is(err, domain.ErrInvalidChecklistTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, servicejob.ErrChecklistTemplatesNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+// writeChecklistAnswersError handles template checklist errors from reception / handover saves.
+func writeChecklistAnswersError(c *gin.Context, err error) bool {
+	switch {
+	case err == domain.ErrChecklistTemplateNotFound:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "checklist template not found"})
+	case err == domain.ErrChecklistTemplateKindMismatch:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidChecklistAnswers):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
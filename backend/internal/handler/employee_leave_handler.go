@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/appointment"
+)
+
+// EmployeeLeaveHandler exposes staff leave windows used by appointment assignment.
+type EmployeeLeaveHandler struct {
+	svc *appointment.AppointmentService
+}
+
+func NewEmployeeLeaveHandler(svc *appointment.AppointmentService) *EmployeeLeaveHandler {
+	return &EmployeeLeaveHandler{svc: svc}
+}
+
+type createEmployeeLeaveJSON struct {
+	UserID   string    `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+// CreateEmployeeLeave POST /api/v1/employee-leaves
+// @Summary     Registrar licencia de un empleado
+// @Tags        employee-leaves
+// @Security    BearerAuth
+// @Accept      json
+// @Param       body body createEmployeeLeaveJSON true "user_id, starts_at, ends_at (RFC3339)"
+// @Success     201 {object} domain.EmployeeLeave
+// @Failure     400,401,403,500
+// @Router      /api/v1/employee-leaves [post]
+func (h *EmployeeLeaveHandler) CreateEmployeeLeave(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var req createEmployeeLeaveJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	staffID, err := uuid.Parse(strings.TrimSpace(req.UserID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+	out, err := h.svc.CreateLeave(c.Request.Context(), &domain.EmployeeLeave{
+		UserID:   staffID,
+		StartsAt: req.StartsAt.UTC(),
+		EndsAt:   req.EndsAt.UTC(),
+		Reason:   req.Reason,
+	}, uid)
+	if err != nil {
+		writeEmployeeLeaveError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListEmployeeLeaves GET /api/v1/employee-leaves?user_id=&from=YYYY-MM-DD&to=YYYY-MM-DD
+// Leaves intersecting [from, to) (local days; default: today plus 30 days).
+// @Summary     Listar licencias
+// @Tags        employee-leaves
+// @Security    BearerAuth
+// @Param       user_id query string false "UUID de usuario del taller"
+// @Param       from query string false "Día local inicial (YYYY-MM-DD)"
+// @Param       to query string false "Día local final, exclusivo (YYYY-MM-DD)"
+// @Success     200 {array} domain.EmployeeLeave
+// @Router      /api/v1/employee-leaves [get]
+func (h *EmployeeLeaveHandler) ListEmployeeLeaves(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var staffID *uuid.UUID
+	if q := strings.TrimSpace(c.Query("user_id")); q != "" {
+		id, err := uuid.Parse(q)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			return
+		}
+		staffID = &id
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 30)
+	if q := strings.TrimSpace(c.Query("from")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		from = d
+	}
+	if q := strings.TrimSpace(c.Query("to")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+		to = d
+	}
+	list, err := h.svc.ListLeaves(c.Request.Context(), staffID, from, to, uid)
+	if err != nil {
+		writeEmployeeLeaveError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteEmployeeLeave DELETE /api/v1/employee-leaves/:id
+// @Summary     Eliminar licencia
+// @Tags        employee-leaves
+// @Security    BearerAuth
+// @Param       id path string true "UUID licencia"
+// @Success     204 "Sin cuerpo"
+// @Router      /api/v1/employee-leaves/{id} [delete]
+func (h *EmployeeLeaveHandler) DeleteEmployeeLeave(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.svc.DeleteLeave(c.Request.Context(), id, uid); err != nil {
+		writeEmployeeLeaveError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeEmployeeLeaveError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrUnauthorizedAccess:
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case domain.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case domain.ErrEmployeeLeaveNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee leave not found"})
+	case domain.ErrInvalidEmployeeLeaveData:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employee leave data"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
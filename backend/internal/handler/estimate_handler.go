@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+// EstimateHandler serves visit estimates: drafting by staff, and approval by the client or a signed link.
+type EstimateHandler struct {
+	svc           *servicejob.Service
+	publicBaseURL string
+}
+
+func NewEstimateHandler(svc *servicejob.Service, publicBaseURL string) *EstimateHandler {
+	return &EstimateHandler{svc: svc, publicBaseURL: publicBaseURL}
+}
+
+type estimateLineJSON struct {
+	Kind         string     `json:"kind"` // labour | part; not needed with operation_id
+	Description  string     `json:"description"`
+	Quantity     float64    `json:"quantity"`
+	UnitPrice    float64    `json:"unit_price"` // per hour with operation_id
+	RepairID     *uuid.UUID `json:"repair_id"`
+	OperationID  *uuid.UUID `json:"operation_id"`  // labour catalog: flat-rate hours plus its parts kit
+	WithoutParts bool       `json:"without_parts"` // with operation_id: leave the parts kit out
+}
+
+type saveEstimateJSON struct {
+	Notes string             `json:"notes"`
+	Lines []estimateLineJSON `json:"lines" binding:"required"`
+}
+
+func (b saveEstimateJSON) toInput() servicejob.EstimateInput {
+	in := servicejob.EstimateInput{Notes: b.Notes, Lines: make([]servicejob.EstimateLineInput, 0, len(b.Lines))}
+	for _, l := range b.Lines {
+		in.Lines = append(in.Lines, servicejob.EstimateLineInput{
+			Kind:         domain.EstimateLineKind(strings.ToLower(strings.TrimSpace(l.Kind))),
+			Description:  l.Description,
+			Quantity:     l.Quantity,
+			UnitPrice:    l.UnitPrice,
+			RepairID:     l.RepairID,
+			OperationID:  l.OperationID,
+			WithoutParts: l.WithoutParts,
+		})
+	}
+	return in
+}
+
+type estimateDecisionJSON struct {
+	ApprovedLineIDs []uuid.UUID `json:"approved_line_ids"` // empty rejects the whole estimate
+}
+
+type publicEstimateDecisionJSON struct {
+	Token           string      `json:"token" binding:"required"`
+	ApprovedLineIDs []uuid.UUID `json:"approved_line_ids"`
+}
+
+// estimateResponse adds totals to the stored estimate.
+type estimateResponse struct {
+	domain.Estimate
+	Total         float64 `json:"total"`
+	ApprovedTotal float64 `json:"approved_total"`
+}
+
+type sentEstimateResponse struct {
+	Estimate    estimateResponse `json:"estimate"`
+	ApprovalURL string           `json:"approval_url"`
+}
+
+// publicEstimateJSON is what the signed approval page shows (no user IDs or IPs).
+type publicEstimateJSON struct {
+	ID            uuid.UUID             `json:"id"`
+	Status        domain.EstimateStatus `json:"status"`
+	Notes         string                `json:"notes,omitempty"`
+	Lines         []domain.EstimateLine `json:"lines"`
+	Total         float64               `json:"total"`
+	ApprovedTotal float64               `json:"approved_total"`
+	SentAt        *time.Time            `json:"sent_at,omitempty"`
+	DecidedAt     *time.Time            `json:"decided_at,omitempty"`
+}
+
+func toEstimateResponse(e *domain.Estimate) estimateResponse {
+	return estimateResponse{Estimate: *e, Total: e.Total(), ApprovedTotal: e.ApprovedTotal()}
+}
+
+func toPublicEstimateJSON(e *domain.Estimate) publicEstimateJSON {
+	return publicEstimateJSON{
+		ID: e.ID, Status: e.Status, Notes: e.Notes, Lines: e.Lines,
+		Total: e.Total(), ApprovedTotal: e.ApprovedTotal(), SentAt: e.SentAt, DecidedAt: e.DecidedAt,
+	}
+}
+
+// CreateEstimate POST /api/v1/service-jobs/:id/estimates
+// @Summary     Crear presupuesto (borrador) de la visita
+// @Tags        estimates
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body saveEstimateJSON true "notes, lines"
+// @Success     201 {object} estimateResponse
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/estimates [post]
+func (h *EstimateHandler) CreateEstimate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body saveEstimateJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.CreateEstimate(c.Request.Context(), jid, body.toInput(), uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, toEstimateResponse(out))
+}
+
+// ListEstimates GET /api/v1/service-jobs/:id/estimates
+// Clients see the estimates of their own visits once sent.
+// @Summary     Listar presupuestos de la visita
+// @Tags        estimates
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} estimateResponse
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/estimates [get]
+func (h *EstimateHandler) ListEstimates(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	list, err := h.svc.ListEstimates(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	out := make([]estimateResponse, 0, len(list))
+	for _, e := range list {
+		out = append(out, toEstimateResponse(e))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetEstimate GET /api/v1/estimates/:id
+// @Summary     Obtener presupuesto
+// @Tags        estimates
+// @Security    BearerAuth
+// @Param       id path string true "UUID presupuesto"
+// @Success     200 {object} estimateResponse
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/estimates/{id} [get]
+func (h *EstimateHandler) GetEstimate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.GetEstimate(c.Request.Context(), id, uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toEstimateResponse(out))
+}
+
+// UpdateEstimate PUT /api/v1/estimates/:id
+// Replaces notes and lines; drafts only.
+// @Summary     Editar presupuesto (borrador)
+// @Tags        estimates
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID presupuesto"
+// @Param       body body saveEstimateJSON true "notes, lines"
+// @Success     200 {object} estimateResponse
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/estimates/{id} [put]
+func (h *EstimateHandler) UpdateEstimate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body saveEstimateJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.UpdateEstimate(c.Request.Context(), id, body.toInput(), uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toEstimateResponse(out))
+}
+
+// SendEstimate POST /api/v1/estimates/:id/send
+// Freezes the draft and emails the client an approval link (also returned for sharing by other means).
+// @Summary     Enviar presupuesto al cliente
+// @Tags        estimates
+// @Security    BearerAuth
+// @Param       id path string true "UUID presupuesto"
+// @Success     200 {object} sentEstimateResponse
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/estimates/{id}/send [post]
+func (h *EstimateHandler) SendEstimate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, token, err := h.svc.SendEstimate(c.Request.Context(), id, uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sentEstimateResponse{
+		Estimate:    toEstimateResponse(out),
+		ApprovalURL: servicejob.EstimateApprovalLink(h.publicBaseURL, token),
+	})
+}
+
+// DecideEstimate POST /api/v1/estimates/:id/decision
+// The car's owner approves the listed lines and rejects the rest.
+// @Summary     Aprobar o rechazar presupuesto (cliente)
+// @Tags        estimates
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID presupuesto"
+// @Param       body body estimateDecisionJSON true "approved_line_ids"
+// @Success     200 {object} estimateResponse
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/estimates/{id}/decision [post]
+func (h *EstimateHandler) DecideEstimate(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body estimateDecisionJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.DecideEstimate(c.Request.Context(), id, servicejob.EstimateDecision{
+		ApprovedLineIDs: body.ApprovedLineIDs,
+		IP:              c.ClientIP(),
+	}, uid)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toEstimateResponse(out))
+}
+
+// GetPublicEstimate GET /api/v1/public/estimates?token=
+// @Summary     Ver presupuesto desde enlace firmado
+// @Tags        estimates
+// @Param       token query string true "Token del enlace"
+// @Success     200 {object} publicEstimateJSON
+// @Failure     400,410
+// @Router      /api/v1/public/estimates [get]
+func (h *EstimateHandler) GetPublicEstimate(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token required"})
+		return
+	}
+	out, err := h.svc.GetEstimateByToken(c.Request.Context(), token)
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toPublicEstimateJSON(out))
+}
+
+// DecidePublicEstimate POST /api/v1/public/estimates/decision
+// @Summary     Aprobar o rechazar presupuesto desde enlace firmado
+// @Tags        estimates
+// @Accept      json
+// @Param       body body publicEstimateDecisionJSON true "token, approved_line_ids"
+// @Success     200 {object} publicEstimateJSON
+// @Failure     400,409,410
+// @Router      /api/v1/public/estimates/decision [post]
+func (h *EstimateHandler) DecidePublicEstimate(c *gin.Context) {
+	var body publicEstimateDecisionJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.DecideEstimateByToken(c.Request.Context(), strings.TrimSpace(body.Token), servicejob.EstimateDecision{
+		ApprovedLineIDs: body.ApprovedLineIDs,
+		IP:              c.ClientIP(),
+	})
+	if err != nil {
+		writeEstimateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toPublicEstimateJSON(out))
+}
+
+func writeEstimateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrEstimateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "estimate not found"})
+	case errors.Is(err, domain.ErrLabourOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "labour operation not found"})
+	case errors.Is(err, domain.ErrEstimateLinkInvalid):
+		c.JSON(http.StatusGone, gin.H{"error": "el enlace no es válido o ya venció"})
+	case errors.Is(err, domain.ErrEstimateNotEditable), errors.Is(err, domain.ErrEstimateNotDecidable),
+		errors.Is(err, domain.ErrEstimateStatusConflict), errors.Is(err, domain.ErrLabourOperationNotApplicable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidEstimate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidServiceJobData):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visit is closed or cancelled"})
+	case errors.Is(err, servicejob.ErrEstimatesNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
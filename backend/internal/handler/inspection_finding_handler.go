@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+type findingJSON struct {
+	Severity      string   `json:"severity" binding:"required"` // green | amber | red
+	Description   string   `json:"description" binding:"required"`
+	PhotoURL      string   `json:"photo_url"`
+	EstimatedCost *float64 `json:"estimated_cost"`
+}
+
+func (b findingJSON) toInput() servicejob.FindingInput {
+	return servicejob.FindingInput{
+		Severity:      domain.FindingSeverity(strings.ToLower(strings.TrimSpace(b.Severity))),
+		Description:   b.Description,
+		PhotoURL:      b.PhotoURL,
+		EstimatedCost: b.EstimatedCost,
+	}
+}
+
+// AddFinding POST /api/v1/service-jobs/:id/findings
+// @Summary     Registrar hallazgo de inspección (verde / ámbar / rojo)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body findingJSON true "severity, description, photo_url, estimated_cost"
+// @Success     201 {object} domain.InspectionFinding
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/findings [post]
+func (h *ServiceJobHandler) AddFinding(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body findingJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.AddFinding(c.Request.Context(), jid, body.toInput(), uid)
+	if err != nil {
+		writeFindingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListFindings GET /api/v1/service-jobs/:id/findings
+// Red first, then amber, then green. Clients may read the findings of their own visits.
+// @Summary     Listar hallazgos de inspección de la visita
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} domain.InspectionFinding
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/findings [get]
+func (h *ServiceJobHandler) ListFindings(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	list, err := h.svc.ListFindings(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeFindingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// UpdateFinding PUT /api/v1/service-jobs/:id/findings/:findingId
+// @Summary     Editar hallazgo de inspección
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       findingId path string true "UUID hallazgo"
+// @Param       body body findingJSON true "severity, description, photo_url, estimated_cost"
+// @Success     200 {object} domain.InspectionFinding
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/service-jobs/{id}/findings/{findingId} [put]
+func (h *ServiceJobHandler) UpdateFinding(c *gin.Context) {
+	uid, jid, fid, ok := parseFindingPath(c)
+	if !ok {
+		return
+	}
+	var body findingJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.UpdateFinding(c.Request.Context(), jid, fid, body.toInput(), uid)
+	if err != nil {
+		writeFindingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteFinding DELETE /api/v1/service-jobs/:id/findings/:findingId
+// @Summary     Eliminar hallazgo de inspección
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Param       findingId path string true "UUID hallazgo"
+// @Success     204
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/service-jobs/{id}/findings/{findingId} [delete]
+func (h *ServiceJobHandler) DeleteFinding(c *gin.Context) {
+	uid, jid, fid, ok := parseFindingPath(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteFinding(c.Request.Context(), jid, fid, uid); err != nil {
+		writeFindingError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ProposeRepairFromFinding POST /api/v1/service-jobs/:id/findings/:findingId/repair
+// Creates a pending repair on the visit from the finding; a finding yields at most one repair.
+// @Summary     Proponer reparación a partir de un hallazgo
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Param       findingId path string true "UUID hallazgo"
+// @Success     201 {object} domain.Repair
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/service-jobs/{id}/findings/{findingId}/repair [post]
+func (h *ServiceJobHandler) ProposeRepairFromFinding(c *gin.Context) {
+	uid, jid, fid, ok := parseFindingPath(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.ProposeRepairFromFinding(c.Request.Context(), jid, fid, uid)
+	if err != nil {
+		writeFindingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func parseFindingPath(c *gin.Context) (uid, jobID, findingID uuid.UUID, ok bool) {
+	uid, ok = parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var err error
+	if jobID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return uid, jobID, findingID, false
+	}
+	if findingID, err = uuid.Parse(c.Param("findingId")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid findingId"})
+		return uid, jobID, findingID, false
+	}
+	return uid, jobID, findingID, true
+}
+
+func writeFindingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrInspectionFindingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "inspection finding not found"})
+	case errors.Is(err, domain.ErrFindingAlreadyConverted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidInspectionFinding):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidServiceJobData):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visit is closed or cancelled"})
+	case errors.Is(err, servicejob.ErrInspectionFindingsNotConfigured), errors.Is(err, servicejob.ErrRepairsNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/labour"
+)
+
+// LabourOperationHandler exposes the labour-operation catalog (staff read; managers edit and import).
+type LabourOperationHandler struct {
+	svc ports.LabourOperationService
+}
+
+func NewLabourOperationHandler(svc ports.LabourOperationService) *LabourOperationHandler {
+	return &LabourOperationHandler{svc: svc}
+}
+
+type labourOperationVehicleJSON struct {
+	Make  string `json:"make" binding:"required"`
+	Model string `json:"model"` // empty: every model of the make
+}
+
+type labourOperationPartJSON struct {
+	PartItemID uuid.UUID `json:"part_item_id" binding:"required"`
+	Quantity   float64   `json:"quantity" binding:"required"`
+	UnitPrice  float64   `json:"unit_price"`
+}
+
+// labourOperationJSON body for POST /labour-operations and PUT /labour-operations/:id (full replace).
+type labourOperationJSON struct {
+	Code          string                       `json:"code" binding:"required"`
+	Description   string                       `json:"description" binding:"required"`
+	FlatRateHours float64                      `json:"flat_rate_hours" binding:"required"`
+	Vehicles      []labourOperationVehicleJSON `json:"vehicles"` // empty: every car
+	Parts         []labourOperationPartJSON    `json:"parts"`    // default parts kit
+}
+
+func (b labourOperationJSON) toDomain(id uuid.UUID) *domain.LabourOperation {
+	op := &domain.LabourOperation{ID: id, Code: b.Code, Description: b.Description, FlatRateHours: b.FlatRateHours}
+	for _, v := range b.Vehicles {
+		op.Vehicles = append(op.Vehicles, domain.LabourOperationVehicle{Make: v.Make, Model: v.Model})
+	}
+	for _, p := range b.Parts {
+		op.Parts = append(op.Parts, domain.LabourOperationPart{PartItemID: p.PartItemID, Quantity: p.Quantity, UnitPrice: p.UnitPrice})
+	}
+	return op
+}
+
+// CreateLabourOperation POST /api/v1/labour-operations
+// @Summary     Crear operación del baremo de mano de obra
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       body body labourOperationJSON true "code, description, flat_rate_hours, vehicles, parts"
+// @Success     201 {object} domain.LabourOperation
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/labour-operations [post]
+func (h *LabourOperationHandler) CreateLabourOperation(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var body labourOperationJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.Create(c.Request.Context(), body.toDomain(uuid.Nil), uid)
+	if err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListLabourOperations GET /api/v1/labour-operations?search=&make=&model=&limit=&offset=
+// make/model keep the operations that apply to that car.
+// @Summary     Buscar en el baremo de mano de obra
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       search query string false "Código o descripción"
+// @Param       make query string false "Marca del coche"
+// @Param       model query string false "Modelo del coche"
+// @Success     200 {object} map[string]interface{}
+// @Failure     401,403,500
+// @Router      /api/v1/labour-operations [get]
+func (h *LabourOperationHandler) ListLabourOperations(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	limit, offset := QueryLimitOffset(c, 50, 500)
+	f := ports.LabourOperationListFilters{
+		Search: c.Query("search"),
+		Make:   c.Query("make"),
+		Model:  c.Query("model"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	items, total, err := h.svc.List(c.Request.Context(), f, uid)
+	if err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// GetLabourOperation GET /api/v1/labour-operations/:id
+// @Summary     Obtener operación del baremo
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "UUID operación"
+// @Success     200 {object} domain.LabourOperation
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/labour-operations/{id} [get]
+func (h *LabourOperationHandler) GetLabourOperation(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.Get(c.Request.Context(), id, uid)
+	if err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// UpdateLabourOperation PUT /api/v1/labour-operations/:id
+// @Summary     Actualizar operación del baremo
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID operación"
+// @Param       body body labourOperationJSON true "Operación completa"
+// @Success     200 {object} domain.LabourOperation
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/labour-operations/{id} [put]
+func (h *LabourOperationHandler) UpdateLabourOperation(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body labourOperationJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.Update(c.Request.Context(), body.toDomain(id), uid)
+	if err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteLabourOperation DELETE /api/v1/labour-operations/:id
+// @Summary     Eliminar operación del baremo
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Param       id path string true "UUID operación"
+// @Success     204
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/labour-operations/{id} [delete]
+func (h *LabourOperationHandler) DeleteLabourOperation(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id, uid); err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ImportLabourOperations POST /api/v1/labour-operations/import
+// CSV as a multipart "file" or the raw body. Columns: code, description, hours, and optionally
+// vehicles ("Marca:Modelo|Marca") and parts ("REF:cantidad:precio|..."). Existing codes are updated.
+// @Summary     Importar baremo de mano de obra desde CSV
+// @Tags        labour-operations
+// @Security    BearerAuth
+// @Accept      mpfd,text/csv
+// @Produce     json
+// @Param       file formData file false "CSV"
+// @Success     200 {object} ports.LabourOperationImport
+// @Failure     400,401,403,413,500
+// @Router      /api/v1/labour-operations/import [post]
+func (h *LabourOperationHandler) ImportLabourOperations(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, labour.MaxCatalogCSVBytes+64<<10)
+	data, err := readCatalogCSV(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload"})
+		return
+	}
+	out, err := h.svc.ImportCSV(c.Request.Context(), data, uid)
+	if err != nil {
+		writeLabourOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func readCatalogCSV(c *gin.Context) ([]byte, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return io.ReadAll(c.Request.Body)
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func writeLabourOperationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrLabourOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "labour operation not found"})
+	case errors.Is(err, domain.ErrPartItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "part item not found"})
+	case errors.Is(err, domain.ErrLabourOperationCodeTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidLabourOperation), errors.Is(err, labour.ErrInvalidCatalogCSV):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
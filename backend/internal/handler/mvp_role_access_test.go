@@ -501,6 +501,25 @@ func (m *mvpRepairRepo) ListIDsByServiceJobID(_ context.Context, _ uuid.UUID) ([
 	return nil, nil
 }
 
+func (m *mvpRepairRepo) ListByServiceJobIDs(context.Context, []uuid.UUID) ([]*domain.Repair, error) {
+	return []*domain.Repair{}, nil
+}
+
+func (m *mvpRepairRepo) ChangeStatus(context.Context, *domain.Repair, *domain.RepairStatusEvent) error {
+	return errors.New("not used")
+}
+
+func (m *mvpRepairRepo) ListStatusEvents(context.Context, uuid.UUID) ([]*domain.RepairStatusEvent, error) {
+	return []*domain.RepairStatusEvent{}, nil
+}
+
+func (m *mvpRepairRepo) ListByTechnician(context.Context, uuid.UUID, domain.RepairStatus, int, int) ([]*domain.Repair, error) {
+	return []*domain.Repair{}, nil
+}
+func (m *mvpRepairRepo) List(context.Context, *ports.RepairFilters) ([]*domain.Repair, int64, error) {
+	return []*domain.Repair{}, 0, nil
+}
+
 var _ ports.UserRepository = (*mvpUserRepo)(nil)
 var _ ports.CarRepository = (*mvpCarRepo)(nil)
 var _ ports.RepairRepository = (*mvpRepairRepo)(nil)
@@ -548,6 +567,31 @@ func (m *mvpSJRepo) ListByCarID(_ context.Context, carID uuid.UUID) ([]*domain.S
 	return m.byCar[carID], nil
 }
 
+func (m *mvpSJRepo) GetByAppointmentID(_ context.Context, appointmentID uuid.UUID) (*domain.ServiceJob, error) {
+	for _, j := range m.byID {
+		if j.AppointmentID != nil && *j.AppointmentID == appointmentID {
+			return j, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mvpSJRepo) ListByAppointmentIDs(_ context.Context, ids []uuid.UUID) ([]*domain.ServiceJob, error) {
+	out := []*domain.ServiceJob{}
+	for _, j := range m.byID {
+		for _, id := range ids {
+			if j.AppointmentID != nil && *j.AppointmentID == id {
+				out = append(out, j)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *mvpSJRepo) CreateForAppointment(ctx context.Context, j *domain.ServiceJob) error {
+	return m.Create(ctx, j)
+}
+
 func (m *mvpSJRepo) ListByOpenedOn(_ context.Context, day time.Time) ([]*domain.ServiceJob, error) {
 	if m.byID == nil {
 		return []*domain.ServiceJob{}, nil
@@ -569,13 +613,23 @@ func (m *mvpSJRepo) ListByOpenedOn(_ context.Context, day time.Time) ([]*domain.
 	return out, nil
 }
 
-func (m *mvpSJRepo) SaveReception(_ context.Context, r *domain.ServiceJobReception) error {
+func (m *mvpSJRepo) ListActive(context.Context) ([]*domain.ServiceJob, error) {
+	out := []*domain.ServiceJob{}
+	for _, j := range m.byID {
+		if j.Status == domain.ServiceJobStatusOpen || j.Status == domain.ServiceJobStatusInProgress {
+			out = append(out, j)
+		}
+	}
+	return out, nil
+}
+
+func (m *mvpSJRepo) SaveReception(_ context.Context, r *domain.ServiceJobReception, ev *domain.ServiceJobStatusEvent) error {
 	if m.rec == nil {
 		m.rec = make(map[uuid.UUID]*domain.ServiceJobReception)
 	}
 	cp := *r
 	m.rec[r.ServiceJobID] = &cp
-	return nil
+	return m.ChangeStatus(context.Background(), ev)
 }
 
 func (m *mvpSJRepo) GetReception(_ context.Context, id uuid.UUID) (*domain.ServiceJobReception, error) {
@@ -585,13 +639,13 @@ func (m *mvpSJRepo) GetReception(_ context.Context, id uuid.UUID) (*domain.Servi
 	return m.rec[id], nil
 }
 
-func (m *mvpSJRepo) SaveHandover(_ context.Context, h *domain.ServiceJobHandover) error {
+func (m *mvpSJRepo) SaveHandover(_ context.Context, h *domain.ServiceJobHandover, ev *domain.ServiceJobStatusEvent) error {
 	if m.ho == nil {
 		m.ho = make(map[uuid.UUID]*domain.ServiceJobHandover)
 	}
 	cp := *h
 	m.ho[h.ServiceJobID] = &cp
-	return nil
+	return m.ChangeStatus(context.Background(), ev)
 }
 
 func (m *mvpSJRepo) GetHandover(_ context.Context, id uuid.UUID) (*domain.ServiceJobHandover, error) {
@@ -601,6 +655,30 @@ func (m *mvpSJRepo) GetHandover(_ context.Context, id uuid.UUID) (*domain.Servic
 	return m.ho[id], nil
 }
 
+func (m *mvpSJRepo) ChangeStatus(_ context.Context, ev *domain.ServiceJobStatusEvent) error {
+	if ev == nil {
+		return nil
+	}
+	j, ok := m.byID[ev.ServiceJobID]
+	if !ok || j.Status != ev.FromStatus {
+		return domain.ErrServiceJobStatusConflict
+	}
+	j.Apply(ev)
+	return nil
+}
+
+func (m *mvpSJRepo) ListStatusEvents(context.Context, uuid.UUID) ([]*domain.ServiceJobStatusEvent, error) {
+	return []*domain.ServiceJobStatusEvent{}, nil
+}
+
+func (m *mvpSJRepo) SetPromise(context.Context, *domain.ServiceJobPromiseRevision) error {
+	return errors.New("not used")
+}
+
+func (m *mvpSJRepo) ListPromiseRevisions(context.Context, uuid.UUID) ([]*domain.ServiceJobPromiseRevision, error) {
+	return []*domain.ServiceJobPromiseRevision{}, nil
+}
+
 var _ ports.ServiceJobRepository = (*mvpSJRepo)(nil)
 
 func serviceJobWorkshopRouter(t *testing.T, secret string, userRepo ports.UserRepository, carRepo ports.CarRepository, jobRepo ports.ServiceJobRepository) *gin.Engine {
@@ -620,7 +698,7 @@ func serviceJobWorkshopRouter(t *testing.T, secret string, userRepo ports.UserRe
 		sj.POST("", h.CreateServiceJob)
 		sj.GET("", h.ListServiceJobsByOpenedOn)
 		sj.GET("/car/:carId", h.ListServiceJobsByCar)
-		sj.GET("/:id/obd", h.StubOBD)
+		sj.GET("/:id/obd", h.ListOBD)
 		sj.GET("/:id", h.GetServiceJob)
 		sj.PUT("/:id/reception", h.PutReception)
 		sj.PUT("/:id/handover", h.PutHandover)
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+// UploadOBD POST /api/v1/service-jobs/:id/obd
+// Accepts the scanner session as multipart field "file", or as the raw request body (text/plain or
+// text/csv, file name in the "file_name" query parameter). ELM327 logs and CSV exports are detected.
+// @Summary     Subir sesión de diagnóstico OBD-II (log ELM327 o CSV)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      multipart/form-data
+// @Param       id path string true "UUID service job"
+// @Param       file formData file true "log ELM327 o exportación CSV"
+// @Success     201 {object} domain.OBDSession
+// @Failure     400,401,403,404,413,500,503
+// @Router      /api/v1/service-jobs/{id}/obd [post]
+func (h *ServiceJobHandler) UploadOBD(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	// Leave room for multipart framing; the service enforces the log size itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, servicejob.MaxOBDLogSize+64<<10)
+	name, data, err := readOBDUpload(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeOBDError(c, domain.ErrOBDLogTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing or unreadable file"})
+		return
+	}
+	out, err := h.svc.UploadOBDSession(c.Request.Context(), jid, name, data, uid)
+	if err != nil {
+		writeOBDError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListOBD GET /api/v1/service-jobs/:id/obd
+// Oldest session first. Clients may read the sessions of their own visits.
+// @Summary     Listar sesiones OBD-II de la visita (códigos, freeze frame y PIDs)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} domain.OBDSession
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/obd [get]
+func (h *ServiceJobHandler) ListOBD(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.ListOBDSessions(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeOBDError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func readOBDUpload(c *gin.Context) (string, []byte, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return "", nil, err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return fh.Filename, data, err
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	return c.Query("file_name"), data, err
+}
+
+func writeOBDError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrOBDLogTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidOBDLog):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidServiceJobData):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visit is closed or cancelled"})
+	case errors.Is(err, servicejob.ErrOBDSessionsNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
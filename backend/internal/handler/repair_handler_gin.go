@@ -1,10 +1,8 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -79,45 +77,20 @@ type createRepairJSON struct {
 	Description string  `json:"description" binding:"required"`
 	Status      string  `json:"status"`
 	Cost        float64 `json:"cost"`
-	StartedAt   *string `json:"started_at"`
-	StartDate   *string `json:"start_date"`
+	StartedAt   *string `json:"started_at"` // ignored: set when the repair starts
+	StartDate   *string `json:"start_date"` // ignored
+	// Technicians sharing the repair (one lead); empty: the creator leads.
+	Technicians []repairTechnicianJSON `json:"technicians"`
+	// Completed repair under active warranty this one reworks; rework is not billed.
+	ReworkOfRepairID *string `json:"rework_of_repair_id"`
 }
 
 type updateRepairJSON struct {
 	Description *string  `json:"description"`
 	Status        *string  `json:"status"`
 	Cost          *float64 `json:"cost"`
-	StartedAt     *string  `json:"started_at"`
-	CompletedAt   *string  `json:"completed_at"`
-}
-
-func parseOptionalTime(s *string) (*time.Time, error) {
-	if s == nil {
-		return nil, nil
-	}
-	v := strings.TrimSpace(*s)
-	if v == "" {
-		return nil, nil
-	}
-	t, err := time.Parse(time.RFC3339, v)
-	if err == nil {
-		return &t, nil
-	}
-	t2, err2 := time.Parse("2006-01-02", v)
-	if err2 != nil {
-		return nil, fmt.Errorf("invalid datetime: %w", err)
-	}
-	return &t2, nil
-}
-
-func firstNonEmptyTimePtr(a, b *string) *string {
-	if a != nil && strings.TrimSpace(*a) != "" {
-		return a
-	}
-	if b != nil && strings.TrimSpace(*b) != "" {
-		return b
-	}
-	return nil
+	StartedAt     *string  `json:"started_at"`   // ignored: set by the status workflow
+	CompletedAt   *string  `json:"completed_at"` // ignored: set by the status workflow
 }
 
 // CreateRepair creates a repair (employee/manager/admin only; enforced in service).
@@ -127,7 +100,7 @@ func firstNonEmptyTimePtr(a, b *string) *string {
 // @Security    BearerAuth
 // @Accept      json
 // @Produce     json
-// @Param       body body createRepairJSON true "Datos (snake_case; started_at o start_date RFC3339 o fecha)"
+// @Param       body body createRepairJSON true "Datos (snake_case; status pending o in_progress; las fechas las fija el servidor)"
 // @Success     201 {object} RepairAPIModel
 // @Failure     400 {object} SwaggerMessage
 // @Failure     401 {object} SwaggerMessage
@@ -158,12 +131,6 @@ func (h *RepairHandler) GinCreateRepair(c *gin.Context) {
 		return
 	}
 
-	startedAt, err := parseOptionalTime(firstNonEmptyTimePtr(req.StartedAt, req.StartDate))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
 	st := domain.RepairStatus(req.Status)
 	if strings.TrimSpace(req.Status) == "" {
 		st = domain.RepairStatusPending
@@ -174,7 +141,17 @@ func (h *RepairHandler) GinCreateRepair(c *gin.Context) {
 		Description: strings.TrimSpace(req.Description),
 		Status:      st,
 		Cost:        req.Cost,
-		StartedAt:   startedAt,
+	}
+	for _, t := range req.Technicians {
+		repair.Technicians = append(repair.Technicians, t.toDomain())
+	}
+	if req.ReworkOfRepairID != nil && strings.TrimSpace(*req.ReworkOfRepairID) != "" {
+		originalID, err := uuid.Parse(strings.TrimSpace(*req.ReworkOfRepairID))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rework_of_repair_id"})
+			return
+		}
+		repair.ReworkOfRepairID = &originalID
 	}
 
 	created, err := h.repairService.CreateRepair(c.Request.Context(), repair, userID)
@@ -183,6 +160,10 @@ func (h *RepairHandler) GinCreateRepair(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
+		if err == domain.ErrNoActiveWarranty {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -247,6 +228,7 @@ func (h *RepairHandler) GinGetRepair(c *gin.Context) {
 // @Failure     401 {object} SwaggerMessage
 // @Failure     403 {object} SwaggerMessage
 // @Failure     404 {object} SwaggerMessage
+// @Failure     409 {object} SwaggerMessage
 // @Router      /api/v1/repairs/{id} [put]
 func (h *RepairHandler) GinUpdateRepair(c *gin.Context) {
 	userIDStr, exists := c.Get("userID")
@@ -295,22 +277,6 @@ func (h *RepairHandler) GinUpdateRepair(c *gin.Context) {
 	if req.Cost != nil {
 		merged.Cost = *req.Cost
 	}
-	if req.StartedAt != nil {
-		t, err := parseOptionalTime(req.StartedAt)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		merged.StartedAt = t
-	}
-	if req.CompletedAt != nil {
-		t, err := parseOptionalTime(req.CompletedAt)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		merged.CompletedAt = t
-	}
 
 	updated, err := h.repairService.UpdateRepair(c.Request.Context(), &merged, userID)
 	if err != nil {
@@ -318,6 +284,14 @@ func (h *RepairHandler) GinUpdateRepair(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
+		if err == domain.ErrRepairEstimateNotApproved {
+			c.JSON(http.StatusConflict, gin.H{"error": "the customer has not approved an estimate line for this repair yet"})
+			return
+		}
+		if err == domain.ErrRepairNotEditable || err == domain.ErrRepairTransitionNotAllowed || err == domain.ErrRepairStatusConflict {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	repairService "github.com/gaston-garcia-cegid/gonsgarage/internal/service/repair"
+)
+
+type repairLineJSON struct {
+	Kind        string     `json:"kind" binding:"required"` // labour | part
+	Description string     `json:"description"`
+	Quantity    float64    `json:"quantity" binding:"required"` // hours for labour, units for parts
+	UnitPrice   *float64   `json:"unit_price"`                  // labour: omit to use the employee's hourly rate
+	EmployeeID  *uuid.UUID `json:"employee_id"`                 // labour
+	PartItemID  *uuid.UUID `json:"part_item_id"`                // part: taken from stock
+}
+
+// AddRepairLine POST /api/v1/repairs/:id/lines
+// Adds a labour or parts line; the repair's cost becomes the sum of its lines and parts leave stock.
+// @Summary     Añadir línea de mano de obra o repuesto a la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       body body repairLineJSON true "kind, quantity, unit_price, employee_id o part_item_id"
+// @Success     201 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/repairs/{id}/lines [post]
+func (h *RepairHandler) AddRepairLine(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	var body repairLineJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.repairService.AddLine(c.Request.Context(), repairID, repairService.LineInput{
+		Kind:        domain.RepairLineKind(strings.ToLower(strings.TrimSpace(body.Kind))),
+		Description: body.Description,
+		Quantity:    body.Quantity,
+		UnitPrice:   body.UnitPrice,
+		EmployeeID:  body.EmployeeID,
+		PartItemID:  body.PartItemID,
+	}, uid)
+	if err != nil {
+		writeRepairLineError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+type repairOperationJSON struct {
+	OperationID  uuid.UUID  `json:"operation_id" binding:"required"`
+	EmployeeID   *uuid.UUID `json:"employee_id"`   // prices the hours at the employee's rate
+	HourlyRate   *float64   `json:"hourly_rate"`   // or at this rate
+	WithoutParts bool       `json:"without_parts"` // skip the operation's parts kit
+}
+
+// AddRepairOperation POST /api/v1/repairs/:id/operations
+// Adds a catalog operation: a labour line with its flat-rate hours and, unless without_parts, its parts kit.
+// @Summary     Añadir operación del baremo a la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       body body repairOperationJSON true "operation_id, employee_id o hourly_rate, without_parts"
+// @Success     201 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/repairs/{id}/operations [post]
+func (h *RepairHandler) AddRepairOperation(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	var body repairOperationJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.repairService.AddOperation(c.Request.Context(), repairID, repairService.OperationInput{
+		OperationID:  body.OperationID,
+		EmployeeID:   body.EmployeeID,
+		HourlyRate:   body.HourlyRate,
+		WithoutParts: body.WithoutParts,
+	}, uid)
+	if err != nil {
+		writeRepairLineError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// RemoveRepairLine DELETE /api/v1/repairs/:id/lines/:lineId
+// A removed parts line goes back to stock.
+// @Summary     Quitar línea de la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       lineId path string true "UUID línea"
+// @Success     200 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/repairs/{id}/lines/{lineId} [delete]
+func (h *RepairHandler) RemoveRepairLine(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	lineID, err := uuid.Parse(c.Param("lineId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid line ID"})
+		return
+	}
+	out, err := h.repairService.RemoveLine(c.Request.Context(), repairID, lineID, uid)
+	if err != nil {
+		writeRepairLineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeRepairLineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrRepairNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair not found"})
+	case errors.Is(err, domain.ErrRepairLineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair line not found"})
+	case errors.Is(err, domain.ErrPartItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "part item not found"})
+	case errors.Is(err, domain.ErrLabourOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "labour operation not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrInvalidRepairLine):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid line: labour needs hours and a price or an employee; parts need a part item, quantity and unit price"})
+	case errors.Is(err, domain.ErrInsufficientStock), errors.Is(err, domain.ErrRepairNotEditable),
+		errors.Is(err, domain.ErrLabourOperationNotApplicable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repairService.ErrRepairLinesNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
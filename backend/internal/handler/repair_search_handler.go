@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	repairService "github.com/gaston-garcia-cegid/gonsgarage/internal/service/repair"
+)
+
+// ListRepairs GET /api/v1/repairs?status=&technician_id=&service_job_id=&from=&to=&min_cost=&max_cost=&search=&limit=&offset=
+// Workshop-wide repair search, newest first.
+// @Summary     Buscar reparaciones del taller (admin/manager)
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       status query string false "pending, in_progress, completed o cancelled"
+// @Param       technician_id query string false "UUID técnico (responsable o asignado)"
+// @Param       service_job_id query string false "UUID visita"
+// @Param       from query string false "Creada desde (YYYY-MM-DD)"
+// @Param       to query string false "Creada hasta, inclusive (YYYY-MM-DD)"
+// @Param       min_cost query number false "Coste mínimo"
+// @Param       max_cost query number false "Coste máximo"
+// @Param       search query string false "Texto en la descripción"
+// @Param       limit query int false "Máximo (por defecto 50)"
+// @Param       offset query int false "Desplazamiento"
+// @Success     200 {object} map[string]interface{}
+// @Failure     400,401,403,500
+// @Router      /api/v1/repairs [get]
+func (h *RepairHandler) ListRepairs(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	f, ok := repairFiltersFromQuery(c)
+	if !ok {
+		return
+	}
+	f.Limit, f.Offset = QueryLimitOffset(c, 50, 500)
+	items, total, err := h.repairService.ListRepairs(c.Request.Context(), f, uid)
+	if err != nil {
+		writeRepairSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// ExportRepairs GET /api/v1/repairs/export — same filters as ListRepairs, every match as CSV.
+// @Summary     Exportar reparaciones a CSV (admin/manager)
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     text/csv
+// @Param       status query string false "pending, in_progress, completed o cancelled"
+// @Param       technician_id query string false "UUID técnico (responsable o asignado)"
+// @Param       service_job_id query string false "UUID visita"
+// @Param       from query string false "Creada desde (YYYY-MM-DD)"
+// @Param       to query string false "Creada hasta, inclusive (YYYY-MM-DD)"
+// @Param       min_cost query number false "Coste mínimo"
+// @Param       max_cost query number false "Coste máximo"
+// @Param       search query string false "Texto en la descripción"
+// @Success     200 {file} file
+// @Failure     400,401,403,500
+// @Router      /api/v1/repairs/export [get]
+func (h *RepairHandler) ExportRepairs(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	f, ok := repairFiltersFromQuery(c)
+	if !ok {
+		return
+	}
+	data, err := h.repairService.ExportRepairsCSV(c.Request.Context(), f, uid)
+	if err != nil {
+		writeRepairSearchError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="reparaciones-`+time.Now().Format("20060102")+`.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// repairFiltersFromQuery reads the search filters; on a malformed value it answers 400 and returns false.
+func repairFiltersFromQuery(c *gin.Context) (ports.RepairFilters, bool) {
+	var f ports.RepairFilters
+	bad := func(field string) (ports.RepairFilters, bool) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + field})
+		return f, false
+	}
+	if q := strings.TrimSpace(c.Query("status")); q != "" {
+		s := domain.RepairStatus(q)
+		if !domain.ValidateRepairStatus(s) {
+			return bad("status")
+		}
+		f.Status = &s
+	}
+	for _, p := range []struct {
+		name string
+		dst  **uuid.UUID
+	}{{"technician_id", &f.TechnicianID}, {"service_job_id", &f.ServiceJobID}} {
+		if q := strings.TrimSpace(c.Query(p.name)); q != "" {
+			id, err := uuid.Parse(q)
+			if err != nil {
+				return bad(p.name)
+			}
+			*p.dst = &id
+		}
+	}
+	if q := strings.TrimSpace(c.Query("from")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			return bad("from")
+		}
+		f.CreatedFrom = &d
+	}
+	if q := strings.TrimSpace(c.Query("to")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			return bad("to")
+		}
+		d = d.AddDate(0, 0, 1)
+		f.CreatedTo = &d
+	}
+	for _, p := range []struct {
+		name string
+		dst  **float64
+	}{{"min_cost", &f.MinCost}, {"max_cost", &f.MaxCost}} {
+		if q := strings.TrimSpace(c.Query(p.name)); q != "" {
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				return bad(p.name)
+			}
+			*p.dst = &v
+		}
+	}
+	f.Search = strings.TrimSpace(c.Query("search"))
+	return f, true
+}
+
+func writeRepairSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, repairService.ErrRepairExportTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
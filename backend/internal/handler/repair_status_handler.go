@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type repairStatusJSON struct {
+	Status string `json:"status" binding:"required"` // in_progress | completed | cancelled | pending (reopen)
+	Reason string `json:"reason"`                    // required to reopen
+}
+
+// TransitionRepair POST /api/v1/repairs/:id/status
+// pending → in_progress → completed/cancelled; started_at and completed_at are stamped here. Reopening a
+// completed (→ in_progress) or cancelled (→ pending) repair is for managers and needs a reason. Cancelling puts
+// the repair's parts back in stock; reopening a cancelled repair takes them out again (409 when short).
+// @Summary     Cambiar estado de la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       body body repairStatusJSON true "status, reason"
+// @Success     200 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/repairs/{id}/status [post]
+func (h *RepairHandler) TransitionRepair(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	var body repairStatusJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	to := domain.RepairStatus(strings.ToLower(strings.TrimSpace(body.Status)))
+	if !domain.ValidateRepairStatus(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair status"})
+		return
+	}
+	out, err := h.repairService.TransitionRepair(c.Request.Context(), repairID, to, body.Reason, uid)
+	if err != nil {
+		writeRepairStatusError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetRepairStatusHistory GET /api/v1/repairs/:id/status-history
+// @Summary     Historial de estados de la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Success     200 {array} domain.RepairStatusEvent
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/repairs/{id}/status-history [get]
+func (h *RepairHandler) GetRepairStatusHistory(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	out, err := h.repairService.ListRepairStatusHistory(c.Request.Context(), repairID, uid)
+	if err != nil {
+		writeRepairStatusError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeRepairStatusError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrRepairNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair not found"})
+	case errors.Is(err, domain.ErrRepairReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRepairEstimateNotApproved):
+		c.JSON(http.StatusConflict, gin.H{"error": "the customer has not approved an estimate line for this repair yet"})
+	case errors.Is(err, domain.ErrRepairTransitionNotAllowed), errors.Is(err, domain.ErrRepairStatusConflict),
+		errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	repairService "github.com/gaston-garcia-cegid/gonsgarage/internal/service/repair"
+)
+
+type repairTechnicianJSON struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Role   string    `json:"role" binding:"required"` // lead | assistant
+	Hours  float64   `json:"hours"`
+}
+
+type repairTechniciansJSON struct {
+	Technicians []repairTechnicianJSON `json:"technicians" binding:"required"`
+}
+
+func (t repairTechnicianJSON) toDomain() domain.RepairTechnician {
+	return domain.RepairTechnician{
+		UserID: t.UserID,
+		Role:   domain.RepairTechnicianRole(strings.ToLower(strings.TrimSpace(t.Role))),
+		Hours:  t.Hours,
+	}
+}
+
+// AssignRepairTechnicians PUT /api/v1/repairs/:id/technicians
+// Replaces who works on the repair: one lead and any assistants, with their hours.
+// @Summary     Asignar técnicos a la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       body body repairTechniciansJSON true "technicians: user_id, role (lead|assistant), hours"
+// @Success     200 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/repairs/{id}/technicians [put]
+func (h *RepairHandler) AssignRepairTechnicians(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	var body repairTechniciansJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	ts := make([]domain.RepairTechnician, len(body.Technicians))
+	for i, t := range body.Technicians {
+		ts[i] = t.toDomain()
+	}
+	out, err := h.repairService.AssignTechnicians(c.Request.Context(), repairID, ts, uid)
+	if err != nil {
+		writeRepairTechnicianError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListMyRepairs GET /api/v1/repairs/mine?status=&limit=&offset=
+// Repairs the caller leads or assists on, across all cars, newest first.
+// @Summary     Mis reparaciones (técnico)
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       status query string false "pending, in_progress, completed o cancelled"
+// @Param       limit query int false "Máximo (por defecto 50)"
+// @Param       offset query int false "Desplazamiento"
+// @Success     200 {array} RepairAPIModel
+// @Failure     400,401,403,500
+// @Router      /api/v1/repairs/mine [get]
+func (h *RepairHandler) ListMyRepairs(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	status := domain.RepairStatus(strings.TrimSpace(c.Query("status")))
+	if status != "" && !domain.ValidateRepairStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair status"})
+		return
+	}
+	limit, offset := QueryLimitOffset(c, 50, 500)
+	out, err := h.repairService.ListMyRepairs(c.Request.Context(), uid, status, limit, offset)
+	if err != nil {
+		writeRepairTechnicianError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeRepairTechnicianError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrRepairNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair not found"})
+	case errors.Is(err, domain.ErrInvalidRepairTechnicians):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRepairNotEditable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repairService.ErrRepairTechniciansNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
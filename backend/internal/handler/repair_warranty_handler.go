@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	repairService "github.com/gaston-garcia-cegid/gonsgarage/internal/service/repair"
+)
+
+type partWarrantyJSON struct {
+	LineID uuid.UUID `json:"line_id" binding:"required"`
+	Months int       `json:"months"`
+	KM     int       `json:"km"`
+}
+
+type repairWarrantyJSON struct {
+	Months     int                `json:"months"` // the repair's work; months and km both 0: no warranty on the work
+	KM         int                `json:"km"`
+	OdometerKM *int               `json:"odometer_km"` // when the car left; default: the car's mileage
+	Parts      []partWarrantyJSON `json:"parts"`
+}
+
+// SetRepairWarranty PUT /api/v1/repairs/:id/warranty
+// Replaces the warranty terms of a completed repair and its fitted parts; they run from completion.
+// @Summary     Registrar garantía de la reparación
+// @Tags        repairs
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "UUID reparación"
+// @Param       body body repairWarrantyJSON true "months/km del trabajo, odometer_km y parts (line_id, months, km)"
+// @Success     200 {object} RepairAPIModel
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/repairs/{id}/warranty [put]
+func (h *RepairHandler) SetRepairWarranty(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	repairID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repair ID"})
+		return
+	}
+	var body repairWarrantyJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	in := repairService.WarrantyInput{Months: body.Months, KM: body.KM, OdometerKM: body.OdometerKM}
+	for _, p := range body.Parts {
+		in.Parts = append(in.Parts, repairService.PartWarrantyInput{LineID: p.LineID, Months: p.Months, KM: p.KM})
+	}
+	out, err := h.repairService.SetWarranties(c.Request.Context(), repairID, in, uid)
+	if err != nil {
+		writeRepairWarrantyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListCarWarranties GET /api/v1/repairs/car/:carId/warranties?odometer_km=
+// Warranties still covering the car today at the given odometer reading.
+// @Summary     Garantías vigentes del vehículo
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       carId path string true "UUID vehículo"
+// @Param       odometer_km query int false "Kilómetros actuales (por defecto los registrados)"
+// @Success     200 {array} map[string]interface{}
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/repairs/car/{carId}/warranties [get]
+func (h *RepairHandler) ListCarWarranties(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	carID, err := uuid.Parse(c.Param("carId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid car ID"})
+		return
+	}
+	var odometer *int
+	if q := strings.TrimSpace(c.Query("odometer_km")); q != "" {
+		km, err := strconv.Atoi(q)
+		if err != nil || km < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid odometer_km"})
+			return
+		}
+		odometer = &km
+	}
+	out, err := h.repairService.ActiveWarranties(c.Request.Context(), carID, odometer, uid)
+	if err != nil {
+		writeRepairWarrantyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetReworkStats GET /api/v1/repairs/rework-stats?from=&to=
+// Warranty rework rate, cost and rework per lead technician of the original repair.
+// @Summary     Indicadores de retrabajos en garantía (admin/manager)
+// @Tags        repairs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       from query string false "Creadas desde (YYYY-MM-DD)"
+// @Param       to query string false "Creadas hasta, inclusive (YYYY-MM-DD)"
+// @Success     200 {object} map[string]interface{}
+// @Failure     400,401,403,500
+// @Router      /api/v1/repairs/rework-stats [get]
+func (h *RepairHandler) GetReworkStats(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var from, to *time.Time
+	if q := strings.TrimSpace(c.Query("from")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		from = &d
+	}
+	if q := strings.TrimSpace(c.Query("to")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+		d = d.AddDate(0, 0, 1)
+		to = &d
+	}
+	out, err := h.repairService.ReworkStats(c.Request.Context(), from, to, uid)
+	if err != nil {
+		writeRepairWarrantyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeRepairWarrantyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrRepairNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrInvalidRepairWarranty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrRepairNotCompleted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repairService.ErrRepairWarrantiesNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
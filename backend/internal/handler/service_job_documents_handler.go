@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// JobCardPDF GET /api/v1/service-jobs/:id/job-card.pdf
+// @Summary     Orden de trabajo en PDF (recepción, trabajo solicitado, firma)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     application/pdf
+// @Param       id path string true "UUID service job"
+// @Success     200 {file} binary
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/job-card.pdf [get]
+func (h *ServiceJobHandler) JobCardPDF(c *gin.Context) {
+	h.servePDF(c, "orden-trabajo", h.svc.JobCardPDF)
+}
+
+// HandoverPDF GET /api/v1/service-jobs/:id/handover.pdf
+// @Summary     Informe de entrega en PDF (trabajos, piezas, km, próximo servicio)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     application/pdf
+// @Param       id path string true "UUID service job"
+// @Success     200 {file} binary
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/{id}/handover.pdf [get]
+func (h *ServiceJobHandler) HandoverPDF(c *gin.Context) {
+	h.servePDF(c, "informe-entrega", h.svc.HandoverPDF)
+}
+
+func (h *ServiceJobHandler) servePDF(c *gin.Context, name string, render func(context.Context, uuid.UUID, uuid.UUID) ([]byte, error)) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := render(c.Request.Context(), jid, uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthorizedAccess):
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case errors.Is(err, domain.ErrServiceJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+		case errors.Is(err, domain.ErrCarNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+		case errors.Is(err, domain.ErrHandoverNotRecorded):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		}
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-%s.pdf"`, name, jid.String()[:8]))
+	c.Data(http.StatusOK, "application/pdf", out)
+}
@@ -30,20 +30,31 @@ type serviceJobDetailResponse struct {
 	Reception  *domain.ServiceJobReception `json:"reception,omitempty"`
 	Handover   *domain.ServiceJobHandover  `json:"handover,omitempty"`
 	RepairIDs  []uuid.UUID                   `json:"repair_ids"`
+	// PromiseState flags an active visit against its promised time: on_track | at_risk | overdue.
+	PromiseState domain.PromiseState `json:"promise_state,omitempty"`
+	// Template versions the checklists were filled in with (only for template-based checklists).
+	ReceptionTemplate *domain.ChecklistTemplate `json:"reception_template,omitempty"`
+	HandoverTemplate  *domain.ChecklistTemplate `json:"handover_template,omitempty"`
 }
 
 type putReceptionJSON struct {
-	OdometerKM   int    `json:"odometer_km"`
-	OilLevel     string `json:"oil_level"`
-	CoolantLevel string `json:"coolant_level"`
-	TiresNote    string `json:"tires_note"`
-	GeneralNotes string `json:"general_notes"`
+	OdometerKM          int                      `json:"odometer_km"`
+	OilLevel            string                   `json:"oil_level"`
+	CoolantLevel        string                   `json:"coolant_level"`
+	TiresNote           string                   `json:"tires_note"`
+	GeneralNotes        string                   `json:"general_notes"`
+	ChecklistTemplateID *uuid.UUID               `json:"checklist_template_id"`
+	ChecklistAnswers    []domain.ChecklistAnswer `json:"checklist_answers"`
+	Signature           *signatureJSON           `json:"signature"`
 }
 
 type putHandoverJSON struct {
-	OdometerKM   int    `json:"odometer_km"`
-	TiresNote    string `json:"tires_note"`
-	GeneralNotes string `json:"general_notes"`
+	OdometerKM          int                      `json:"odometer_km"`
+	TiresNote           string                   `json:"tires_note"`
+	GeneralNotes        string                   `json:"general_notes"`
+	ChecklistTemplateID *uuid.UUID               `json:"checklist_template_id"`
+	ChecklistAnswers    []domain.ChecklistAnswer `json:"checklist_answers"`
+	Signature           *signatureJSON           `json:"signature"`
 }
 
 // CreateServiceJob POST /api/v1/service-jobs
@@ -118,7 +129,14 @@ func (h *ServiceJobHandler) GetServiceJob(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, serviceJobDetailResponse{Job: *job, Reception: rec, Handover: ho, RepairIDs: repIDs})
+	out := serviceJobDetailResponse{Job: *job, Reception: rec, Handover: ho, RepairIDs: repIDs, PromiseState: h.svc.PromiseState(job)}
+	if rec != nil && rec.ChecklistTemplateID != nil {
+		out.ReceptionTemplate, _ = h.svc.GetChecklistTemplate(c.Request.Context(), *rec.ChecklistTemplateID, uid)
+	}
+	if ho != nil && ho.ChecklistTemplateID != nil {
+		out.HandoverTemplate, _ = h.svc.GetChecklistTemplate(c.Request.Context(), *ho.ChecklistTemplateID, uid)
+	}
+	c.JSON(http.StatusOK, out)
 }
 
 // ListServiceJobsByOpenedOn GET /api/v1/service-jobs?opened_on=YYYY-MM-DD
@@ -212,6 +230,8 @@ func (h *ServiceJobHandler) PutReception(c *gin.Context) {
 		CoolantLevel: strings.TrimSpace(body.CoolantLevel),
 		TiresNote:    strings.TrimSpace(body.TiresNote),
 		GeneralNotes: strings.TrimSpace(body.GeneralNotes),
+		Checklist:    servicejob.ChecklistAnswersInput{TemplateID: body.ChecklistTemplateID, Answers: body.ChecklistAnswers},
+		Signature:    body.Signature.input(),
 	}, uid)
 	if err != nil {
 		if err == domain.ErrUnauthorizedAccess {
@@ -226,6 +246,13 @@ func (h *ServiceJobHandler) PutReception(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reception data"})
 			return
 		}
+		if err == domain.ErrServiceJobStatusConflict {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if writeChecklistAnswersError(c, err) || writeSignatureError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -253,6 +280,8 @@ func (h *ServiceJobHandler) PutHandover(c *gin.Context) {
 		OdometerKM:   body.OdometerKM,
 		TiresNote:    strings.TrimSpace(body.TiresNote),
 		GeneralNotes: strings.TrimSpace(body.GeneralNotes),
+		Checklist:    servicejob.ChecklistAnswersInput{TemplateID: body.ChecklistTemplateID, Answers: body.ChecklistAnswers},
+		Signature:    body.Signature.input(),
 	}, uid)
 	if err != nil {
 		if err == domain.ErrUnauthorizedAccess {
@@ -271,21 +300,107 @@ func (h *ServiceJobHandler) PutHandover(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid handover data"})
 			return
 		}
+		if err == domain.ErrServiceJobStatusConflict {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if writeChecklistAnswersError(c, err) || writeSignatureError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, out)
 }
 
-// StubOBD GET /api/v1/service-jobs/:id/obd — OBD not implemented in MVP1 (spec stub).
-// @Router      /api/v1/service-jobs/{id}/obd [get]
-func (h *ServiceJobHandler) StubOBD(c *gin.Context) {
-	_, err := uuid.Parse(c.Param("id"))
+type checkInJSON struct {
+	AppointmentID string `json:"appointment_id" binding:"required"`
+}
+
+// CheckInAppointment POST /api/v1/service-jobs/check-in
+// @Summary     Check-in de una cita (abre la visita)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       body body checkInJSON true "appointment_id"
+// @Success     201 {object} domain.ServiceJob
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/check-in [post]
+func (h *ServiceJobHandler) CheckInAppointment(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var req checkInJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	apptID, err := uuid.Parse(strings.TrimSpace(req.AppointmentID))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment_id"})
 		return
 	}
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "OBD not implemented", "code": "obd_not_implemented"})
+	job, err := h.svc.CheckInAppointment(c.Request.Context(), apptID, uid)
+	if err != nil {
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if err == domain.ErrAppointmentNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		if err == domain.ErrCarNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+			return
+		}
+		if err == domain.ErrAppointmentAlreadyCheckedIn {
+			c.JSON(http.StatusConflict, gin.H{"error": "appointment already checked in"})
+			return
+		}
+		if err == domain.ErrAppointmentNotCheckInable {
+			c.JSON(http.StatusConflict, gin.H{"error": "appointment cannot be checked in"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, job)
+}
+
+// ListArrivals GET /api/v1/service-jobs/arrivals?day=YYYY-MM-DD
+// Expected appointments for the workshop-local day and which ones already checked in.
+// @Summary     Citas esperadas vs llegadas del día
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       day query string false "Local calendar day (YYYY-MM-DD); default today"
+// @Success     200 {object} servicejob.ArrivalsSummary
+// @Router      /api/v1/service-jobs/arrivals [get]
+func (h *ServiceJobHandler) ListArrivals(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	day := time.Now()
+	if q := strings.TrimSpace(c.Query("day")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid day"})
+			return
+		}
+		day = d
+	}
+	out, err := h.svc.ArrivalsOn(c.Request.Context(), day, uid)
+	if err != nil {
+		if err == domain.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
 }
 
 func parseGinUserID(c *gin.Context) (uuid.UUID, bool) {
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+type postMessageJSON struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// ListMessages GET /api/v1/service-jobs/:id/messages
+// Oldest message first. Clients may read the thread of their own visits.
+// @Summary     Mensajes de la visita entre taller y cliente
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} domain.ServiceJobMessage
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/messages [get]
+func (h *ServiceJobHandler) ListMessages(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.ListMessages(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// PostMessage POST /api/v1/service-jobs/:id/messages
+// JSON {"body"} or multipart with a "body" field and up to 5 "attachments" files (photos or PDF).
+// The other side is notified.
+// @Summary     Escribir en el hilo de la visita
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json,mpfd
+// @Param       id path string true "UUID service job"
+// @Param       body formData string false "Texto del mensaje"
+// @Param       attachments formData file false "Adjuntos (JPEG, PNG, WebP, GIF o PDF)"
+// @Success     201 {object} domain.ServiceJobMessage
+// @Failure     400,401,403,404,413,500,503
+// @Router      /api/v1/service-jobs/{id}/messages [post]
+func (h *ServiceJobHandler) PostMessage(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	// Leave room for multipart framing; the service enforces the per-file limit itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, servicejob.MaxMessageAttachments*servicejob.MaxMessageAttachmentBytes+256<<10)
+	in, err := readMessageInput(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "message too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.PostMessage(c.Request.Context(), jid, in, uid)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// MarkMessagesRead POST /api/v1/service-jobs/:id/messages/read
+// Stamps the other side's messages as read by the caller (read receipts).
+// @Summary     Marcar mensajes de la visita como leídos
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {object} map[string]int64
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/messages/read [post]
+func (h *ServiceJobHandler) MarkMessagesRead(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	n, err := h.svc.MarkMessagesRead(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": n})
+}
+
+// GetMessageAttachment GET /api/v1/service-jobs/:id/messages/attachments/:attachmentId
+// @Summary     Descargar adjunto de un mensaje de la visita
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     octet-stream
+// @Param       id path string true "UUID service job"
+// @Param       attachmentId path string true "UUID adjunto"
+// @Success     200 {file} binary
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/messages/attachments/{attachmentId} [get]
+func (h *ServiceJobHandler) GetMessageAttachment(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	aid, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id"})
+		return
+	}
+	a, data, err := h.svc.MessageAttachment(c.Request.Context(), jid, aid, uid)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, a.ContentType, data)
+}
+
+func readMessageInput(c *gin.Context) (servicejob.MessageInput, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		var body postMessageJSON
+		if err := c.ShouldBindJSON(&body); err != nil {
+			return servicejob.MessageInput{}, err
+		}
+		return servicejob.MessageInput{Body: body.Body}, nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return servicejob.MessageInput{}, err
+	}
+	in := servicejob.MessageInput{Body: c.PostForm("body")}
+	for _, fh := range form.File["attachments"] {
+		f, err := fh.Open()
+		if err != nil {
+			return servicejob.MessageInput{}, err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return servicejob.MessageInput{}, err
+		}
+		in.Attachments = append(in.Attachments, servicejob.MessageAttachmentInput{FileName: fh.Filename, Data: data})
+	}
+	return in, nil
+}
+
+func writeMessageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrMessageAttachmentNotFound), errors.Is(err, domain.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+	case errors.Is(err, domain.ErrInvalidMessage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message needs text or attachments (up to 5 photos or PDFs of 10 MB each)"})
+	case errors.Is(err, servicejob.ErrMessagingNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
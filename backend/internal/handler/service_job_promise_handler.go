@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type putPromiseJSON struct {
+	PromisedAt time.Time `json:"promised_at" binding:"required"` // RFC 3339
+	Reason     string    `json:"reason"`
+}
+
+// PutPromise PUT /api/v1/service-jobs/:id/promise
+// Sets or revises when the car will be ready; each change is kept in the promise history and the client is notified.
+// @Summary     Fijar o revisar la hora de entrega prometida
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body putPromiseJSON true "promised_at, reason"
+// @Success     200 {object} domain.ServiceJob
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/{id}/promise [put]
+func (h *ServiceJobHandler) PutPromise(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body putPromiseJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := h.svc.SetPromisedTime(c.Request.Context(), jid, body.PromisedAt, body.Reason, uid)
+	if err != nil {
+		writePromiseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListPromiseHistory GET /api/v1/service-jobs/:id/promise-history
+// Oldest change first. Clients may read the history of their own visits.
+// @Summary     Historial de la hora de entrega prometida
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} domain.ServiceJobPromiseRevision
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/promise-history [get]
+func (h *ServiceJobHandler) ListPromiseHistory(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.ListPromiseRevisions(c.Request.Context(), jid, uid)
+	if err != nil {
+		writePromiseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListPromiseAlerts GET /api/v1/service-jobs/promise-alerts
+// Active visits whose promised time has passed (overdue) or is close (at_risk), most urgent first.
+// @Summary     Visitas atrasadas o en riesgo de atraso
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Success     200 {array} servicejob.BoardItem
+// @Failure     401,403,500
+// @Router      /api/v1/service-jobs/promise-alerts [get]
+func (h *ServiceJobHandler) ListPromiseAlerts(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.PromiseAlerts(c.Request.Context(), uid)
+	if err != nil {
+		writeBoardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writePromiseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrInvalidPromisedTime):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidServiceJobData), errors.Is(err, domain.ErrServiceJobStatusConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "visit is closed or cancelled"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+// signatureJSON is the client's signature sent with a reception or handover: svg_path (path data
+// drawn on a width×height pad) or png (base64).
+type signatureJSON struct {
+	SignerName string     `json:"signer_name"`
+	SignedAt   *time.Time `json:"signed_at"`
+	SVGPath    string     `json:"svg_path"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	PNG        []byte     `json:"png"`
+}
+
+func (b *signatureJSON) input() *servicejob.SignatureInput {
+	if b == nil {
+		return nil
+	}
+	in := &servicejob.SignatureInput{SignerName: b.SignerName, SVGPath: b.SVGPath, Width: b.Width, Height: b.Height, PNG: b.PNG}
+	if b.SignedAt != nil {
+		in.SignedAt = *b.SignedAt
+	}
+	return in
+}
+
+// GetSignature GET /api/v1/service-jobs/:id/signatures/:stage
+// @Summary     Firma del cliente en la recepción o la entrega (SVG o PNG, verificada con su hash)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     image/svg+xml,image/png
+// @Param       id    path string true "UUID service job"
+// @Param       stage path string true "reception | handover"
+// @Success     200 {file} binary
+// @Failure     400,401,403,404,500,503
+// @Router      /api/v1/service-jobs/{id}/signatures/{stage} [get]
+func (h *ServiceJobHandler) GetSignature(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	f, err := h.svc.SignatureImage(c.Request.Context(), jid, strings.ToLower(c.Param("stage")), uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthorizedAccess):
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		case errors.Is(err, domain.ErrServiceJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+		case errors.Is(err, domain.ErrFileNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "signature not found"})
+		case errors.Is(err, domain.ErrInvalidSignature):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "stage must be reception or handover"})
+		default:
+			if !writeSignatureError(c, err) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			}
+		}
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, f.Name))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, f.ContentType, f.Data)
+}
+
+func writeSignatureError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSignature), errors.Is(err, domain.ErrSignatureRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, servicejob.ErrSignatureStorageNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, servicejob.ErrSignatureHashMismatch):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type statusReasonJSON struct {
+	Reason string `json:"reason"`
+}
+
+type statusTransitionJSON struct {
+	Status string `json:"status" binding:"required"` // open | in_progress | closed | cancelled
+	Reason string `json:"reason"`
+}
+
+// CancelServiceJob POST /api/v1/service-jobs/:id/cancel
+// @Summary     Cancelar visita (motivo obligatorio)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body statusReasonJSON true "reason"
+// @Success     200 {object} domain.ServiceJob
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/{id}/cancel [post]
+func (h *ServiceJobHandler) CancelServiceJob(c *gin.Context) {
+	h.changeStatusWithReason(c, h.svc.CancelServiceJob)
+}
+
+// ReopenServiceJob POST /api/v1/service-jobs/:id/reopen
+// A closed visit goes back to in progress; a cancelled one back to open.
+// @Summary     Reabrir visita cerrada o cancelada (motivo obligatorio)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body statusReasonJSON true "reason"
+// @Success     200 {object} domain.ServiceJob
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/{id}/reopen [post]
+func (h *ServiceJobHandler) ReopenServiceJob(c *gin.Context) {
+	h.changeStatusWithReason(c, h.svc.ReopenServiceJob)
+}
+
+// TransitionServiceJob POST /api/v1/service-jobs/:id/status
+// Allowed: open → in_progress | cancelled; in_progress → open | closed | cancelled; closed → in_progress;
+// cancelled → open. Closing by hand needs a recorded handover.
+// @Summary     Cambiar estado de la visita
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body statusTransitionJSON true "status, reason"
+// @Success     200 {object} domain.ServiceJob
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/service-jobs/{id}/status [post]
+func (h *ServiceJobHandler) TransitionServiceJob(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body statusTransitionJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	to := domain.ServiceJobStatus(strings.ToLower(strings.TrimSpace(body.Status)))
+	out, err := h.svc.TransitionServiceJob(c.Request.Context(), jid, to, body.Reason, uid)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListStatusHistory GET /api/v1/service-jobs/:id/status-history
+// Oldest change first. Clients may read the history of their own visits.
+// @Summary     Historial de estados de la visita
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {array} domain.ServiceJobStatusEvent
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/status-history [get]
+func (h *ServiceJobHandler) ListStatusHistory(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.ListStatusHistory(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// changeStatusWithReason runs a cancel or reopen call with the reason from the body.
+func (h *ServiceJobHandler) changeStatusWithReason(c *gin.Context, apply func(context.Context, uuid.UUID, string, uuid.UUID) (*domain.ServiceJob, error)) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body statusReasonJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	out, err := apply(c.Request.Context(), jid, body.Reason, uid)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeStatusChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, domain.ErrServiceJobTransitionNotAllowed), errors.Is(err, domain.ErrServiceJobStatusConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrServiceJobReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidServiceJobData):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
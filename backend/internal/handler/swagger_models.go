@@ -68,4 +68,42 @@ type RepairAPIModel struct {
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
 	DeletedAt     *string `json:"deleted_at,omitempty"`
+	Lines         []RepairLineAPIModel `json:"lines,omitempty"` // labour and parts; cost is their sum when present
+	Technicians   []RepairTechnicianAPIModel `json:"technicians,omitempty"` // lead first; technician_id is the lead
+	ReworkOfRepairID *string `json:"rework_of_repair_id,omitempty"` // warranty rework: not billed
+	Warranties    []RepairWarrantyAPIModel `json:"warranties,omitempty"` // completed repairs only
+}
+
+// RepairWarrantyAPIModel garantía de una reparación completada (trabajo o repuesto montado).
+type RepairWarrantyAPIModel struct {
+	ID              string  `json:"id"`
+	RepairID        string  `json:"repair_id"`
+	RepairLineID    *string `json:"repair_line_id,omitempty"` // repuesto; vacío: el trabajo
+	Months          int     `json:"months"`
+	KM              int     `json:"km"`
+	StartsAt        string  `json:"starts_at"`
+	StartOdometerKM int     `json:"start_odometer_km"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// RepairTechnicianAPIModel técnico asignado a una reparación con su rol y horas.
+type RepairTechnicianAPIModel struct {
+	RepairID   string  `json:"repair_id"`
+	UserID     string  `json:"user_id"`
+	Role       string  `json:"role"` // lead | assistant
+	Hours      float64 `json:"hours"`
+	AssignedAt string  `json:"assigned_at"`
+}
+
+// RepairLineAPIModel línea de mano de obra o repuesto de una reparación.
+type RepairLineAPIModel struct {
+	ID          string  `json:"id"`
+	RepairID    string  `json:"repair_id"`
+	Kind        string  `json:"kind"` // labour | part
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	EmployeeID  *string `json:"employee_id,omitempty"`
+	PartItemID  *string `json:"part_item_id,omitempty"`
+	CreatedAt   string  `json:"created_at"`
 }
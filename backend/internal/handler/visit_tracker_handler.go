@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+type trackerLinkJSON struct {
+	Send  bool   `json:"send"`  // text the link to the client
+	Phone string `json:"phone"` // empty: the car owner's phone, or their email when they have none
+}
+
+// GetVisitTracker GET /api/v1/service-jobs/:id/tracker
+// The client's view of a visit: timeline, approved work, findings, promised time and whether the car is ready.
+// @Summary     Seguimiento de la visita (vista cliente)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Param       id path string true "UUID service job"
+// @Success     200 {object} servicejob.VisitTracker
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/tracker [get]
+func (h *ServiceJobHandler) GetVisitTracker(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.VisitTracker(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeTrackerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateTrackerLink POST /api/v1/service-jobs/:id/tracker-link
+// Signs an expiring link to the visit tracker for clients without an account, and texts it when asked.
+// @Summary     Crear enlace de seguimiento para el cliente
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Accept      json
+// @Param       id path string true "UUID service job"
+// @Param       body body trackerLinkJSON false "send, phone"
+// @Success     201 {object} servicejob.TrackerLink
+// @Failure     400,401,403,404,409,500,503
+// @Router      /api/v1/service-jobs/{id}/tracker-link [post]
+func (h *ServiceJobHandler) CreateTrackerLink(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var body trackerLinkJSON
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+	out, err := h.svc.CreateTrackerLink(c.Request.Context(), jid, servicejob.TrackerLinkInput{Phone: body.Phone, Send: body.Send}, uid)
+	if err != nil {
+		writeTrackerError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// GetPublicVisitTracker GET /api/v1/public/visits?token=
+// @Summary     Seguimiento de la visita desde enlace firmado
+// @Tags        service-jobs
+// @Param       token query string true "Token del enlace"
+// @Success     200 {object} servicejob.VisitTracker
+// @Failure     400,410
+// @Router      /api/v1/public/visits [get]
+func (h *ServiceJobHandler) GetPublicVisitTracker(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token required"})
+		return
+	}
+	out, err := h.svc.VisitTrackerByToken(c.Request.Context(), token)
+	if err != nil {
+		writeTrackerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeTrackerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVisitLinkInvalid):
+		c.JSON(http.StatusGone, gin.H{"error": "el enlace no es válido o ya venció"})
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrCarNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "car not found"})
+	case errors.Is(err, servicejob.ErrNoTrackerRecipient):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, servicejob.ErrTrackerLinksNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/appointment"
+)
+
+// WaitlistHandler exposes the per-day waitlist for fully booked days.
+type WaitlistHandler struct {
+	svc *appointment.AppointmentService
+}
+
+func NewWaitlistHandler(svc *appointment.AppointmentService) *WaitlistHandler {
+	return &WaitlistHandler{svc: svc}
+}
+
+type joinWaitlistJSON struct {
+	CarID       string `json:"car_id" binding:"required"`
+	ServiceType string `json:"service_type" binding:"required"`
+	Day         string `json:"day" binding:"required"` // YYYY-MM-DD (local)
+	CustomerID  string `json:"customer_id"`            // staff only
+}
+
+// JoinWaitlist POST /api/v1/appointments/waitlist
+// @Summary     Anotarse en lista de espera de un día completo
+// @Tags        appointments
+// @Security    BearerAuth
+// @Accept      json
+// @Param       body body joinWaitlistJSON true "car_id, service_type, day (YYYY-MM-DD)"
+// @Success     201 {object} domain.WaitlistEntry
+// @Failure     400,401,403,409,500
+// @Router      /api/v1/appointments/waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var req joinWaitlistJSON
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	carID, err := uuid.Parse(strings.TrimSpace(req.CarID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid car_id"})
+		return
+	}
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(req.Day), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid day"})
+		return
+	}
+	entry := &domain.WaitlistEntry{CarID: carID, ServiceType: req.ServiceType, Day: day}
+	if cid := strings.TrimSpace(req.CustomerID); cid != "" {
+		if entry.CustomerID, err = uuid.Parse(cid); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer_id"})
+			return
+		}
+	}
+	out, err := h.svc.JoinWaitlist(c.Request.Context(), entry, uid)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ListWaitlist GET /api/v1/appointments/waitlist?day=YYYY-MM-DD
+// Clients get their open entries; staff get the queue of one day (default today).
+// @Summary     Listar lista de espera
+// @Tags        appointments
+// @Security    BearerAuth
+// @Param       day query string false "Día local (YYYY-MM-DD), solo staff"
+// @Success     200 {array} domain.WaitlistEntry
+// @Router      /api/v1/appointments/waitlist [get]
+func (h *WaitlistHandler) ListWaitlist(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	day := time.Now()
+	if q := strings.TrimSpace(c.Query("day")); q != "" {
+		d, err := time.ParseInLocation("2006-01-02", q, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid day"})
+			return
+		}
+		day = d
+	}
+	list, err := h.svc.ListWaitlist(c.Request.Context(), day, uid)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// LeaveWaitlist DELETE /api/v1/appointments/waitlist/:id
+// @Summary     Salir de la lista de espera
+// @Tags        appointments
+// @Security    BearerAuth
+// @Param       id path string true "UUID de la entrada"
+// @Success     204 "Sin cuerpo"
+// @Router      /api/v1/appointments/waitlist/{id} [delete]
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := h.svc.LeaveWaitlist(c.Request.Context(), id, uid); err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeWaitlistError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrUnauthorizedAccess:
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case domain.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case domain.ErrWaitlistEntryNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "waitlist entry not found"})
+	case domain.ErrInvalidWaitlistData:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid waitlist data"})
+	case domain.ErrWaitlistDayNotFull:
+		c.JSON(http.StatusConflict, gin.H{"error": "Ese día todavía tiene turnos libres; reservá directamente."})
+	case domain.ErrWaitlistDuplicate:
+		c.JSON(http.StatusConflict, gin.H{"error": "Ya estás en la lista de espera de ese día."})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
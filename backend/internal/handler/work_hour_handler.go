@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/service/servicejob"
+)
+
+type clockInJSON struct {
+	ServiceJobID *uuid.UUID `json:"service_job_id"`
+	RepairID     *uuid.UUID `json:"repair_id"`
+	Description  string     `json:"description"`
+}
+
+// ClockIn POST /api/v1/work-hours/clock-in
+// @Summary     Iniciar cronómetro del técnico en una visita o reparación (uno activo por técnico)
+// @Tags        work-hours
+// @Security    BearerAuth
+// @Accept      json
+// @Produce     json
+// @Success     201 {object} domain.WorkHour
+// @Failure     400,401,403,404,409,500
+// @Router      /api/v1/work-hours/clock-in [post]
+func (h *ServiceJobHandler) ClockIn(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	var body clockInJSON
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	w, err := h.svc.ClockIn(c.Request.Context(), servicejob.ClockInInput{
+		ServiceJobID: body.ServiceJobID,
+		RepairID:     body.RepairID,
+		Description:  body.Description,
+	}, uid)
+	if err != nil {
+		writeWorkHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// ClockOut POST /api/v1/work-hours/clock-out
+// @Summary     Detener el cronómetro del técnico
+// @Tags        work-hours
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} domain.WorkHour
+// @Failure     401,403,409,500
+// @Router      /api/v1/work-hours/clock-out [post]
+func (h *ServiceJobHandler) ClockOut(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	w, err := h.svc.ClockOut(c.Request.Context(), uid)
+	if err != nil {
+		writeWorkHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// GetRunningTimer GET /api/v1/work-hours/running
+// @Summary     Cronómetro en curso del técnico (204 si no hay)
+// @Tags        work-hours
+// @Security    BearerAuth
+// @Produce     json
+// @Success     200 {object} domain.WorkHour
+// @Success     204
+// @Failure     401,403,500
+// @Router      /api/v1/work-hours/running [get]
+func (h *ServiceJobHandler) GetRunningTimer(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	w, err := h.svc.RunningTimer(c.Request.Context(), uid)
+	if err != nil {
+		writeWorkHourError(c, err)
+		return
+	}
+	if w == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// GetLabour GET /api/v1/service-jobs/:id/labour
+// @Summary     Horas reales vs facturadas de la visita (por reparación y técnico)
+// @Tags        service-jobs
+// @Security    BearerAuth
+// @Produce     json
+// @Param       id path string true "UUID service job"
+// @Success     200 {object} servicejob.LabourSummary
+// @Failure     400,401,403,404,500
+// @Router      /api/v1/service-jobs/{id}/labour [get]
+func (h *ServiceJobHandler) GetLabour(c *gin.Context) {
+	uid, ok := parseGinUserID(c)
+	if !ok {
+		return
+	}
+	jid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	out, err := h.svc.Labour(c.Request.Context(), jid, uid)
+	if err != nil {
+		writeWorkHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func writeWorkHourError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthorizedAccess):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrServiceJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "service job not found"})
+	case errors.Is(err, domain.ErrRepairNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "repair not found"})
+	case errors.Is(err, domain.ErrInvalidWorkTarget):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrWorkTimerRunning), errors.Is(err, domain.ErrNoWorkTimerRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, servicejob.ErrWorkTimeNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
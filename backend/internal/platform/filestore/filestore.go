@@ -0,0 +1,188 @@
+// Package filestore keeps uploaded files on the local disk behind the external.FileStorage port:
+// each file is stored under its ID with a small JSON sidecar for its name and content type.
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+const metaSuffix = ".meta.json"
+
+// ErrInvalidID is returned for IDs that are empty or would leave the storage directory.
+var ErrInvalidID = errors.New("invalid file id")
+
+// Local stores files in one directory.
+type Local struct {
+	dir string
+}
+
+var _ external.FileStorage = (*Local)(nil)
+
+// NewLocal returns a store rooted at dir, creating it if needed.
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("file storage dir: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+type meta struct {
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UploadFile writes file.Data under file.ID, replacing any file with the same ID.
+func (l *Local) UploadFile(ctx context.Context, file *domain.File) error {
+	path, err := l.path(file.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	m := meta{Name: file.Name, ContentType: file.ContentType, Size: int64(len(file.Data)), CreatedAt: file.CreatedAt, UpdatedAt: now}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	}
+	mb, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(path, file.Data); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	if err := writeAtomic(path+metaSuffix, mb); err != nil {
+		return fmt.Errorf("write file metadata: %w", err)
+	}
+	file.Size = m.Size
+	file.CreatedAt, file.UpdatedAt = m.CreatedAt, m.UpdatedAt
+	return nil
+}
+
+// DownloadFile returns the file with its content, or domain.ErrFileNotFound.
+func (l *Local) DownloadFile(ctx context.Context, id string) (*domain.File, error) {
+	path, err := l.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := l.readMeta(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	f.Data = data
+	return f, nil
+}
+
+// DeleteFile removes the file, or returns domain.ErrFileNotFound.
+func (l *Local) DeleteFile(ctx context.Context, id string) error {
+	path, err := l.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.ErrFileNotFound
+		}
+		return fmt.Errorf("delete file: %w", err)
+	}
+	if err := os.Remove(path + metaSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete file metadata: %w", err)
+	}
+	return nil
+}
+
+// ListFiles returns files newest first, without their content, and the total count.
+func (l *Local) ListFiles(ctx context.Context, limit, offset int) ([]*domain.File, int64, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list files: %w", err)
+	}
+	out := []*domain.File{}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), metaSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		f, err := l.readMeta(id)
+		if err != nil {
+			continue
+		}
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	total := int64(len(out))
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(out) {
+		offset = len(out)
+	}
+	out = out[offset:]
+	if limit > 0 && limit < len(out) {
+		out = out[:limit]
+	}
+	return out, total, nil
+}
+
+func (l *Local) readMeta(id string) (*domain.File, error) {
+	path, err := l.path(id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path + metaSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("read file metadata: %w", err)
+	}
+	var m meta
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("file metadata %s: %w", id, err)
+	}
+	return &domain.File{ID: id, Name: m.Name, ContentType: m.ContentType, Size: m.Size, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}, nil
+}
+
+// path maps an ID to a file in the storage directory. IDs are single path elements.
+func (l *Local) path(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") || strings.HasSuffix(id, metaSuffix) {
+		return "", ErrInvalidID
+	}
+	return filepath.Join(l.dir, id), nil
+}
+
+// writeAtomic writes through a temp file and rename, so readers never see half a file.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
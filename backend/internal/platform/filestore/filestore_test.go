@@ -0,0 +1,49 @@
+package filestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+func TestLocal_RoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	l, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	f := &domain.File{ID: "sig-1", Name: "reception.svg", ContentType: "image/svg+xml", Data: []byte("<svg/>")}
+	require.NoError(t, l.UploadFile(ctx, f))
+	assert.Equal(t, int64(6), f.Size)
+	require.NoError(t, l.UploadFile(ctx, &domain.File{ID: "sig-2", Name: "handover.png", Data: []byte{1, 2}}))
+
+	got, err := l.DownloadFile(ctx, "sig-1")
+	require.NoError(t, err)
+	assert.Equal(t, "reception.svg", got.Name)
+	assert.Equal(t, "image/svg+xml", got.ContentType)
+	assert.Equal(t, []byte("<svg/>"), got.Data)
+
+	list, total, err := l.ListFiles(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 1)
+	assert.Nil(t, list[0].Data)
+
+	require.NoError(t, l.DeleteFile(ctx, "sig-1"))
+	_, err = l.DownloadFile(ctx, "sig-1")
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+	assert.ErrorIs(t, l.DeleteFile(ctx, "sig-1"), domain.ErrFileNotFound)
+}
+
+func TestLocal_RejectsPathIDs(t *testing.T) {
+	t.Parallel()
+	l, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+	for _, id := range []string{"", "../x", "a/b", ".hidden", "x" + metaSuffix} {
+		assert.ErrorIs(t, l.UploadFile(context.Background(), &domain.File{ID: id}), ErrInvalidID, id)
+	}
+}
@@ -0,0 +1,271 @@
+// Package ical renders RFC 5545 iCalendar documents (calendar feeds and .ics attachments).
+// Event times are written as local wall-clock times with a TZID, and the matching VTIMEZONE
+// is generated from the Go location so clients do not need to know the zone name.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event statuses (RFC 5545 §3.8.1.11).
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event is one VEVENT.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string // one of the Status* constants; empty omits STATUS
+	Start       time.Time
+	End         time.Time
+	Updated     time.Time // LAST-MODIFIED; zero omits it
+	Sequence    int
+}
+
+// Calendar is a VCALENDAR with its events.
+type Calendar struct {
+	Name     string         // X-WR-CALNAME shown by calendar apps; optional
+	Method   string         // e.g. "PUBLISH"; optional
+	Location *time.Location // zone for event times; nil or UTC writes UTC times without VTIMEZONE
+	Events   []Event
+}
+
+const prodID = "-//GonsGarage//Appointments//ES"
+
+// Bytes renders the calendar with CRLF line endings and 75-octet line folding.
+func (c Calendar) Bytes() []byte {
+	return c.BytesAt(time.Now())
+}
+
+// BytesAt is Bytes with an explicit DTSTAMP (stable output for tests).
+func (c Calendar) BytesAt(stamp time.Time) []byte {
+	var b bytes.Buffer
+	w := func(name, value string) { writeLine(&b, name+":"+value) }
+
+	w("BEGIN", "VCALENDAR")
+	w("VERSION", "2.0")
+	w("PRODID", prodID)
+	w("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		w("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		w("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	loc := c.Location
+	tzid := ""
+	if loc != nil && loc != time.UTC {
+		tzid = loc.String()
+		if len(c.Events) > 0 {
+			from, to := c.span()
+			writeTimezone(&b, loc, tzid, from, to)
+		} else {
+			w("X-WR-TIMEZONE", tzid)
+		}
+	}
+
+	for _, e := range c.Events {
+		w("BEGIN", "VEVENT")
+		w("UID", escapeText(e.UID))
+		w("DTSTAMP", formatUTC(stamp))
+		writeLine(&b, dateTimeProp("DTSTART", e.Start, loc, tzid))
+		if !e.End.IsZero() {
+			writeLine(&b, dateTimeProp("DTEND", e.End, loc, tzid))
+		}
+		w("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			w("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			w("LOCATION", escapeText(e.Location))
+		}
+		if e.URL != "" {
+			w("URL", e.URL)
+		}
+		if e.Status != "" {
+			w("STATUS", e.Status)
+		}
+		if !e.Updated.IsZero() {
+			w("LAST-MODIFIED", formatUTC(e.Updated))
+		}
+		if e.Sequence > 0 {
+			w("SEQUENCE", fmt.Sprint(e.Sequence))
+		}
+		w("END", "VEVENT")
+	}
+	w("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func (c Calendar) span() (from, to time.Time) {
+	for i, e := range c.Events {
+		end := e.End
+		if end.IsZero() {
+			end = e.Start
+		}
+		if i == 0 || e.Start.Before(from) {
+			from = e.Start
+		}
+		if i == 0 || end.After(to) {
+			to = end
+		}
+	}
+	return from, to
+}
+
+func dateTimeProp(name string, t time.Time, loc *time.Location, tzid string) string {
+	if tzid == "" {
+		return name + ":" + formatUTC(t)
+	}
+	return name + ";TZID=" + paramValue(tzid) + ":" + t.In(loc).Format("20060102T150405")
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// paramValue quotes a parameter value when it contains characters reserved in parameters.
+func paramValue(v string) string {
+	if strings.ContainsAny(v, ":;,") {
+		return `"` + strings.ReplaceAll(v, `"`, "") + `"`
+	}
+	return v
+}
+
+// escapeText escapes a TEXT value (RFC 5545 §3.3.11).
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// writeLine folds content lines longer than 75 octets without splitting UTF-8 sequences.
+func writeLine(b *bytes.Buffer, line string) {
+	const limit = 75
+	first := true
+	for len(line) > 0 {
+		max := limit
+		if !first {
+			max = limit - 1 // the leading space counts
+		}
+		if len(line) <= max {
+			if !first {
+				b.WriteByte(' ')
+			}
+			b.WriteString(line)
+			break
+		}
+		cut := max
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n")
+		line = line[cut:]
+		first = false
+	}
+	b.WriteString("\r\n")
+}
+
+// transition is a UTC-offset change of a location.
+type transition struct {
+	at         time.Time // instant the new offset starts
+	fromOffset int
+	toOffset   int
+	name       string
+	dst        bool
+}
+
+// writeTimezone emits a VTIMEZONE covering [from, to] with one observance per offset change.
+// Transitions are listed explicitly (no RRULE), which every client accepts; the range is
+// widened to whole years so events moved a few weeks still resolve correctly.
+func writeTimezone(b *bytes.Buffer, loc *time.Location, tzid string, from, to time.Time) {
+	start := time.Date(from.In(loc).Year(), 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to.In(loc).Year()+1, 1, 1, 0, 0, 0, 0, loc)
+	ts := transitions(loc, start, end)
+
+	writeLine(b, "BEGIN:VTIMEZONE")
+	writeLine(b, "TZID:"+tzid)
+	if len(ts) == 0 {
+		// Fixed offset over the range: a single STANDARD observance.
+		name, off := start.In(loc).Zone()
+		writeObservance(b, transition{at: start, fromOffset: off, toOffset: off, name: name, dst: start.In(loc).IsDST()})
+	} else {
+		// The observance in effect before the first change, so times earlier in the year resolve too.
+		before := ts[0].at.Add(-time.Second).In(loc)
+		name, off := before.Zone()
+		writeObservance(b, transition{at: start, fromOffset: off, toOffset: off, name: name, dst: before.IsDST()})
+		for _, t := range ts {
+			writeObservance(b, t)
+		}
+	}
+	writeLine(b, "END:VTIMEZONE")
+}
+
+func writeObservance(b *bytes.Buffer, t transition) {
+	kind := "STANDARD"
+	if t.dst {
+		kind = "DAYLIGHT"
+	}
+	writeLine(b, "BEGIN:"+kind)
+	// DTSTART is the local time in effect before the change (RFC 5545 §3.6.5).
+	writeLine(b, "DTSTART:"+t.at.Add(time.Duration(t.fromOffset)*time.Second).UTC().Format("20060102T150405"))
+	writeLine(b, "TZOFFSETFROM:"+formatOffset(t.fromOffset))
+	writeLine(b, "TZOFFSETTO:"+formatOffset(t.toOffset))
+	if t.name != "" {
+		writeLine(b, "TZNAME:"+escapeText(t.name))
+	}
+	writeLine(b, "END:"+kind)
+}
+
+func formatOffset(sec int) string {
+	sign := "+"
+	if sec < 0 {
+		sign = "-"
+		sec = -sec
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, sec/3600, (sec%3600)/60)
+	if rem := sec % 60; rem != 0 {
+		s += fmt.Sprintf("%02d", rem)
+	}
+	return s
+}
+
+// transitions finds offset changes of loc in [start, end) by scanning days and bisecting to the second.
+func transitions(loc *time.Location, start, end time.Time) []transition {
+	var out []transition
+	_, prevOff := start.In(loc).Zone()
+	for t := start; t.Before(end); t = t.Add(24 * time.Hour) {
+		next := t.Add(24 * time.Hour)
+		_, off := next.In(loc).Zone()
+		if off == prevOff {
+			continue
+		}
+		lo, hi := t, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == prevOff {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		at := hi.Truncate(time.Second)
+		name, _ := at.In(loc).Zone()
+		out = append(out, transition{at: at, fromOffset: prevOff, toOffset: off, name: name, dst: at.In(loc).IsDST()})
+		prevOff = off
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_EventWithTimezone(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	start := time.Date(2030, 6, 14, 10, 0, 0, 0, loc)
+	out := string(Calendar{
+		Name:     "Turnos",
+		Method:   "PUBLISH",
+		Location: loc,
+		Events: []Event{{
+			UID:     "a1@gonsgarage",
+			Summary: "Revisión; frenos, aceite",
+			Status:  StatusConfirmed,
+			Start:   start,
+			End:     start.Add(time.Hour),
+		}},
+	}.BytesAt(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Lisbon:20300614T100000\r\n")
+	assert.Contains(t, out, "DTEND;TZID=Europe/Lisbon:20300614T110000\r\n")
+	assert.Contains(t, out, `SUMMARY:Revisión\; frenos\, aceite`)
+	assert.Contains(t, out, "DTSTAMP:20300601T000000Z\r\n")
+
+	// 2030 spring-forward in Lisbon: 01:00 UTC, local 01:00 WET -> 02:00 WEST.
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20300331T010000\r\nTZOFFSETFROM:+0000\r\nTZOFFSETTO:+0100\r\n")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20301027T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0000\r\n")
+}
+
+func TestCalendar_UTCAndFixedZone(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2030, 1, 2, 9, 30, 0, 0, time.UTC)
+	out := string(Calendar{Events: []Event{{UID: "x", Summary: "s", Start: start}}}.Bytes())
+	assert.Contains(t, out, "DTSTART:20300102T093000Z\r\n")
+	assert.NotContains(t, out, "VTIMEZONE")
+
+	fixed := time.FixedZone("ART", -3*3600)
+	out = string(Calendar{Location: fixed, Events: []Event{{UID: "x", Summary: "s", Start: start}}}.Bytes())
+	assert.Contains(t, out, "DTSTART;TZID=ART:20300102T063000\r\n")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20300101T000000\r\nTZOFFSETFROM:-0300\r\nTZOFFSETTO:-0300\r\n")
+}
+
+func TestWriteLine_FoldsAt75Octets(t *testing.T) {
+	t.Parallel()
+	out := string(Calendar{Events: []Event{{UID: "x", Summary: strings.Repeat("ñ", 100), Start: time.Unix(0, 0)}}}.Bytes())
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75, line)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("ñ", 100)+"\r\n")
+}
@@ -0,0 +1,137 @@
+package obd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvLayout describes a CSV export, from its header row. Two layouts are understood:
+//
+//   - records: one row per item, with "type" (dtc, pid, freeze, clear), "code" (DTC, or PID as hex
+//     or name) and optional "status", "value", "unit", "frame" and "dtc" columns;
+//   - exchanges: one row per command, with "request" (or "command") and the raw "response", which is
+//     read like an ELM327 log. Several answer lines may be separated by ";" or newlines.
+type csvLayout struct {
+	comma    rune
+	cols     map[string]int
+	exchange bool
+}
+
+func csvColumns(header string) (csvLayout, bool) {
+	l := csvLayout{comma: ',', cols: map[string]int{}}
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		l.comma = ';'
+	}
+	rd := csv.NewReader(strings.NewReader(header))
+	rd.Comma = l.comma
+	names, err := rd.Read()
+	if err != nil {
+		return l, false
+	}
+	for i, n := range names {
+		l.cols[strings.ToLower(strings.TrimSpace(n))] = i
+	}
+	if _, ok := l.cols["command"]; ok {
+		l.cols["request"] = l.cols["command"]
+	}
+	_, hasType := l.cols["type"]
+	_, hasCode := l.cols["code"]
+	_, hasRequest := l.cols["request"]
+	_, hasResponse := l.cols["response"]
+	l.exchange = hasRequest && hasResponse
+	return l, l.exchange || (hasType && hasCode)
+}
+
+func (l csvLayout) get(row []string, col string) string {
+	i, ok := l.cols[col]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// ParseCSV parses a CSV export in one of the layouts described on csvLayout.
+func ParseCSV(data []byte) (*Report, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	header, _, _ := strings.Cut(strings.TrimLeft(string(data), "\r\n\t "), "\n")
+	layout, ok := csvColumns(strings.TrimSpace(header))
+	if !ok {
+		return nil, ErrUnrecognisedLog
+	}
+	rd := csv.NewReader(bytes.NewReader(data))
+	rd.Comma = layout.comma
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	if _, err := rd.Read(); err != nil {
+		return nil, ErrUnrecognisedLog
+	}
+	b := newBuilder(FormatCSV)
+	elm := newELMReader(b)
+	for {
+		row, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrUnrecognisedLog
+		}
+		if layout.exchange {
+			for _, part := range strings.FieldsFunc(layout.get(row, "response"), func(r rune) bool { return r == ';' || r == '\n' || r == '\r' }) {
+				elm.line(part)
+			}
+			elm.flush()
+			continue
+		}
+		b.record(layout, row)
+	}
+	return b.done(), nil
+}
+
+// record applies one row of the records layout; rows that cannot be read are skipped.
+func (b *builder) record(l csvLayout, row []string) {
+	code := strings.ToUpper(l.get(row, "code"))
+	switch strings.ToLower(l.get(row, "type")) {
+	case "dtc":
+		if !ValidCode(code) {
+			return
+		}
+		status := strings.ToLower(l.get(row, "status"))
+		switch status {
+		case StatusStored, StatusPending, StatusPermanent, StatusCleared:
+		case "", "confirmed", "active":
+			status = StatusStored
+		default:
+			return
+		}
+		b.addDTC(code, status)
+	case "clear":
+		b.clearCodes()
+	case "pid", "freeze":
+		pid, ok := pidByName(code)
+		if !ok {
+			return
+		}
+		v, err := strconv.ParseFloat(strings.Replace(l.get(row, "value"), ",", ".", 1), 64)
+		if err != nil {
+			return
+		}
+		def := pids[pid]
+		rd := Reading{PID: hexByte(pid), Name: def.name, Value: v, Unit: def.unit}
+		if u := l.get(row, "unit"); u != "" {
+			rd.Unit = u
+		}
+		frame := l.get(row, "frame")
+		if frame == "" && strings.EqualFold(l.get(row, "type"), "pid") {
+			b.setReading(rd)
+			return
+		}
+		n, _ := strconv.Atoi(frame)
+		b.setFrameReading(n, rd)
+		if d := strings.ToUpper(l.get(row, "dtc")); ValidCode(d) {
+			b.frame(n).DTC = d
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package obd
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dtcTable holds SAE J2012 generic code descriptions (code,description per line).
+//
+//go:embed dtc_descriptions.csv
+var dtcTable string
+
+var dtcDescriptions = loadDTCDescriptions(dtcTable)
+
+func loadDTCDescriptions(raw string) map[string]string {
+	rows, err := csv.NewReader(strings.NewReader(raw)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("obd: embedded DTC table: %v", err))
+	}
+	out := make(map[string]string, len(rows))
+	for _, r := range rows {
+		if len(r) == 2 {
+			out[strings.ToUpper(strings.TrimSpace(r[0]))] = strings.TrimSpace(r[1])
+		}
+	}
+	return out
+}
+
+// DecodeDTC turns the two bytes of a mode 03/07/0A answer into a code such as "P0133".
+func DecodeDTC(a, b byte) string {
+	return fmt.Sprintf("%c%d%X%02X", "PCBU"[a>>6], (a>>4)&0x03, a&0x0F, b)
+}
+
+// ValidCode reports whether s looks like a DTC ("P0133", "U0100").
+func ValidCode(s string) bool {
+	if len(s) != 5 || !strings.ContainsRune("PCBU", rune(s[0])) || s[1] < '0' || s[1] > '3' {
+		return false
+	}
+	_, err := strconv.ParseUint(s[2:], 16, 16)
+	return err == nil
+}
+
+// Describe returns the generic description of code, or a description of its group when the
+// table has no entry (manufacturer-specific codes in particular).
+func Describe(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if d, ok := dtcDescriptions[code]; ok {
+		return d
+	}
+	if !ValidCode(code) {
+		return ""
+	}
+	system := map[byte]string{'P': "Powertrain", 'C': "Chassis", 'B': "Body", 'U': "Network communication"}[code[0]]
+	if code[1] == '1' || code[1] == '3' || (code[0] != 'P' && code[1] == '2') {
+		return system + " — manufacturer-specific code"
+	}
+	if code[0] == 'P' {
+		if g, ok := powertrainGroups[code[2]]; ok {
+			return "Powertrain — " + g
+		}
+	}
+	return system + " — generic code"
+}
+
+// powertrainGroups are the SAE subsystems of generic P0/P2 codes, by third character.
+var powertrainGroups = map[byte]string{
+	'0': "fuel and air metering and auxiliary emission controls",
+	'1': "fuel and air metering",
+	'2': "fuel and air metering (injector circuit)",
+	'3': "ignition system or misfire",
+	'4': "auxiliary emission controls",
+	'5': "vehicle speed, idle control and auxiliary inputs",
+	'6': "computer and output circuits",
+	'7': "transmission",
+	'8': "transmission",
+	'9': "transmission",
+	'A': "hybrid propulsion",
+}
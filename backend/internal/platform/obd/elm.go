@@ -0,0 +1,208 @@
+package obd
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// ParseELM parses an ELM327 terminal log: the commands sent (with or without the ">" prompt and echo)
+// followed by the adapter's hex answers. Answers identify their own mode and PID, so commands, AT
+// commands and adapter chatter (SEARCHING..., NO DATA, OK) are skipped. CAN headers (ATH1) and
+// multi-frame answers, both the ELM "0:"/"1:" form and raw ISO-TP frames, are reassembled.
+func ParseELM(data []byte) (*Report, error) {
+	r := newELMReader(newBuilder(FormatELM327))
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r", "\n"), "\n") {
+		r.line(line)
+	}
+	r.flush()
+	return r.b.done(), nil
+}
+
+// elmReader turns adapter output lines into complete OBD answers.
+type elmReader struct {
+	b *builder
+
+	multi     []byte // "0:"/"1:" continuation lines of the current answer
+	multiWant int    // announced byte count, 0 if none
+
+	isoTP     map[string][]byte // header -> reassembly buffer for first/consecutive frames
+	isoTPWant map[string]int
+}
+
+func newELMReader(b *builder) *elmReader {
+	return &elmReader{b: b, isoTP: map[string][]byte{}, isoTPWant: map[string]int{}}
+}
+
+func (r *elmReader) line(s string) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, ">") {
+		// Prompt followed by the command typed; the answer comes on the next lines.
+		r.flush()
+		return
+	}
+	s = strings.TrimSpace(strings.TrimPrefix(s, "SEARCHING..."))
+	if s == "" {
+		return
+	}
+	if i := strings.Index(s, ":"); i > 0 && i <= 2 {
+		if _, err := strconv.ParseUint(s[:i], 16, 8); err == nil {
+			if b, ok := hexBytes(s[i+1:]); ok {
+				r.multi = append(r.multi, b...)
+				if r.multiWant > 0 && len(r.multi) >= r.multiWant {
+					r.b.answer(r.multi[:r.multiWant])
+					r.multi, r.multiWant = nil, 0
+				}
+			}
+			return
+		}
+	}
+	r.flush()
+	tokens := strings.Fields(s)
+	compact := strings.Join(tokens, "")
+	if len(compact) == 3 {
+		// Byte count announcing a multi-line answer ("00E").
+		if n, err := strconv.ParseUint(compact, 16, 16); err == nil {
+			r.multiWant = int(n)
+		}
+		return
+	}
+	header := ""
+	switch {
+	case len(tokens) > 1 && len(tokens[0]) == 3:
+		header, compact = tokens[0], strings.Join(tokens[1:], "")
+	case len(tokens) > 4 && tokens[0] == "18" && tokens[1] == "DA":
+		header, compact = strings.Join(tokens[:4], ""), strings.Join(tokens[4:], "")
+	case len(tokens) == 1 && len(compact)%2 == 1 && len(compact) > 3:
+		header, compact = compact[:3], compact[3:]
+	case len(tokens) == 1 && strings.HasPrefix(compact, "18DA") && len(compact) > 10:
+		header, compact = compact[:8], compact[8:]
+	}
+	msg, ok := hexBytes(compact)
+	if !ok || len(msg) == 0 {
+		return // AT command, adapter message or noise
+	}
+	if header == "" {
+		if msg[0] >= 0x01 && msg[0] <= 0x0A && len(msg) <= 3 {
+			return // echoed request such as "010C"
+		}
+		r.b.answer(msg)
+		return
+	}
+	r.frame(header, msg)
+}
+
+// frame handles one CAN frame shown with its header: the first byte is the ISO-TP PCI.
+func (r *elmReader) frame(header string, f []byte) {
+	pci := f[0]
+	switch pci >> 4 {
+	case 0x0: // single frame
+		n := int(pci & 0x0F)
+		if n > len(f)-1 {
+			n = len(f) - 1
+		}
+		r.b.answer(f[1 : 1+n])
+	case 0x1: // first frame
+		if len(f) < 2 {
+			return
+		}
+		r.isoTPWant[header] = int(pci&0x0F)<<8 | int(f[1])
+		r.isoTP[header] = append([]byte(nil), f[2:]...)
+	case 0x2: // consecutive frame
+		want, ok := r.isoTPWant[header]
+		if !ok {
+			return
+		}
+		buf := append(r.isoTP[header], f[1:]...)
+		if len(buf) >= want {
+			r.b.answer(buf[:want])
+			delete(r.isoTP, header)
+			delete(r.isoTPWant, header)
+			return
+		}
+		r.isoTP[header] = buf
+	}
+}
+
+// flush hands over a continuation answer whose byte count was not announced.
+func (r *elmReader) flush() {
+	if len(r.multi) > 0 {
+		r.b.answer(r.multi)
+	}
+	r.multi, r.multiWant = nil, 0
+}
+
+// answer records one complete OBD answer (mode byte + 0x40 first).
+func (b *builder) answer(msg []byte) {
+	if len(msg) == 0 {
+		return
+	}
+	switch msg[0] {
+	case 0x41:
+		if len(msg) < 2 {
+			return
+		}
+		if msg[1] == 0x01 {
+			if len(msg) >= 3 {
+				mil := msg[2]&0x80 != 0
+				b.report.MILOn = &mil
+			}
+			return
+		}
+		if rd, ok := decodePID(msg[1], msg[2:]); ok {
+			b.setReading(rd)
+		}
+	case 0x42:
+		if len(msg) < 3 {
+			return
+		}
+		pid, n, data := msg[1], int(msg[2]), msg[3:]
+		if pid == 0x02 {
+			if len(data) >= 2 && (data[0] != 0 || data[1] != 0) {
+				b.frame(n).DTC = DecodeDTC(data[0], data[1])
+			}
+			return
+		}
+		if rd, ok := decodePID(pid, data); ok {
+			b.setFrameReading(n, rd)
+		}
+	case 0x43, 0x47, 0x4A:
+		status := map[byte]string{0x43: StatusStored, 0x47: StatusPending, 0x4A: StatusPermanent}[msg[0]]
+		codes := msg[1:]
+		if len(codes)%2 == 1 {
+			codes = codes[1:] // CAN answers lead with the number of codes
+		}
+		for i := 0; i+1 < len(codes); i += 2 {
+			if codes[i] == 0 && codes[i+1] == 0 {
+				continue // padding
+			}
+			b.addDTC(DecodeDTC(codes[i], codes[i+1]), status)
+		}
+	case 0x44:
+		b.clearCodes()
+	}
+}
+
+func hexBytes(s string) ([]byte, bool) {
+	s = strings.Join(strings.Fields(s), "")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func hexByte(b byte) string {
+	return strings.ToUpper(hex.EncodeToString([]byte{b}))
+}
+
+func parseHexByte(s string) (byte, bool) {
+	if len(s) != 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s, 16, 8)
+	if err != nil {
+		return 0, false
+	}
+	return byte(n), true
+}
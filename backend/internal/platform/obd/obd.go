@@ -0,0 +1,184 @@
+// Package obd parses OBD-II scanner sessions captured as ELM327 terminal logs or CSV exports.
+// It extracts diagnostic trouble codes (stored, pending, permanent, and those cleared during the
+// session), freeze-frame data and a set of common mode 01 PIDs.
+package obd
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Log formats.
+const (
+	FormatELM327 = "elm327"
+	FormatCSV    = "csv"
+)
+
+// DTC statuses.
+const (
+	StatusStored    = "stored"    // mode 03, MIL-relevant confirmed codes
+	StatusPending   = "pending"   // mode 07, seen in the current or last drive cycle
+	StatusPermanent = "permanent" // mode 0A, survive a clear until the monitor passes
+	StatusCleared   = "cleared"   // read as stored or pending, then erased with mode 04 in the same session
+)
+
+var (
+	ErrEmptyLog        = errors.New("obd log is empty")
+	ErrUnrecognisedLog = errors.New("obd log contains no readable diagnostic data")
+)
+
+// DTC is one diagnostic trouble code.
+type DTC struct {
+	Code        string // e.g. "P0133"
+	Status      string // one of the Status* constants
+	Description string
+}
+
+// Reading is a decoded PID value.
+type Reading struct {
+	PID   string // two hex digits, e.g. "0C"
+	Name  string
+	Value float64
+	Unit  string
+}
+
+// FreezeFrame is the snapshot an ECU stored when a code was set (mode 02).
+type FreezeFrame struct {
+	Frame    int
+	DTC      string // code that triggered the snapshot, when reported
+	Readings []Reading
+}
+
+// Report is everything read from one session.
+type Report struct {
+	Format       string
+	MILOn        *bool // from PID 01 monitor status, when read
+	DTCs         []DTC
+	FreezeFrames []FreezeFrame
+	Readings     []Reading // last value of each mode 01 PID, in the order first seen
+}
+
+// Parse detects the format of data and parses it.
+func Parse(data []byte) (*Report, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyLog
+	}
+	var (
+		r   *Report
+		err error
+	)
+	if looksLikeCSV(data) {
+		r, err = ParseCSV(data)
+	} else {
+		r, err = ParseELM(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(r.DTCs) == 0 && len(r.FreezeFrames) == 0 && len(r.Readings) == 0 && r.MILOn == nil {
+		return nil, ErrUnrecognisedLog
+	}
+	return r, nil
+}
+
+// builder accumulates a report while a log is read.
+type builder struct {
+	report   Report
+	dtcIndex map[string]int // code|status -> index in report.DTCs
+	pidIndex map[string]int // pid -> index in report.Readings
+	frames   map[int]*FreezeFrame
+}
+
+func newBuilder(format string) *builder {
+	return &builder{
+		report:   Report{Format: format},
+		dtcIndex: map[string]int{},
+		pidIndex: map[string]int{},
+		frames:   map[int]*FreezeFrame{},
+	}
+}
+
+func (b *builder) addDTC(code, status string) {
+	key := code + "|" + status
+	if _, ok := b.dtcIndex[key]; ok {
+		return
+	}
+	b.dtcIndex[key] = len(b.report.DTCs)
+	b.report.DTCs = append(b.report.DTCs, DTC{Code: code, Status: status, Description: Describe(code)})
+}
+
+// clearCodes marks stored and pending codes read so far as cleared (mode 04 does not erase permanent codes).
+func (b *builder) clearCodes() {
+	kept := b.report.DTCs[:0]
+	seen := map[string]bool{}
+	for _, d := range b.report.DTCs {
+		if d.Status == StatusStored || d.Status == StatusPending {
+			if seen[d.Code] {
+				continue
+			}
+			d.Status = StatusCleared
+			seen[d.Code] = true
+		}
+		kept = append(kept, d)
+	}
+	b.report.DTCs = kept
+	b.dtcIndex = map[string]int{}
+	for i, d := range b.report.DTCs {
+		b.dtcIndex[d.Code+"|"+d.Status] = i
+	}
+}
+
+func (b *builder) setReading(rd Reading) {
+	if i, ok := b.pidIndex[rd.PID]; ok {
+		b.report.Readings[i] = rd
+		return
+	}
+	b.pidIndex[rd.PID] = len(b.report.Readings)
+	b.report.Readings = append(b.report.Readings, rd)
+}
+
+func (b *builder) frame(n int) *FreezeFrame {
+	f, ok := b.frames[n]
+	if !ok {
+		f = &FreezeFrame{Frame: n}
+		b.frames[n] = f
+	}
+	return f
+}
+
+func (b *builder) setFrameReading(n int, rd Reading) {
+	f := b.frame(n)
+	for i := range f.Readings {
+		if f.Readings[i].PID == rd.PID {
+			f.Readings[i] = rd
+			return
+		}
+	}
+	f.Readings = append(f.Readings, rd)
+}
+
+func (b *builder) done() *Report {
+	nums := make([]int, 0, len(b.frames))
+	for n := range b.frames {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	for _, n := range nums {
+		b.report.FreezeFrames = append(b.report.FreezeFrames, *b.frames[n])
+	}
+	return &b.report
+}
+
+func looksLikeCSV(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		_, ok := csvColumns(line)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package obd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeDTC(t *testing.T) {
+	assert.Equal(t, "P0133", DecodeDTC(0x01, 0x33))
+	assert.Equal(t, "C0035", DecodeDTC(0x40, 0x35))
+	assert.Equal(t, "B1234", DecodeDTC(0x92, 0x34))
+	assert.Equal(t, "U0100", DecodeDTC(0xC1, 0x00))
+	assert.Equal(t, "P242F", DecodeDTC(0x24, 0x2F))
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "O2 Sensor Circuit Slow Response (Bank 1 Sensor 1)", Describe("p0133"))
+	assert.Equal(t, "Powertrain — manufacturer-specific code", Describe("P1234"))
+	assert.Equal(t, "Powertrain — transmission", Describe("P0799"))
+	assert.Equal(t, "Network communication — generic code", Describe("U0999"))
+	assert.Equal(t, "", Describe("X0100"))
+}
+
+func TestParseELM_SessionWithClear(t *testing.T) {
+	log := `ELM327 v1.5
+>ATZ
+ELM327 v1.5
+>ATE0
+OK
+>0101
+SEARCHING...
+41 01 83 07 65 04
+>03
+43 01 33 03 00 00 00
+>07
+47 01 71 00 00 00 00
+>0A
+NO DATA
+>0202
+42 02 00 01 33
+>020C00
+42 0C 00 1A F8
+>0105
+41 05 7B
+>010C
+41 0C 0F A0
+>010C
+41 0C 1A F8
+>04
+44
+>03
+43 00 00 00 00 00 00
+`
+	r, err := Parse([]byte(log))
+	require.NoError(t, err)
+	assert.Equal(t, FormatELM327, r.Format)
+	require.NotNil(t, r.MILOn)
+	assert.True(t, *r.MILOn)
+
+	assert.Equal(t, []DTC{
+		{Code: "P0133", Status: StatusCleared, Description: "O2 Sensor Circuit Slow Response (Bank 1 Sensor 1)"},
+		{Code: "P0300", Status: StatusCleared, Description: "Random/Multiple Cylinder Misfire Detected"},
+		{Code: "P0171", Status: StatusCleared, Description: "System Too Lean (Bank 1)"},
+	}, r.DTCs)
+
+	require.Len(t, r.FreezeFrames, 1)
+	assert.Equal(t, "P0133", r.FreezeFrames[0].DTC)
+	assert.Equal(t, []Reading{{PID: "0C", Name: "Engine speed", Value: 1726, Unit: "rpm"}}, r.FreezeFrames[0].Readings)
+
+	assert.Equal(t, []Reading{
+		{PID: "05", Name: "Engine coolant temperature", Value: 83, Unit: "°C"},
+		{PID: "0C", Name: "Engine speed", Value: 1726, Unit: "rpm"},
+	}, r.Readings)
+}
+
+func TestParseELM_HeadersAndMultiFrame(t *testing.T) {
+	log := `>ATH1
+OK
+>03
+7E8 10 0E 43 06 01 33 01 71
+7E8 21 02 17 C1 00 92 34 04
+7E8 22 20 00 00 00 00 00 00
+>0A
+00E
+0: 4A 06 01 33 01 71
+1: 02 17 C1 00 92 34 04
+2: 20 00 00 00 00 00 00
+>0142
+7E8064142319C000000
+`
+	r, err := Parse([]byte(log))
+	require.NoError(t, err)
+
+	codes := func(status string) []string {
+		var out []string
+		for _, d := range r.DTCs {
+			if d.Status == status {
+				out = append(out, d.Code)
+			}
+		}
+		return out
+	}
+	assert.Equal(t, []string{"P0133", "P0171", "P0217", "U0100", "B1234", "P0420"}, codes(StatusStored))
+	assert.Equal(t, []string{"P0133", "P0171", "P0217", "U0100", "B1234", "P0420"}, codes(StatusPermanent))
+	assert.Equal(t, []Reading{{PID: "42", Name: "Control module voltage", Value: 12.7, Unit: "V"}}, r.Readings)
+}
+
+func TestParseCSV_Records(t *testing.T) {
+	data := "type;code;status;value;unit;frame;dtc\n" +
+		"dtc;P0420;stored;;;;\n" +
+		"dtc;P0128;pending;;;;\n" +
+		"dtc;P0300;cleared;;;;\n" +
+		"pid;Engine coolant temperature;;88;;;\n" +
+		"pid;010D;;0;;;\n" +
+		"freeze;0C;;2100,5;;0;P0420\n" +
+		"dtc;garbage;stored;;;;\n"
+	r, err := Parse([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, r.Format)
+	require.Len(t, r.DTCs, 3)
+	assert.Equal(t, DTC{Code: "P0128", Status: StatusPending, Description: Describe("P0128")}, r.DTCs[1])
+	assert.Equal(t, StatusCleared, r.DTCs[2].Status)
+	assert.Equal(t, []Reading{
+		{PID: "05", Name: "Engine coolant temperature", Value: 88, Unit: "°C"},
+		{PID: "0D", Name: "Vehicle speed", Value: 0, Unit: "km/h"},
+	}, r.Readings)
+	require.Len(t, r.FreezeFrames, 1)
+	assert.Equal(t, "P0420", r.FreezeFrames[0].DTC)
+	assert.Equal(t, 2100.5, r.FreezeFrames[0].Readings[0].Value)
+}
+
+func TestParseCSV_Exchanges(t *testing.T) {
+	data := "timestamp,request,response\n" +
+		"10:00:01,0101,41 01 00 07 65 04\n" +
+		"10:00:02,07,\"47 01 28 00 00 00 00\"\n" +
+		"10:00:03,0111,41 11 33;41 11 34\n"
+	r, err := Parse([]byte(data))
+	require.NoError(t, err)
+	require.NotNil(t, r.MILOn)
+	assert.False(t, *r.MILOn)
+	assert.Equal(t, []DTC{{Code: "P0128", Status: StatusPending, Description: Describe("P0128")}}, r.DTCs)
+	assert.Equal(t, []Reading{{PID: "11", Name: "Throttle position", Value: 20.39, Unit: "%"}}, r.Readings)
+}
+
+func TestParse_Rejects(t *testing.T) {
+	_, err := Parse([]byte("  \n"))
+	assert.ErrorIs(t, err, ErrEmptyLog)
+
+	_, err = Parse([]byte(">ATZ\nELM327 v1.5\n>0100\nUNABLE TO CONNECT\n"))
+	assert.ErrorIs(t, err, ErrUnrecognisedLog)
+
+	_, err = Parse([]byte("hello,world\n1,2\n"))
+	assert.ErrorIs(t, err, ErrUnrecognisedLog)
+}
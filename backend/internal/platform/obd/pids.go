@@ -0,0 +1,74 @@
+package obd
+
+import (
+	"math"
+	"strings"
+)
+
+// pidDef decodes one mode 01 / 02 PID from its data bytes.
+type pidDef struct {
+	name   string
+	unit   string
+	size   int
+	decode func(d []byte) float64
+}
+
+func word(d []byte) float64 { return float64(int(d[0])*256 + int(d[1])) }
+
+func percent(d []byte) float64 { return round2(float64(d[0]) * 100 / 255) }
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+// pids lists the basic PIDs we decode; others are skipped.
+var pids = map[byte]pidDef{
+	0x04: {"Calculated engine load", "%", 1, percent},
+	0x05: {"Engine coolant temperature", "°C", 1, func(d []byte) float64 { return float64(d[0]) - 40 }},
+	0x06: {"Short term fuel trim bank 1", "%", 1, func(d []byte) float64 { return round2(float64(d[0])*100/128 - 100) }},
+	0x07: {"Long term fuel trim bank 1", "%", 1, func(d []byte) float64 { return round2(float64(d[0])*100/128 - 100) }},
+	0x0B: {"Intake manifold pressure", "kPa", 1, func(d []byte) float64 { return float64(d[0]) }},
+	0x0C: {"Engine speed", "rpm", 2, func(d []byte) float64 { return word(d) / 4 }},
+	0x0D: {"Vehicle speed", "km/h", 1, func(d []byte) float64 { return float64(d[0]) }},
+	0x0E: {"Timing advance", "°", 1, func(d []byte) float64 { return float64(d[0])/2 - 64 }},
+	0x0F: {"Intake air temperature", "°C", 1, func(d []byte) float64 { return float64(d[0]) - 40 }},
+	0x10: {"MAF air flow rate", "g/s", 2, func(d []byte) float64 { return round2(word(d) / 100) }},
+	0x11: {"Throttle position", "%", 1, percent},
+	0x1F: {"Run time since engine start", "s", 2, word},
+	0x21: {"Distance travelled with MIL on", "km", 2, word},
+	0x2F: {"Fuel tank level", "%", 1, percent},
+	0x31: {"Distance travelled since codes cleared", "km", 2, word},
+	0x33: {"Barometric pressure", "kPa", 1, func(d []byte) float64 { return float64(d[0]) }},
+	0x42: {"Control module voltage", "V", 2, func(d []byte) float64 { return round2(word(d) / 1000) }},
+	0x46: {"Ambient air temperature", "°C", 1, func(d []byte) float64 { return float64(d[0]) - 40 }},
+	0x5C: {"Engine oil temperature", "°C", 1, func(d []byte) float64 { return float64(d[0]) - 40 }},
+}
+
+// decodePID returns the reading for pid from data, and false for unknown PIDs or short data.
+func decodePID(pid byte, data []byte) (Reading, bool) {
+	def, ok := pids[pid]
+	if !ok || len(data) < def.size {
+		return Reading{}, false
+	}
+	return Reading{PID: hexByte(pid), Name: def.name, Value: def.decode(data[:def.size]), Unit: def.unit}, true
+}
+
+// pidByName resolves a PID given as hex ("0C", "010C") or by its name, for CSV rows.
+func pidByName(s string) (byte, bool) {
+	if b, ok := parseHexByte(s); ok {
+		if _, known := pids[b]; known {
+			return b, true
+		}
+	}
+	if len(s) == 4 && (s[:2] == "01" || s[:2] == "02") {
+		if b, ok := parseHexByte(s[2:]); ok {
+			if _, known := pids[b]; known {
+				return b, true
+			}
+		}
+	}
+	for b, def := range pids {
+		if strings.EqualFold(def.name, s) {
+			return b, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,169 @@
+package pdf
+
+// Flow lays content out top to bottom between the page margins, starting a new page when the
+// next block does not fit.
+type Flow struct {
+	Doc    *Document
+	Margin float64
+	// Header, when set, is drawn at the top of every page the flow starts and returns where content begins.
+	Header func(f *Flow) float64
+
+	y float64
+}
+
+// NewFlow starts the first page of d.
+func NewFlow(d *Document, margin float64, header func(f *Flow) float64) *Flow {
+	f := &Flow{Doc: d, Margin: margin, Header: header}
+	f.NewPage()
+	return f
+}
+
+// NewPage starts a page and draws the header.
+func (f *Flow) NewPage() {
+	f.Doc.AddPage()
+	f.y = f.Margin
+	if f.Header != nil {
+		f.y = f.Header(f)
+	}
+}
+
+// Y is the top of the next block.
+func (f *Flow) Y() float64 { return f.y }
+
+// Left and Right are the x positions of the margins; Width is the space between them.
+func (f *Flow) Left() float64  { return f.Margin }
+func (f *Flow) Right() float64 { return PageWidth - f.Margin }
+func (f *Flow) Width() float64 { return PageWidth - 2*f.Margin }
+
+// Space moves down by h.
+func (f *Flow) Space(h float64) { f.y += h }
+
+// Ensure starts a new page unless h points fit above the bottom margin.
+func (f *Flow) Ensure(h float64) {
+	if f.y+h > PageHeight-f.Margin {
+		f.NewPage()
+	}
+}
+
+// Heading writes a section title over a thin rule.
+func (f *Flow) Heading(s string, c Color) {
+	f.Ensure(40)
+	f.y += 14
+	f.Doc.Text(f.Left(), f.y, Bold, 12, c, s)
+	f.y += 4
+	f.Doc.Line(f.Left(), f.y, f.Right(), f.y, 0.8, c)
+	f.y += 12
+}
+
+// Paragraph writes wrapped text.
+func (f *Flow) Paragraph(s string, font Font, size float64, c Color) {
+	lead := size * 1.35
+	for _, line := range WrapText(s, font, size, f.Width()) {
+		f.Ensure(lead)
+		f.Doc.Text(f.Left(), f.y+size, font, size, c, line)
+		f.y += lead
+	}
+}
+
+// Field is one label/value pair of Fields.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Fields writes label/value pairs in the given number of columns, label above value.
+func (f *Flow) Fields(fields []Field, columns int) {
+	if columns < 1 {
+		columns = 1
+	}
+	colW := f.Width() / float64(columns)
+	for i := 0; i < len(fields); i += columns {
+		f.Ensure(26)
+		for c := 0; c < columns && i+c < len(fields); c++ {
+			x := f.Left() + float64(c)*colW
+			f.Doc.Text(x, f.y+7, Regular, 7, Gray, fields[i+c].Label)
+			value := fields[i+c].Value
+			if value == "" {
+				value = "—"
+			}
+			lines := WrapText(value, Regular, 10, colW-8)
+			f.Doc.Text(x, f.y+19, Regular, 10, Black, lines[0])
+		}
+		f.y += 26
+	}
+}
+
+// Column describes one table column; Width is a share of the line (all columns together should sum to 1).
+type Column struct {
+	Title string
+	Width float64
+	Right bool // right-align, e.g. amounts
+}
+
+// Table writes a header row and rows; long cells wrap within their column. The header repeats after a page break.
+func (f *Flow) Table(cols []Column, rows [][]string, accent Color) {
+	const size, lead, pad = 9.0, 12.0, 4.0
+	header := func() {
+		f.Ensure(lead + 2*pad)
+		f.Doc.FillRect(f.Left(), f.y, f.Width(), lead+pad, LightGray)
+		x := f.Left()
+		for _, c := range cols {
+			w := c.Width * f.Width()
+			if c.Right {
+				f.Doc.TextRight(x+w-pad, f.y+lead-1, Bold, size, accent, c.Title)
+			} else {
+				f.Doc.Text(x+pad, f.y+lead-1, Bold, size, accent, c.Title)
+			}
+			x += w
+		}
+		f.y += lead + pad
+	}
+	header()
+	for _, row := range rows {
+		cells := make([][]string, len(cols))
+		height := 1
+		for i, c := range cols {
+			text := ""
+			if i < len(row) {
+				text = row[i]
+			}
+			cells[i] = WrapText(text, Regular, size, c.Width*f.Width()-2*pad)
+			if len(cells[i]) > height {
+				height = len(cells[i])
+			}
+		}
+		h := float64(height)*lead + pad
+		if f.y+h > PageHeight-f.Margin {
+			f.NewPage()
+			header()
+		}
+		x := f.Left()
+		for i, c := range cols {
+			w := c.Width * f.Width()
+			for n, line := range cells[i] {
+				y := f.y + float64(n+1)*lead - 1
+				if c.Right {
+					f.Doc.TextRight(x+w-pad, y, Regular, size, Black, line)
+				} else {
+					f.Doc.Text(x+pad, y, Regular, size, Black, line)
+				}
+			}
+			x += w
+		}
+		f.y += h
+		f.Doc.Line(f.Left(), f.y, f.Right(), f.y, 0.3, LightGray)
+	}
+}
+
+// SignatureBoxes draws side-by-side boxes with a caption under each, for hand signatures.
+func (f *Flow) SignatureBoxes(captions ...string) {
+	const h, gap = 60.0, 16.0
+	f.Ensure(h + 24)
+	w := (f.Width() - gap*float64(len(captions)-1)) / float64(len(captions))
+	for i, caption := range captions {
+		x := f.Left() + float64(i)*(w+gap)
+		f.Doc.StrokeRect(x, f.y, w, h, 0.6, Gray)
+		f.Doc.Text(x, f.y+h+11, Regular, 8, Gray, caption)
+	}
+	f.y += h + 20
+}
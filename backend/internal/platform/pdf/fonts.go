@@ -0,0 +1,125 @@
+package pdf
+
+import "strings"
+
+// Glyph widths of the standard Helvetica fonts for ASCII 32..126, in 1/1000 em (from the Adobe AFM files).
+var asciiWidths = [2][95]uint16{
+	Regular: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space … /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 … ?
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ … O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P … _
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` … o
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p … ~
+	},
+	Bold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// winAnsiExtra maps the non-Latin-1 characters of Windows-1252 (0x80-0x9F) that invoices and
+// reports actually use.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‰': 0x89, 'Š': 0x8A, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+	'š': 0x9A, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// extraWidths are the widths of non-ASCII glyphs that differ from their base letter (same in both fonts
+// to within a few units, which is fine for layout).
+var extraWidths = map[byte]uint16{
+	0x80: 556, 0x85: 1000, 0x89: 1000, 0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350,
+	0x96: 556, 0x97: 1000, 0x99: 1000, 0xA0: 278, 0xA1: 333, 0xAA: 370, 0xAB: 556, 0xB0: 400,
+	0xBA: 365, 0xBB: 556, 0xBF: 611, 0xC6: 1000, 0xD7: 584, 0xDF: 611, 0xE6: 889, 0xF7: 584,
+}
+
+// latinBase gives the unaccented letter for Latin-1 letters 0xC0..0xFF, which share its width.
+const latinBase = "AAAAAAACEEEEIIIIDNOOOOOxOUUUUYPsaaaaaaaceeeeiiiidnooooo/ouuuuypy"
+
+// encode converts s to Windows-1252 bytes; unsupported characters become '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtra[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func glyphWidth(b byte, f Font) uint16 {
+	switch {
+	case b >= 32 && b <= 126:
+		return asciiWidths[f][b-32]
+	case extraWidths[b] != 0:
+		return extraWidths[b]
+	case b >= 0xC0:
+		if base := latinBase[b-0xC0]; base >= 32 && base <= 126 {
+			return asciiWidths[f][base-32]
+		}
+	}
+	return 556
+}
+
+// TextWidth returns the width of s in points at the given font size.
+func TextWidth(s string, f Font, size float64) float64 {
+	var units int
+	for _, b := range encode(s) {
+		units += int(glyphWidth(b, f))
+	}
+	return float64(units) * size / 1000
+}
+
+// WrapText breaks s into lines no wider than width, at spaces where possible. Newlines in s are kept.
+func WrapText(s string, f Font, size, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := ""
+		for _, w := range words {
+			for TextWidth(w, f, size) > width && len([]rune(w)) > 1 {
+				// A single word longer than the line: cut it where it stops fitting.
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				rs := []rune(w)
+				n := len(rs) - 1
+				for n > 1 && TextWidth(string(rs[:n]), f, size) > width {
+					n--
+				}
+				lines = append(lines, string(rs[:n]))
+				w = string(rs[n:])
+			}
+			switch {
+			case line == "":
+				line = w
+			case TextWidth(line+" "+w, f, size) <= width:
+				line += " " + w
+			default:
+				lines = append(lines, line)
+				line = w
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,190 @@
+// Package pdf writes simple A4 documents (text, lines and boxes) without external dependencies.
+// Text uses the standard Helvetica fonts every PDF reader ships, encoded as WinAnsi, so Spanish and
+// Portuguese accents print but characters outside Windows-1252 are replaced with '?'.
+// Coordinates are in points (1/72 in) from the top-left corner of the page; y grows downwards.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font selects one of the built-in fonts.
+type Font int
+
+const (
+	Regular Font = iota // Helvetica
+	Bold                // Helvetica-Bold
+)
+
+// Color is an RGB colour.
+type Color struct{ R, G, B uint8 }
+
+// Common colours.
+var (
+	Black     = Color{0, 0, 0}
+	Gray      = Color{110, 110, 110}
+	LightGray = Color{225, 225, 225}
+	White     = Color{255, 255, 255}
+)
+
+// ParseHexColor reads "#RRGGBB" (or "RRGGBB").
+func ParseHexColor(s string) (Color, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return Color{}, fmt.Errorf("pdf: invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("pdf: invalid color %q", s)
+	}
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// Document is a PDF being built; draw calls go to the current page.
+type Document struct {
+	title   string
+	created time.Time
+	pages   []*bytes.Buffer
+	cur     int
+}
+
+// New starts an empty document; title goes to the document properties.
+func New(title string) *Document {
+	return &Document{title: title, created: time.Now().UTC(), cur: -1}
+}
+
+// AddPage appends a page and makes it current.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.cur = len(d.pages) - 1
+}
+
+// PageCount returns the number of pages so far.
+func (d *Document) PageCount() int { return len(d.pages) }
+
+// SetPage makes page i (0-based) current again, e.g. to add footers once the page count is known.
+func (d *Document) SetPage(i int) {
+	if i >= 0 && i < len(d.pages) {
+		d.cur = i
+	}
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if d.cur < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.cur]
+}
+
+// Text draws s with its baseline at (x, y).
+func (d *Document) Text(x, y float64, f Font, size float64, c Color, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s rg %s %s Td (%s) Tj ET\n",
+		int(f)+1, num(size), rgb(c), num(x), num(PageHeight-y), escape(encode(s)))
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y float64, f Font, size float64, c Color, s string) {
+	d.Text(x-TextWidth(s, f, size), y, f, size, c, s)
+}
+
+// Line draws a straight line.
+func (d *Document) Line(x1, y1, x2, y2, width float64, c Color) {
+	fmt.Fprintf(d.page(), "%s RG %s w %s %s m %s %s l S\n",
+		rgb(c), num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// FillRect paints a rectangle whose top-left corner is (x, y).
+func (d *Document) FillRect(x, y, w, h float64, c Color) {
+	fmt.Fprintf(d.page(), "%s rg %s %s %s %s re f\n", rgb(c), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// StrokeRect outlines a rectangle whose top-left corner is (x, y).
+func (d *Document) StrokeRect(x, y, w, h, width float64, c Color) {
+	fmt.Fprintf(d.page(), "%s RG %s w %s %s %s %s re S\n", rgb(c), num(width), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Bytes serializes the document. A document without pages gets one blank page.
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts, 5 info; then a page and its content per page.
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (gonsgarage) /CreationDate (D:%s) >>",
+		escape(encode(d.title)), d.created.Format("20060102150405Z")))
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), firstPage+2*i+1))
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		_, _ = zw.Write(p.Bytes())
+		_ = zw.Close()
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", len(offsets), z.Len())
+		out.Write(z.Bytes())
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func rgb(c Color) string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// escape writes a PDF literal string body; bytes outside printable ASCII become octal escapes.
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
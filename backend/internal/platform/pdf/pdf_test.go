@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contents inflates every page content stream of a serialized document.
+func contents(t *testing.T, doc []byte) []string {
+	t.Helper()
+	var out []string
+	re := regexp.MustCompile(`(?s)<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	for _, m := range re.FindAllSubmatchIndex(doc, -1) {
+		n, _ := strconv.Atoi(string(doc[m[2]:m[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(doc[m[1] : m[1]+n]))
+		require.NoError(t, err)
+		b, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		out = append(out, string(b))
+	}
+	return out
+}
+
+func TestDocument_Structure(t *testing.T) {
+	t.Parallel()
+	d := New("Orden (de) trabajo")
+	d.Text(40, 50, Bold, 14, Black, "Recepción — 1234ABC (€)")
+	d.Line(40, 60, 500, 60, 1, Gray)
+	d.AddPage()
+	d.FillRect(0, 0, PageWidth, 20, Color{200, 30, 30})
+	out := d.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	assert.Contains(t, string(out), `/Title (Orden \(de\) trabajo)`)
+
+	// Every xref entry points at the start of its object.
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	require.NotNil(t, m)
+	xref, _ := strconv.Atoi(string(m[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 5+2*2)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		assert.True(t, bytes.HasPrefix(out[off:], []byte(fmt.Sprintf("%d 0 obj", i+1))), "object %d", i+1)
+	}
+
+	pages := contents(t, out)
+	require.Len(t, pages, 2)
+	assert.Contains(t, pages[0], `(Recepci\363n \227 1234ABC \(\200\)) Tj`)
+	assert.Contains(t, pages[0], "/F2 14 Tf")
+	assert.Contains(t, pages[1], "re f")
+}
+
+func TestEncode_ReplacesUnsupported(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []byte{'a', 0xF1, 'o', ' ', '?'}, encode("año\t✓"))
+}
+
+func TestWrapText(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 64, len(latinBase))
+	assert.InDelta(t, 20.56, TextWidth("Hola", Regular, 10), 0.001) // (722+556+222+556) / 100
+	lines := WrapText("Cambio de pastillas y discos delanteros, revisión de líquido", Regular, 10, 120)
+	require.Greater(t, len(lines), 1)
+	for _, l := range lines {
+		assert.LessOrEqual(t, TextWidth(l, Regular, 10), 120.0, l)
+	}
+	assert.Equal(t, "Cambio de pastillas y discos delanteros, revisión de líquido", strings.Join(lines, " "))
+
+	long := WrapText("WVWZZZ1JZXW000001", Bold, 10, 40)
+	assert.Greater(t, len(long), 1)
+	assert.Equal(t, []string{"uno", "", "dos"}, WrapText("uno\n\ndos", Regular, 10, 200))
+}
+
+func TestParseHexColor(t *testing.T) {
+	t.Parallel()
+	c, err := ParseHexColor("#1F6FEB")
+	require.NoError(t, err)
+	assert.Equal(t, Color{0x1F, 0x6F, 0xEB}, c)
+	_, err = ParseHexColor("blue")
+	assert.Error(t, err)
+}
+
+func TestFlow_BreaksPages(t *testing.T) {
+	t.Parallel()
+	d := New("x")
+	headers := 0
+	f := NewFlow(d, 40, func(f *Flow) float64 { headers++; return f.Margin + 30 })
+	rows := make([][]string, 120)
+	for i := range rows {
+		rows[i] = []string{strconv.Itoa(i), "Pastillas de freno"}
+	}
+	f.Table([]Column{{Title: "#", Width: 0.2}, {Title: "Pieza", Width: 0.8}}, rows, Black)
+	assert.Greater(t, d.PageCount(), 1)
+	assert.Equal(t, d.PageCount(), headers)
+}
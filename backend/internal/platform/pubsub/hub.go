@@ -0,0 +1,69 @@
+// Package pubsub fans events out to in-process subscribers, such as server-sent event streams.
+// It is a best-effort live feed: events are not stored, and a subscriber that stops reading is
+// dropped rather than allowed to block publishers (clients reconnect and reload their view).
+package pubsub
+
+import "sync"
+
+// Event is one published message; Data is marshalled by the subscriber (JSON for SSE).
+type Event struct {
+	Type string
+	Data any
+}
+
+// Hub delivers every published event to all current subscribers.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// New returns an empty hub.
+func New() *Hub {
+	return &Hub{subs: map[chan Event]struct{}{}}
+}
+
+// Subscribe returns a channel receiving events published from now on, and a cancel function that
+// unsubscribes. The channel is closed on cancel, or when more than buffer events are waiting.
+func (h *Hub) Subscribe(buffer int) (<-chan Event, func()) {
+	if buffer < 1 {
+		buffer = 1
+	}
+	ch := make(chan Event, buffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() { h.remove(ch) }
+}
+
+// Publish delivers e to every subscriber without blocking. A nil hub ignores the call.
+func (h *Hub) Publish(e Event) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of live subscribers.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+func (h *Hub) remove(ch chan Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_FanOut(t *testing.T) {
+	h := New()
+	a, cancelA := h.Subscribe(4)
+	b, cancelB := h.Subscribe(4)
+	defer cancelB()
+
+	h.Publish(Event{Type: "x", Data: 1})
+	assert.Equal(t, Event{Type: "x", Data: 1}, <-a)
+	assert.Equal(t, Event{Type: "x", Data: 1}, <-b)
+
+	cancelA()
+	cancelA() // idempotent
+	_, open := <-a
+	assert.False(t, open)
+	assert.Equal(t, 1, h.Subscribers())
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	h := New()
+	slow, cancel := h.Subscribe(1)
+	defer cancel()
+
+	h.Publish(Event{Type: "1"})
+	h.Publish(Event{Type: "2"}) // buffer full: subscriber is dropped
+
+	e, ok := <-slow
+	require.True(t, ok)
+	assert.Equal(t, "1", e.Type)
+	_, ok = <-slow
+	assert.False(t, ok)
+	assert.Zero(t, h.Subscribers())
+}
+
+func TestHub_NilIsNoop(t *testing.T) {
+	var h *Hub
+	h.Publish(Event{Type: "x"})
+}
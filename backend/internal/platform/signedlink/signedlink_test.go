@@ -0,0 +1,46 @@
+package signedlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify_RoundTrip(t *testing.T) {
+	s := New("secret")
+	id := uuid.New()
+	tok := s.Sign("appointment-rsvp", id, time.Now().Add(time.Hour))
+
+	got, err := s.Verify("appointment-rsvp", tok)
+	require.NoError(t, err)
+	assert.Equal(t, id, got)
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	s := New("secret")
+	id := uuid.New()
+	tok := s.Sign("appointment-rsvp", id, time.Now().Add(time.Hour))
+
+	_, err := s.Verify("other-purpose", tok)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = New("different").Verify("appointment-rsvp", tok)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = s.Verify("appointment-rsvp", tok[:len(tok)-2]+"xx")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = s.Verify("appointment-rsvp", "garbage")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerify_Expired(t *testing.T) {
+	s := New("secret")
+	tok := s.Sign("appointment-rsvp", uuid.New(), time.Now().Add(-time.Second))
+
+	_, err := s.Verify("appointment-rsvp", tok)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
 )
 
-const sqlAppointmentSelectList = `SELECT id, customer_id, car_id, scheduled_at, COALESCE(notes, '') AS notes, status, service_type, created_at, updated_at, deleted_at FROM appointments`
+const sqlAppointmentSelectList = `SELECT id, customer_id, car_id, scheduled_at, COALESCE(notes, '') AS notes, status, service_type, employee_id, sequence, created_at, updated_at, deleted_at FROM appointments`
 
 // postgresAppointmentRepository implements AppointmentRepository using PostgreSQL
 type postgresAppointmentRepository struct {
@@ -38,6 +38,8 @@ type AppointmentModel struct {
 	Notes         string     `gorm:"column:notes" db:"notes"`
 	Status        string     `gorm:"column:status" db:"status"`
 	ServiceType   string     `gorm:"column:service_type" db:"service_type"`
+	EmployeeID    *uuid.UUID `gorm:"type:uuid;column:employee_id" db:"employee_id"`
+	Sequence      int        `gorm:"column:sequence;not null;default:0" db:"sequence"`
 	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at"`
 	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at"`
 	DeletedAt     *time.Time `gorm:"column:deleted_at;index" db:"deleted_at"`
@@ -62,11 +64,11 @@ func (r *postgresAppointmentRepository) Create(ctx context.Context, appointment
 
 func (r *postgresAppointmentRepository) createAppointmentSQLX(ctx context.Context, appointment *domain.Appointment) error {
 	now := time.Now().UTC()
-	const q = `INSERT INTO appointments (id, customer_id, car_id, scheduled_at, notes, status, service_type, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	const q = `INSERT INTO appointments (id, customer_id, car_id, scheduled_at, notes, status, service_type, employee_id, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 	_, err := r.sqlx.ExecContext(ctx, q,
 		appointment.ID, appointment.CustomerID, appointment.CarID, appointment.ScheduledAt.UTC(),
-		appointment.Notes, string(appointment.Status), appointment.ServiceType,
+		appointment.Notes, string(appointment.Status), appointment.ServiceType, appointment.EmployeeID,
 		now, now,
 	)
 	if err != nil {
@@ -87,6 +89,8 @@ func (r *postgresAppointmentRepository) toAppointmentModel(appointment *domain.A
 		Notes:         appointment.Notes,
 		Status:        string(appointment.Status),
 		ServiceType:   appointment.ServiceType,
+		EmployeeID:    appointment.EmployeeID,
+		Sequence:      appointment.Sequence,
 		CreatedAt:     appointment.CreatedAt,
 		UpdatedAt:     appointment.UpdatedAt,
 		DeletedAt:     appointment.DeletedAt,
@@ -103,6 +107,8 @@ func (r *postgresAppointmentRepository) toDomainAppointment(model *AppointmentMo
 		Notes:       model.Notes,
 		Status:      domain.AppointmentStatus(model.Status),
 		ServiceType: model.ServiceType,
+		EmployeeID:  model.EmployeeID,
+		Sequence:    model.Sequence,
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
 		DeletedAt:   model.DeletedAt,
@@ -135,26 +141,50 @@ func (r *postgresAppointmentRepository) GetByID(ctx context.Context, id uuid.UUI
 	return r.toDomainAppointment(&model), nil
 }
 
-// Update modifies an existing appointment in the database
+// Update modifies an existing appointment in the database and bumps its sequence.
 func (r *postgresAppointmentRepository) Update(ctx context.Context, appointment *domain.Appointment) error {
 	if r.sqlx != nil {
 		return r.updateAppointmentSQLX(ctx, appointment)
 	}
-	dbAppointment := r.toAppointmentModel(appointment)
-	if err := r.db.WithContext(ctx).Save(dbAppointment).Error; err != nil {
-		return fmt.Errorf("failed to update appointment: %w", err)
+	now := time.Now().UTC()
+	res := r.db.WithContext(ctx).Model(&AppointmentModel{}).
+		Where("id = ? AND deleted_at IS NULL", appointment.ID).
+		Updates(appointmentUpdates(appointment, now))
+	if res.Error != nil {
+		return fmt.Errorf("failed to update appointment: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrAppointmentNotFound
 	}
+	appointment.Sequence++
+	appointment.UpdatedAt = now
 	return nil
 }
 
+// appointmentUpdates is the column set written on every appointment update.
+func appointmentUpdates(appointment *domain.Appointment, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"customer_id":  appointment.CustomerID,
+		"car_id":       appointment.CarID,
+		"scheduled_at": appointment.ScheduledAt.UTC(),
+		"notes":        appointment.Notes,
+		"status":       string(appointment.Status),
+		"service_type": appointment.ServiceType,
+		"employee_id":  appointment.EmployeeID,
+		"sequence":     gorm.Expr("sequence + 1"),
+		"updated_at":   now,
+	}
+}
+
 func (r *postgresAppointmentRepository) updateAppointmentSQLX(ctx context.Context, appointment *domain.Appointment) error {
 	now := time.Now().UTC()
 	const q = `UPDATE appointments SET
-customer_id = $1, car_id = $2, scheduled_at = $3, notes = $4, status = $5, service_type = $6, updated_at = $7
-WHERE id = $8 AND deleted_at IS NULL`
+customer_id = $1, car_id = $2, scheduled_at = $3, notes = $4, status = $5, service_type = $6, employee_id = $7, updated_at = $8,
+sequence = sequence + 1
+WHERE id = $9 AND deleted_at IS NULL`
 	res, err := r.sqlx.ExecContext(ctx, q,
 		appointment.CustomerID, appointment.CarID, appointment.ScheduledAt.UTC(),
-		appointment.Notes, string(appointment.Status), appointment.ServiceType, now, appointment.ID,
+		appointment.Notes, string(appointment.Status), appointment.ServiceType, appointment.EmployeeID, now, appointment.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update appointment: %w", err)
@@ -166,6 +196,7 @@ WHERE id = $8 AND deleted_at IS NULL`
 	if n == 0 {
 		return domain.ErrAppointmentNotFound
 	}
+	appointment.Sequence++
 	appointment.UpdatedAt = now
 	return nil
 }
@@ -202,9 +233,18 @@ func (r *postgresAppointmentRepository) List(ctx context.Context, filters *ports
 		if filters.CarID != nil {
 			q = q.Where("car_id = ?", *filters.CarID)
 		}
+		if filters.EmployeeID != nil {
+			q = q.Where("employee_id = ?", *filters.EmployeeID)
+		}
 		if filters.Status != nil && *filters.Status != "" {
 			q = q.Where("status = ?", *filters.Status)
 		}
+		if filters.ScheduledFrom != nil {
+			q = q.Where("scheduled_at >= ?", filters.ScheduledFrom.UTC())
+		}
+		if filters.ScheduledTo != nil {
+			q = q.Where("scheduled_at < ?", filters.ScheduledTo.UTC())
+		}
 		return q
 	}
 
@@ -266,10 +306,22 @@ func (r *postgresAppointmentRepository) listAppointmentsSQLX(ctx context.Context
 			where = append(where, fmt.Sprintf("car_id = $%d", len(args)+1))
 			args = append(args, *filters.CarID)
 		}
+		if filters.EmployeeID != nil {
+			where = append(where, fmt.Sprintf("employee_id = $%d", len(args)+1))
+			args = append(args, *filters.EmployeeID)
+		}
 		if filters.Status != nil && *filters.Status != "" {
 			where = append(where, fmt.Sprintf("status = $%d", len(args)+1))
 			args = append(args, *filters.Status)
 		}
+		if filters.ScheduledFrom != nil {
+			where = append(where, fmt.Sprintf("scheduled_at >= $%d", len(args)+1))
+			args = append(args, filters.ScheduledFrom.UTC())
+		}
+		if filters.ScheduledTo != nil {
+			where = append(where, fmt.Sprintf("scheduled_at < $%d", len(args)+1))
+			args = append(args, filters.ScheduledTo.UTC())
+		}
 	}
 	whereSQL := strings.Join(where, " AND ")
 
@@ -348,3 +400,67 @@ func (r *postgresAppointmentRepository) CountNonCancelledBetween(ctx context.Con
 	}
 	return n, nil
 }
+
+// CreateWithinDayCap implements ports.AppointmentRepository.
+func (r *postgresAppointmentRepository) CreateWithinDayCap(ctx context.Context, appointment *domain.Appointment, dayStart, dayEnd time.Time, limit int64) error {
+	now := time.Now().UTC()
+	return r.withinDayCap(ctx, dayStart, dayEnd, limit, nil, func(tx *gorm.DB) error {
+		m := r.toAppointmentModel(appointment)
+		m.ScheduledTime = appointment.ScheduledAt.UTC()
+		m.CreatedAt, m.UpdatedAt = now, now
+		if err := tx.Create(m).Error; err != nil {
+			return fmt.Errorf("failed to create appointment: %w", err)
+		}
+		appointment.CreatedAt = now
+		appointment.UpdatedAt = now
+		return nil
+	})
+}
+
+// UpdateWithinDayCap implements ports.AppointmentRepository.
+func (r *postgresAppointmentRepository) UpdateWithinDayCap(ctx context.Context, appointment *domain.Appointment, dayStart, dayEnd time.Time, limit int64) error {
+	now := time.Now().UTC()
+	return r.withinDayCap(ctx, dayStart, dayEnd, limit, &appointment.ID, func(tx *gorm.DB) error {
+		res := tx.Model(&AppointmentModel{}).
+			Where("id = ? AND deleted_at IS NULL", appointment.ID).
+			Updates(appointmentUpdates(appointment, now))
+		if res.Error != nil {
+			return fmt.Errorf("failed to update appointment: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrAppointmentNotFound
+		}
+		appointment.Sequence++
+		appointment.UpdatedAt = now
+		return nil
+	})
+}
+
+// withinDayCap runs write in a transaction after taking the day's lock row and re-counting the day.
+// The upsert blocks other transactions on the same row until commit (a row lock on PostgreSQL, the
+// write lock on SQLite), and the count that follows sees every booking committed before it.
+func (r *postgresAppointmentRepository) withinDayCap(ctx context.Context, dayStart, dayEnd time.Time, limit int64, excludeID *uuid.UUID, write func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO appointment_day_locks (day, locked_at) VALUES (?, ?)
+ON CONFLICT (day) DO UPDATE SET locked_at = excluded.locked_at`,
+			dayStart.UTC().Format(time.RFC3339), time.Now().UTC()).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock appointment day: %w", err)
+		}
+
+		q := tx.Model(&AppointmentModel{}).
+			Where("deleted_at IS NULL AND status <> ?", string(domain.AppointmentStatusCancelled)).
+			Where("scheduled_at >= ? AND scheduled_at < ?", dayStart.UTC(), dayEnd.UTC())
+		if excludeID != nil {
+			q = q.Where("id <> ?", *excludeID)
+		}
+		var n int64
+		if err := q.Count(&n).Error; err != nil {
+			return fmt.Errorf("failed to count appointments: %w", err)
+		}
+		if n >= limit {
+			return domain.ErrAppointmentDailyCapReached
+		}
+		return write(tx)
+	})
+}
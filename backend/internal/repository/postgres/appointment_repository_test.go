@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type AppointmentRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.AppointmentRepository
+}
+
+func (suite *AppointmentRepositoryTestSuite) SetupSuite() {
+	// A file database with a busy timeout: concurrent transactions need separate connections
+	// that wait for the write lock instead of failing with SQLITE_BUSY.
+	dsn := filepath.Join(suite.T().TempDir(), "appointments.db") + "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&AppointmentModel{}, &domain.AppointmentDayLock{}))
+	suite.db = db
+	suite.repo = NewPostgresAppointmentRepository(db)
+}
+
+func (suite *AppointmentRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM appointments")
+	suite.db.Exec("DELETE FROM appointment_day_locks")
+}
+
+func (suite *AppointmentRepositoryTestSuite) newAppointment(at time.Time) *domain.Appointment {
+	return &domain.Appointment{
+		ID:          uuid.New(),
+		CustomerID:  uuid.New(),
+		CarID:       uuid.New(),
+		ServiceType: "inspection",
+		Status:      domain.AppointmentStatusScheduled,
+		ScheduledAt: at,
+	}
+}
+
+func (suite *AppointmentRepositoryTestSuite) TestCreateWithinDayCap_ConcurrentBookings() {
+	ctx := context.Background()
+	dayStart := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	dayEnd := dayStart.Add(24 * time.Hour)
+	const limit, workers = 8, 24
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		booked  int
+		refused int
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a := suite.newAppointment(dayStart.Add(9*time.Hour + time.Duration(i)*time.Minute))
+			<-start
+			err := suite.repo.CreateWithinDayCap(ctx, a, dayStart, dayEnd, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				booked++
+			case domain.ErrAppointmentDailyCapReached:
+				refused++
+			default:
+				suite.T().Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(suite.T(), limit, booked)
+	assert.Equal(suite.T(), workers-limit, refused)
+	n, err := suite.repo.CountNonCancelledBetween(ctx, dayStart, dayEnd, nil)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(limit), n)
+}
+
+func (suite *AppointmentRepositoryTestSuite) TestCreateWithinDayCap_IgnoresCancelledAndOtherDays() {
+	ctx := context.Background()
+	dayStart := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	dayEnd := dayStart.Add(24 * time.Hour)
+
+	cancelled := suite.newAppointment(dayStart.Add(10 * time.Hour))
+	cancelled.Status = domain.AppointmentStatusCancelled
+	require.NoError(suite.T(), suite.repo.Create(ctx, cancelled))
+	require.NoError(suite.T(), suite.repo.Create(ctx, suite.newAppointment(dayEnd.Add(10*time.Hour))))
+
+	require.NoError(suite.T(), suite.repo.CreateWithinDayCap(ctx, suite.newAppointment(dayStart.Add(11*time.Hour)), dayStart, dayEnd, 1))
+	err := suite.repo.CreateWithinDayCap(ctx, suite.newAppointment(dayStart.Add(12*time.Hour)), dayStart, dayEnd, 1)
+	assert.ErrorIs(suite.T(), err, domain.ErrAppointmentDailyCapReached)
+}
+
+func (suite *AppointmentRepositoryTestSuite) TestUpdateWithinDayCap() {
+	ctx := context.Background()
+	day1 := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	full := suite.newAppointment(day2.Add(10 * time.Hour))
+	require.NoError(suite.T(), suite.repo.Create(ctx, full))
+	moving := suite.newAppointment(day1.Add(10 * time.Hour))
+	require.NoError(suite.T(), suite.repo.Create(ctx, moving))
+
+	// The appointment itself does not count against its own day.
+	moving.Notes = "same day"
+	require.NoError(suite.T(), suite.repo.UpdateWithinDayCap(ctx, moving, day1, day2, 1))
+
+	moving.ScheduledAt = day2.Add(11 * time.Hour)
+	err := suite.repo.UpdateWithinDayCap(ctx, moving, day2, day2.Add(24*time.Hour), 1)
+	assert.ErrorIs(suite.T(), err, domain.ErrAppointmentDailyCapReached)
+
+	got, err := suite.repo.GetByID(ctx, moving.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "same day", got.Notes)
+	assert.True(suite.T(), got.ScheduledAt.Equal(day1.Add(10*time.Hour)))
+
+	missing := suite.newAppointment(day1.Add(9 * time.Hour))
+	assert.ErrorIs(suite.T(), suite.repo.UpdateWithinDayCap(ctx, missing, day1, day2, 8), domain.ErrAppointmentNotFound)
+}
+
+func (suite *AppointmentRepositoryTestSuite) TestUpdateBumpsSequence() {
+	ctx := context.Background()
+	day := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	a := suite.newAppointment(day.Add(10 * time.Hour))
+	require.NoError(suite.T(), suite.repo.Create(ctx, a))
+
+	a.Notes = "first"
+	require.NoError(suite.T(), suite.repo.Update(ctx, a))
+	a.Notes = "second"
+	require.NoError(suite.T(), suite.repo.UpdateWithinDayCap(ctx, a, day, day.Add(24*time.Hour), 8))
+	assert.Equal(suite.T(), 2, a.Sequence)
+
+	got, err := suite.repo.GetByID(ctx, a.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, got.Sequence)
+	assert.Equal(suite.T(), "second", got.Notes)
+
+	assert.ErrorIs(suite.T(), suite.repo.Update(ctx, suite.newAppointment(day)), domain.ErrAppointmentNotFound)
+}
+
+func TestAppointmentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AppointmentRepositoryTestSuite))
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresCalendarFeedKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresCalendarFeedKeyRepository returns a CalendarFeedKeyRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresCalendarFeedKeyRepository(db *gorm.DB) ports.CalendarFeedKeyRepository {
+	return &postgresCalendarFeedKeyRepository{db: db}
+}
+
+func (r *postgresCalendarFeedKeyRepository) Ensure(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error) {
+	k := &domain.CalendarFeedKey{UserID: userID, Key: uuid.New(), RotatedAt: time.Now().UTC()}
+	// A concurrent first use keeps whichever key was stored first.
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(k).Error; err != nil {
+		return nil, fmt.Errorf("failed to create calendar feed key: %w", err)
+	}
+	var got domain.CalendarFeedKey
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&got).Error; err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed key: %w", err)
+	}
+	return &got, nil
+}
+
+func (r *postgresCalendarFeedKeyRepository) GetByKey(ctx context.Context, key uuid.UUID) (*domain.CalendarFeedKey, error) {
+	var got domain.CalendarFeedKey
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&got).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCalendarFeedInvalid
+		}
+		return nil, fmt.Errorf("failed to get calendar feed key: %w", err)
+	}
+	return &got, nil
+}
+
+func (r *postgresCalendarFeedKeyRepository) Rotate(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error) {
+	k := &domain.CalendarFeedKey{UserID: userID, Key: uuid.New(), RotatedAt: time.Now().UTC()}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"key", "rotated_at"}),
+	}).Create(k).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate calendar feed key: %w", err)
+	}
+	return k, nil
+}
+
+var _ ports.CalendarFeedKeyRepository = (*postgresCalendarFeedKeyRepository)(nil)
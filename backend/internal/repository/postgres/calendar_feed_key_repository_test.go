@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type CalendarFeedKeyRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.CalendarFeedKeyRepository
+}
+
+func (suite *CalendarFeedKeyRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.CalendarFeedKey{}))
+	suite.db = db
+	suite.repo = NewPostgresCalendarFeedKeyRepository(db)
+}
+
+func (suite *CalendarFeedKeyRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM calendar_feed_keys")
+}
+
+func (suite *CalendarFeedKeyRepositoryTestSuite) TestEnsureAndRotate() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	first, err := suite.repo.Ensure(ctx, userID)
+	require.NoError(suite.T(), err)
+	again, err := suite.repo.Ensure(ctx, userID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), first.Key, again.Key)
+
+	got, err := suite.repo.GetByKey(ctx, first.Key)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, got.UserID)
+
+	rotated, err := suite.repo.Rotate(ctx, userID)
+	require.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), first.Key, rotated.Key)
+	_, err = suite.repo.GetByKey(ctx, first.Key)
+	assert.ErrorIs(suite.T(), err, domain.ErrCalendarFeedInvalid)
+	got, err = suite.repo.GetByKey(ctx, rotated.Key)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, got.UserID)
+
+	current, err := suite.repo.Ensure(ctx, userID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), rotated.Key, current.Key)
+}
+
+func TestCalendarFeedKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarFeedKeyRepositoryTestSuite))
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresChecklistTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresChecklistTemplateRepository returns a ChecklistTemplateRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresChecklistTemplateRepository(db *gorm.DB) ports.ChecklistTemplateRepository {
+	return &postgresChecklistTemplateRepository{db: db}
+}
+
+func (r *postgresChecklistTemplateRepository) Create(ctx context.Context, t *domain.ChecklistTemplate) error {
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
+		// Two editors saved the same next version: the unique index keeps the first.
+		var n int64
+		if xerr := r.db.WithContext(ctx).Model(&domain.ChecklistTemplate{}).
+			Where("code = ? AND kind = ? AND version = ?", t.Code, t.Kind, t.Version).
+			Count(&n).Error; xerr == nil && n > 0 {
+			return domain.ErrChecklistVersionConflict
+		}
+		return fmt.Errorf("failed to create checklist template: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresChecklistTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ChecklistTemplate, error) {
+	var row domain.ChecklistTemplate
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrChecklistTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresChecklistTemplateRepository) GetLatest(ctx context.Context, code string, kind domain.ChecklistKind) (*domain.ChecklistTemplate, error) {
+	var row domain.ChecklistTemplate
+	err := r.db.WithContext(ctx).
+		Where("code = ? AND kind = ?", code, kind).
+		Order("version DESC").
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresChecklistTemplateRepository) ListLatest(ctx context.Context, kind domain.ChecklistKind) ([]*domain.ChecklistTemplate, error) {
+	limit, _ := clampRepoList(500, 0)
+	q := r.db.WithContext(ctx).
+		Where("version = (SELECT MAX(t2.version) FROM checklist_templates t2 WHERE t2.code = checklist_templates.code AND t2.kind = checklist_templates.kind)")
+	if kind != "" {
+		q = q.Where("kind = ?", kind)
+	}
+	var rows []*domain.ChecklistTemplate
+	if err := q.Order("code ASC, kind ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list checklist templates: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.ChecklistTemplate{}
+	}
+	return rows, nil
+}
+
+func (r *postgresChecklistTemplateRepository) ListVersions(ctx context.Context, code string, kind domain.ChecklistKind) ([]*domain.ChecklistTemplate, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.ChecklistTemplate
+	err := r.db.WithContext(ctx).
+		Where("code = ? AND kind = ?", code, kind).
+		Order("version DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklist template versions: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.ChecklistTemplate{}
+	}
+	return rows, nil
+}
+
+var _ ports.ChecklistTemplateRepository = (*postgresChecklistTemplateRepository)(nil)
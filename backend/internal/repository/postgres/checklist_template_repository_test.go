@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type ChecklistTemplateRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.ChecklistTemplateRepository
+	jobs ports.ServiceJobRepository
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.ChecklistTemplate{}, &domain.ServiceJobReception{}))
+	suite.db = db
+	suite.repo = NewPostgresChecklistTemplateRepository(db)
+	suite.jobs = NewPostgresServiceJobRepository(db)
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM checklist_templates")
+	suite.db.Exec("DELETE FROM service_job_receptions")
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) create(code string, kind domain.ChecklistKind, version int) *domain.ChecklistTemplate {
+	t := &domain.ChecklistTemplate{
+		ID:              uuid.New(),
+		Code:            code,
+		Kind:            kind,
+		Version:         version,
+		Name:            code,
+		Items:           []domain.ChecklistItem{{Key: "k", Label: "K", Type: domain.ChecklistItemText}},
+		CreatedByUserID: uuid.New(),
+	}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), t))
+	return t
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) TestLatestAndVersions() {
+	ctx := context.Background()
+	suite.create("ev", domain.ChecklistKindReception, 1)
+	ev2 := suite.create("ev", domain.ChecklistKindReception, 2)
+	moto := suite.create("motorbike", domain.ChecklistKindReception, 1)
+	evOut := suite.create("ev", domain.ChecklistKindHandover, 1)
+
+	latest, err := suite.repo.GetLatest(ctx, "ev", domain.ChecklistKindReception)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ev2.ID, latest.ID)
+	assert.Equal(suite.T(), ev2.Items, latest.Items)
+
+	none, err := suite.repo.GetLatest(ctx, "pre_itv", domain.ChecklistKindReception)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), none)
+
+	list, err := suite.repo.ListLatest(ctx, domain.ChecklistKindReception)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 2)
+	assert.Equal(suite.T(), ev2.ID, list[0].ID)
+	assert.Equal(suite.T(), moto.ID, list[1].ID)
+
+	all, err := suite.repo.ListLatest(ctx, "")
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), all, 3)
+	assert.Contains(suite.T(), []uuid.UUID{all[0].ID, all[1].ID}, evOut.ID)
+
+	versions, err := suite.repo.ListVersions(ctx, "ev", domain.ChecklistKindReception)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), versions, 2)
+	assert.Equal(suite.T(), 2, versions[0].Version)
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) TestCreate_VersionConflict() {
+	suite.create("ev", domain.ChecklistKindReception, 1)
+	dup := &domain.ChecklistTemplate{ID: uuid.New(), Code: "ev", Kind: domain.ChecklistKindReception, Version: 1, Name: "ev", CreatedByUserID: uuid.New()}
+	assert.ErrorIs(suite.T(), suite.repo.Create(context.Background(), dup), domain.ErrChecklistVersionConflict)
+
+	_, err := suite.repo.GetByID(context.Background(), uuid.New())
+	assert.ErrorIs(suite.T(), err, domain.ErrChecklistTemplateNotFound)
+}
+
+func (suite *ChecklistTemplateRepositoryTestSuite) TestReceptionStoresAnswers() {
+	ctx := context.Background()
+	tmpl := suite.create("ev", domain.ChecklistKindReception, 1)
+	jobID := uuid.New()
+	rec := &domain.ServiceJobReception{
+		ServiceJobID:        jobID,
+		OdometerKM:          100,
+		RecordedByUserID:    uuid.New(),
+		RecordedAt:          time.Now().UTC(),
+		SchemaVersion:       domain.ChecklistSchemaTemplate,
+		ChecklistTemplateID: &tmpl.ID,
+		ChecklistAnswers:    []domain.ChecklistAnswer{{Key: "k", Text: "first"}},
+	}
+	require.NoError(suite.T(), suite.jobs.SaveReception(ctx, rec, nil))
+
+	rec.ChecklistAnswers = []domain.ChecklistAnswer{{Key: "k", Text: "second"}}
+	require.NoError(suite.T(), suite.jobs.SaveReception(ctx, rec, nil))
+
+	got, err := suite.jobs.GetReception(ctx, jobID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ChecklistSchemaTemplate, got.SchemaVersion)
+	assert.Equal(suite.T(), tmpl.ID, *got.ChecklistTemplateID)
+	assert.Equal(suite.T(), "second", got.ChecklistAnswers[0].Text)
+}
+
+func TestChecklistTemplateRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ChecklistTemplateRepositoryTestSuite))
+}
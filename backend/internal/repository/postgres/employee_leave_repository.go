@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresEmployeeLeaveRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresEmployeeLeaveRepository returns an EmployeeLeaveRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresEmployeeLeaveRepository(db *gorm.DB) ports.EmployeeLeaveRepository {
+	return &postgresEmployeeLeaveRepository{db: db}
+}
+
+func (r *postgresEmployeeLeaveRepository) Create(ctx context.Context, leave *domain.EmployeeLeave) error {
+	if err := r.db.WithContext(ctx).Create(leave).Error; err != nil {
+		return fmt.Errorf("failed to create employee leave: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresEmployeeLeaveRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.EmployeeLeave, error) {
+	var row domain.EmployeeLeave
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrEmployeeLeaveNotFound
+		}
+		return nil, fmt.Errorf("failed to get employee leave: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresEmployeeLeaveRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.EmployeeLeave{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete employee leave: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrEmployeeLeaveNotFound
+	}
+	return nil
+}
+
+func (r *postgresEmployeeLeaveRepository) ListOverlapping(ctx context.Context, userID *uuid.UUID, start, end time.Time) ([]*domain.EmployeeLeave, error) {
+	q := r.db.WithContext(ctx).Where("starts_at < ? AND ends_at > ?", end.UTC(), start.UTC())
+	if userID != nil {
+		q = q.Where("user_id = ?", *userID)
+	}
+	var rows []*domain.EmployeeLeave
+	if err := q.Order("starts_at ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list employee leaves: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.EmployeeLeave{}
+	}
+	return rows, nil
+}
+
+var _ ports.EmployeeLeaveRepository = (*postgresEmployeeLeaveRepository)(nil)
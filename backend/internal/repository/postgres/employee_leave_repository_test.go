@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type EmployeeLeaveRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.EmployeeLeaveRepository
+}
+
+func (suite *EmployeeLeaveRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.EmployeeLeave{}))
+	suite.db = db
+	suite.repo = NewPostgresEmployeeLeaveRepository(db)
+}
+
+func (suite *EmployeeLeaveRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM employee_leaves")
+}
+
+func (suite *EmployeeLeaveRepositoryTestSuite) TestListOverlapping() {
+	ctx := context.Background()
+	userID := uuid.New()
+	day := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	leave := &domain.EmployeeLeave{ID: uuid.New(), UserID: userID, StartsAt: day, EndsAt: day.Add(48 * time.Hour), CreatedByUserID: uuid.New()}
+	require.NoError(suite.T(), suite.repo.Create(ctx, leave))
+
+	got, err := suite.repo.ListOverlapping(ctx, &userID, day.Add(10*time.Hour), day.Add(11*time.Hour))
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), got, 1)
+
+	other := uuid.New()
+	got, err = suite.repo.ListOverlapping(ctx, &other, day.Add(10*time.Hour), day.Add(11*time.Hour))
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), got, 0)
+
+	got, err = suite.repo.ListOverlapping(ctx, nil, day.Add(48*time.Hour), day.Add(49*time.Hour))
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), got, 0, "end is exclusive")
+}
+
+func (suite *EmployeeLeaveRepositoryTestSuite) TestDelete() {
+	ctx := context.Background()
+	leave := &domain.EmployeeLeave{ID: uuid.New(), UserID: uuid.New(), StartsAt: time.Now(), EndsAt: time.Now().Add(time.Hour), CreatedByUserID: uuid.New()}
+	require.NoError(suite.T(), suite.repo.Create(ctx, leave))
+	require.NoError(suite.T(), suite.repo.Delete(ctx, leave.ID))
+	_, err := suite.repo.GetByID(ctx, leave.ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrEmployeeLeaveNotFound)
+	assert.ErrorIs(suite.T(), suite.repo.Delete(ctx, leave.ID), domain.ErrEmployeeLeaveNotFound)
+}
+
+func TestEmployeeLeaveRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(EmployeeLeaveRepositoryTestSuite))
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
 )
 
-const sqlSelectEmployeeBase = `SELECT id, user_id, employee_code, position, department, hire_date, salary, hours_per_week, is_active, created_at, updated_at, deleted_at
+const sqlSelectEmployeeBase = `SELECT id, user_id, employee_code, position, department, hire_date, salary, hours_per_week, COALESCE(hours_worked, 0) AS hours_worked, is_active, created_at, updated_at, deleted_at
 FROM employees WHERE deleted_at IS NULL`
 
 // PostgresEmployeeRepository implements EmployeeRepository interface
@@ -374,6 +374,7 @@ func (r *PostgresEmployeeRepository) toDomainEmployee(dbEmployee *EmployeeModel)
 		HireDate:     dbEmployee.HireDate,
 		Salary:       dbEmployee.Salary,
 		HoursPerWeek: dbEmployee.HoursPerWeek,
+		HoursWorked:  dbEmployee.HoursWorked,
 		IsActive:     dbEmployee.IsActive,
 		CreatedAt:    dbEmployee.CreatedAt,
 		UpdatedAt:    dbEmployee.UpdatedAt,
@@ -403,6 +404,7 @@ type EmployeeModel struct {
 	HireDate     time.Time  `gorm:"not null" db:"hire_date"`
 	Salary       float64    `gorm:"type:decimal(10,2)" db:"salary"`
 	HoursPerWeek int        `gorm:"default:40" db:"hours_per_week"`
+	HoursWorked  float64    `gorm:"default:0" db:"hours_worked"` // kept by technician clock-out, not by Update
 	IsActive     bool       `gorm:"default:true;index" db:"is_active"`
 	CreatedAt    time.Time  `gorm:"autoCreateTime" db:"created_at"`
 	UpdatedAt    time.Time  `gorm:"autoUpdateTime" db:"updated_at"`
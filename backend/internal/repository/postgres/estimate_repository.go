@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresEstimateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresEstimateRepository returns an EstimateRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresEstimateRepository(db *gorm.DB) ports.EstimateRepository {
+	return &postgresEstimateRepository{db: db}
+}
+
+func (r *postgresEstimateRepository) Create(ctx context.Context, e *domain.Estimate) error {
+	if err := r.db.WithContext(ctx).Create(e).Error; err != nil {
+		return fmt.Errorf("failed to create estimate: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresEstimateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Estimate, error) {
+	var row domain.Estimate
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", id).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrEstimateNotFound
+		}
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresEstimateRepository) ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.Estimate, error) {
+	limit, _ := clampRepoList(100, 0)
+	var rows []*domain.Estimate
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("service_job_id = ?", serviceJobID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list estimates: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.Estimate{}
+	}
+	return rows, nil
+}
+
+func (r *postgresEstimateRepository) Save(ctx context.Context, e *domain.Estimate, from domain.EstimateStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		e.UpdatedAt = time.Now().UTC()
+		res := tx.Model(&domain.Estimate{}).
+			Where("id = ? AND status = ?", e.ID, from).
+			Updates(map[string]interface{}{
+				"status":             e.Status,
+				"notes":              e.Notes,
+				"sent_at":            e.SentAt,
+				"decided_at":         e.DecidedAt,
+				"decided_by_user_id": e.DecidedByUserID,
+				"decided_via":        e.DecidedVia,
+				"decision_ip":        e.DecisionIP,
+				"updated_at":         e.UpdatedAt,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update estimate: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			var n int64
+			if err := tx.Model(&domain.Estimate{}).Where("id = ?", e.ID).Count(&n).Error; err != nil {
+				return fmt.Errorf("failed to update estimate: %w", err)
+			}
+			if n == 0 {
+				return domain.ErrEstimateNotFound
+			}
+			return domain.ErrEstimateStatusConflict
+		}
+		if err := tx.Where("estimate_id = ?", e.ID).Delete(&domain.EstimateLine{}).Error; err != nil {
+			return fmt.Errorf("failed to replace estimate lines: %w", err)
+		}
+		if len(e.Lines) == 0 {
+			return nil
+		}
+		if err := tx.Omit(clause.Associations).Create(&e.Lines).Error; err != nil {
+			return fmt.Errorf("failed to replace estimate lines: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *postgresEstimateRepository) HasApprovedLine(ctx context.Context, repairID uuid.UUID) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&domain.EstimateLine{}).
+		Joins("JOIN estimates ON estimates.id = estimate_lines.estimate_id").
+		Where("estimate_lines.repair_id = ? AND estimate_lines.approved = ? AND estimates.status IN ?", repairID, true,
+			[]domain.EstimateStatus{domain.EstimateStatusApproved, domain.EstimateStatusPartiallyApproved}).
+		Count(&n).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check estimate approval: %w", err)
+	}
+	return n > 0, nil
+}
+
+var _ ports.EstimateRepository = (*postgresEstimateRepository)(nil)
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type EstimateRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.EstimateRepository
+}
+
+func (suite *EstimateRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.Estimate{}, &domain.EstimateLine{}))
+	suite.db = db
+	suite.repo = NewPostgresEstimateRepository(db)
+}
+
+func (suite *EstimateRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM estimate_lines")
+	suite.db.Exec("DELETE FROM estimates")
+}
+
+func (suite *EstimateRepositoryTestSuite) create(jobID uuid.UUID, repairID *uuid.UUID) *domain.Estimate {
+	id := uuid.New()
+	e := &domain.Estimate{
+		ID:              id,
+		ServiceJobID:    jobID,
+		Status:          domain.EstimateStatusDraft,
+		CreatedByUserID: uuid.New(),
+		Lines: []domain.EstimateLine{
+			{ID: uuid.New(), EstimateID: id, Position: 2, Kind: domain.EstimateLinePart, Description: "Filtro", Quantity: 1, UnitPrice: 12},
+			{ID: uuid.New(), EstimateID: id, Position: 1, Kind: domain.EstimateLineLabour, Description: "Mano de obra", Quantity: 0.5, UnitPrice: 40, RepairID: repairID},
+		},
+	}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), e))
+	return e
+}
+
+func (suite *EstimateRepositoryTestSuite) TestCreateGetList() {
+	ctx := context.Background()
+	jobID := uuid.New()
+	e := suite.create(jobID, nil)
+	suite.create(uuid.New(), nil)
+
+	got, err := suite.repo.GetByID(ctx, e.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), got.Lines, 2)
+	assert.Equal(suite.T(), "Mano de obra", got.Lines[0].Description)
+	assert.InDelta(suite.T(), 32.0, got.Total(), 0.001)
+
+	list, err := suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 1)
+	assert.Len(suite.T(), list[0].Lines, 2)
+
+	_, err = suite.repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(suite.T(), err, domain.ErrEstimateNotFound)
+}
+
+func (suite *EstimateRepositoryTestSuite) TestSaveOnlyFromExpectedStatus() {
+	ctx := context.Background()
+	e := suite.create(uuid.New(), nil)
+	e.Lines = e.Lines[:1]
+	e.Lines[0].Description = "Filtro de aceite"
+	now := time.Now().UTC()
+	e.Status = domain.EstimateStatusSent
+	e.SentAt = &now
+	require.NoError(suite.T(), suite.repo.Save(ctx, e, domain.EstimateStatusDraft))
+
+	got, err := suite.repo.GetByID(ctx, e.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.EstimateStatusSent, got.Status)
+	require.Len(suite.T(), got.Lines, 1)
+	assert.Equal(suite.T(), "Filtro de aceite", got.Lines[0].Description)
+
+	// A second sender still expecting a draft loses.
+	assert.ErrorIs(suite.T(), suite.repo.Save(ctx, e, domain.EstimateStatusDraft), domain.ErrEstimateStatusConflict)
+	e.ID = uuid.New()
+	assert.ErrorIs(suite.T(), suite.repo.Save(ctx, e, domain.EstimateStatusDraft), domain.ErrEstimateNotFound)
+}
+
+func (suite *EstimateRepositoryTestSuite) TestHasApprovedLine() {
+	ctx := context.Background()
+	repairID := uuid.New()
+	e := suite.create(uuid.New(), &repairID)
+
+	ok, err := suite.repo.HasApprovedLine(ctx, repairID)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	e.Status = domain.EstimateStatusSent
+	require.NoError(suite.T(), suite.repo.Save(ctx, e, domain.EstimateStatusDraft))
+	require.NoError(suite.T(), e.ApplyDecision([]uuid.UUID{e.Lines[0].ID})) // the part line only
+	require.NoError(suite.T(), suite.repo.Save(ctx, e, domain.EstimateStatusSent))
+	ok, err = suite.repo.HasApprovedLine(ctx, repairID)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	other := suite.create(uuid.New(), &repairID)
+	other.Status = domain.EstimateStatusSent
+	require.NoError(suite.T(), suite.repo.Save(ctx, other, domain.EstimateStatusDraft))
+	require.NoError(suite.T(), other.ApplyDecision([]uuid.UUID{other.Lines[1].ID}))
+	require.NoError(suite.T(), suite.repo.Save(ctx, other, domain.EstimateStatusSent))
+	ok, err = suite.repo.HasApprovedLine(ctx, repairID)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+}
+
+func TestEstimateRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(EstimateRepositoryTestSuite))
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresInspectionFindingRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresInspectionFindingRepository returns an InspectionFindingRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresInspectionFindingRepository(db *gorm.DB) ports.InspectionFindingRepository {
+	return &postgresInspectionFindingRepository{db: db}
+}
+
+func (r *postgresInspectionFindingRepository) Create(ctx context.Context, f *domain.InspectionFinding) error {
+	if err := r.db.WithContext(ctx).Create(f).Error; err != nil {
+		return fmt.Errorf("failed to create inspection finding: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresInspectionFindingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.InspectionFinding, error) {
+	var row domain.InspectionFinding
+	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInspectionFindingNotFound
+		}
+		return nil, fmt.Errorf("failed to get inspection finding: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresInspectionFindingRepository) ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.InspectionFinding, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.InspectionFinding
+	err := r.db.WithContext(ctx).
+		Where("service_job_id = ? AND deleted_at IS NULL", serviceJobID).
+		Order("CASE severity WHEN 'red' THEN 0 WHEN 'amber' THEN 1 ELSE 2 END, created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspection findings: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.InspectionFinding{}
+	}
+	return rows, nil
+}
+
+func (r *postgresInspectionFindingRepository) Update(ctx context.Context, f *domain.InspectionFinding) error {
+	res := r.db.WithContext(ctx).Model(&domain.InspectionFinding{}).
+		Where("id = ? AND deleted_at IS NULL", f.ID).
+		Updates(map[string]interface{}{
+			"severity":       f.Severity,
+			"description":    f.Description,
+			"photo_url":      f.PhotoURL,
+			"estimated_cost": f.EstimatedCost,
+			"updated_at":     time.Now().UTC(),
+		})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update inspection finding: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrInspectionFindingNotFound
+	}
+	return nil
+}
+
+func (r *postgresInspectionFindingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&domain.InspectionFinding{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", time.Now().UTC())
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete inspection finding: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrInspectionFindingNotFound
+	}
+	return nil
+}
+
+func (r *postgresInspectionFindingRepository) SetRepair(ctx context.Context, id uuid.UUID, repairID *uuid.UUID, onlyIfUnset bool) (bool, error) {
+	q := r.db.WithContext(ctx).Model(&domain.InspectionFinding{}).Where("id = ? AND deleted_at IS NULL", id)
+	if onlyIfUnset {
+		q = q.Where("repair_id IS NULL")
+	}
+	res := q.Updates(map[string]interface{}{"repair_id": repairID, "updated_at": time.Now().UTC()})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to link repair to inspection finding: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+var _ ports.InspectionFindingRepository = (*postgresInspectionFindingRepository)(nil)
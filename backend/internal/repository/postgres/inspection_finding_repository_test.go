@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type InspectionFindingRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.InspectionFindingRepository
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.InspectionFinding{}))
+	suite.db = db
+	suite.repo = NewPostgresInspectionFindingRepository(db)
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM inspection_findings")
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) create(jobID uuid.UUID, sev domain.FindingSeverity, created time.Time) *domain.InspectionFinding {
+	f := &domain.InspectionFinding{
+		ID:               uuid.New(),
+		ServiceJobID:     jobID,
+		Severity:         sev,
+		Description:      string(sev),
+		RecordedByUserID: uuid.New(),
+		CreatedAt:        created,
+	}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), f))
+	return f
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) TestListOrdersBySeverity() {
+	ctx := context.Background()
+	jobID := uuid.New()
+	t0 := time.Now().UTC().Truncate(time.Second)
+	green := suite.create(jobID, domain.FindingSeverityGreen, t0)
+	red2 := suite.create(jobID, domain.FindingSeverityRed, t0.Add(2*time.Minute))
+	amber := suite.create(jobID, domain.FindingSeverityAmber, t0)
+	red1 := suite.create(jobID, domain.FindingSeverityRed, t0.Add(time.Minute))
+	suite.create(uuid.New(), domain.FindingSeverityRed, t0)
+
+	list, err := suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	var ids []uuid.UUID
+	for _, f := range list {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(suite.T(), []uuid.UUID{red1.ID, red2.ID, amber.ID, green.ID}, ids)
+
+	require.NoError(suite.T(), suite.repo.Delete(ctx, amber.ID))
+	list, err = suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), list, 3)
+	_, err = suite.repo.GetByID(ctx, amber.ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrInspectionFindingNotFound)
+	assert.ErrorIs(suite.T(), suite.repo.Delete(ctx, amber.ID), domain.ErrInspectionFindingNotFound)
+
+	empty, err := suite.repo.ListByServiceJob(ctx, uuid.New())
+	require.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), empty)
+	assert.Empty(suite.T(), empty)
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) TestUpdate() {
+	ctx := context.Background()
+	f := suite.create(uuid.New(), domain.FindingSeverityAmber, time.Now().UTC())
+	cost := 120.5
+	f.Severity = domain.FindingSeverityRed
+	f.Description = "Pastillas al límite"
+	f.EstimatedCost = &cost
+	require.NoError(suite.T(), suite.repo.Update(ctx, f))
+
+	got, err := suite.repo.GetByID(ctx, f.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.FindingSeverityRed, got.Severity)
+	assert.Equal(suite.T(), "Pastillas al límite", got.Description)
+	require.NotNil(suite.T(), got.EstimatedCost)
+	assert.InDelta(suite.T(), 120.5, *got.EstimatedCost, 0.001)
+
+	f.ID = uuid.New()
+	assert.ErrorIs(suite.T(), suite.repo.Update(ctx, f), domain.ErrInspectionFindingNotFound)
+}
+
+func (suite *InspectionFindingRepositoryTestSuite) TestSetRepairOnlyOnce() {
+	ctx := context.Background()
+	f := suite.create(uuid.New(), domain.FindingSeverityRed, time.Now().UTC())
+	first, second := uuid.New(), uuid.New()
+
+	ok, err := suite.repo.SetRepair(ctx, f.ID, &first, true)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	ok, err = suite.repo.SetRepair(ctx, f.ID, &second, true)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	got, err := suite.repo.GetByID(ctx, f.ID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), got.RepairID)
+	assert.Equal(suite.T(), first, *got.RepairID)
+
+	ok, err = suite.repo.SetRepair(ctx, f.ID, nil, false)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	got, err = suite.repo.GetByID(ctx, f.ID)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), got.RepairID)
+}
+
+func TestInspectionFindingRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InspectionFindingRepositoryTestSuite))
+}
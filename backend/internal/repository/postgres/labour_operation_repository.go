@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresLabourOperationRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresLabourOperationRepository returns a LabourOperationRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresLabourOperationRepository(db *gorm.DB) ports.LabourOperationRepository {
+	return &postgresLabourOperationRepository{db: db}
+}
+
+func (r *postgresLabourOperationRepository) Create(ctx context.Context, op *domain.LabourOperation) error {
+	if err := r.db.WithContext(ctx).Create(op).Error; err != nil {
+		return fmt.Errorf("failed to create labour operation: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresLabourOperationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LabourOperation, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *postgresLabourOperationRepository) GetByCode(ctx context.Context, code string) (*domain.LabourOperation, error) {
+	return r.first(ctx, "code = ?", strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func (r *postgresLabourOperationRepository) first(ctx context.Context, cond string, arg interface{}) (*domain.LabourOperation, error) {
+	var op domain.LabourOperation
+	err := r.db.WithContext(ctx).Preload("Vehicles").Preload("Parts").Where(cond, arg).First(&op).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLabourOperationNotFound
+		}
+		return nil, fmt.Errorf("failed to get labour operation: %w", err)
+	}
+	return &op, nil
+}
+
+func (r *postgresLabourOperationRepository) Update(ctx context.Context, op *domain.LabourOperation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.LabourOperation{}).Where("id = ?", op.ID).Updates(map[string]interface{}{
+			"code":            op.Code,
+			"description":     op.Description,
+			"flat_rate_hours": op.FlatRateHours,
+			"updated_at":      op.UpdatedAt,
+		})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update labour operation: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrLabourOperationNotFound
+		}
+		if err := deleteOperationChildren(tx, op.ID); err != nil {
+			return err
+		}
+		if len(op.Vehicles) > 0 {
+			if err := tx.Create(&op.Vehicles).Error; err != nil {
+				return fmt.Errorf("failed to create labour operation vehicles: %w", err)
+			}
+		}
+		if len(op.Parts) > 0 {
+			if err := tx.Create(&op.Parts).Error; err != nil {
+				return fmt.Errorf("failed to create labour operation parts: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresLabourOperationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteOperationChildren(tx, id); err != nil {
+			return err
+		}
+		res := tx.Where("id = ?", id).Delete(&domain.LabourOperation{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete labour operation: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrLabourOperationNotFound
+		}
+		return nil
+	})
+}
+
+func deleteOperationChildren(tx *gorm.DB, id uuid.UUID) error {
+	if err := tx.Where("operation_id = ?", id).Delete(&domain.LabourOperationVehicle{}).Error; err != nil {
+		return fmt.Errorf("failed to clear labour operation vehicles: %w", err)
+	}
+	if err := tx.Where("operation_id = ?", id).Delete(&domain.LabourOperationPart{}).Error; err != nil {
+		return fmt.Errorf("failed to clear labour operation parts: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresLabourOperationRepository) List(ctx context.Context, f ports.LabourOperationListFilters) ([]*domain.LabourOperation, int64, error) {
+	limit, offset := clampRepoList(f.Limit, f.Offset)
+	q := r.db.WithContext(ctx).Model(&domain.LabourOperation{})
+	if s := strings.ToLower(strings.TrimSpace(f.Search)); s != "" {
+		like := "%" + s + "%"
+		q = q.Where("LOWER(code) LIKE ? OR LOWER(description) LIKE ?", like, like)
+	}
+	if mk := strings.ToLower(strings.TrimSpace(f.Make)); mk != "" {
+		md := strings.ToLower(strings.TrimSpace(f.Model))
+		vehicles := r.db.Model(&domain.LabourOperationVehicle{}).Select("operation_id")
+		q = q.Where("id NOT IN (?) OR id IN (?)", vehicles,
+			r.db.Model(&domain.LabourOperationVehicle{}).Select("operation_id").
+				Where("LOWER(make) = ? AND (model IS NULL OR model = '' OR LOWER(model) = ?)", mk, md))
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count labour operations: %w", err)
+	}
+	var rows []*domain.LabourOperation
+	err := q.Preload("Vehicles").Preload("Parts").Order("code ASC").Limit(limit).Offset(offset).Find(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list labour operations: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.LabourOperation{}
+	}
+	return rows, total, nil
+}
+
+var _ ports.LabourOperationRepository = (*postgresLabourOperationRepository)(nil)
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type LabourOperationRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.LabourOperationRepository
+}
+
+func (suite *LabourOperationRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.LabourOperation{}, &domain.LabourOperationVehicle{}, &domain.LabourOperationPart{}))
+	suite.db = db
+	suite.repo = NewPostgresLabourOperationRepository(db)
+}
+
+func (suite *LabourOperationRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM labour_operation_parts")
+	suite.db.Exec("DELETE FROM labour_operation_vehicles")
+	suite.db.Exec("DELETE FROM labour_operations")
+}
+
+func newTestLabourOperation(code, description string, vehicles ...domain.LabourOperationVehicle) *domain.LabourOperation {
+	id := uuid.New()
+	for i := range vehicles {
+		vehicles[i].ID = uuid.New()
+		vehicles[i].OperationID = id
+	}
+	return &domain.LabourOperation{
+		ID:            id,
+		Code:          code,
+		Description:   description,
+		FlatRateHours: 1.5,
+		Vehicles:      vehicles,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+func (suite *LabourOperationRepositoryTestSuite) TestCreateAndGetByCode() {
+	ctx := context.Background()
+	op := newTestLabourOperation("DIST-01", "Cambio de distribución", domain.LabourOperationVehicle{Make: "Seat", Model: "Ibiza"})
+	op.Parts = []domain.LabourOperationPart{{ID: uuid.New(), OperationID: op.ID, PartItemID: uuid.New(), Description: "Kit distribución", Quantity: 1, UnitPrice: 120}}
+	require.NoError(suite.T(), suite.repo.Create(ctx, op))
+
+	got, err := suite.repo.GetByCode(ctx, " dist-01 ")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), op.ID, got.ID)
+	require.Len(suite.T(), got.Vehicles, 1)
+	require.Len(suite.T(), got.Parts, 1)
+	assert.Equal(suite.T(), 120.0, got.Parts[0].UnitPrice)
+
+	_, err = suite.repo.GetByCode(ctx, "NOPE")
+	assert.ErrorIs(suite.T(), err, domain.ErrLabourOperationNotFound)
+}
+
+func (suite *LabourOperationRepositoryTestSuite) TestUpdateReplacesVehiclesAndParts() {
+	ctx := context.Background()
+	op := newTestLabourOperation("FRE-01", "Pastillas delanteras", domain.LabourOperationVehicle{Make: "Seat"})
+	require.NoError(suite.T(), suite.repo.Create(ctx, op))
+
+	op.FlatRateHours = 0.8
+	op.Vehicles = []domain.LabourOperationVehicle{{ID: uuid.New(), OperationID: op.ID, Make: "Renault", Model: "Clio"}}
+	require.NoError(suite.T(), suite.repo.Update(ctx, op))
+
+	got, err := suite.repo.GetByID(ctx, op.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0.8, got.FlatRateHours)
+	require.Len(suite.T(), got.Vehicles, 1)
+	assert.Equal(suite.T(), "Renault", got.Vehicles[0].Make)
+
+	require.NoError(suite.T(), suite.repo.Delete(ctx, op.ID))
+	var n int64
+	suite.db.Model(&domain.LabourOperationVehicle{}).Count(&n)
+	assert.Zero(suite.T(), n)
+	assert.ErrorIs(suite.T(), suite.repo.Delete(ctx, op.ID), domain.ErrLabourOperationNotFound)
+}
+
+func (suite *LabourOperationRepositoryTestSuite) TestList_SearchAndVehicleFilters() {
+	ctx := context.Background()
+	require.NoError(suite.T(), suite.repo.Create(ctx, newTestLabourOperation("ACE-01", "Cambio de aceite")))
+	require.NoError(suite.T(), suite.repo.Create(ctx, newTestLabourOperation("ACE-02", "Cambio de aceite y filtros",
+		domain.LabourOperationVehicle{Make: "Seat", Model: "Ibiza"})))
+	require.NoError(suite.T(), suite.repo.Create(ctx, newTestLabourOperation("EMB-01", "Cambio de embrague",
+		domain.LabourOperationVehicle{Make: "Renault"})))
+
+	list, total, err := suite.repo.List(ctx, ports.LabourOperationListFilters{Make: "seat", Model: "ibiza", Limit: 10})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+	require.Len(suite.T(), list, 2)
+	assert.Equal(suite.T(), "ACE-01", list[0].Code)
+	assert.Equal(suite.T(), "ACE-02", list[1].Code)
+
+	// The vehicle filter must not widen the search (OR groups stay parenthesised).
+	list, total, err = suite.repo.List(ctx, ports.LabourOperationListFilters{Search: "embrague", Make: "Renault", Model: "Clio", Limit: 10})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), list, 1)
+	assert.Equal(suite.T(), "EMB-01", list[0].Code)
+
+	list, _, err = suite.repo.List(ctx, ports.LabourOperationListFilters{Search: "aceite", Make: "Renault", Limit: 10})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 1)
+	assert.Equal(suite.T(), "ACE-01", list[0].Code)
+}
+
+func TestLabourOperationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LabourOperationRepositoryTestSuite))
+}
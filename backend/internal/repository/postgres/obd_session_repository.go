@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresOBDSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresOBDSessionRepository returns an OBDSessionRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresOBDSessionRepository(db *gorm.DB) ports.OBDSessionRepository {
+	return &postgresOBDSessionRepository{db: db}
+}
+
+func (r *postgresOBDSessionRepository) Create(ctx context.Context, s *domain.OBDSession) error {
+	if err := r.db.WithContext(ctx).Create(s).Error; err != nil {
+		return fmt.Errorf("failed to create obd session: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresOBDSessionRepository) ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.OBDSession, error) {
+	limit, _ := clampRepoList(100, 0)
+	var rows []*domain.OBDSession
+	err := r.db.WithContext(ctx).
+		Where("service_job_id = ?", serviceJobID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list obd sessions: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.OBDSession{}
+	}
+	return rows, nil
+}
+
+var _ ports.OBDSessionRepository = (*postgresOBDSessionRepository)(nil)
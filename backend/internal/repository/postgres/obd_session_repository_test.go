@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type OBDSessionRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.OBDSessionRepository
+}
+
+func (suite *OBDSessionRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.OBDSession{}))
+	suite.db = db
+	suite.repo = NewPostgresOBDSessionRepository(db)
+}
+
+func (suite *OBDSessionRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM obd_sessions")
+}
+
+func (suite *OBDSessionRepositoryTestSuite) TestCreateAndListRoundTrip() {
+	ctx := context.Background()
+	jobID := uuid.New()
+	t0 := time.Now().UTC().Truncate(time.Second)
+	mil := true
+	second := &domain.OBDSession{
+		ID:           uuid.New(),
+		ServiceJobID: jobID,
+		FileName:     "after.txt",
+		Format:       "elm327",
+		TroubleCodes: []domain.OBDTroubleCode{{Code: "P0420", Status: "permanent"}},
+		RawLog:       "43 00",
+		CreatedAt:    t0.Add(time.Minute),
+	}
+	first := &domain.OBDSession{
+		ID:           uuid.New(),
+		ServiceJobID: jobID,
+		FileName:     "before.csv",
+		Format:       "csv",
+		MILOn:        &mil,
+		TroubleCodes: []domain.OBDTroubleCode{{Code: "P0133", Status: "cleared", Description: "O2 Sensor Circuit Slow Response (Bank 1 Sensor 1)"}},
+		FreezeFrames: []domain.OBDFreezeFrame{{Frame: 0, DTC: "P0133", Readings: []domain.OBDReading{{PID: "0C", Name: "Engine speed", Value: 1726, Unit: "rpm"}}}},
+		Readings:     []domain.OBDReading{{PID: "05", Name: "Engine coolant temperature", Value: 83, Unit: "°C"}},
+		RawLog:       "type,code\ndtc,P0133",
+		CreatedAt:    t0,
+	}
+	for _, s := range []*domain.OBDSession{second, first} {
+		s.UploadedByUserID = uuid.New()
+		require.NoError(suite.T(), suite.repo.Create(ctx, s))
+	}
+	require.NoError(suite.T(), suite.repo.Create(ctx, &domain.OBDSession{ID: uuid.New(), ServiceJobID: uuid.New(), Format: "csv", UploadedByUserID: uuid.New()}))
+
+	list, err := suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 2)
+	assert.Equal(suite.T(), first.ID, list[0].ID)
+	assert.Equal(suite.T(), second.ID, list[1].ID)
+	require.NotNil(suite.T(), list[0].MILOn)
+	assert.True(suite.T(), *list[0].MILOn)
+	assert.Equal(suite.T(), first.TroubleCodes, list[0].TroubleCodes)
+	assert.Equal(suite.T(), first.FreezeFrames, list[0].FreezeFrames)
+	assert.Equal(suite.T(), first.Readings, list[0].Readings)
+	assert.Equal(suite.T(), "type,code\ndtc,P0133", list[0].RawLog)
+
+	empty, err := suite.repo.ListByServiceJob(ctx, uuid.New())
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), empty)
+	assert.NotNil(suite.T(), empty)
+}
+
+func TestOBDSessionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OBDSessionRepositoryTestSuite))
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresOutboundNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresOutboundNotificationRepository returns the notification outbox backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresOutboundNotificationRepository(db *gorm.DB) ports.OutboundNotificationRepository {
+	return &postgresOutboundNotificationRepository{db: db}
+}
+
+func (r *postgresOutboundNotificationRepository) dedupeKeyExists(ctx context.Context, key string) (bool, error) {
+	var n int64
+	if err := r.db.WithContext(ctx).Model(&domain.OutboundNotification{}).Where("dedupe_key = ?", key).Count(&n).Error; err != nil {
+		return false, fmt.Errorf("failed to check notification dedupe key: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *postgresOutboundNotificationRepository) Enqueue(ctx context.Context, n *domain.OutboundNotification) (bool, error) {
+	if n.DedupeKey != "" {
+		exists, err := r.dedupeKeyExists(ctx, n.DedupeKey)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return false, nil
+		}
+	}
+	if err := r.db.WithContext(ctx).Create(n).Error; err != nil {
+		// Lost a race on the unique dedupe index: the other writer queued the same message.
+		if n.DedupeKey != "" {
+			if exists, xerr := r.dedupeKeyExists(ctx, n.DedupeKey); xerr == nil && exists {
+				return false, nil
+			}
+		}
+		return false, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return true, nil
+}
+
+func (r *postgresOutboundNotificationRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.OutboundNotification, error) {
+	limit, _ = clampRepoList(limit, 0)
+	var rows []*domain.OutboundNotification
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND send_after <= ?", domain.OutboundNotificationPending, now.UTC()).
+		Order("send_after ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list due notifications: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *postgresOutboundNotificationRepository) MarkSent(ctx context.Context, id uuid.UUID, at time.Time) error {
+	at = at.UTC()
+	err := r.db.WithContext(ctx).Model(&domain.OutboundNotification{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     domain.OutboundNotificationSent,
+			"sent_at":    &at,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"updated_at": at,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark notification sent: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresOutboundNotificationRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastErr string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastErr,
+		"updated_at": time.Now().UTC(),
+	}
+	if retryAt != nil {
+		updates["send_after"] = retryAt.UTC()
+	} else {
+		updates["status"] = domain.OutboundNotificationFailed
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.OutboundNotification{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to mark notification failed: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresOutboundNotificationRepository) Postpone(ctx context.Context, id uuid.UUID, until time.Time, reason string) error {
+	err := r.db.WithContext(ctx).Model(&domain.OutboundNotification{}).
+		Where("id = ? AND status = ?", id, domain.OutboundNotificationPending).
+		Updates(map[string]interface{}{
+			"send_after": until.UTC(),
+			"last_error": reason,
+			"updated_at": time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to postpone notification: %w", err)
+	}
+	return nil
+}
+
+var _ ports.OutboundNotificationRepository = (*postgresOutboundNotificationRepository)(nil)
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type OutboundNotificationRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.OutboundNotificationRepository
+}
+
+func (suite *OutboundNotificationRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.OutboundNotification{}))
+	suite.db = db
+	suite.repo = NewPostgresOutboundNotificationRepository(db)
+}
+
+func (suite *OutboundNotificationRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM outbound_notifications")
+}
+
+func newTestOutbound(key string, sendAfter time.Time) *domain.OutboundNotification {
+	return &domain.OutboundNotification{
+		ID:        uuid.New(),
+		Channel:   domain.NotificationChannelSMS,
+		Recipient: "+351900000000",
+		Body:      "hola",
+		DedupeKey: key,
+		Status:    domain.OutboundNotificationPending,
+		SendAfter: sendAfter,
+	}
+}
+
+func (suite *OutboundNotificationRepositoryTestSuite) TestEnqueue_Dedupe() {
+	ctx := context.Background()
+	ok, err := suite.repo.Enqueue(ctx, newTestOutbound("k1", time.Now()))
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+
+	ok, err = suite.repo.Enqueue(ctx, newTestOutbound("k1", time.Now()))
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	ok, err = suite.repo.Enqueue(ctx, newTestOutbound("", time.Now()))
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+}
+
+func (suite *OutboundNotificationRepositoryTestSuite) TestListDue_MarkSentAndFailed() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	due := newTestOutbound("due", now.Add(-time.Minute))
+	due.Channel = domain.NotificationChannelEmail
+	due.Attachments = []domain.NotificationAttachment{{Filename: "turno.ics", ContentType: "text/calendar", Content: []byte("BEGIN:VCALENDAR")}}
+	later := newTestOutbound("later", now.Add(time.Hour))
+	for _, n := range []*domain.OutboundNotification{due, later} {
+		_, err := suite.repo.Enqueue(ctx, n)
+		require.NoError(suite.T(), err)
+	}
+
+	rows, err := suite.repo.ListDue(ctx, now, 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rows, 1)
+	assert.Equal(suite.T(), due.ID, rows[0].ID)
+	require.Len(suite.T(), rows[0].Attachments, 1)
+	assert.Equal(suite.T(), []byte("BEGIN:VCALENDAR"), rows[0].Attachments[0].Content)
+
+	retry := now.Add(2 * time.Hour)
+	require.NoError(suite.T(), suite.repo.MarkFailed(ctx, due.ID, "boom", &retry))
+	rows, err = suite.repo.ListDue(ctx, now, 10)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), rows, 0)
+
+	require.NoError(suite.T(), suite.repo.MarkSent(ctx, later.ID, now))
+	rows, err = suite.repo.ListDue(ctx, now.Add(3*time.Hour), 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rows, 1)
+	assert.Equal(suite.T(), due.ID, rows[0].ID)
+	assert.Equal(suite.T(), 1, rows[0].Attempts)
+	assert.Equal(suite.T(), "boom", rows[0].LastError)
+
+	require.NoError(suite.T(), suite.repo.MarkFailed(ctx, due.ID, "boom again", nil))
+	rows, err = suite.repo.ListDue(ctx, now.Add(3*time.Hour), 10)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), rows, 0)
+}
+
+func (suite *OutboundNotificationRepositoryTestSuite) TestPostponeKeepsAttempts() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	n := newTestOutbound("", now.Add(-time.Minute))
+	_, err := suite.repo.Enqueue(ctx, n)
+	require.NoError(suite.T(), err)
+
+	require.NoError(suite.T(), suite.repo.Postpone(ctx, n.ID, now.Add(time.Hour), "not configured"))
+	rows, err := suite.repo.ListDue(ctx, now, 10)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), rows, 0)
+
+	rows, err = suite.repo.ListDue(ctx, now.Add(2*time.Hour), 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rows, 1)
+	assert.Zero(suite.T(), rows[0].Attempts)
+	assert.Equal(suite.T(), domain.OutboundNotificationPending, rows[0].Status)
+	assert.Equal(suite.T(), "not configured", rows[0].LastError)
+}
+
+func TestOutboundNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboundNotificationRepositoryTestSuite))
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresRepairLineRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepairLineRepository returns a RepairLineRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresRepairLineRepository(db *gorm.DB) ports.RepairLineRepository {
+	return &postgresRepairLineRepository{db: db}
+}
+
+func (r *postgresRepairLineRepository) Add(ctx context.Context, line *domain.RepairLine) (float64, error) {
+	var cost float64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepair(tx, line.RepairID); err != nil {
+			return err
+		}
+		if line.Kind == domain.RepairLinePart && line.PartItemID != nil {
+			if err := moveStock(tx, *line.PartItemID, -line.Quantity); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(line).Error; err != nil {
+			return fmt.Errorf("failed to create repair line: %w", err)
+		}
+		var err error
+		cost, err = syncRepairCost(tx, line.RepairID)
+		return err
+	})
+	return cost, err
+}
+
+func (r *postgresRepairLineRepository) Remove(ctx context.Context, repairID, lineID uuid.UUID) (float64, error) {
+	var cost float64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepair(tx, repairID); err != nil {
+			return err
+		}
+		var line domain.RepairLine
+		if err := tx.Where("id = ? AND repair_id = ?", lineID, repairID).First(&line).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrRepairLineNotFound
+			}
+			return fmt.Errorf("failed to get repair line: %w", err)
+		}
+		if err := tx.Delete(&domain.RepairLine{}, "id = ?", line.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete repair line: %w", err)
+		}
+		if line.Kind == domain.RepairLinePart && line.PartItemID != nil {
+			if err := moveStock(tx, *line.PartItemID, line.Quantity); err != nil && !errors.Is(err, domain.ErrPartItemNotFound) {
+				return err
+			}
+		}
+		var err error
+		cost, err = syncRepairCost(tx, repairID)
+		return err
+	})
+	return cost, err
+}
+
+func (r *postgresRepairLineRepository) ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairLine, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []domain.RepairLine
+	err := r.db.WithContext(ctx).Where("repair_id = ?", repairID).Order("created_at ASC").Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair lines: %w", err)
+	}
+	if rows == nil {
+		rows = []domain.RepairLine{}
+	}
+	return rows, nil
+}
+
+// lockRepair checks the repair exists and holds its row until the transaction ends, so concurrent line
+// changes compute the cost one after the other (sqlite has no row locks and ignores the clause).
+func lockRepair(tx *gorm.DB, repairID uuid.UUID) error {
+	var row RepairModel
+	err := tx.Model(&RepairModel{}).Select("id").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", repairID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrRepairNotFound
+		}
+		return fmt.Errorf("failed to lock repair: %w", err)
+	}
+	return nil
+}
+
+// moveStock adds delta to the part's on-hand quantity; a withdrawal larger than the stock fails.
+func moveStock(tx *gorm.DB, partItemID uuid.UUID, delta float64) error {
+	q := tx.Model(&domain.PartItem{}).Where("id = ? AND deleted_at IS NULL", partItemID)
+	if delta < 0 {
+		q = q.Where("quantity >= ?", -delta)
+	}
+	res := q.Updates(map[string]interface{}{"quantity": gorm.Expr("quantity + ?", delta), "updated_at": time.Now().UTC()})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update part stock: %w", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	var n int64
+	if err := tx.Model(&domain.PartItem{}).Where("id = ? AND deleted_at IS NULL", partItemID).Count(&n).Error; err != nil {
+		return fmt.Errorf("failed to get part item: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrPartItemNotFound
+	}
+	return domain.ErrInsufficientStock
+}
+
+// restockRepairParts moves the quantities of the repair's part lines back to stock (sign 1) or out of it
+// again (sign -1). Parts deleted from the inventory meanwhile are skipped.
+func restockRepairParts(tx *gorm.DB, repairID uuid.UUID, sign float64) error {
+	var lines []domain.RepairLine
+	err := tx.Where("repair_id = ? AND kind = ? AND part_item_id IS NOT NULL", repairID, domain.RepairLinePart).Find(&lines).Error
+	if err != nil {
+		return fmt.Errorf("failed to list repair lines: %w", err)
+	}
+	for _, l := range lines {
+		if err := moveStock(tx, *l.PartItemID, sign*l.Quantity); err != nil && !errors.Is(err, domain.ErrPartItemNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncRepairCost(tx *gorm.DB, repairID uuid.UUID) (float64, error) {
+	var lines []domain.RepairLine
+	if err := tx.Where("repair_id = ?", repairID).Find(&lines).Error; err != nil {
+		return 0, fmt.Errorf("failed to list repair lines: %w", err)
+	}
+	cost := domain.RepairLinesTotal(lines)
+	err := tx.Model(&RepairModel{}).Where("id = ?", repairID).
+		Updates(map[string]interface{}{"cost": cost, "updated_at": time.Now().UTC()}).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to update repair cost: %w", err)
+	}
+	return cost, nil
+}
+
+var _ ports.RepairLineRepository = (*postgresRepairLineRepository)(nil)
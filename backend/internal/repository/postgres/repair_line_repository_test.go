@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type RepairLineRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.RepairLineRepository
+}
+
+func (suite *RepairLineRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&RepairModel{}, &domain.PartItem{}, &domain.RepairLine{}, &domain.RepairStatusEvent{}))
+	suite.db = db
+	suite.repo = NewPostgresRepairLineRepository(db)
+}
+
+func (suite *RepairLineRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM repair_lines")
+	suite.db.Exec("DELETE FROM part_items")
+	suite.db.Exec("DELETE FROM repairs")
+	suite.db.Exec("DELETE FROM repair_status_events")
+}
+
+func (suite *RepairLineRepositoryTestSuite) fixture() (repairID, partID uuid.UUID) {
+	repairID, partID = uuid.New(), uuid.New()
+	require.NoError(suite.T(), suite.db.Create(&RepairModel{ID: repairID, CarID: uuid.New(), TechnicianID: uuid.New(), Description: "Frenos", Status: "pending", Cost: 99}).Error)
+	require.NoError(suite.T(), suite.db.Create(&domain.PartItem{ID: partID, Reference: "P-1", Brand: "Bosch", Name: "Pastillas", Quantity: 3, UOM: domain.PartUOMUnit}).Error)
+	return repairID, partID
+}
+
+func (suite *RepairLineRepositoryTestSuite) stock(partID uuid.UUID) float64 {
+	var p domain.PartItem
+	require.NoError(suite.T(), suite.db.First(&p, "id = ?", partID).Error)
+	return p.Quantity
+}
+
+func (suite *RepairLineRepositoryTestSuite) cost(repairID uuid.UUID) float64 {
+	var r RepairModel
+	require.NoError(suite.T(), suite.db.First(&r, "id = ?", repairID).Error)
+	return r.Cost
+}
+
+func (suite *RepairLineRepositoryTestSuite) TestAddRemoveMovesStockAndCost() {
+	ctx := context.Background()
+	repairID, partID := suite.fixture()
+
+	labour := &domain.RepairLine{ID: uuid.New(), RepairID: repairID, Kind: domain.RepairLineLabour, Description: "Mano de obra", Quantity: 1.5, UnitPrice: 40}
+	cost, err := suite.repo.Add(ctx, labour)
+	require.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 60.0, cost, 0.001)
+
+	part := &domain.RepairLine{ID: uuid.New(), RepairID: repairID, Kind: domain.RepairLinePart, Description: "Pastillas", Quantity: 2, UnitPrice: 25.5, PartItemID: &partID}
+	cost, err = suite.repo.Add(ctx, part)
+	require.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 111.0, cost, 0.001)
+	assert.InDelta(suite.T(), 111.0, suite.cost(repairID), 0.001)
+	assert.InDelta(suite.T(), 1.0, suite.stock(partID), 0.001)
+
+	// Not enough left: nothing changes.
+	_, err = suite.repo.Add(ctx, &domain.RepairLine{ID: uuid.New(), RepairID: repairID, Kind: domain.RepairLinePart, Description: "Pastillas", Quantity: 2, UnitPrice: 25.5, PartItemID: &partID})
+	assert.ErrorIs(suite.T(), err, domain.ErrInsufficientStock)
+	missing := uuid.New()
+	_, err = suite.repo.Add(ctx, &domain.RepairLine{ID: uuid.New(), RepairID: repairID, Kind: domain.RepairLinePart, Description: "x", Quantity: 1, PartItemID: &missing})
+	assert.ErrorIs(suite.T(), err, domain.ErrPartItemNotFound)
+	_, err = suite.repo.Add(ctx, &domain.RepairLine{ID: uuid.New(), RepairID: uuid.New(), Kind: domain.RepairLineLabour, Description: "x", Quantity: 1})
+	assert.ErrorIs(suite.T(), err, domain.ErrRepairNotFound)
+	lines, err := suite.repo.ListByRepair(ctx, repairID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), lines, 2)
+	assert.InDelta(suite.T(), 1.0, suite.stock(partID), 0.001)
+
+	cost, err = suite.repo.Remove(ctx, repairID, part.ID)
+	require.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 60.0, cost, 0.001)
+	assert.InDelta(suite.T(), 3.0, suite.stock(partID), 0.001)
+	_, err = suite.repo.Remove(ctx, repairID, part.ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrRepairLineNotFound)
+}
+
+func (suite *RepairLineRepositoryTestSuite) TestCancelReopenAndDeleteMoveStock() {
+	ctx := context.Background()
+	repairID, partID := suite.fixture()
+	repairs := NewPostgresRepairRepository(suite.db)
+	_, err := suite.repo.Add(ctx, &domain.RepairLine{ID: uuid.New(), RepairID: repairID, Kind: domain.RepairLinePart, Description: "Pastillas", Quantity: 2, UnitPrice: 25.5, PartItemID: &partID})
+	require.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), 1.0, suite.stock(partID), 0.001)
+
+	change := func(from, to domain.RepairStatus) error {
+		r := &domain.Repair{ID: repairID, Status: to}
+		return repairs.ChangeStatus(ctx, r, &domain.RepairStatusEvent{ID: uuid.New(), RepairID: repairID, FromStatus: from, ToStatus: to,
+			ChangedByUserID: uuid.New(), ChangedAt: time.Now().UTC()})
+	}
+	require.NoError(suite.T(), change(domain.RepairStatusPending, domain.RepairStatusCancelled))
+	assert.InDelta(suite.T(), 3.0, suite.stock(partID), 0.001, "cancelling gives the parts back")
+
+	// Reopening takes them out again, and fails as a whole when they are gone meanwhile.
+	require.NoError(suite.T(), suite.db.Model(&domain.PartItem{}).Where("id = ?", partID).Update("quantity", 1).Error)
+	assert.ErrorIs(suite.T(), change(domain.RepairStatusCancelled, domain.RepairStatusPending), domain.ErrInsufficientStock)
+	got, err := repairs.GetByID(ctx, repairID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.RepairStatusCancelled, got.Status)
+	require.NoError(suite.T(), suite.db.Model(&domain.PartItem{}).Where("id = ?", partID).Update("quantity", 3).Error)
+	require.NoError(suite.T(), change(domain.RepairStatusCancelled, domain.RepairStatusPending))
+	assert.InDelta(suite.T(), 1.0, suite.stock(partID), 0.001)
+
+	require.NoError(suite.T(), repairs.Delete(ctx, repairID))
+	assert.InDelta(suite.T(), 3.0, suite.stock(partID), 0.001, "deleting gives the parts back")
+	assert.ErrorIs(suite.T(), repairs.Delete(ctx, repairID), domain.ErrRepairNotFound)
+	assert.InDelta(suite.T(), 3.0, suite.stock(partID), 0.001)
+}
+
+func TestRepairLineRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RepairLineRepositoryTestSuite))
+}
@@ -5,18 +5,20 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
 )
 
 // Columns match domain.Repair / GORM AutoMigrate (repairs has no denormalized car columns).
-const sqlSelectRepairBase = `SELECT r.id, r.car_id, r.technician_id, r.description, r.status, r.cost, r.started_at, r.completed_at, r.created_at, r.updated_at, r.deleted_at
+const sqlSelectRepairBase = `SELECT r.id, r.car_id, r.technician_id, r.service_job_id, r.description, r.status, r.cost, r.started_at, r.completed_at, r.created_at, r.updated_at, r.deleted_at, r.rework_of_repair_id
 FROM repairs r WHERE r.deleted_at IS NULL`
 
 type PostgresRepairRepository struct {
@@ -33,6 +35,7 @@ type RepairModel struct {
 	ID           uuid.UUID  `gorm:"type:uuid;primary_key" db:"id"`
 	CarID        uuid.UUID  `gorm:"type:uuid;not null;index" db:"car_id"`
 	TechnicianID uuid.UUID  `gorm:"type:uuid;not null;index" db:"technician_id"`
+	ServiceJobID *uuid.UUID `gorm:"type:uuid;index" db:"service_job_id"`
 	Description  string     `gorm:"not null" db:"description"`
 	Status       string     `gorm:"not null;default:'pending'" db:"status"`
 	Cost         float64    `gorm:"not null;default:0" db:"cost"`
@@ -41,6 +44,8 @@ type RepairModel struct {
 	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at"`
 	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at"`
 	DeletedAt    *time.Time `gorm:"column:deleted_at;index" db:"deleted_at"`
+	// ReworkOfRepairID is set on warranty rework; written on create only.
+	ReworkOfRepairID *uuid.UUID `gorm:"type:uuid;index" db:"rework_of_repair_id"`
 }
 
 func (RepairModel) TableName() string {
@@ -58,14 +63,14 @@ func (r *PostgresRepairRepository) Create(ctx context.Context, repair *domain.Re
 }
 
 func (r *PostgresRepairRepository) createRepairSQLX(ctx context.Context, repair *domain.Repair) error {
-	started := time.Now().UTC()
+	var started interface{}
 	if repair.StartedAt != nil {
 		started = repair.StartedAt.UTC()
 	}
 	const q = `INSERT INTO repairs (
-id, car_id, technician_id, description, status, cost, started_at, completed_at, created_at, updated_at, deleted_at
+id, car_id, technician_id, service_job_id, description, status, cost, started_at, completed_at, created_at, updated_at, deleted_at, rework_of_repair_id
 ) VALUES (
-$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )`
 	var completed interface{}
 	if repair.CompletedAt != nil {
@@ -73,9 +78,9 @@ $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 	}
 	_, err := r.sqlx.ExecContext(ctx, q,
 		repair.ID,
-		repair.CarID, repair.TechnicianID, repair.Description, string(repair.Status), repair.Cost,
+		repair.CarID, repair.TechnicianID, repair.ServiceJobID, repair.Description, string(repair.Status), repair.Cost,
 		started, completed,
-		repair.CreatedAt.UTC(), repair.UpdatedAt.UTC(), nil,
+		repair.CreatedAt.UTC(), repair.UpdatedAt.UTC(), nil, repair.ReworkOfRepairID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create repair: %w", err)
@@ -153,25 +158,150 @@ func (r *PostgresRepairRepository) ListIDsByServiceJobID(ctx context.Context, se
 	return ids, nil
 }
 
-func (r *PostgresRepairRepository) List(ctx context.Context, limit, offset int) ([]*domain.Repair, error) {
+// ListByServiceJobIDs implements ports.RepairRepository.
+func (r *PostgresRepairRepository) ListByServiceJobIDs(ctx context.Context, serviceJobIDs []uuid.UUID) ([]*domain.Repair, error) {
+	if len(serviceJobIDs) == 0 {
+		return []*domain.Repair{}, nil
+	}
+	var rows []RepairModel
 	if r.sqlx != nil {
-		return r.selectRepairsSQLX(ctx, "", nil, limit, offset, "failed to list repairs")
+		q, args, err := sqlx.In(sqlSelectRepairBase+` AND r.service_job_id IN (?) ORDER BY r.created_at`, serviceJobIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repairs by service jobs: %w", err)
+		}
+		if err := r.sqlx.SelectContext(ctx, &rows, r.sqlx.Rebind(q), args...); err != nil {
+			return nil, fmt.Errorf("failed to list repairs by service jobs: %w", err)
+		}
+	} else {
+		err := r.db.WithContext(ctx).Where("service_job_id IN ? AND deleted_at IS NULL", serviceJobIDs).
+			Order("created_at").Find(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repairs by service jobs: %w", err)
+		}
 	}
-	var dbRepairs []RepairModel
-	query := r.db.WithContext(ctx).Where("deleted_at IS NULL").Order("created_at DESC")
-	if limit > 0 {
-		query = query.Limit(limit)
+	return r.repairsToDomain(rows), nil
+}
+
+// ListByTechnician implements ports.RepairRepository.
+func (r *PostgresRepairRepository) ListByTechnician(ctx context.Context, userID uuid.UUID, status domain.RepairStatus, limit, offset int) ([]*domain.Repair, error) {
+	limit, offset = clampRepoList(limit, offset)
+	if r.sqlx != nil {
+		cond := "(r.technician_id = $1 OR r.id IN (SELECT repair_id FROM repair_technicians WHERE user_id = $1))"
+		args := []interface{}{userID}
+		if status != "" {
+			cond += " AND r.status = $2"
+			args = append(args, string(status))
+		}
+		return r.selectRepairsSQLX(ctx, cond, args, limit, offset, "failed to list repairs by technician")
 	}
-	if offset > 0 {
-		query = query.Offset(offset)
+	var dbRepairs []RepairModel
+	q := r.db.WithContext(ctx).
+		Where("deleted_at IS NULL AND (technician_id = ? OR id IN (?))", userID,
+			r.db.Model(&domain.RepairTechnician{}).Select("repair_id").Where("user_id = ?", userID))
+	if status != "" {
+		q = q.Where("status = ?", string(status))
 	}
-	err := query.Find(&dbRepairs).Error
+	err := q.Order("created_at DESC").Limit(limit).Offset(offset).Find(&dbRepairs).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to list repairs: %w", err)
+		return nil, fmt.Errorf("failed to list repairs by technician: %w", err)
 	}
 	return r.repairsToDomain(dbRepairs), nil
 }
 
+// List implements ports.RepairRepository.
+func (r *PostgresRepairRepository) List(ctx context.Context, filters *ports.RepairFilters) ([]*domain.Repair, int64, error) {
+	var limit, offset int
+	if filters != nil {
+		limit, offset = filters.Limit, filters.Offset
+	}
+	limit, offset = clampRepoList(limit, offset)
+	cond, args := repairFilterConds(filters)
+	if r.sqlx != nil {
+		cond = r.sqlx.Rebind(cond)
+		countQ := `SELECT COUNT(*) FROM repairs r WHERE r.deleted_at IS NULL`
+		if cond != "" {
+			countQ += " AND " + cond
+		}
+		var total int64
+		if err := r.sqlx.GetContext(ctx, &total, countQ, args...); err != nil {
+			return nil, 0, fmt.Errorf("failed to count repairs: %w", err)
+		}
+		out, err := r.selectRepairsSQLX(ctx, cond, args, limit, offset, "failed to list repairs")
+		if err != nil {
+			return nil, 0, err
+		}
+		return out, total, nil
+	}
+	buildQuery := func() *gorm.DB {
+		q := r.db.WithContext(ctx).Model(&RepairModel{}).Where("deleted_at IS NULL")
+		if cond != "" {
+			q = q.Where(cond, args...)
+		}
+		return q
+	}
+	var total int64
+	if err := buildQuery().Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count repairs: %w", err)
+	}
+	var dbRepairs []RepairModel
+	if err := buildQuery().Order("created_at DESC").Limit(limit).Offset(offset).Find(&dbRepairs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list repairs: %w", err)
+	}
+	return r.repairsToDomain(dbRepairs), total, nil
+}
+
+// repairFilterConds renders filters as one AND-ed condition with "?" placeholders, shared by both query paths.
+func repairFilterConds(f *ports.RepairFilters) (string, []interface{}) {
+	if f == nil {
+		return "", nil
+	}
+	var where []string
+	var args []interface{}
+	if f.Status != nil && *f.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, string(*f.Status))
+	}
+	if f.TechnicianID != nil {
+		where = append(where, "(technician_id = ? OR id IN (SELECT repair_id FROM repair_technicians WHERE user_id = ?))")
+		args = append(args, *f.TechnicianID, *f.TechnicianID)
+	}
+	if f.ServiceJobID != nil {
+		where = append(where, "service_job_id = ?")
+		args = append(args, *f.ServiceJobID)
+	}
+	if f.CreatedFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.CreatedFrom.UTC())
+	}
+	if f.CreatedTo != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, f.CreatedTo.UTC())
+	}
+	if f.MinCost != nil {
+		where = append(where, "cost >= ?")
+		args = append(args, *f.MinCost)
+	}
+	if f.MaxCost != nil {
+		where = append(where, "cost <= ?")
+		args = append(args, *f.MaxCost)
+	}
+	if f.Rework != nil {
+		if *f.Rework {
+			where = append(where, "rework_of_repair_id IS NOT NULL")
+		} else {
+			where = append(where, "rework_of_repair_id IS NULL")
+		}
+	}
+	if s := strings.ToLower(strings.TrimSpace(f.Search)); s != "" {
+		where = append(where, `LOWER(description) LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(s)+"%")
+	}
+	return strings.Join(where, " AND "), args
+}
+
+// likeEscaper makes user text match literally in a LIKE pattern with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (r *PostgresRepairRepository) selectRepairsSQLX(ctx context.Context, cond string, condArgs []interface{}, limit, offset int, errLabel string) ([]*domain.Repair, error) {
 	q := sqlSelectRepairBase
 	args := make([]interface{}, 0, 4+len(condArgs))
@@ -227,19 +357,19 @@ func (r *PostgresRepairRepository) Update(ctx context.Context, repair *domain.Re
 
 func (r *PostgresRepairRepository) updateRepairSQLX(ctx context.Context, repair *domain.Repair) error {
 	now := time.Now().UTC()
-	started := now
+	var started interface{}
 	if repair.StartedAt != nil {
 		started = repair.StartedAt.UTC()
 	}
 	const q = `UPDATE repairs SET
-car_id = $1, technician_id = $2, description = $3, status = $4, cost = $5, started_at = $6, completed_at = $7, updated_at = $8
-WHERE id = $9 AND deleted_at IS NULL`
+car_id = $1, technician_id = $2, service_job_id = $3, description = $4, status = $5, cost = $6, started_at = $7, completed_at = $8, updated_at = $9
+WHERE id = $10 AND deleted_at IS NULL`
 	var completed interface{}
 	if repair.CompletedAt != nil {
 		completed = repair.CompletedAt.UTC()
 	}
 	res, err := r.sqlx.ExecContext(ctx, q,
-		repair.CarID, repair.TechnicianID, repair.Description, string(repair.Status), repair.Cost,
+		repair.CarID, repair.TechnicianID, repair.ServiceJobID, repair.Description, string(repair.Status), repair.Cost,
 		started, completed, now, repair.ID,
 	)
 	if err != nil {
@@ -255,30 +385,73 @@ WHERE id = $9 AND deleted_at IS NULL`
 	return nil
 }
 
-func (r *PostgresRepairRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if r.sqlx != nil {
-		const q = `UPDATE repairs SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
-		res, err := r.sqlx.ExecContext(ctx, q, time.Now().UTC(), id)
-		if err != nil {
-			return fmt.Errorf("failed to delete repair: %w", err)
+func (r *PostgresRepairRepository) ChangeStatus(ctx context.Context, repair *domain.Repair, ev *domain.RepairStatusEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&RepairModel{}).
+			Where("id = ? AND status = ? AND deleted_at IS NULL", ev.RepairID, string(ev.FromStatus)).
+			Updates(map[string]interface{}{
+				"status":       string(ev.ToStatus),
+				"started_at":   repair.StartedAt,
+				"completed_at": repair.CompletedAt,
+				"updated_at":   ev.ChangedAt,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update repair status: %w", res.Error)
 		}
-		n, err := res.RowsAffected()
-		if err != nil {
-			return fmt.Errorf("failed to read rows affected: %w", err)
+		if res.RowsAffected == 0 {
+			return domain.ErrRepairStatusConflict
 		}
-		if n == 0 {
-			return domain.ErrRepairNotFound
+		if err := tx.Create(ev).Error; err != nil {
+			return fmt.Errorf("failed to create repair status event: %w", err)
+		}
+		// A cancelled repair gives its parts back; reopening it takes them out again.
+		switch {
+		case ev.ToStatus == domain.RepairStatusCancelled:
+			return restockRepairParts(tx, ev.RepairID, 1)
+		case ev.FromStatus == domain.RepairStatusCancelled:
+			return restockRepairParts(tx, ev.RepairID, -1)
 		}
 		return nil
+	})
+}
+
+func (r *PostgresRepairRepository) ListStatusEvents(ctx context.Context, repairID uuid.UUID) ([]*domain.RepairStatusEvent, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.RepairStatusEvent
+	err := r.db.WithContext(ctx).
+		Where("repair_id = ?", repairID).
+		Order("changed_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair status events: %w", err)
 	}
-	result := r.db.WithContext(ctx).Model(&RepairModel{}).Where("id = ?", id).Update("deleted_at", time.Now())
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete repair: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return domain.ErrRepairNotFound
+	if rows == nil {
+		rows = []*domain.RepairStatusEvent{}
 	}
-	return nil
+	return rows, nil
+}
+
+// Delete soft-deletes the repair and puts its part lines back in stock (a cancelled repair already did).
+func (r *PostgresRepairRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row RepairModel
+		err := tx.Model(&RepairModel{}).Select("id", "status").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", id).First(&row).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrRepairNotFound
+			}
+			return fmt.Errorf("failed to get repair: %w", err)
+		}
+		if err := tx.Model(&RepairModel{}).Where("id = ?", id).Update("deleted_at", time.Now().UTC()).Error; err != nil {
+			return fmt.Errorf("failed to delete repair: %w", err)
+		}
+		if row.Status == string(domain.RepairStatusCancelled) {
+			return nil
+		}
+		return restockRepairParts(tx, id, 1)
+	})
 }
 
 func (r *PostgresRepairRepository) GetByLicensePlate(ctx context.Context, licensePlate string) (*domain.Repair, error) {
@@ -317,6 +490,7 @@ func (r *PostgresRepairRepository) toDomainRepair(dbRepair *RepairModel) *domain
 		ID:           dbRepair.ID,
 		CarID:        dbRepair.CarID,
 		TechnicianID: dbRepair.TechnicianID,
+		ServiceJobID: dbRepair.ServiceJobID,
 		Description:  dbRepair.Description,
 		Status:       domain.RepairStatus(dbRepair.Status),
 		Cost:         dbRepair.Cost,
@@ -325,5 +499,7 @@ func (r *PostgresRepairRepository) toDomainRepair(dbRepair *RepairModel) *domain
 		CreatedAt:    dbRepair.CreatedAt,
 		UpdatedAt:    dbRepair.UpdatedAt,
 		DeletedAt:    dbRepair.DeletedAt,
+
+		ReworkOfRepairID: dbRepair.ReworkOfRepairID,
 	}
 }
@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type RepairRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.RepairRepository
+}
+
+func (suite *RepairRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&RepairModel{}, &domain.RepairStatusEvent{}, &domain.RepairTechnician{}, &domain.RepairWarranty{}))
+	suite.db = db
+	suite.repo = NewPostgresRepairRepository(db)
+}
+
+func (suite *RepairRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM repair_status_events")
+	suite.db.Exec("DELETE FROM repair_technicians")
+	suite.db.Exec("DELETE FROM repair_warranties")
+	suite.db.Exec("DELETE FROM repairs")
+}
+
+func (suite *RepairRepositoryTestSuite) TestChangeStatusStampsAndRecords() {
+	ctx := context.Background()
+	id := uuid.New()
+	require.NoError(suite.T(), suite.db.Create(&RepairModel{ID: id, CarID: uuid.New(), TechnicianID: uuid.New(), Description: "Frenos", Status: "pending"}).Error)
+	r, err := suite.repo.GetByID(ctx, id)
+	require.NoError(suite.T(), err)
+
+	at := time.Now().UTC().Truncate(time.Second)
+	ev := &domain.RepairStatusEvent{ID: uuid.New(), RepairID: id, FromStatus: domain.RepairStatusPending, ToStatus: domain.RepairStatusInProgress, ChangedByUserID: uuid.New(), ChangedAt: at}
+	r.Apply(ev)
+	require.NoError(suite.T(), suite.repo.ChangeStatus(ctx, r, ev))
+
+	got, err := suite.repo.GetByID(ctx, id)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.RepairStatusInProgress, got.Status)
+	require.NotNil(suite.T(), got.StartedAt)
+	assert.True(suite.T(), at.Equal(*got.StartedAt))
+	assert.Nil(suite.T(), got.CompletedAt)
+
+	// A second writer still believing the repair is pending loses.
+	stale := &domain.RepairStatusEvent{ID: uuid.New(), RepairID: id, FromStatus: domain.RepairStatusPending, ToStatus: domain.RepairStatusCancelled, ChangedByUserID: uuid.New(), ChangedAt: at}
+	assert.ErrorIs(suite.T(), suite.repo.ChangeStatus(ctx, r, stale), domain.ErrRepairStatusConflict)
+
+	events, err := suite.repo.ListStatusEvents(ctx, id)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), ev.ID, events[0].ID)
+}
+
+func (suite *RepairRepositoryTestSuite) TestTechniciansSharedRepair() {
+	ctx := context.Background()
+	creator, lead, assistant := uuid.New(), uuid.New(), uuid.New()
+	shared, own := uuid.New(), uuid.New()
+	require.NoError(suite.T(), suite.db.Create(&RepairModel{ID: shared, CarID: uuid.New(), TechnicianID: creator, Description: "Caja de cambios", Status: "in_progress"}).Error)
+	require.NoError(suite.T(), suite.db.Create(&RepairModel{ID: own, CarID: uuid.New(), TechnicianID: assistant, Description: "Aceite", Status: "pending"}).Error)
+
+	techs := NewPostgresRepairTechnicianRepository(suite.db)
+	now := time.Now().UTC()
+	require.NoError(suite.T(), techs.Replace(ctx, shared, []domain.RepairTechnician{
+		{RepairID: shared, UserID: assistant, Role: domain.RepairTechnicianAssistant, Hours: 1.5, AssignedAt: now},
+		{RepairID: shared, UserID: lead, Role: domain.RepairTechnicianLead, Hours: 4, AssignedAt: now},
+	}))
+	ts, err := techs.ListByRepair(ctx, shared)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), ts, 2)
+	assert.Equal(suite.T(), lead, ts[0].UserID, "lead first")
+	got, err := suite.repo.GetByID(ctx, shared)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), lead, got.TechnicianID)
+
+	mine, err := suite.repo.ListByTechnician(ctx, assistant, "", 0, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), mine, 2, "assisted and led repairs")
+	mine, err = suite.repo.ListByTechnician(ctx, assistant, domain.RepairStatusPending, 0, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), mine, 1)
+	assert.Equal(suite.T(), own, mine[0].ID)
+	mine, err = suite.repo.ListByTechnician(ctx, creator, "", 0, 0)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), mine, "the creator no longer works on it")
+}
+
+func (suite *RepairRepositoryTestSuite) TestListFilters() {
+	ctx := context.Background()
+	tech, job := uuid.New(), uuid.New()
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	rows := []RepairModel{
+		{ID: uuid.New(), CarID: uuid.New(), TechnicianID: tech, ServiceJobID: &job, Description: "Cambio de EMBRAGUE", Status: "completed", Cost: 650, CreatedAt: day},
+		{ID: uuid.New(), CarID: uuid.New(), TechnicianID: uuid.New(), Description: "Pastillas de freno", Status: "completed", Cost: 120, CreatedAt: day.AddDate(0, 0, 1)},
+		{ID: uuid.New(), CarID: uuid.New(), TechnicianID: uuid.New(), Description: "Aceite", Status: "pending", Cost: 60, CreatedAt: day.AddDate(0, 0, 5)},
+	}
+	rows[2].ReworkOfRepairID = &rows[1].ID
+	for i := range rows {
+		require.NoError(suite.T(), suite.db.Create(&rows[i]).Error)
+	}
+	require.NoError(suite.T(), suite.db.Create(&domain.RepairTechnician{RepairID: rows[2].ID, UserID: tech, Role: domain.RepairTechnicianAssistant, AssignedAt: day}).Error)
+
+	all, total, err := suite.repo.List(ctx, nil)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+	require.Len(suite.T(), all, 3)
+	assert.Equal(suite.T(), rows[2].ID, all[0].ID, "newest first")
+
+	completed := domain.RepairStatusCompleted
+	minCost := 100.0
+	page, total, err := suite.repo.List(ctx, &ports.RepairFilters{Status: &completed, MinCost: &minCost, Limit: 1})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total, "total ignores the page size")
+	require.Len(suite.T(), page, 1)
+	assert.Equal(suite.T(), rows[1].ID, page[0].ID)
+
+	got, total, err := suite.repo.List(ctx, &ports.RepairFilters{TechnicianID: &tech})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total, "led and assisted repairs")
+	assert.Len(suite.T(), got, 2)
+
+	from, to := day, day.AddDate(0, 0, 2)
+	maxCost := 700.0
+	got, total, err = suite.repo.List(ctx, &ports.RepairFilters{ServiceJobID: &job, CreatedFrom: &from, CreatedTo: &to, MaxCost: &maxCost, Search: "embrague"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.Len(suite.T(), got, 1)
+	assert.Equal(suite.T(), rows[0].ID, got[0].ID)
+
+	// LIKE wildcards in the search text match literally.
+	for _, search := range []string{"%", "_", `\`} {
+		_, total, err = suite.repo.List(ctx, &ports.RepairFilters{Search: search})
+		require.NoError(suite.T(), err)
+		assert.Zero(suite.T(), total, search)
+	}
+
+	got, total, err = suite.repo.List(ctx, &ports.RepairFilters{CreatedFrom: &to})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Equal(suite.T(), rows[2].ID, got[0].ID)
+
+	rework := true
+	got, total, err = suite.repo.List(ctx, &ports.RepairFilters{Rework: &rework})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	require.NotNil(suite.T(), got[0].ReworkOfRepairID)
+	assert.Equal(suite.T(), rows[1].ID, *got[0].ReworkOfRepairID)
+	rework = false
+	_, total, err = suite.repo.List(ctx, &ports.RepairFilters{Rework: &rework})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), total)
+}
+
+func (suite *RepairRepositoryTestSuite) TestWarrantiesByRepairAndCar() {
+	ctx := context.Background()
+	warranties := NewPostgresRepairWarrantyRepository(suite.db)
+	carID := uuid.New()
+	day := time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC)
+	older := RepairModel{ID: uuid.New(), CarID: carID, TechnicianID: uuid.New(), Description: "Frenos", Status: "completed", CreatedAt: day}
+	newer := RepairModel{ID: uuid.New(), CarID: carID, TechnicianID: uuid.New(), Description: "Embrague", Status: "completed", CreatedAt: day.AddDate(0, 2, 0)}
+	elsewhere := RepairModel{ID: uuid.New(), CarID: uuid.New(), TechnicianID: uuid.New(), Description: "Aceite", Status: "completed", CreatedAt: day}
+	for _, m := range []*RepairModel{&older, &newer, &elsewhere} {
+		require.NoError(suite.T(), suite.db.Create(m).Error)
+	}
+	warranty := func(repairID uuid.UUID, lineID *uuid.UUID, starts time.Time) domain.RepairWarranty {
+		return domain.RepairWarranty{ID: uuid.New(), RepairID: repairID, RepairLineID: lineID, Months: 12, StartsAt: starts, StartOdometerKM: 40000}
+	}
+	kit := uuid.New()
+	require.NoError(suite.T(), warranties.Replace(ctx, newer.ID, []domain.RepairWarranty{
+		warranty(newer.ID, &kit, newer.CreatedAt), warranty(newer.ID, nil, newer.CreatedAt)}))
+	require.NoError(suite.T(), warranties.Replace(ctx, older.ID, []domain.RepairWarranty{warranty(older.ID, nil, day)}))
+	require.NoError(suite.T(), warranties.Replace(ctx, elsewhere.ID, []domain.RepairWarranty{warranty(elsewhere.ID, nil, day)}))
+
+	got, err := warranties.ListByRepair(ctx, newer.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), got, 2)
+	assert.Nil(suite.T(), got[0].RepairLineID, "the work's warranty first")
+
+	byCar, err := warranties.ListByCar(ctx, carID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), byCar, 3)
+	assert.Equal(suite.T(), newer.ID, byCar[0].RepairID, "newest first")
+	assert.Equal(suite.T(), older.ID, byCar[2].RepairID)
+
+	require.NoError(suite.T(), warranties.Replace(ctx, newer.ID, nil))
+	got, err = warranties.ListByRepair(ctx, newer.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), got)
+	assert.ErrorIs(suite.T(), warranties.Replace(ctx, uuid.New(), nil), domain.ErrRepairNotFound)
+}
+
+func (suite *RepairRepositoryTestSuite) TestCreateRepairWithTechniciansIsAtomic() {
+	ctx := context.Background()
+	techs := NewPostgresRepairTechnicianRepository(suite.db)
+	lead, now := uuid.New(), time.Now().UTC()
+	newRepair := func() *domain.Repair {
+		return &domain.Repair{ID: uuid.New(), CarID: uuid.New(), TechnicianID: lead, Description: "Embrague", Status: domain.RepairStatusPending, CreatedAt: now, UpdatedAt: now}
+	}
+
+	ok := newRepair()
+	require.NoError(suite.T(), techs.CreateRepair(ctx, ok, []domain.RepairTechnician{{RepairID: ok.ID, UserID: lead, Role: domain.RepairTechnicianLead, AssignedAt: now}}))
+	ts, err := techs.ListByRepair(ctx, ok.ID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), ts, 1)
+
+	// A technician row that cannot be stored leaves no repair behind.
+	bad := newRepair()
+	dup := domain.RepairTechnician{RepairID: bad.ID, UserID: lead, Role: domain.RepairTechnicianLead, AssignedAt: now}
+	assert.Error(suite.T(), techs.CreateRepair(ctx, bad, []domain.RepairTechnician{dup, dup}))
+	_, err = suite.repo.GetByID(ctx, bad.ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrRepairNotFound)
+}
+
+func TestRepairRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RepairRepositoryTestSuite))
+}
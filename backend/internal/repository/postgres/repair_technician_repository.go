@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresRepairTechnicianRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepairTechnicianRepository returns a RepairTechnicianRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresRepairTechnicianRepository(db *gorm.DB) ports.RepairTechnicianRepository {
+	return &postgresRepairTechnicianRepository{db: db}
+}
+
+func (r *postgresRepairTechnicianRepository) CreateRepair(ctx context.Context, repair *domain.Repair, ts []domain.RepairTechnician) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(repair).Error; err != nil {
+			return fmt.Errorf("failed to create repair: %w", err)
+		}
+		if len(ts) == 0 {
+			return nil
+		}
+		if err := tx.Create(&ts).Error; err != nil {
+			return fmt.Errorf("failed to create repair technicians: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepairTechnicianRepository) Replace(ctx context.Context, repairID uuid.UUID, ts []domain.RepairTechnician) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepair(tx, repairID); err != nil {
+			return err
+		}
+		if err := tx.Where("repair_id = ?", repairID).Delete(&domain.RepairTechnician{}).Error; err != nil {
+			return fmt.Errorf("failed to clear repair technicians: %w", err)
+		}
+		if len(ts) == 0 {
+			return nil
+		}
+		if err := tx.Create(&ts).Error; err != nil {
+			return fmt.Errorf("failed to create repair technicians: %w", err)
+		}
+		if lead := domain.LeadTechnician(ts); lead != uuid.Nil {
+			if err := tx.Model(&RepairModel{}).Where("id = ?", repairID).Update("technician_id", lead).Error; err != nil {
+				return fmt.Errorf("failed to update repair lead technician: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepairTechnicianRepository) ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairTechnician, error) {
+	var rows []domain.RepairTechnician
+	err := r.db.WithContext(ctx).
+		Where("repair_id = ?", repairID).
+		Order("CASE WHEN role = 'lead' THEN 0 ELSE 1 END, assigned_at ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair technicians: %w", err)
+	}
+	if rows == nil {
+		rows = []domain.RepairTechnician{}
+	}
+	return rows, nil
+}
+
+var _ ports.RepairTechnicianRepository = (*postgresRepairTechnicianRepository)(nil)
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresRepairWarrantyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepairWarrantyRepository returns a RepairWarrantyRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresRepairWarrantyRepository(db *gorm.DB) ports.RepairWarrantyRepository {
+	return &postgresRepairWarrantyRepository{db: db}
+}
+
+func (r *postgresRepairWarrantyRepository) Replace(ctx context.Context, repairID uuid.UUID, ws []domain.RepairWarranty) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockRepair(tx, repairID); err != nil {
+			return err
+		}
+		if err := tx.Where("repair_id = ?", repairID).Delete(&domain.RepairWarranty{}).Error; err != nil {
+			return fmt.Errorf("failed to clear repair warranties: %w", err)
+		}
+		if len(ws) == 0 {
+			return nil
+		}
+		if err := tx.Create(&ws).Error; err != nil {
+			return fmt.Errorf("failed to create repair warranties: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepairWarrantyRepository) ListByRepair(ctx context.Context, repairID uuid.UUID) ([]domain.RepairWarranty, error) {
+	var rows []domain.RepairWarranty
+	// The work's warranty first, then the parts.
+	err := r.db.WithContext(ctx).
+		Where("repair_id = ?", repairID).
+		Order("CASE WHEN repair_line_id IS NULL THEN 0 ELSE 1 END, created_at ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair warranties: %w", err)
+	}
+	if rows == nil {
+		rows = []domain.RepairWarranty{}
+	}
+	return rows, nil
+}
+
+func (r *postgresRepairWarrantyRepository) ListByCar(ctx context.Context, carID uuid.UUID) ([]domain.RepairWarranty, error) {
+	var rows []domain.RepairWarranty
+	err := r.db.WithContext(ctx).
+		Where("repair_id IN (?)", r.db.Model(&RepairModel{}).Select("id").Where("car_id = ? AND deleted_at IS NULL", carID)).
+		Order("starts_at DESC, CASE WHEN repair_line_id IS NULL THEN 0 ELSE 1 END").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list car warranties: %w", err)
+	}
+	if rows == nil {
+		rows = []domain.RepairWarranty{}
+	}
+	return rows, nil
+}
+
+var _ ports.RepairWarrantyRepository = (*postgresRepairWarrantyRepository)(nil)
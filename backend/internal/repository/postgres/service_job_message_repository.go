@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresServiceJobMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresServiceJobMessageRepository returns a ServiceJobMessageRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresServiceJobMessageRepository(db *gorm.DB) ports.ServiceJobMessageRepository {
+	return &postgresServiceJobMessageRepository{db: db}
+}
+
+func (r *postgresServiceJobMessageRepository) Create(ctx context.Context, m *domain.ServiceJobMessage) error {
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return fmt.Errorf("failed to create service job message: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresServiceJobMessageRepository) ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobMessage, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.ServiceJobMessage
+	err := r.db.WithContext(ctx).
+		Preload("Attachments").
+		Where("service_job_id = ?", serviceJobID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service job messages: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.ServiceJobMessage{}
+	}
+	for _, m := range rows {
+		if m.Attachments == nil {
+			m.Attachments = []domain.ServiceJobMessageAttachment{}
+		}
+	}
+	return rows, nil
+}
+
+func (r *postgresServiceJobMessageRepository) MarkRead(ctx context.Context, serviceJobID uuid.UUID, sentBy domain.MessageSide, readerID uuid.UUID, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&domain.ServiceJobMessage{}).
+		Where("service_job_id = ? AND sender_side = ? AND read_at IS NULL AND created_at <= ?", serviceJobID, sentBy, at).
+		Updates(map[string]interface{}{"read_at": at, "read_by_user_id": readerID})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to mark service job messages read: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *postgresServiceJobMessageRepository) GetAttachment(ctx context.Context, serviceJobID, attachmentID uuid.UUID) (*domain.ServiceJobMessageAttachment, error) {
+	var row domain.ServiceJobMessageAttachment
+	err := r.db.WithContext(ctx).
+		Joins("JOIN service_job_messages m ON m.id = service_job_message_attachments.message_id").
+		Where("service_job_message_attachments.id = ? AND m.service_job_id = ?", attachmentID, serviceJobID).
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMessageAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get message attachment: %w", err)
+	}
+	return &row, nil
+}
+
+var _ ports.ServiceJobMessageRepository = (*postgresServiceJobMessageRepository)(nil)
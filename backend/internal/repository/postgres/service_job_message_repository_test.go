@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type ServiceJobMessageRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.ServiceJobMessageRepository
+}
+
+func (suite *ServiceJobMessageRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.ServiceJobMessage{}, &domain.ServiceJobMessageAttachment{}))
+	suite.db = db
+	suite.repo = NewPostgresServiceJobMessageRepository(db)
+}
+
+func (suite *ServiceJobMessageRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM service_job_message_attachments")
+	suite.db.Exec("DELETE FROM service_job_messages")
+}
+
+func (suite *ServiceJobMessageRepositoryTestSuite) post(jobID uuid.UUID, side domain.MessageSide, at time.Time, files ...string) *domain.ServiceJobMessage {
+	m := &domain.ServiceJobMessage{ID: uuid.New(), ServiceJobID: jobID, SenderUserID: uuid.New(), SenderSide: side, Body: "hola", CreatedAt: at}
+	for _, f := range files {
+		m.Attachments = append(m.Attachments, domain.ServiceJobMessageAttachment{ID: uuid.New(), MessageID: m.ID, FileID: f, FileName: f, ContentType: "image/jpeg", Size: 3})
+	}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), m))
+	return m
+}
+
+func (suite *ServiceJobMessageRepositoryTestSuite) TestThreadAndReadReceipts() {
+	ctx := context.Background()
+	jobID := uuid.New()
+	t0 := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	first := suite.post(jobID, domain.MessageSideClient, t0)
+	reply := suite.post(jobID, domain.MessageSideStaff, t0.Add(time.Minute), "foto.jpg")
+	suite.post(uuid.New(), domain.MessageSideClient, t0)
+
+	list, err := suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 2)
+	assert.Equal(suite.T(), first.ID, list[0].ID)
+	assert.NotNil(suite.T(), list[0].Attachments)
+	require.Len(suite.T(), list[1].Attachments, 1)
+
+	reader := uuid.New()
+	n, err := suite.repo.MarkRead(ctx, jobID, domain.MessageSideClient, reader, t0.Add(2*time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), n)
+	n, err = suite.repo.MarkRead(ctx, jobID, domain.MessageSideClient, uuid.New(), t0.Add(3*time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Zero(suite.T(), n, "already read")
+
+	list, err = suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), list[0].ReadAt)
+	assert.Equal(suite.T(), reader, *list[0].ReadByUserID)
+	assert.Nil(suite.T(), list[1].ReadAt)
+
+	att, err := suite.repo.GetAttachment(ctx, jobID, reply.Attachments[0].ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "foto.jpg", att.FileID)
+	_, err = suite.repo.GetAttachment(ctx, uuid.New(), reply.Attachments[0].ID)
+	assert.ErrorIs(suite.T(), err, domain.ErrMessageAttachmentNotFound)
+}
+
+func TestServiceJobMessageRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceJobMessageRepositoryTestSuite))
+}
@@ -73,33 +73,117 @@ func (r *PostgresServiceJobRepository) ListByOpenedOn(ctx context.Context, day t
 	return rows, nil
 }
 
-func (r *PostgresServiceJobRepository) SaveReception(ctx context.Context, rec *domain.ServiceJobReception) error {
-	var m domain.ServiceJobReception
-	tx := r.db.WithContext(ctx)
-	err := tx.Where("service_job_id = ?", rec.ServiceJobID).First(&m).Error
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+// ListActive returns open and in-progress visits, oldest first (the workshop board).
+func (r *PostgresServiceJobRepository) ListActive(ctx context.Context) ([]*domain.ServiceJob, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.ServiceJob
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND deleted_at IS NULL", []domain.ServiceJobStatus{domain.ServiceJobStatusOpen, domain.ServiceJobStatusInProgress}).
+		Order("opened_at asc").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []*domain.ServiceJob{}
 	}
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if err := tx.Create(rec).Error; err != nil {
-			return fmt.Errorf("create reception: %w", err)
+	return rows, nil
+}
+
+// GetByAppointmentID returns the visit opened from the appointment at check-in, or nil, nil if none.
+func (r *PostgresServiceJobRepository) GetByAppointmentID(ctx context.Context, appointmentID uuid.UUID) (*domain.ServiceJob, error) {
+	var j domain.ServiceJob
+	if err := r.db.WithContext(ctx).Where("appointment_id = ? AND deleted_at IS NULL", appointmentID).First(&j).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
-		return nil
+		return nil, err
 	}
-	m.OdometerKM = rec.OdometerKM
-	m.OilLevel = rec.OilLevel
-	m.CoolantLevel = rec.CoolantLevel
-	m.TiresNote = rec.TiresNote
-	m.GeneralNotes = rec.GeneralNotes
-	m.RecordedByUserID = rec.RecordedByUserID
-	m.RecordedAt = rec.RecordedAt
-	if rec.SchemaVersion > 0 {
-		m.SchemaVersion = rec.SchemaVersion
+	return &j, nil
+}
+
+func (r *PostgresServiceJobRepository) ListByAppointmentIDs(ctx context.Context, appointmentIDs []uuid.UUID) ([]*domain.ServiceJob, error) {
+	rows := []*domain.ServiceJob{}
+	if len(appointmentIDs) == 0 {
+		return rows, nil
 	}
-	if err := tx.Save(&m).Error; err != nil {
-		return fmt.Errorf("update reception: %w", err)
+	if err := r.db.WithContext(ctx).Where("appointment_id IN ? AND deleted_at IS NULL", appointmentIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list service jobs by appointment: %w", err)
 	}
-	return nil
+	return rows, nil
+}
+
+func (r *PostgresServiceJobRepository) CreateForAppointment(ctx context.Context, job *domain.ServiceJob) error {
+	if job.AppointmentID == nil {
+		return domain.ErrAppointmentNotCheckInable
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The conditional update locks the appointment row, so a concurrent check-in waits and then finds it checked in.
+		res := tx.Model(&AppointmentModel{}).
+			Where("id = ? AND status IN ? AND deleted_at IS NULL", *job.AppointmentID,
+				[]string{string(domain.AppointmentStatusScheduled), string(domain.AppointmentStatusConfirmed)}).
+			Updates(map[string]interface{}{
+				"status":     string(domain.AppointmentStatusCheckedIn),
+				"sequence":   gorm.Expr("sequence + 1"),
+				"updated_at": job.CreatedAt,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("check in appointment: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			var m AppointmentModel
+			if err := tx.Select("status").Where("id = ? AND deleted_at IS NULL", *job.AppointmentID).First(&m).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrAppointmentNotFound
+				}
+				return fmt.Errorf("check in appointment: %w", err)
+			}
+			if m.Status == string(domain.AppointmentStatusCheckedIn) {
+				return domain.ErrAppointmentAlreadyCheckedIn
+			}
+			return domain.ErrAppointmentNotCheckInable
+		}
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("create service job: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresServiceJobRepository) SaveReception(ctx context.Context, rec *domain.ServiceJobReception, ev *domain.ServiceJobStatusEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m domain.ServiceJobReception
+		err := tx.Where("service_job_id = ?", rec.ServiceJobID).First(&m).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Create(rec).Error; err != nil {
+				return fmt.Errorf("create reception: %w", err)
+			}
+			return applyStatusEvent(tx, ev)
+		}
+		m.OdometerKM = rec.OdometerKM
+		m.OilLevel = rec.OilLevel
+		m.CoolantLevel = rec.CoolantLevel
+		m.TiresNote = rec.TiresNote
+		m.GeneralNotes = rec.GeneralNotes
+		m.ChecklistTemplateID = rec.ChecklistTemplateID
+		m.ChecklistAnswers = rec.ChecklistAnswers
+		m.RecordedByUserID = rec.RecordedByUserID
+		m.RecordedAt = rec.RecordedAt
+		if rec.SchemaVersion > 0 {
+			m.SchemaVersion = rec.SchemaVersion
+		}
+		if rec.Signature != nil {
+			m.Signature = rec.Signature // a re-save without a new signature keeps the one already given
+		}
+		if err := tx.Save(&m).Error; err != nil {
+			return fmt.Errorf("update reception: %w", err)
+		}
+		return applyStatusEvent(tx, ev)
+	})
 }
 
 func (r *PostgresServiceJobRepository) GetReception(ctx context.Context, serviceJobID uuid.UUID) (*domain.ServiceJobReception, error) {
@@ -113,31 +197,37 @@ func (r *PostgresServiceJobRepository) GetReception(ctx context.Context, service
 	return &out, nil
 }
 
-func (r *PostgresServiceJobRepository) SaveHandover(ctx context.Context, h *domain.ServiceJobHandover) error {
-	var m domain.ServiceJobHandover
-	tx := r.db.WithContext(ctx)
-	err := tx.Where("service_job_id = ?", h.ServiceJobID).First(&m).Error
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
-	}
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if err := tx.Create(h).Error; err != nil {
-			return fmt.Errorf("create handover: %w", err)
+func (r *PostgresServiceJobRepository) SaveHandover(ctx context.Context, h *domain.ServiceJobHandover, ev *domain.ServiceJobStatusEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m domain.ServiceJobHandover
+		err := tx.Where("service_job_id = ?", h.ServiceJobID).First(&m).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
 		}
-		return nil
-	}
-	m.OdometerKM = h.OdometerKM
-	m.TiresNote = h.TiresNote
-	m.GeneralNotes = h.GeneralNotes
-	m.RecordedByUserID = h.RecordedByUserID
-	m.RecordedAt = h.RecordedAt
-	if h.SchemaVersion > 0 {
-		m.SchemaVersion = h.SchemaVersion
-	}
-	if err := tx.Save(&m).Error; err != nil {
-		return fmt.Errorf("update handover: %w", err)
-	}
-	return nil
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Create(h).Error; err != nil {
+				return fmt.Errorf("create handover: %w", err)
+			}
+			return applyStatusEvent(tx, ev)
+		}
+		m.OdometerKM = h.OdometerKM
+		m.TiresNote = h.TiresNote
+		m.GeneralNotes = h.GeneralNotes
+		m.ChecklistTemplateID = h.ChecklistTemplateID
+		m.ChecklistAnswers = h.ChecklistAnswers
+		m.RecordedByUserID = h.RecordedByUserID
+		m.RecordedAt = h.RecordedAt
+		if h.SchemaVersion > 0 {
+			m.SchemaVersion = h.SchemaVersion
+		}
+		if h.Signature != nil {
+			m.Signature = h.Signature
+		}
+		if err := tx.Save(&m).Error; err != nil {
+			return fmt.Errorf("update handover: %w", err)
+		}
+		return applyStatusEvent(tx, ev)
+	})
 }
 
 func (r *PostgresServiceJobRepository) GetHandover(ctx context.Context, serviceJobID uuid.UUID) (*domain.ServiceJobHandover, error) {
@@ -150,3 +240,85 @@ func (r *PostgresServiceJobRepository) GetHandover(ctx context.Context, serviceJ
 	}
 	return &out, nil
 }
+
+func (r *PostgresServiceJobRepository) ChangeStatus(ctx context.Context, ev *domain.ServiceJobStatusEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return applyStatusEvent(tx, ev)
+	})
+}
+
+func (r *PostgresServiceJobRepository) ListStatusEvents(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobStatusEvent, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.ServiceJobStatusEvent
+	err := r.db.WithContext(ctx).
+		Where("service_job_id = ?", serviceJobID).
+		Order("changed_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list service job status events: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.ServiceJobStatusEvent{}
+	}
+	return rows, nil
+}
+
+// applyStatusEvent moves the visit to ev.ToStatus only while it is still in ev.FromStatus, then records ev.
+// A nil ev is a no-op, for checklist saves that do not change the status.
+func (r *PostgresServiceJobRepository) SetPromise(ctx context.Context, rev *domain.ServiceJobPromiseRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.ServiceJob{}).
+			Where("id = ? AND status IN ? AND deleted_at IS NULL", rev.ServiceJobID,
+				[]domain.ServiceJobStatus{domain.ServiceJobStatusOpen, domain.ServiceJobStatusInProgress}).
+			Updates(map[string]interface{}{"promised_at": rev.PromisedAt, "updated_at": rev.ChangedAt})
+		if res.Error != nil {
+			return fmt.Errorf("update service job promise: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrServiceJobStatusConflict
+		}
+		if err := tx.Create(rev).Error; err != nil {
+			return fmt.Errorf("create service job promise revision: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresServiceJobRepository) ListPromiseRevisions(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.ServiceJobPromiseRevision, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.ServiceJobPromiseRevision
+	err := r.db.WithContext(ctx).
+		Where("service_job_id = ?", serviceJobID).
+		Order("changed_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list service job promise revisions: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.ServiceJobPromiseRevision{}
+	}
+	return rows, nil
+}
+
+func applyStatusEvent(tx *gorm.DB, ev *domain.ServiceJobStatusEvent) error {
+	if ev == nil {
+		return nil
+	}
+	var j domain.ServiceJob
+	j.Apply(ev)
+	res := tx.Model(&domain.ServiceJob{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", ev.ServiceJobID, ev.FromStatus).
+		Updates(map[string]interface{}{"status": j.Status, "closed_at": j.ClosedAt, "updated_at": j.UpdatedAt})
+	if res.Error != nil {
+		return fmt.Errorf("update service job status: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrServiceJobStatusConflict
+	}
+	if err := tx.Create(ev).Error; err != nil {
+		return fmt.Errorf("create service job status event: %w", err)
+	}
+	return nil
+}
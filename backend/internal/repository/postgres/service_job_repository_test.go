@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type ServiceJobRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.ServiceJobRepository
+}
+
+func (suite *ServiceJobRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	// service_jobs defaults its id with gen_random_uuid(), which sqlite cannot migrate.
+	require.NoError(suite.T(), db.Exec(`CREATE TABLE service_jobs (
+		id uuid PRIMARY KEY, car_id uuid NOT NULL, status text NOT NULL DEFAULT 'open',
+		opened_by_user_id uuid NOT NULL, opened_at datetime NOT NULL, closed_at datetime, promised_at datetime,
+		appointment_id uuid, created_at datetime, updated_at datetime, deleted_at datetime)`).Error)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.ServiceJobReception{}, &domain.ServiceJobHandover{}, &domain.ServiceJobStatusEvent{}, &domain.ServiceJobPromiseRevision{}, &AppointmentModel{}))
+	suite.db = db
+	suite.repo = NewPostgresServiceJobRepository(db)
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM service_job_promise_revisions")
+	suite.db.Exec("DELETE FROM service_job_status_events")
+	suite.db.Exec("DELETE FROM service_job_handovers")
+	suite.db.Exec("DELETE FROM service_job_receptions")
+	suite.db.Exec("DELETE FROM service_jobs")
+	suite.db.Exec("DELETE FROM appointments")
+}
+
+func (suite *ServiceJobRepositoryTestSuite) createJob(status domain.ServiceJobStatus) *domain.ServiceJob {
+	j := &domain.ServiceJob{ID: uuid.New(), CarID: uuid.New(), Status: status, OpenedByUserID: uuid.New(), OpenedAt: time.Now().UTC()}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), j))
+	return j
+}
+
+func statusEvent(j *domain.ServiceJob, from, to domain.ServiceJobStatus, at time.Time) *domain.ServiceJobStatusEvent {
+	return &domain.ServiceJobStatusEvent{ID: uuid.New(), ServiceJobID: j.ID, FromStatus: from, ToStatus: to, ChangedByUserID: uuid.New(), ChangedAt: at}
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestChangeStatusComparesAndRecords() {
+	ctx := context.Background()
+	j := suite.createJob(domain.ServiceJobStatusInProgress)
+	t0 := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(suite.T(), suite.repo.ChangeStatus(ctx, statusEvent(j, domain.ServiceJobStatusInProgress, domain.ServiceJobStatusClosed, t0)))
+	got, err := suite.repo.GetByID(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ServiceJobStatusClosed, got.Status)
+	require.NotNil(suite.T(), got.ClosedAt)
+
+	stale := statusEvent(j, domain.ServiceJobStatusInProgress, domain.ServiceJobStatusCancelled, t0.Add(time.Minute))
+	assert.ErrorIs(suite.T(), suite.repo.ChangeStatus(ctx, stale), domain.ErrServiceJobStatusConflict)
+
+	require.NoError(suite.T(), suite.repo.ChangeStatus(ctx, statusEvent(j, domain.ServiceJobStatusClosed, domain.ServiceJobStatusInProgress, t0.Add(2*time.Minute))))
+	got, err = suite.repo.GetByID(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ServiceJobStatusInProgress, got.Status)
+	assert.Nil(suite.T(), got.ClosedAt)
+
+	history, err := suite.repo.ListStatusEvents(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), history, 2)
+	assert.Equal(suite.T(), domain.ServiceJobStatusClosed, history[0].ToStatus)
+	assert.Equal(suite.T(), domain.ServiceJobStatusInProgress, history[1].ToStatus)
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestSaveReceptionRollsBackOnStatusConflict() {
+	ctx := context.Background()
+	j := suite.createJob(domain.ServiceJobStatusCancelled)
+	rec := &domain.ServiceJobReception{ServiceJobID: j.ID, OdometerKM: 10, RecordedByUserID: uuid.New(), RecordedAt: time.Now().UTC()}
+
+	err := suite.repo.SaveReception(ctx, rec, statusEvent(j, domain.ServiceJobStatusOpen, domain.ServiceJobStatusInProgress, time.Now().UTC()))
+	assert.ErrorIs(suite.T(), err, domain.ErrServiceJobStatusConflict)
+	got, err := suite.repo.GetReception(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), got)
+
+	open := suite.createJob(domain.ServiceJobStatusOpen)
+	rec.ServiceJobID = open.ID
+	require.NoError(suite.T(), suite.repo.SaveReception(ctx, rec, statusEvent(open, domain.ServiceJobStatusOpen, domain.ServiceJobStatusInProgress, time.Now().UTC())))
+	job, err := suite.repo.GetByID(ctx, open.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ServiceJobStatusInProgress, job.Status)
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestReceptionSignatureKeptOnResave() {
+	ctx := context.Background()
+	j := suite.createJob(domain.ServiceJobStatusInProgress)
+	rec := &domain.ServiceJobReception{ServiceJobID: j.ID, OdometerKM: 10, RecordedByUserID: uuid.New(), RecordedAt: time.Now().UTC()}
+	require.NoError(suite.T(), suite.repo.SaveReception(ctx, rec, nil))
+	got, err := suite.repo.GetReception(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), got.Signature)
+
+	sig := &domain.CustomerSignature{FileID: "signature-1.svg", ContentType: domain.SignatureContentTypeSVG, SHA256: "abc", SignerName: "Ana", SignedAt: time.Now().UTC().Truncate(time.Second)}
+	rec.Signature = sig
+	require.NoError(suite.T(), suite.repo.SaveReception(ctx, rec, nil))
+	rec.Signature, rec.OdometerKM = nil, 12
+	require.NoError(suite.T(), suite.repo.SaveReception(ctx, rec, nil))
+	got, err = suite.repo.GetReception(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 12, got.OdometerKM)
+	require.NotNil(suite.T(), got.Signature)
+	assert.Equal(suite.T(), "Ana", got.Signature.SignerName)
+	assert.Equal(suite.T(), "abc", got.Signature.SHA256)
+	assert.True(suite.T(), sig.SignedAt.Equal(got.Signature.SignedAt))
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestListActiveOldestFirst() {
+	ctx := context.Background()
+	later := suite.createJob(domain.ServiceJobStatusOpen)
+	older := suite.createJob(domain.ServiceJobStatusInProgress)
+	require.NoError(suite.T(), suite.db.Exec("UPDATE service_jobs SET opened_at = ? WHERE id = ?", time.Now().UTC().Add(-time.Hour), older.ID).Error)
+	suite.createJob(domain.ServiceJobStatusClosed)
+	suite.createJob(domain.ServiceJobStatusCancelled)
+
+	got, err := suite.repo.ListActive(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), got, 2)
+	assert.Equal(suite.T(), older.ID, got[0].ID)
+	assert.Equal(suite.T(), later.ID, got[1].ID)
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestSetPromiseOnlyOnActiveVisits() {
+	ctx := context.Background()
+	j := suite.createJob(domain.ServiceJobStatusOpen)
+	at := time.Now().UTC().Add(3 * time.Hour).Truncate(time.Second)
+	rev := &domain.ServiceJobPromiseRevision{ID: uuid.New(), ServiceJobID: j.ID, PromisedAt: at, ChangedByUserID: uuid.New(), ChangedAt: time.Now().UTC()}
+	require.NoError(suite.T(), suite.repo.SetPromise(ctx, rev))
+	got, err := suite.repo.GetByID(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), got.PromisedAt)
+	assert.True(suite.T(), at.Equal(*got.PromisedAt))
+
+	closed := suite.createJob(domain.ServiceJobStatusClosed)
+	stale := &domain.ServiceJobPromiseRevision{ID: uuid.New(), ServiceJobID: closed.ID, PromisedAt: at, ChangedByUserID: uuid.New(), ChangedAt: time.Now().UTC()}
+	assert.ErrorIs(suite.T(), suite.repo.SetPromise(ctx, stale), domain.ErrServiceJobStatusConflict)
+
+	revs, err := suite.repo.ListPromiseRevisions(ctx, j.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), revs, 1)
+	assert.Equal(suite.T(), rev.ID, revs[0].ID)
+	revs, err = suite.repo.ListPromiseRevisions(ctx, closed.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), revs)
+}
+
+func (suite *ServiceJobRepositoryTestSuite) TestCreateForAppointmentIsAtomic() {
+	ctx := context.Background()
+	appt := AppointmentModel{ID: uuid.New(), CustomerID: uuid.New(), CarID: uuid.New(), ScheduledTime: time.Now().UTC(),
+		Status: string(domain.AppointmentStatusConfirmed), ServiceType: "oil"}
+	require.NoError(suite.T(), suite.db.Create(&appt).Error)
+	newJob := func() *domain.ServiceJob {
+		return &domain.ServiceJob{ID: uuid.New(), CarID: appt.CarID, Status: domain.ServiceJobStatusOpen, OpenedByUserID: uuid.New(),
+			OpenedAt: time.Now().UTC(), AppointmentID: &appt.ID, CreatedAt: time.Now().UTC()}
+	}
+
+	job := newJob()
+	require.NoError(suite.T(), suite.repo.CreateForAppointment(ctx, job))
+	var got AppointmentModel
+	require.NoError(suite.T(), suite.db.First(&got, "id = ?", appt.ID).Error)
+	assert.Equal(suite.T(), string(domain.AppointmentStatusCheckedIn), got.Status)
+	assert.ErrorIs(suite.T(), suite.repo.CreateForAppointment(ctx, newJob()), domain.ErrAppointmentAlreadyCheckedIn)
+
+	// A failed insert leaves the appointment as it was.
+	require.NoError(suite.T(), suite.db.Model(&AppointmentModel{}).Where("id = ?", appt.ID).Update("status", string(domain.AppointmentStatusScheduled)).Error)
+	dup := newJob()
+	dup.ID = job.ID
+	assert.Error(suite.T(), suite.repo.CreateForAppointment(ctx, dup))
+	require.NoError(suite.T(), suite.db.First(&got, "id = ?", appt.ID).Error)
+	assert.Equal(suite.T(), string(domain.AppointmentStatusScheduled), got.Status)
+
+	require.NoError(suite.T(), suite.db.Model(&AppointmentModel{}).Where("id = ?", appt.ID).Update("status", string(domain.AppointmentStatusCancelled)).Error)
+	assert.ErrorIs(suite.T(), suite.repo.CreateForAppointment(ctx, newJob()), domain.ErrAppointmentNotCheckInable)
+
+	jobs, err := suite.repo.ListByAppointmentIDs(ctx, []uuid.UUID{appt.ID, uuid.New()})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), jobs, 1)
+	assert.Equal(suite.T(), job.ID, jobs[0].ID)
+}
+
+func TestServiceJobRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceJobRepositoryTestSuite))
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresWaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresWaitlistRepository returns a WaitlistRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresWaitlistRepository(db *gorm.DB) ports.WaitlistRepository {
+	return &postgresWaitlistRepository{db: db}
+}
+
+func (r *postgresWaitlistRepository) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresWaitlistRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WaitlistEntry, error) {
+	var row domain.WaitlistEntry
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresWaitlistRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("customer_id = ? AND status IN ?", customerID, []domain.WaitlistStatus{domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered}).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.WaitlistEntry{}
+	}
+	return rows, nil
+}
+
+func (r *postgresWaitlistRepository) ListByDay(ctx context.Context, day time.Time) ([]*domain.WaitlistEntry, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("day = ?", day.UTC()).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.WaitlistEntry{}
+	}
+	return rows, nil
+}
+
+func (r *postgresWaitlistRepository) NextWaiting(ctx context.Context, day time.Time) (*domain.WaitlistEntry, error) {
+	var row domain.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("day = ? AND status = ?", day.UTC(), domain.WaitlistStatusWaiting).
+		Order("created_at ASC, id ASC").
+		First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get next waitlist entry: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *postgresWaitlistRepository) Transition(ctx context.Context, id uuid.UUID, from, to domain.WaitlistStatus, patch ports.WaitlistPatch) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     to,
+		"updated_at": time.Now().UTC(),
+	}
+	if patch.OfferedSlot != nil {
+		updates["offered_slot"] = patch.OfferedSlot.UTC()
+	}
+	if patch.OfferExpiresAt != nil {
+		updates["offer_expires_at"] = patch.OfferExpiresAt.UTC()
+	}
+	if patch.AppointmentID != nil {
+		updates["appointment_id"] = *patch.AppointmentID
+	}
+	res := r.db.WithContext(ctx).Model(&domain.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to update waitlist entry: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *postgresWaitlistRepository) ListExpiredOffers(ctx context.Context, now time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	limit, _ = clampRepoList(limit, 0)
+	var rows []*domain.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND offer_expires_at < ?", domain.WaitlistStatusOffered, now.UTC()).
+		Order("offer_expires_at ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired waitlist offers: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.WaitlistEntry{}
+	}
+	return rows, nil
+}
+
+var _ ports.WaitlistRepository = (*postgresWaitlistRepository)(nil)
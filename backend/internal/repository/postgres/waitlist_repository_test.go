@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type WaitlistRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.WaitlistRepository
+}
+
+func (suite *WaitlistRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.WaitlistEntry{}))
+	suite.db = db
+	suite.repo = NewPostgresWaitlistRepository(db)
+}
+
+func (suite *WaitlistRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM waitlist_entries")
+}
+
+func (suite *WaitlistRepositoryTestSuite) newEntry(day time.Time, createdAt time.Time) *domain.WaitlistEntry {
+	e := &domain.WaitlistEntry{
+		ID:          uuid.New(),
+		CustomerID:  uuid.New(),
+		CarID:       uuid.New(),
+		ServiceType: "inspection",
+		Day:         day,
+		Status:      domain.WaitlistStatusWaiting,
+		CreatedAt:   createdAt,
+	}
+	require.NoError(suite.T(), suite.repo.Create(context.Background(), e))
+	return e
+}
+
+func (suite *WaitlistRepositoryTestSuite) TestNextWaiting_FIFO() {
+	ctx := context.Background()
+	day := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	base := time.Now().UTC()
+	second := suite.newEntry(day, base.Add(time.Minute))
+	first := suite.newEntry(day, base)
+	suite.newEntry(day.AddDate(0, 0, 1), base.Add(-time.Hour))
+
+	next, err := suite.repo.NextWaiting(ctx, day)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), next)
+	assert.Equal(suite.T(), first.ID, next.ID)
+
+	ok, err := suite.repo.Transition(ctx, first.ID, domain.WaitlistStatusWaiting, domain.WaitlistStatusCancelled, ports.WaitlistPatch{})
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	next, err = suite.repo.NextWaiting(ctx, day)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), second.ID, next.ID)
+
+	list, err := suite.repo.ListByDay(ctx, day)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), list, 2)
+}
+
+func (suite *WaitlistRepositoryTestSuite) TestTransition_IsConditional() {
+	ctx := context.Background()
+	day := time.Date(2030, 6, 14, 0, 0, 0, 0, time.UTC)
+	e := suite.newEntry(day, time.Now().UTC())
+	slot := day.Add(10 * time.Hour)
+	expires := time.Now().UTC().Add(-time.Minute)
+
+	ok, err := suite.repo.Transition(ctx, e.ID, domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered,
+		ports.WaitlistPatch{OfferedSlot: &slot, OfferExpiresAt: &expires})
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+
+	// Already offered: a second offer of the same entry loses.
+	ok, err = suite.repo.Transition(ctx, e.ID, domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered, ports.WaitlistPatch{})
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), ok)
+
+	expired, err := suite.repo.ListExpiredOffers(ctx, time.Now(), 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), expired, 1)
+	require.NotNil(suite.T(), expired[0].OfferedSlot)
+	assert.True(suite.T(), slot.Equal(*expired[0].OfferedSlot))
+
+	apptID := uuid.New()
+	ok, err = suite.repo.Transition(ctx, e.ID, domain.WaitlistStatusOffered, domain.WaitlistStatusClaimed, ports.WaitlistPatch{AppointmentID: &apptID})
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), ok)
+	got, err := suite.repo.GetByID(ctx, e.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.WaitlistStatusClaimed, got.Status)
+	assert.Equal(suite.T(), apptID, *got.AppointmentID)
+
+	mine, err := suite.repo.ListByCustomer(ctx, e.CustomerID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), mine)
+
+	_, err = suite.repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(suite.T(), err, domain.ErrWaitlistEntryNotFound)
+}
+
+func TestWaitlistRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WaitlistRepositoryTestSuite))
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type postgresWorkHourRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresWorkHourRepository returns a WorkHourRepository backed by GORM (PostgreSQL or sqlite tests).
+func NewPostgresWorkHourRepository(db *gorm.DB) ports.WorkHourRepository {
+	return &postgresWorkHourRepository{db: db}
+}
+
+func (r *postgresWorkHourRepository) Start(ctx context.Context, w *domain.WorkHour) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var running int64
+		if err := tx.Model(&domain.WorkHour{}).Where("technician_id = ? AND end_time IS NULL", w.TechnicianID).Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return domain.ErrWorkTimerRunning
+		}
+		var employeeIDs []uuid.UUID
+		if err := tx.Table("employees").Where("user_id = ? AND deleted_at IS NULL", w.TechnicianID).Limit(1).Pluck("id", &employeeIDs).Error; err != nil {
+			return fmt.Errorf("find employee: %w", err)
+		}
+		if len(employeeIDs) > 0 {
+			w.EmployeeID = &employeeIDs[0]
+		}
+		return tx.Omit(clause.Associations).Create(w).Error
+	})
+	if err == nil || errors.Is(err, domain.ErrWorkTimerRunning) {
+		return err
+	}
+	// Two clock-ins racing past the check: the partial unique index rejects the second.
+	if running, _ := r.GetRunning(ctx, w.TechnicianID); running != nil {
+		return domain.ErrWorkTimerRunning
+	}
+	return fmt.Errorf("failed to start work timer: %w", err)
+}
+
+func (r *postgresWorkHourRepository) Stop(ctx context.Context, technicianID uuid.UUID, end time.Time) (*domain.WorkHour, error) {
+	var out *domain.WorkHour
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var w domain.WorkHour
+		err := tx.Where("technician_id = ? AND end_time IS NULL", technicianID).First(&w).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNoWorkTimerRunning
+		}
+		if err != nil {
+			return err
+		}
+		if end.Before(w.StartTime) {
+			end = w.StartTime
+		}
+		w.EndTime = &end
+		w.Hours = w.CalculateHours()
+		res := tx.Model(&domain.WorkHour{}).
+			Where("id = ? AND end_time IS NULL", w.ID).
+			Updates(map[string]interface{}{"end_time": end, "hours": w.Hours, "updated_at": time.Now().UTC()})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrNoWorkTimerRunning // stopped meanwhile
+		}
+		if w.EmployeeID != nil {
+			if err := tx.Table("employees").Where("id = ?", *w.EmployeeID).
+				Update("hours_worked", gorm.Expr("COALESCE(hours_worked, 0) + ?", w.Hours)).Error; err != nil {
+				return fmt.Errorf("add employee hours: %w", err)
+			}
+		}
+		out = &w
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNoWorkTimerRunning) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to stop work timer: %w", err)
+	}
+	return out, nil
+}
+
+func (r *postgresWorkHourRepository) GetRunning(ctx context.Context, technicianID uuid.UUID) (*domain.WorkHour, error) {
+	var w domain.WorkHour
+	err := r.db.WithContext(ctx).Where("technician_id = ? AND end_time IS NULL", technicianID).First(&w).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *postgresWorkHourRepository) ListByServiceJob(ctx context.Context, serviceJobID uuid.UUID) ([]*domain.WorkHour, error) {
+	limit, _ := clampRepoList(500, 0)
+	var rows []*domain.WorkHour
+	err := r.db.WithContext(ctx).
+		Where("service_job_id = ?", serviceJobID).
+		Order("start_time ASC").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work hours: %w", err)
+	}
+	if rows == nil {
+		rows = []*domain.WorkHour{}
+	}
+	return rows, nil
+}
+
+var _ ports.WorkHourRepository = (*postgresWorkHourRepository)(nil)
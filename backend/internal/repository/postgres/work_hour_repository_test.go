@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type WorkHourRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ports.WorkHourRepository
+}
+
+func (suite *WorkHourRepositoryTestSuite) SetupSuite() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), db.Exec(`CREATE TABLE employees (
+		id text PRIMARY KEY, user_id text NOT NULL, hours_worked real DEFAULT 0, deleted_at datetime
+	)`).Error)
+	require.NoError(suite.T(), db.AutoMigrate(&domain.WorkHour{}))
+	suite.db = db
+	suite.repo = NewPostgresWorkHourRepository(db)
+}
+
+func (suite *WorkHourRepositoryTestSuite) TearDownTest() {
+	suite.db.Exec("DELETE FROM work_hours")
+	suite.db.Exec("DELETE FROM employees")
+}
+
+func (suite *WorkHourRepositoryTestSuite) TestOneRunningTimerAndHoursBooked() {
+	ctx := context.Background()
+	techID, employeeID, jobID := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(suite.T(), suite.db.Exec("INSERT INTO employees (id, user_id, hours_worked) VALUES (?, ?, 2)", employeeID, techID).Error)
+	start := time.Now().UTC().Add(-90 * time.Minute).Truncate(time.Second)
+
+	w := domain.NewWorkHour(techID, start, start)
+	w.ServiceJobID = &jobID
+	require.NoError(suite.T(), suite.repo.Start(ctx, w))
+	require.NotNil(suite.T(), w.EmployeeID)
+	assert.Equal(suite.T(), employeeID, *w.EmployeeID)
+	assert.ErrorIs(suite.T(), suite.repo.Start(ctx, domain.NewWorkHour(techID, start, start)), domain.ErrWorkTimerRunning)
+
+	// The partial unique index backs the check when two clock-ins race.
+	dup := domain.NewWorkHour(techID, start, start)
+	assert.Error(suite.T(), suite.db.Create(dup).Error)
+
+	running, err := suite.repo.GetRunning(ctx, techID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), running)
+	assert.Equal(suite.T(), w.ID, running.ID)
+
+	stopped, err := suite.repo.Stop(ctx, techID, start.Add(90*time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1.5, stopped.Hours)
+	var worked float64
+	require.NoError(suite.T(), suite.db.Raw("SELECT hours_worked FROM employees WHERE id = ?", employeeID).Scan(&worked).Error)
+	assert.Equal(suite.T(), 3.5, worked)
+
+	_, err = suite.repo.Stop(ctx, techID, time.Now().UTC())
+	assert.ErrorIs(suite.T(), err, domain.ErrNoWorkTimerRunning)
+	running, err = suite.repo.GetRunning(ctx, techID)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), running)
+
+	// Free again: the next timer starts, and both show on the visit.
+	next := domain.NewWorkHour(techID, start, start.Add(2*time.Hour))
+	next.ServiceJobID = &jobID
+	require.NoError(suite.T(), suite.repo.Start(ctx, next))
+	rows, err := suite.repo.ListByServiceJob(ctx, jobID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rows, 2)
+	assert.Equal(suite.T(), w.ID, rows[0].ID)
+	assert.True(suite.T(), rows[1].Running())
+}
+
+func (suite *WorkHourRepositoryTestSuite) TestTechnicianWithoutEmployeeRecord() {
+	ctx := context.Background()
+	techID := uuid.New()
+	start := time.Now().UTC().Add(-time.Hour)
+	w := domain.NewWorkHour(techID, start, start)
+	require.NoError(suite.T(), suite.repo.Start(ctx, w))
+	assert.Nil(suite.T(), w.EmployeeID)
+	stopped, err := suite.repo.Stop(ctx, techID, start.Add(30*time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0.5, stopped.Hours)
+}
+
+func TestWorkHourRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkHourRepositoryTestSuite))
+}
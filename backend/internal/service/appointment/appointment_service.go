@@ -10,14 +10,47 @@ import (
 	"strings"
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
 	"github.com/google/uuid"
 )
 
 type AppointmentService struct {
-	repo     ports.AppointmentRepository
-	userRepo ports.UserRepository
-	carRepo  ports.CarRepository
+	repo          ports.AppointmentRepository
+	userRepo      ports.UserRepository
+	carRepo       ports.CarRepository
+	leaveRepo     ports.EmployeeLeaveRepository   // optional: nil skips the on-leave check
+	signer        *signedlink.Signer              // optional: required for confirm/cancel links and calendar feeds
+	notifier      services.NotificationService    // optional: nil skips confirmation emails
+	calendarLoc   *time.Location                  // zone of .ics event times (default time.Local)
+	waitRepo      ports.WaitlistRepository        // optional: nil disables the waitlist
+	publicBaseURL string                          // frontend origin for links in waitlist offers
+	policy        ChangePolicy                    // client cancel / reschedule rules
+	feedKeys      ports.CalendarFeedKeyRepository // optional: required for calendar feeds
+}
+
+// Option configures optional collaborators of AppointmentService.
+type Option func(*AppointmentService)
+
+// WithEmployeeLeaveRepository enables leave management and the on-leave check on assignment.
+func WithEmployeeLeaveRepository(repo ports.EmployeeLeaveRepository) Option {
+	return func(s *AppointmentService) { s.leaveRepo = repo }
+}
+
+// WithLinkSigner enables tokenized confirm/cancel links (see rsvp.go).
+func WithLinkSigner(signer *signedlink.Signer) Option {
+	return func(s *AppointmentService) { s.signer = signer }
+}
+
+// WithNotifier enables the confirmation email (with .ics invite) when an appointment is confirmed.
+func WithNotifier(n services.NotificationService) Option {
+	return func(s *AppointmentService) { s.notifier = n }
+}
+
+// WithCalendarLocation sets the time zone written into calendar feeds and invites.
+func WithCalendarLocation(loc *time.Location) Option {
+	return func(s *AppointmentService) { s.calendarLoc = loc }
 }
 
 // NewAppointmentService wires appointment persistence, users, and cars (car ownership is validated on create/update).
@@ -25,12 +58,19 @@ func NewAppointmentService(
 	repo ports.AppointmentRepository,
 	userRepo ports.UserRepository,
 	carRepo ports.CarRepository,
+	opts ...Option,
 ) *AppointmentService {
-	return &AppointmentService{
-		repo:     repo,
-		userRepo: userRepo,
-		carRepo:  carRepo,
+	s := &AppointmentService{
+		repo:        repo,
+		userRepo:    userRepo,
+		carRepo:     carRepo,
+		calendarLoc: time.Local,
+		policy:      DefaultChangePolicy,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func canAccessAppointment(u *domain.User, appt *domain.Appointment, requestingUserID uuid.UUID) bool {
@@ -94,15 +134,31 @@ func (s *AppointmentService) CreateAppointment(
 		return nil, err
 	}
 	dayStart, dayEnd := dayRangeUTC(appointment.ScheduledAt)
-	nSameDay, err := s.repo.CountNonCancelledBetween(queryCtx, dayStart, dayEnd, nil)
+	nSameDay, err := s.dayLoad(queryCtx, dayStart, dayEnd, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count appointments for day: %w", err)
+		return nil, err
 	}
 	if nSameDay >= MaxAppointmentsPerDay {
 		return nil, domain.ErrAppointmentDailyCapReached
 	}
 
 	appointment.CustomerID = customerID
+	if requestingUser.IsClient() {
+		appointment.EmployeeID = nil
+		appointment.Status = domain.AppointmentStatusScheduled
+		needsApproval, err := s.requiresApproval(queryCtx, customerID)
+		if err != nil {
+			return nil, err
+		}
+		if needsApproval {
+			appointment.Status = domain.AppointmentStatusPendingApproval
+		}
+	}
+	if appointment.EmployeeID != nil {
+		if err := s.validateAssignee(queryCtx, appointment); err != nil {
+			return nil, err
+		}
+	}
 	if appointment.Status == "" {
 		appointment.Status = domain.AppointmentStatusScheduled
 	}
@@ -114,9 +170,17 @@ func (s *AppointmentService) CreateAppointment(
 	appointment.CreatedAt = time.Now()
 	appointment.UpdatedAt = time.Now()
 
-	if err := s.repo.Create(ctx, appointment); err != nil {
+	// The check above rejects early; this one is authoritative under the day lock.
+	limit, err := s.dayLimit(queryCtx, dayStart)
+	if err != nil {
 		return nil, err
 	}
+	if err := s.repo.CreateWithinDayCap(ctx, appointment, dayStart, dayEnd, limit); err != nil {
+		return nil, err
+	}
+	if appointment.Status == domain.AppointmentStatusConfirmed {
+		s.sendConfirmation(ctx, appointment)
+	}
 
 	return appointment, nil
 }
@@ -173,7 +237,15 @@ func (s *AppointmentService) UpdateAppointment(ctx context.Context, appointment
 	if appointment.ServiceType != "" {
 		merged.ServiceType = appointment.ServiceType
 	}
+	if appointment.EmployeeID != nil && requestingUser.IsEmployee() {
+		merged.EmployeeID = appointment.EmployeeID
+	}
 	merged.UpdatedAt = time.Now()
+	if requestingUser.IsClient() {
+		if err := s.checkClientUpdate(existing, &merged, merged.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
 
 	if strings.TrimSpace(merged.ServiceType) == "" {
 		return nil, domain.ErrInvalidAppointmentData
@@ -182,17 +254,40 @@ func (s *AppointmentService) UpdateAppointment(ctx context.Context, appointment
 		return nil, err
 	}
 	uDay0, uDay1 := dayRangeUTC(merged.ScheduledAt)
-	nSameDay, err := s.repo.CountNonCancelledBetween(ctx, uDay0, uDay1, &merged.ID)
+	nSameDay, err := s.dayLoad(ctx, uDay0, uDay1, &merged.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count appointments for day: %w", err)
+		return nil, err
 	}
 	if nSameDay >= MaxAppointmentsPerDay {
 		return nil, domain.ErrAppointmentDailyCapReached
 	}
+	if merged.EmployeeID != nil && merged.Status != domain.AppointmentStatusCancelled {
+		// Re-check on reschedule too: the old slot may have been free while the new one is not.
+		if err := s.validateAssignee(ctx, &merged); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.repo.Update(ctx, &merged); err != nil {
+	if merged.Status == domain.AppointmentStatusCancelled {
+		err = s.repo.Update(ctx, &merged)
+	} else {
+		var limit int64
+		if limit, err = s.dayLimit(ctx, uDay0); err == nil {
+			err = s.repo.UpdateWithinDayCap(ctx, &merged, uDay0, uDay1, limit)
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
+	if merged.Status == domain.AppointmentStatusConfirmed && existing.Status != domain.AppointmentStatusConfirmed {
+		s.sendConfirmation(ctx, &merged)
+	}
+	if existing.Status != domain.AppointmentStatusCancelled {
+		oldDay, _ := dayRangeUTC(existing.ScheduledAt)
+		if merged.Status == domain.AppointmentStatusCancelled || !oldDay.Equal(uDay0) {
+			s.slotFreed(ctx, existing)
+		}
+	}
 	return &merged, nil
 }
 
@@ -297,6 +392,17 @@ func (s *AppointmentService) DeleteAppointment(ctx context.Context, appointmentI
 	if !canAccessAppointment(requestingUser, appt, requestingUserID) {
 		return domain.ErrUnauthorizedAccess
 	}
+	if requestingUser.IsClient() && appt.Status != domain.AppointmentStatusCancelled {
+		if err := s.checkClientCancel(appt, time.Now()); err != nil {
+			return err
+		}
+	}
 
-	return s.repo.Delete(ctx, appointmentID)
+	if err := s.repo.Delete(ctx, appointmentID); err != nil {
+		return err
+	}
+	if appt.Status != domain.AppointmentStatusCancelled {
+		s.slotFreed(ctx, appt)
+	}
+	return nil
 }
@@ -107,6 +107,20 @@ func (s *stubApptRepo) CountNonCancelledBetween(ctx context.Context, start, end
 	return s.countN, nil
 }
 
+func (s *stubApptRepo) CreateWithinDayCap(ctx context.Context, a *domain.Appointment, _, _ time.Time, limit int64) error {
+	if s.countN >= limit {
+		return domain.ErrAppointmentDailyCapReached
+	}
+	return s.Create(ctx, a)
+}
+
+func (s *stubApptRepo) UpdateWithinDayCap(ctx context.Context, a *domain.Appointment, _, _ time.Time, limit int64) error {
+	if s.countN >= limit {
+		return domain.ErrAppointmentDailyCapReached
+	}
+	return s.Update(ctx, a)
+}
+
 type stubCarRepo struct {
 	byID map[uuid.UUID]*domain.Car
 }
@@ -320,7 +334,8 @@ func TestAppointmentService_DeleteAppointment(t *testing.T) {
 	user, err := domain.NewUser("c@example.com", "pw", "C", "L", domain.RoleClient)
 	require.NoError(t, err)
 	user.ID = userID
-	existing := &domain.Appointment{ID: apptID, CustomerID: userID}
+	// Far enough ahead for the client cancellation notice.
+	existing := &domain.Appointment{ID: apptID, CustomerID: userID, Status: domain.AppointmentStatusScheduled, ScheduledAt: time.Now().Add(48 * time.Hour)}
 
 	svc := NewAppointmentService(
 		&stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{apptID: existing}},
@@ -338,7 +353,8 @@ func TestAppointmentService_DeleteAppointment_RepoError(t *testing.T) {
 	user, err := domain.NewUser("c@example.com", "pw", "C", "L", domain.RoleClient)
 	require.NoError(t, err)
 	user.ID = userID
-	existing := &domain.Appointment{ID: apptID, CustomerID: userID}
+	// Far enough ahead for the client cancellation notice.
+	existing := &domain.Appointment{ID: apptID, CustomerID: userID, Status: domain.AppointmentStatusScheduled, ScheduledAt: time.Now().Add(48 * time.Hour)}
 
 	delErr := errors.New("delete failed")
 	svc := NewAppointmentService(
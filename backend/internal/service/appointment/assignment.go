@@ -0,0 +1,190 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrLeaveRepositoryNotConfigured is returned by leave management without WithEmployeeLeaveRepository.
+var ErrLeaveRepositoryNotConfigured = errors.New("employee leave repository not configured")
+
+// maxAssignedPerDay bounds "my appointments" for one day (well above MaxAppointmentsPerDay).
+const maxAssignedPerDay = 100
+
+// validateAssignee checks a.EmployeeID is an active staff user, free for the slot, and not on leave.
+func (s *AppointmentService) validateAssignee(ctx context.Context, a *domain.Appointment) error {
+	if a.EmployeeID == nil {
+		return nil
+	}
+	assignee, err := s.userRepo.GetByID(ctx, *a.EmployeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get assignee: %w", err)
+	}
+	if assignee == nil || !assignee.IsActive || !assignee.IsEmployee() {
+		return domain.ErrAssigneeNotActiveEmployee
+	}
+
+	slotStart := a.ScheduledAt
+	slotEnd := a.ScheduledAt.Add(AppointmentSlotDuration)
+	from := slotStart.Add(-AppointmentSlotDuration)
+	others, _, err := s.repo.List(ctx, &ports.AppointmentFilters{
+		EmployeeID:    a.EmployeeID,
+		ScheduledFrom: &from,
+		ScheduledTo:   &slotEnd,
+		SortBy:        "scheduled_at",
+		SortOrder:     "ASC",
+		Limit:         maxAssignedPerDay,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list assignee appointments: %w", err)
+	}
+	for _, o := range others {
+		if o.ID == a.ID || o.Status == domain.AppointmentStatusCancelled {
+			continue
+		}
+		// Two slots of equal length overlap when their starts are less than one slot apart.
+		if d := o.ScheduledAt.Sub(slotStart); d > -AppointmentSlotDuration && d < AppointmentSlotDuration {
+			return domain.ErrAssigneeDoubleBooked
+		}
+	}
+
+	if s.leaveRepo != nil {
+		leaves, err := s.leaveRepo.ListOverlapping(ctx, a.EmployeeID, slotStart, slotEnd)
+		if err != nil {
+			return fmt.Errorf("failed to list assignee leave: %w", err)
+		}
+		if len(leaves) > 0 {
+			return domain.ErrAssigneeOnLeave
+		}
+	}
+	return nil
+}
+
+func (s *AppointmentService) requireStaff(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if !u.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	return u, nil
+}
+
+// AssignTechnician sets (or clears, with nil) the technician of an appointment. Staff only.
+func (s *AppointmentService) AssignTechnician(ctx context.Context, appointmentID uuid.UUID, employeeID *uuid.UUID, requestingUserID uuid.UUID) (*domain.Appointment, error) {
+	if _, err := s.requireStaff(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	appt, err := s.repo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appt.Status == domain.AppointmentStatusCancelled || appt.Status == domain.AppointmentStatusCompleted {
+		return nil, domain.ErrInvalidAppointmentData
+	}
+	appt.EmployeeID = employeeID
+	if err := s.validateAssignee(ctx, appt); err != nil {
+		return nil, err
+	}
+	appt.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, appt); err != nil {
+		return nil, err
+	}
+	return appt, nil
+}
+
+// ListAssignedOn returns the requesting technician's appointments on the local calendar day of `day`.
+func (s *AppointmentService) ListAssignedOn(ctx context.Context, day time.Time, requestingUserID uuid.UUID) ([]*domain.Appointment, error) {
+	if _, err := s.requireStaff(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	start, end := dayRangeUTC(day)
+	uid := requestingUserID
+	list, _, err := s.repo.List(ctx, &ports.AppointmentFilters{
+		EmployeeID:    &uid,
+		ScheduledFrom: &start,
+		ScheduledTo:   &end,
+		SortBy:        "scheduled_at",
+		SortOrder:     "ASC",
+		Limit:         maxAssignedPerDay,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*domain.Appointment, 0, len(list))
+	for _, a := range list {
+		if a.Status != domain.AppointmentStatusCancelled {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (s *AppointmentService) requireLeaveManager(ctx context.Context, userID uuid.UUID) error {
+	if s.leaveRepo == nil {
+		return ErrLeaveRepositoryNotConfigured
+	}
+	u, err := s.requireStaff(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.CanManageUsers() {
+		return domain.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// CreateLeave records a leave window for a staff member. Admin / manager only.
+func (s *AppointmentService) CreateLeave(ctx context.Context, leave *domain.EmployeeLeave, requestingUserID uuid.UUID) (*domain.EmployeeLeave, error) {
+	if err := s.requireLeaveManager(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	if leave.UserID == uuid.Nil || leave.StartsAt.IsZero() || !leave.EndsAt.After(leave.StartsAt) {
+		return nil, domain.ErrInvalidEmployeeLeaveData
+	}
+	staff, err := s.userRepo.GetByID(ctx, leave.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if staff == nil || !staff.IsEmployee() {
+		return nil, domain.ErrInvalidEmployeeLeaveData
+	}
+	leave.ID = uuid.New()
+	leave.Reason = strings.TrimSpace(leave.Reason)
+	leave.CreatedByUserID = requestingUserID
+	leave.CreatedAt = time.Now()
+	if err := s.leaveRepo.Create(ctx, leave); err != nil {
+		return nil, err
+	}
+	return leave, nil
+}
+
+// ListLeaves returns leaves intersecting [from, to), optionally for one staff member. Admin / manager only.
+func (s *AppointmentService) ListLeaves(ctx context.Context, userID *uuid.UUID, from, to time.Time, requestingUserID uuid.UUID) ([]*domain.EmployeeLeave, error) {
+	if err := s.requireLeaveManager(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, domain.ErrInvalidEmployeeLeaveData
+	}
+	return s.leaveRepo.ListOverlapping(ctx, userID, from, to)
+}
+
+// DeleteLeave removes a leave window. Admin / manager only.
+func (s *AppointmentService) DeleteLeave(ctx context.Context, leaveID uuid.UUID, requestingUserID uuid.UUID) error {
+	if err := s.requireLeaveManager(ctx, requestingUserID); err != nil {
+		return err
+	}
+	return s.leaveRepo.Delete(ctx, leaveID)
+}
@@ -0,0 +1,181 @@
+package appointment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLeaveRepo struct {
+	leaves []*domain.EmployeeLeave
+}
+
+func (s *stubLeaveRepo) Create(ctx context.Context, l *domain.EmployeeLeave) error {
+	s.leaves = append(s.leaves, l)
+	return nil
+}
+
+func (s *stubLeaveRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.EmployeeLeave, error) {
+	for _, l := range s.leaves {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, domain.ErrEmployeeLeaveNotFound
+}
+
+func (s *stubLeaveRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (s *stubLeaveRepo) ListOverlapping(ctx context.Context, userID *uuid.UUID, start, end time.Time) ([]*domain.EmployeeLeave, error) {
+	var out []*domain.EmployeeLeave
+	for _, l := range s.leaves {
+		if (userID == nil || l.UserID == *userID) && l.Overlaps(start, end) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+type assignmentFixture struct {
+	svc    *AppointmentService
+	repo   *stubApptRepo
+	leaves *stubLeaveRepo
+	users  map[uuid.UUID]*domain.User
+	mgrID  uuid.UUID
+	techID uuid.UUID
+	appt   *domain.Appointment
+}
+
+func newAssignmentFixture(t *testing.T) *assignmentFixture {
+	t.Helper()
+	mgr, err := domain.NewUser("m@example.com", "pw", "M", "Gr", domain.RoleManager)
+	require.NoError(t, err)
+	mgr.ID = uuid.New()
+	tech, err := domain.NewUser("t@example.com", "pw", "T", "Ech", domain.RoleEmployee)
+	require.NoError(t, err)
+	tech.ID = uuid.New()
+	appt := sampleAppointment(uuid.New(), uuid.New())
+	appt.ID = uuid.New()
+
+	users := map[uuid.UUID]*domain.User{mgr.ID: mgr, tech.ID: tech}
+	repo := &stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{appt.ID: appt}}
+	leaves := &stubLeaveRepo{}
+	svc := NewAppointmentService(repo, &apptTestUserRepo{users: users}, &stubCarRepo{}, WithEmployeeLeaveRepository(leaves))
+	return &assignmentFixture{svc: svc, repo: repo, leaves: leaves, users: users, mgrID: mgr.ID, techID: tech.ID, appt: appt}
+}
+
+func TestAppointmentService_AssignTechnician_OK(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+
+	out, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, f.mgrID)
+	require.NoError(t, err)
+	require.NotNil(t, out.EmployeeID)
+	assert.Equal(t, f.techID, *out.EmployeeID)
+}
+
+func TestAppointmentService_AssignTechnician_InactiveOrClient(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	f.users[f.techID].IsActive = false
+	_, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, f.mgrID)
+	assert.ErrorIs(t, err, domain.ErrAssigneeNotActiveEmployee)
+
+	client, _ := domain.NewUser("c@example.com", "pw", "C", "Li", domain.RoleClient)
+	client.ID = uuid.New()
+	f.users[client.ID] = client
+	_, err = f.svc.AssignTechnician(context.Background(), f.appt.ID, &client.ID, f.mgrID)
+	assert.ErrorIs(t, err, domain.ErrAssigneeNotActiveEmployee)
+}
+
+func TestAppointmentService_AssignTechnician_Overlap(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	f.repo.listApps = []*domain.Appointment{{
+		ID:          uuid.New(),
+		Status:      domain.AppointmentStatusScheduled,
+		EmployeeID:  &f.techID,
+		ScheduledAt: f.appt.ScheduledAt.Add(30 * time.Minute),
+	}}
+
+	_, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, f.mgrID)
+	assert.ErrorIs(t, err, domain.ErrAssigneeDoubleBooked)
+	require.NotNil(t, f.repo.lastList.EmployeeID)
+	assert.Equal(t, f.techID, *f.repo.lastList.EmployeeID)
+}
+
+func TestAppointmentService_AssignTechnician_AdjacentSlotAllowed(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	f.repo.listApps = []*domain.Appointment{{
+		ID:          uuid.New(),
+		Status:      domain.AppointmentStatusScheduled,
+		EmployeeID:  &f.techID,
+		ScheduledAt: f.appt.ScheduledAt.Add(-AppointmentSlotDuration),
+	}}
+
+	_, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, f.mgrID)
+	assert.NoError(t, err)
+}
+
+func TestAppointmentService_AssignTechnician_OnLeave(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	day := f.appt.ScheduledAt.Truncate(24 * time.Hour)
+	f.leaves.leaves = []*domain.EmployeeLeave{{ID: uuid.New(), UserID: f.techID, StartsAt: day.Add(-24 * time.Hour), EndsAt: day.Add(48 * time.Hour)}}
+
+	_, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, f.mgrID)
+	assert.ErrorIs(t, err, domain.ErrAssigneeOnLeave)
+}
+
+func TestAppointmentService_AssignTechnician_ClientDenied(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	client, _ := domain.NewUser("c@example.com", "pw", "C", "Li", domain.RoleClient)
+	client.ID = uuid.New()
+	f.users[client.ID] = client
+
+	_, err := f.svc.AssignTechnician(context.Background(), f.appt.ID, &f.techID, client.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestAppointmentService_ListAssignedOn_ScopesToCaller(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	f.repo.listApps = []*domain.Appointment{
+		{ID: uuid.New(), Status: domain.AppointmentStatusScheduled, EmployeeID: &f.techID},
+		{ID: uuid.New(), Status: domain.AppointmentStatusCancelled, EmployeeID: &f.techID},
+	}
+
+	out, err := f.svc.ListAssignedOn(context.Background(), f.appt.ScheduledAt, f.techID)
+	require.NoError(t, err)
+	assert.Len(t, out, 1)
+	require.NotNil(t, f.repo.lastList.EmployeeID)
+	assert.Equal(t, f.techID, *f.repo.lastList.EmployeeID)
+	require.NotNil(t, f.repo.lastList.ScheduledFrom)
+	require.NotNil(t, f.repo.lastList.ScheduledTo)
+	assert.Equal(t, 24*time.Hour, f.repo.lastList.ScheduledTo.Sub(*f.repo.lastList.ScheduledFrom))
+}
+
+func TestAppointmentService_CreateLeave_RequiresManager(t *testing.T) {
+	t.Parallel()
+	f := newAssignmentFixture(t)
+	start := time.Now()
+	leave := &domain.EmployeeLeave{UserID: f.techID, StartsAt: start, EndsAt: start.Add(8 * time.Hour)}
+
+	_, err := f.svc.CreateLeave(context.Background(), leave, f.techID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	out, err := f.svc.CreateLeave(context.Background(), leave, f.mgrID)
+	require.NoError(t, err)
+	assert.Equal(t, f.mgrID, out.CreatedByUserID)
+	assert.Len(t, f.leaves.leaves, 1)
+
+	_, err = f.svc.CreateLeave(context.Background(), &domain.EmployeeLeave{UserID: f.techID, StartsAt: start, EndsAt: start}, f.mgrID)
+	assert.ErrorIs(t, err, domain.ErrInvalidEmployeeLeaveData)
+}
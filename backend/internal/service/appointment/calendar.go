@@ -0,0 +1,232 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/ical"
+	"github.com/google/uuid"
+)
+
+// CalendarFeedKind selects which appointments a subscribed calendar shows.
+type CalendarFeedKind string
+
+const (
+	CalendarFeedClient     CalendarFeedKind = "client"     // the client's own appointments
+	CalendarFeedTechnician CalendarFeedKind = "technician" // appointments assigned to the staff member
+	CalendarFeedWorkshop   CalendarFeedKind = "workshop"   // every appointment; admin / manager only
+)
+
+// CalendarFeedTokenTTL is how long a feed URL keeps working; calendar apps poll it unattended,
+// so it is long-lived. RotateCalendarFeeds revokes a user's feeds before that.
+const CalendarFeedTokenTTL = 365 * 24 * time.Hour
+
+// ErrCalendarFeedsNotConfigured is returned when feeds are used without a link signer or feed key repository.
+var ErrCalendarFeedsNotConfigured = errors.New("calendar feeds not configured")
+
+// WithCalendarFeedKeyRepository enables calendar feeds, signed with each user's revocable feed key.
+func WithCalendarFeedKeyRepository(repo ports.CalendarFeedKeyRepository) Option {
+	return func(s *AppointmentService) { s.feedKeys = repo }
+}
+
+// Feed window around now: recent history plus the booking horizon.
+const (
+	calendarFeedPast   = 30 * 24 * time.Hour
+	calendarFeedFuture = 180 * 24 * time.Hour
+	calendarFeedLimit  = 500
+)
+
+func calendarFeedPurpose(kind CalendarFeedKind) string {
+	return "calendar-feed:" + string(kind)
+}
+
+func canSubscribe(u *domain.User, kind CalendarFeedKind) bool {
+	if u == nil || !u.IsActive {
+		return false
+	}
+	switch kind {
+	case CalendarFeedClient:
+		return u.IsClient()
+	case CalendarFeedTechnician:
+		return u.IsEmployee()
+	case CalendarFeedWorkshop:
+		return u.CanManageUsers()
+	default:
+		return false
+	}
+}
+
+// CalendarFeedTokens returns a feed token for every kind the requesting user may subscribe to.
+func (s *AppointmentService) CalendarFeedTokens(ctx context.Context, requestingUserID uuid.UUID) (map[CalendarFeedKind]string, error) {
+	return s.calendarFeedTokens(ctx, requestingUserID, false)
+}
+
+// RotateCalendarFeeds revokes every feed URL the requesting user has handed out and returns new tokens.
+func (s *AppointmentService) RotateCalendarFeeds(ctx context.Context, requestingUserID uuid.UUID) (map[CalendarFeedKind]string, error) {
+	return s.calendarFeedTokens(ctx, requestingUserID, true)
+}
+
+func (s *AppointmentService) calendarFeedTokens(ctx context.Context, userID uuid.UUID, rotate bool) (map[CalendarFeedKind]string, error) {
+	if s.signer == nil || s.feedKeys == nil {
+		return nil, ErrCalendarFeedsNotConfigured
+	}
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	var key *domain.CalendarFeedKey
+	if rotate {
+		key, err = s.feedKeys.Rotate(ctx, u.ID)
+	} else {
+		key, err = s.feedKeys.Ensure(ctx, u.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(CalendarFeedTokenTTL)
+	out := map[CalendarFeedKind]string{}
+	for _, kind := range []CalendarFeedKind{CalendarFeedClient, CalendarFeedTechnician, CalendarFeedWorkshop} {
+		if canSubscribe(u, kind) {
+			out[kind] = s.signer.Sign(calendarFeedPurpose(kind), key.Key, expires)
+		}
+	}
+	return out, nil
+}
+
+// CalendarFeed renders the iCalendar document behind a feed token. The token must carry the user's
+// current feed key, and the user's role is checked again on every fetch, so a demoted manager's
+// workshop feed stops working.
+func (s *AppointmentService) CalendarFeed(ctx context.Context, kind CalendarFeedKind, token string) ([]byte, error) {
+	if s.signer == nil || s.feedKeys == nil {
+		return nil, domain.ErrCalendarFeedInvalid
+	}
+	keyID, err := s.signer.Verify(calendarFeedPurpose(kind), token)
+	if err != nil {
+		return nil, domain.ErrCalendarFeedInvalid
+	}
+	key, err := s.feedKeys.GetByKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil || !canSubscribe(u, kind) {
+		return nil, domain.ErrCalendarFeedInvalid
+	}
+
+	now := time.Now()
+	from, to := now.Add(-calendarFeedPast), now.Add(calendarFeedFuture)
+	filters := &ports.AppointmentFilters{
+		ScheduledFrom: &from,
+		ScheduledTo:   &to,
+		SortBy:        "scheduled_at",
+		SortOrder:     "ASC",
+		Limit:         calendarFeedLimit,
+	}
+	name := "GonsGarage – Mis turnos"
+	switch kind {
+	case CalendarFeedClient:
+		filters.CustomerID = &u.ID
+	case CalendarFeedTechnician:
+		filters.EmployeeID = &u.ID
+		name = "GonsGarage – Trabajos asignados"
+	case CalendarFeedWorkshop:
+		name = "GonsGarage – Taller"
+	}
+	list, _, err := s.repo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.Calendar{Name: name, Method: "PUBLISH", Location: s.calendarLoc}
+	plates := map[uuid.UUID]string{}
+	for _, a := range list {
+		cal.Events = append(cal.Events, s.appointmentEvent(ctx, a, kind != CalendarFeedClient, plates))
+	}
+	return cal.Bytes(), nil
+}
+
+// AppointmentICS renders a single appointment as an .ics invite (email attachment).
+func (s *AppointmentService) AppointmentICS(ctx context.Context, a *domain.Appointment) []byte {
+	cal := ical.Calendar{Method: "PUBLISH", Location: s.calendarLoc}
+	cal.Events = []ical.Event{s.appointmentEvent(ctx, a, false, map[uuid.UUID]string{})}
+	return cal.Bytes()
+}
+
+// appointmentEvent maps an appointment to a VEVENT; staff views add the plate to the title.
+// plates caches car lookups across one feed.
+func (s *AppointmentService) appointmentEvent(ctx context.Context, a *domain.Appointment, staffView bool, plates map[uuid.UUID]string) ical.Event {
+	summary := "Turno GonsGarage: " + a.ServiceType
+	if staffView {
+		plate, ok := plates[a.CarID]
+		if !ok {
+			if car, err := s.carRepo.GetByID(ctx, a.CarID); err == nil && car != nil {
+				plate = car.LicensePlate
+			}
+			plates[a.CarID] = plate
+		}
+		summary = a.ServiceType
+		if plate != "" {
+			summary += " · " + plate
+		}
+	}
+	return ical.Event{
+		UID:         a.ID.String() + "@gonsgarage",
+		Summary:     summary,
+		Description: a.Notes,
+		Status:      icalStatus(a.Status),
+		Start:       a.ScheduledAt,
+		End:         a.ScheduledAt.Add(AppointmentSlotDuration),
+		Updated:     a.UpdatedAt,
+		Sequence:    a.Sequence, // calendar apps only replace an event whose SEQUENCE increased
+	}
+}
+
+func icalStatus(st domain.AppointmentStatus) string {
+	switch st {
+	case domain.AppointmentStatusScheduled, domain.AppointmentStatusPendingApproval:
+		return ical.StatusTentative
+	case domain.AppointmentStatusCancelled, domain.AppointmentStatusNoShow:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusConfirmed
+	}
+}
+
+// sendConfirmation queues the confirmation email with an .ics invite. Failures are logged:
+// the appointment is already confirmed and the reminder still goes out later.
+func (s *AppointmentService) sendConfirmation(ctx context.Context, a *domain.Appointment) {
+	if s.notifier == nil {
+		return
+	}
+	customer, err := s.userRepo.GetByID(ctx, a.CustomerID)
+	if err != nil || customer == nil || customer.Email == "" {
+		log.Printf("appointment confirmation: no recipient for appointment %s: %v", a.ID, err)
+		return
+	}
+	local := a.ScheduledAt.In(s.calendarLoc)
+	err = s.notifier.QueueNotification(ctx, services.NotificationRequest{
+		Type:    domain.NotificationChannelEmail,
+		To:      customer.Email,
+		Subject: "Turno confirmado",
+		Message: fmt.Sprintf("Hola %s, tu turno (%s) quedó confirmado para el %s a las %s.\nTe adjuntamos el evento para que lo agregues a tu calendario.",
+			customer.FirstName, a.ServiceType, local.Format("02/01/2006"), local.Format("15:04")),
+		DedupeKey: fmt.Sprintf("appointment-confirmation:%s:%d", a.ID, a.ScheduledAt.Unix()),
+		Attachments: []domain.NotificationAttachment{{
+			Filename:    "turno.ics",
+			ContentType: "text/calendar; charset=UTF-8; method=PUBLISH",
+			Content:     s.AppointmentICS(ctx, a),
+		}},
+	})
+	if err != nil {
+		log.Printf("appointment confirmation: queue for %s: %v", a.ID, err)
+	}
+}
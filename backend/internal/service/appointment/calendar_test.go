@@ -0,0 +1,157 @@
+package appointment
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func calendarUser(role string) *domain.User {
+	return &domain.User{ID: uuid.New(), Email: role + "@example.com", FirstName: "Ana", Role: role, IsActive: true}
+}
+
+// memFeedKeys is an in-memory CalendarFeedKeyRepository.
+type memFeedKeys struct {
+	byUser map[uuid.UUID]*domain.CalendarFeedKey
+}
+
+func (m *memFeedKeys) Ensure(_ context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error) {
+	if k, ok := m.byUser[userID]; ok {
+		return k, nil
+	}
+	return m.Rotate(context.Background(), userID)
+}
+
+func (m *memFeedKeys) GetByKey(_ context.Context, key uuid.UUID) (*domain.CalendarFeedKey, error) {
+	for _, k := range m.byUser {
+		if k.Key == key {
+			return k, nil
+		}
+	}
+	return nil, domain.ErrCalendarFeedInvalid
+}
+
+func (m *memFeedKeys) Rotate(_ context.Context, userID uuid.UUID) (*domain.CalendarFeedKey, error) {
+	if m.byUser == nil {
+		m.byUser = map[uuid.UUID]*domain.CalendarFeedKey{}
+	}
+	k := &domain.CalendarFeedKey{UserID: userID, Key: uuid.New(), RotatedAt: time.Now().UTC()}
+	m.byUser[userID] = k
+	return k, nil
+}
+
+func TestAppointmentService_CalendarFeeds(t *testing.T) {
+	t.Parallel()
+	client := calendarUser(domain.RoleClient)
+	tech := calendarUser(domain.RoleEmployee)
+	manager := calendarUser(domain.RoleManager)
+	users := &apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client, tech.ID: tech, manager.ID: manager}}
+	car := &domain.Car{ID: uuid.New(), LicensePlate: "AA-00-BB"}
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	appt := &domain.Appointment{ID: uuid.New(), CustomerID: client.ID, CarID: car.ID, EmployeeID: &tech.ID, ServiceType: "Revisión",
+		Status: domain.AppointmentStatusConfirmed, ScheduledAt: start, Sequence: 3}
+	repo := &stubApptRepo{listApps: []*domain.Appointment{appt}}
+	svc := NewAppointmentService(repo, users, &stubCarRepo{byID: map[uuid.UUID]*domain.Car{car.ID: car}},
+		WithLinkSigner(signedlink.New("secret")), WithCalendarFeedKeyRepository(&memFeedKeys{}), WithCalendarLocation(time.UTC))
+	ctx := context.Background()
+
+	clientTokens, err := svc.CalendarFeedTokens(ctx, client.ID)
+	require.NoError(t, err)
+	assert.Len(t, clientTokens, 1)
+	managerTokens, err := svc.CalendarFeedTokens(ctx, manager.ID)
+	require.NoError(t, err)
+	assert.Len(t, managerTokens, 2)
+	techTokens, err := svc.CalendarFeedTokens(ctx, tech.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, techTokens, CalendarFeedWorkshop)
+
+	body, err := svc.CalendarFeed(ctx, CalendarFeedClient, clientTokens[CalendarFeedClient])
+	require.NoError(t, err)
+	out := string(body)
+	assert.Contains(t, out, "UID:"+appt.ID.String()+"@gonsgarage")
+	assert.Contains(t, out, "SUMMARY:Turno GonsGarage: Revisión")
+	assert.Contains(t, out, "STATUS:CONFIRMED")
+	assert.Contains(t, out, "SEQUENCE:3")
+	assert.Contains(t, out, "DTSTART:"+start.UTC().Format("20060102T150405Z"))
+	require.NotNil(t, repo.lastList.CustomerID)
+	assert.Equal(t, client.ID, *repo.lastList.CustomerID)
+
+	body, err = svc.CalendarFeed(ctx, CalendarFeedTechnician, techTokens[CalendarFeedTechnician])
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "SUMMARY:Revisión · AA-00-BB")
+	require.NotNil(t, repo.lastList.EmployeeID)
+	assert.Equal(t, tech.ID, *repo.lastList.EmployeeID)
+
+	_, err = svc.CalendarFeed(ctx, CalendarFeedWorkshop, managerTokens[CalendarFeedWorkshop])
+	require.NoError(t, err)
+	assert.Nil(t, repo.lastList.CustomerID)
+	assert.Nil(t, repo.lastList.EmployeeID)
+
+	// A token is bound to its feed kind, and the role is re-checked on every fetch.
+	_, err = svc.CalendarFeed(ctx, CalendarFeedWorkshop, managerTokens[CalendarFeedTechnician])
+	assert.ErrorIs(t, err, domain.ErrCalendarFeedInvalid)
+	manager.Role = domain.RoleEmployee
+	_, err = svc.CalendarFeed(ctx, CalendarFeedWorkshop, managerTokens[CalendarFeedWorkshop])
+	assert.ErrorIs(t, err, domain.ErrCalendarFeedInvalid)
+
+	// Listing again reuses the key; rotating revokes every URL handed out before.
+	again, err := svc.CalendarFeedTokens(ctx, client.ID)
+	require.NoError(t, err)
+	_, err = svc.CalendarFeed(ctx, CalendarFeedClient, again[CalendarFeedClient])
+	require.NoError(t, err)
+	rotated, err := svc.RotateCalendarFeeds(ctx, client.ID)
+	require.NoError(t, err)
+	_, err = svc.CalendarFeed(ctx, CalendarFeedClient, clientTokens[CalendarFeedClient])
+	assert.ErrorIs(t, err, domain.ErrCalendarFeedInvalid)
+	_, err = svc.CalendarFeed(ctx, CalendarFeedClient, rotated[CalendarFeedClient])
+	require.NoError(t, err)
+	_, err = svc.CalendarFeed(ctx, CalendarFeedTechnician, techTokens[CalendarFeedTechnician])
+	require.NoError(t, err)
+}
+
+func TestAppointmentService_CalendarFeedsNotConfigured(t *testing.T) {
+	t.Parallel()
+	client := calendarUser(domain.RoleClient)
+	svc := NewAppointmentService(&stubApptRepo{}, &apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client}}, &stubCarRepo{},
+		WithLinkSigner(signedlink.New("secret")))
+	_, err := svc.CalendarFeedTokens(context.Background(), client.ID)
+	assert.ErrorIs(t, err, ErrCalendarFeedsNotConfigured)
+	_, err = svc.RotateCalendarFeeds(context.Background(), client.ID)
+	assert.ErrorIs(t, err, ErrCalendarFeedsNotConfigured)
+}
+
+func TestAppointmentService_ConfirmationEmailWithInvite(t *testing.T) {
+	t.Parallel()
+	client := calendarUser(domain.RoleClient)
+	appt := &domain.Appointment{ID: uuid.New(), CustomerID: client.ID, ServiceType: "Revisión",
+		Status: domain.AppointmentStatusScheduled, ScheduledAt: time.Now().Add(24 * time.Hour)}
+	notifier := &recordingNotifier{}
+	svc := NewAppointmentService(&stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{appt.ID: appt}},
+		&apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client}}, &stubCarRepo{},
+		WithLinkSigner(signedlink.New("secret")), WithNotifier(notifier))
+	token, err := svc.RSVPToken(appt)
+	require.NoError(t, err)
+
+	_, err = svc.ConfirmByToken(context.Background(), token)
+	require.NoError(t, err)
+	require.Len(t, notifier.reqs, 1)
+	req := notifier.reqs[0]
+	assert.Equal(t, domain.NotificationChannelEmail, req.Type)
+	assert.Equal(t, client.Email, req.To)
+	require.Len(t, req.Attachments, 1)
+	assert.Equal(t, "turno.ics", req.Attachments[0].Filename)
+	assert.True(t, strings.HasPrefix(string(req.Attachments[0].Content), "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, string(req.Attachments[0].Content), "BEGIN:VTIMEZONE")
+
+	// Already confirmed: no second email.
+	_, err = svc.ConfirmByToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Len(t, notifier.reqs, 1)
+}
@@ -0,0 +1,143 @@
+package appointment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ChangePolicy limits what clients may do to their own appointments. Staff are not bound by it.
+type ChangePolicy struct {
+	CancelMinNotice     time.Duration // no online cancellation closer than this to the start
+	RescheduleMinNotice time.Duration // no online reschedule closer than this to the current start
+	// NoShowApprovalThreshold: clients with at least this many no-shows book as pending_approval.
+	// Zero disables the rule.
+	NoShowApprovalThreshold int
+}
+
+// DefaultChangePolicy: 12 hours' notice for cancelling or moving; no approval rule.
+var DefaultChangePolicy = ChangePolicy{
+	CancelMinNotice:     12 * time.Hour,
+	RescheduleMinNotice: 12 * time.Hour,
+}
+
+// WithChangePolicy overrides DefaultChangePolicy.
+func WithChangePolicy(p ChangePolicy) Option {
+	return func(s *AppointmentService) { s.policy = p }
+}
+
+// clientChangeable reports whether a client may still touch an appointment in this status.
+func clientChangeable(st domain.AppointmentStatus) bool {
+	switch st {
+	case domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed, domain.AppointmentStatusPendingApproval:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkClientCancel applies the cancellation notice to a client-initiated cancellation.
+func (s *AppointmentService) checkClientCancel(existing *domain.Appointment, now time.Time) error {
+	if !clientChangeable(existing.Status) {
+		return domain.ErrAppointmentNotChangeable
+	}
+	if existing.ScheduledAt.Sub(now) < s.policy.CancelMinNotice {
+		return domain.ErrAppointmentCancelTooLate
+	}
+	return nil
+}
+
+// checkClientUpdate applies the policy to a client's PUT: clients may only cancel (status) and
+// move the appointment with enough notice before its current start.
+func (s *AppointmentService) checkClientUpdate(existing, merged *domain.Appointment, now time.Time) error {
+	if merged.Status != existing.Status {
+		if merged.Status != domain.AppointmentStatusCancelled {
+			return domain.ErrAppointmentStatusChangeForbidden
+		}
+		return s.checkClientCancel(existing, now)
+	}
+	if !clientChangeable(existing.Status) {
+		return domain.ErrAppointmentNotChangeable
+	}
+	if !merged.ScheduledAt.Equal(existing.ScheduledAt) {
+		if existing.ScheduledAt.Sub(now) < s.policy.RescheduleMinNotice {
+			return domain.ErrAppointmentRescheduleTooLate
+		}
+		if !merged.ScheduledAt.After(now) {
+			return domain.ErrInvalidAppointmentData
+		}
+	}
+	return nil
+}
+
+// noShowCount counts the customer's appointments marked no_show.
+func (s *AppointmentService) noShowCount(ctx context.Context, customerID uuid.UUID) (int64, error) {
+	st := string(domain.AppointmentStatusNoShow)
+	_, total, err := s.repo.List(ctx, &ports.AppointmentFilters{
+		CustomerID: &customerID,
+		Status:     &st,
+		SortBy:     "scheduled_at",
+		SortOrder:  "DESC",
+		Limit:      1,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count no-shows: %w", err)
+	}
+	return total, nil
+}
+
+// requiresApproval reports whether a client's new bookings need staff confirmation.
+func (s *AppointmentService) requiresApproval(ctx context.Context, customerID uuid.UUID) (bool, error) {
+	if s.policy.NoShowApprovalThreshold <= 0 {
+		return false, nil
+	}
+	n, err := s.noShowCount(ctx, customerID)
+	if err != nil {
+		return false, err
+	}
+	return n >= int64(s.policy.NoShowApprovalThreshold), nil
+}
+
+// MarkNoShow records that the client did not turn up. Staff only, once the start time has passed.
+func (s *AppointmentService) MarkNoShow(ctx context.Context, appointmentID uuid.UUID, requestingUserID uuid.UUID) (*domain.Appointment, error) {
+	if _, err := s.requireStaff(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	appt, err := s.repo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appt.Status == domain.AppointmentStatusNoShow {
+		return appt, nil
+	}
+	if (appt.Status != domain.AppointmentStatusScheduled && appt.Status != domain.AppointmentStatusConfirmed) ||
+		appt.ScheduledAt.After(time.Now()) {
+		return nil, domain.ErrAppointmentNoShowNotApplicable
+	}
+	appt.Status = domain.AppointmentStatusNoShow
+	appt.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, appt); err != nil {
+		return nil, err
+	}
+	return appt, nil
+}
+
+// CustomerNoShows returns a customer's no-show count and whether the approval rule applies. Staff only.
+func (s *AppointmentService) CustomerNoShows(ctx context.Context, customerID uuid.UUID, requestingUserID uuid.UUID) (*ports.NoShowSummary, error) {
+	if _, err := s.requireStaff(ctx, requestingUserID); err != nil {
+		return nil, err
+	}
+	n, err := s.noShowCount(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.NoShowSummary{
+		CustomerID:       customerID,
+		NoShows:          n,
+		RequiresApproval: s.policy.NoShowApprovalThreshold > 0 && n >= int64(s.policy.NoShowApprovalThreshold),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func policyFixture(startsIn time.Duration) (*AppointmentService, *stubApptRepo, *domain.User, *domain.User, *domain.Appointment) {
+	client := calendarUser(domain.RoleClient)
+	staff := calendarUser(domain.RoleEmployee)
+	appt := &domain.Appointment{ID: uuid.New(), CustomerID: client.ID, CarID: uuid.New(), ServiceType: "inspection",
+		Status: domain.AppointmentStatusScheduled, ScheduledAt: time.Now().Add(startsIn)}
+	repo := &stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{appt.ID: appt}}
+	users := &apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client, staff.ID: staff}}
+	return NewAppointmentService(repo, users, &stubCarRepo{}), repo, client, staff, appt
+}
+
+func TestChangePolicy_ClientCancelTooLate(t *testing.T) {
+	t.Parallel()
+	svc, _, client, staff, appt := policyFixture(2 * time.Hour)
+
+	err := svc.DeleteAppointment(context.Background(), appt.ID, client.ID)
+	require.ErrorIs(t, err, domain.ErrAppointmentCancelTooLate)
+	var derr *domain.Error
+	require.True(t, errors.As(err, &derr))
+	assert.Equal(t, "appointment_cancel_too_late", derr.Code)
+
+	_, err = svc.UpdateAppointment(context.Background(), &domain.Appointment{ID: appt.ID, Status: domain.AppointmentStatusCancelled}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentCancelTooLate)
+
+	// Staff are not bound by the notice.
+	assert.NoError(t, svc.DeleteAppointment(context.Background(), appt.ID, staff.ID))
+}
+
+func TestChangePolicy_ClientReschedule(t *testing.T) {
+	t.Parallel()
+	svc, _, client, _, appt := policyFixture(2 * time.Hour)
+	moveTo := sampleAppointment(client.ID, appt.CarID).ScheduledAt
+
+	_, err := svc.UpdateAppointment(context.Background(), &domain.Appointment{ID: appt.ID, ScheduledAt: moveTo}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentRescheduleTooLate)
+
+	_, err = svc.UpdateAppointment(context.Background(), &domain.Appointment{ID: appt.ID, Status: domain.AppointmentStatusConfirmed}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentStatusChangeForbidden)
+
+	appt.Status = domain.AppointmentStatusCompleted
+	_, err = svc.UpdateAppointment(context.Background(), &domain.Appointment{ID: appt.ID, Notes: "x"}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentNotChangeable)
+}
+
+func TestChangePolicy_CustomNotice(t *testing.T) {
+	t.Parallel()
+	svc, _, client, _, appt := policyFixture(2 * time.Hour)
+	svc.policy = ChangePolicy{CancelMinNotice: time.Hour}
+	assert.NoError(t, svc.DeleteAppointment(context.Background(), appt.ID, client.ID))
+}
+
+func TestAppointmentService_MarkNoShow(t *testing.T) {
+	t.Parallel()
+	svc, _, client, staff, appt := policyFixture(2 * time.Hour)
+
+	_, err := svc.MarkNoShow(context.Background(), appt.ID, staff.ID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentNoShowNotApplicable)
+
+	appt.ScheduledAt = time.Now().Add(-time.Hour)
+	_, err = svc.MarkNoShow(context.Background(), appt.ID, client.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	out, err := svc.MarkNoShow(context.Background(), appt.ID, staff.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusNoShow, out.Status)
+
+	// Idempotent.
+	_, err = svc.MarkNoShow(context.Background(), appt.ID, staff.ID)
+	assert.NoError(t, err)
+}
+
+func TestChangePolicy_RepeatNoShowsNeedApproval(t *testing.T) {
+	t.Parallel()
+	svc, repo, client, staff, _ := policyFixture(48 * time.Hour)
+	svc.policy.NoShowApprovalThreshold = 2
+	carID := uuid.New()
+	svc.carRepo = &stubCarRepo{byID: map[uuid.UUID]*domain.Car{carID: {ID: carID, OwnerID: client.ID}}}
+
+	repo.listTotal = 1
+	out, err := svc.CreateAppointment(context.Background(), sampleAppointment(client.ID, carID), client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusScheduled, out.Status)
+
+	repo.listTotal = 2
+	out, err = svc.CreateAppointment(context.Background(), sampleAppointment(client.ID, carID), client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusPendingApproval, out.Status)
+	require.NotNil(t, repo.lastList.Status)
+	assert.Equal(t, string(domain.AppointmentStatusNoShow), *repo.lastList.Status)
+
+	sum, err := svc.CustomerNoShows(context.Background(), client.ID, staff.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), sum.NoShows)
+	assert.True(t, sum.RequiresApproval)
+}
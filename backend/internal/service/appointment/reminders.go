@@ -0,0 +1,164 @@
+package appointment
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// DefaultReminderOffsets are used when APPOINTMENT_REMINDER_OFFSETS is empty.
+var DefaultReminderOffsets = []time.Duration{24 * time.Hour, 2 * time.Hour}
+
+// maxRemindersPerRun bounds the appointments scanned per scheduler tick.
+const maxRemindersPerRun = 500
+
+// ReminderConfig drives the reminder scheduler.
+type ReminderConfig struct {
+	Offsets []time.Duration // how long before ScheduledAt to remind
+	// Channel is the preferred channel; sms / whatsapp fall back to email when the customer has no phone.
+	Channel       string
+	PublicBaseURL string // frontend origin used in confirm/cancel links
+}
+
+// ReminderScheduler queues appointment reminders through the notification service.
+// Each (appointment, offset, scheduled time) is queued once thanks to the outbox dedupe key,
+// so ticks can overlap and a reschedule produces fresh reminders.
+type ReminderScheduler struct {
+	svc      *AppointmentService
+	notifier services.NotificationService
+	cfg      ReminderConfig
+	now      func() time.Time
+}
+
+func NewReminderScheduler(svc *AppointmentService, notifier services.NotificationService, cfg ReminderConfig) *ReminderScheduler {
+	offsets := append([]time.Duration(nil), cfg.Offsets...)
+	if len(offsets) == 0 {
+		offsets = append(offsets, DefaultReminderOffsets...)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	cfg.Offsets = offsets
+	if !domain.ValidNotificationChannel(cfg.Channel) {
+		cfg.Channel = domain.NotificationChannelEmail
+	}
+	return &ReminderScheduler{svc: svc, notifier: notifier, cfg: cfg, now: time.Now}
+}
+
+// ParseReminderOffsets parses a comma-separated list of Go durations ("24h,2h").
+func ParseReminderOffsets(raw string) ([]time.Duration, error) {
+	var out []time.Duration
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		d, err := time.ParseDuration(p)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid reminder offset %q", p)
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// RunOnce queues reminders that are due now and returns how many appointments were handled.
+func (r *ReminderScheduler) RunOnce(ctx context.Context) (int, error) {
+	now := r.now()
+	from := now
+	to := now.Add(r.cfg.Offsets[len(r.cfg.Offsets)-1])
+	list, _, err := r.svc.repo.List(ctx, &ports.AppointmentFilters{
+		ScheduledFrom: &from,
+		ScheduledTo:   &to,
+		SortBy:        "scheduled_at",
+		SortOrder:     "ASC",
+		Limit:         maxRemindersPerRun,
+	})
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for _, a := range list {
+		if a.Status != domain.AppointmentStatusScheduled && a.Status != domain.AppointmentStatusConfirmed {
+			continue
+		}
+		offset, ok := r.dueOffset(a, now)
+		if !ok {
+			continue
+		}
+		if err := r.queue(ctx, a, offset); err != nil {
+			log.Printf("appointment reminder %s: %v", a.ID, err)
+			continue
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// dueOffset returns the smallest offset whose send time has passed: an appointment booked
+// at short notice gets one reminder, not one per offset.
+func (r *ReminderScheduler) dueOffset(a *domain.Appointment, now time.Time) (time.Duration, bool) {
+	for _, off := range r.cfg.Offsets {
+		if !now.Before(a.ScheduledAt.Add(-off)) {
+			return off, true
+		}
+	}
+	return 0, false
+}
+
+func (r *ReminderScheduler) queue(ctx context.Context, a *domain.Appointment, offset time.Duration) error {
+	customer, err := r.svc.userRepo.GetByID(ctx, a.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.ErrUserNotFound
+	}
+	channel, to := reminderRecipient(r.cfg.Channel, customer)
+	if to == "" {
+		return nil // nothing to reach the customer with
+	}
+	msg := fmt.Sprintf("Recordatorio GonsGarage: tu turno (%s) es el %s a las %s.",
+		a.ServiceType, a.ScheduledAt.In(time.Local).Format("02/01"), a.ScheduledAt.In(time.Local).Format("15:04"))
+	if r.cfg.PublicBaseURL != "" {
+		if token, err := r.svc.RSVPToken(a); err == nil {
+			msg += " Confirmá o cancelá acá: " + RSVPLink(r.cfg.PublicBaseURL, token)
+		}
+	}
+	return r.notifier.QueueNotification(ctx, services.NotificationRequest{
+		Type:      channel,
+		To:        to,
+		Subject:   "Recordatorio de turno",
+		Message:   msg,
+		DedupeKey: fmt.Sprintf("appointment-reminder:%s:%s:%d", a.ID, offset, a.ScheduledAt.Unix()),
+	})
+}
+
+func reminderRecipient(preferred string, u *domain.User) (channel, to string) {
+	phone := strings.TrimSpace(u.Phone)
+	if preferred != domain.NotificationChannelEmail && phone != "" {
+		return preferred, phone
+	}
+	return domain.NotificationChannelEmail, strings.TrimSpace(u.Email)
+}
+
+// Run calls RunOnce every interval until ctx is done.
+func (r *ReminderScheduler) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := r.RunOnce(ctx); err != nil {
+			log.Printf("appointment reminders: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
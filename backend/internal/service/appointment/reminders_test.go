@@ -0,0 +1,135 @@
+package appointment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	reqs []services.NotificationRequest
+}
+
+func (n *recordingNotifier) SendWorkshopCreatedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *recordingNotifier) SendWorkshopUpdatedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *recordingNotifier) SendWorkshopDeletedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *recordingNotifier) QueueNotification(_ context.Context, req services.NotificationRequest) error {
+	n.reqs = append(n.reqs, req)
+	return nil
+}
+
+func reminderFixture(t *testing.T, phone string, channel string) (*ReminderScheduler, *recordingNotifier, *stubApptRepo, *domain.Appointment, time.Time) {
+	t.Helper()
+	client, err := domain.NewUser("c@example.com", "pw", "C", "Li", domain.RoleClient)
+	require.NoError(t, err)
+	client.ID = uuid.New()
+	client.Phone = phone
+	now := time.Date(2030, 6, 14, 12, 0, 0, 0, time.UTC)
+	appt := &domain.Appointment{ID: uuid.New(), CustomerID: client.ID, ServiceType: "inspection", Status: domain.AppointmentStatusScheduled, ScheduledAt: now.Add(23 * time.Hour)}
+	repo := &stubApptRepo{listApps: []*domain.Appointment{appt}}
+	svc := NewAppointmentService(repo, &apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client}}, &stubCarRepo{},
+		WithLinkSigner(signedlink.New("secret")))
+	notifier := &recordingNotifier{}
+	sched := NewReminderScheduler(svc, notifier, ReminderConfig{Channel: channel, PublicBaseURL: "https://app.example"})
+	sched.now = func() time.Time { return now }
+	return sched, notifier, repo, appt, now
+}
+
+func TestReminderScheduler_QueuesDueOffsetOnce(t *testing.T) {
+	t.Parallel()
+	sched, notifier, repo, appt, now := reminderFixture(t, "+351900000000", domain.NotificationChannelSMS)
+
+	n, err := sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, notifier.reqs, 1)
+	req := notifier.reqs[0]
+	assert.Equal(t, domain.NotificationChannelSMS, req.Type)
+	assert.Equal(t, "+351900000000", req.To)
+	assert.Contains(t, req.Message, "https://app.example/appointments/rsvp?token=")
+	assert.Contains(t, req.DedupeKey, appt.ID.String())
+	assert.Contains(t, req.DedupeKey, "24h0m0s")
+	require.NotNil(t, repo.lastList.ScheduledTo)
+	assert.Equal(t, now.Add(24*time.Hour), *repo.lastList.ScheduledTo)
+
+	// Same tick again yields the same dedupe key; the outbox drops it.
+	_, err = sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, req.DedupeKey, notifier.reqs[1].DedupeKey)
+
+	// Close to the appointment only the 2h reminder is due.
+	sched.now = func() time.Time { return appt.ScheduledAt.Add(-90 * time.Minute) }
+	_, err = sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, notifier.reqs[2].DedupeKey, "2h0m0s")
+}
+
+func TestReminderScheduler_NotYetDueAndCancelled(t *testing.T) {
+	t.Parallel()
+	sched, notifier, _, appt, now := reminderFixture(t, "", domain.NotificationChannelSMS)
+	appt.ScheduledAt = now.Add(25 * time.Hour)
+	_, err := sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, notifier.reqs)
+
+	appt.ScheduledAt = now.Add(time.Hour)
+	appt.Status = domain.AppointmentStatusCancelled
+	_, err = sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, notifier.reqs)
+}
+
+func TestReminderScheduler_FallsBackToEmail(t *testing.T) {
+	t.Parallel()
+	sched, notifier, _, _, _ := reminderFixture(t, "", domain.NotificationChannelWhatsApp)
+	_, err := sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, notifier.reqs, 1)
+	assert.Equal(t, domain.NotificationChannelEmail, notifier.reqs[0].Type)
+	assert.Equal(t, "c@example.com", notifier.reqs[0].To)
+}
+
+func TestParseReminderOffsets(t *testing.T) {
+	t.Parallel()
+	got, err := ParseReminderOffsets(" 24h, 2h ,")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{24 * time.Hour, 2 * time.Hour}, got)
+	_, err = ParseReminderOffsets("tomorrow")
+	assert.Error(t, err)
+}
+
+func TestAppointmentService_RSVPByToken(t *testing.T) {
+	t.Parallel()
+	appt := &domain.Appointment{ID: uuid.New(), Status: domain.AppointmentStatusScheduled, ScheduledAt: time.Now().Add(24 * time.Hour)}
+	repo := &stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{appt.ID: appt}}
+	svc := NewAppointmentService(repo, &apptTestUserRepo{}, &stubCarRepo{}, WithLinkSigner(signedlink.New("secret")))
+	token, err := svc.RSVPToken(appt)
+	require.NoError(t, err)
+
+	out, err := svc.ConfirmByToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusConfirmed, out.Status)
+
+	out, err = svc.CancelByToken(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AppointmentStatusCancelled, out.Status)
+
+	_, err = svc.ConfirmByToken(context.Background(), token)
+	assert.ErrorIs(t, err, domain.ErrAppointmentNotRespondable)
+
+	_, err = svc.ConfirmByToken(context.Background(), "bogus")
+	assert.ErrorIs(t, err, domain.ErrAppointmentLinkInvalid)
+}
@@ -0,0 +1,90 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// RSVPLinkPurpose binds confirm/cancel tokens so they cannot be replayed on other signed links.
+const RSVPLinkPurpose = "appointment-rsvp"
+
+// RSVPToken returns the confirm/cancel token for an appointment; it expires at the appointment time.
+func (s *AppointmentService) RSVPToken(appt *domain.Appointment) (string, error) {
+	if s.signer == nil {
+		return "", errors.New("link signer not configured")
+	}
+	return s.signer.Sign(RSVPLinkPurpose, appt.ID, appt.ScheduledAt), nil
+}
+
+// RSVPLink builds the public page URL the client opens from a reminder.
+func RSVPLink(publicBaseURL, token string) string {
+	return strings.TrimRight(publicBaseURL, "/") + "/appointments/rsvp?token=" + url.QueryEscape(token)
+}
+
+func (s *AppointmentService) appointmentFromRSVPToken(ctx context.Context, token string) (*domain.Appointment, error) {
+	if s.signer == nil {
+		return nil, domain.ErrAppointmentLinkInvalid
+	}
+	id, err := s.signer.Verify(RSVPLinkPurpose, token)
+	if err != nil {
+		return nil, domain.ErrAppointmentLinkInvalid
+	}
+	appt, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrAppointmentNotFound) {
+			return nil, domain.ErrAppointmentLinkInvalid
+		}
+		return nil, err
+	}
+	return appt, nil
+}
+
+// GetByRSVPToken resolves the appointment behind a confirm/cancel link (no login).
+func (s *AppointmentService) GetByRSVPToken(ctx context.Context, token string) (*domain.Appointment, error) {
+	return s.appointmentFromRSVPToken(ctx, token)
+}
+
+// ConfirmByToken marks the appointment confirmed from a signed link; repeating it is a no-op.
+func (s *AppointmentService) ConfirmByToken(ctx context.Context, token string) (*domain.Appointment, error) {
+	return s.respondByToken(ctx, token, domain.AppointmentStatusConfirmed)
+}
+
+// CancelByToken cancels the appointment from a signed link; repeating it is a no-op.
+func (s *AppointmentService) CancelByToken(ctx context.Context, token string) (*domain.Appointment, error) {
+	return s.respondByToken(ctx, token, domain.AppointmentStatusCancelled)
+}
+
+func (s *AppointmentService) respondByToken(ctx context.Context, token string, to domain.AppointmentStatus) (*domain.Appointment, error) {
+	appt, err := s.appointmentFromRSVPToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if appt.Status == to {
+		return appt, nil
+	}
+	if appt.Status != domain.AppointmentStatusScheduled && appt.Status != domain.AppointmentStatusConfirmed {
+		return nil, domain.ErrAppointmentNotRespondable
+	}
+	if to == domain.AppointmentStatusCancelled {
+		if err := s.checkClientCancel(appt, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	appt.Status = to
+	appt.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, appt); err != nil {
+		return nil, err
+	}
+	if to == domain.AppointmentStatusConfirmed {
+		s.sendConfirmation(ctx, appt)
+	}
+	if to == domain.AppointmentStatusCancelled {
+		s.slotFreed(ctx, appt)
+	}
+	return appt, nil
+}
@@ -0,0 +1,397 @@
+package appointment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrWaitlistNotConfigured is returned by waitlist operations without WithWaitlistRepository.
+var ErrWaitlistNotConfigured = errors.New("waitlist repository not configured")
+
+// WaitlistClaimPurpose binds claim tokens to waitlist offers.
+const WaitlistClaimPurpose = "waitlist-claim"
+
+// WaitlistOfferTTL is how long a freed slot is held for one waitlisted client before it moves on.
+const WaitlistOfferTTL = 2 * time.Hour
+
+// maxOfferAttempts bounds retries when a concurrent offer takes the head of the queue first.
+const maxOfferAttempts = 5
+
+// WithWaitlistRepository enables the per-day waitlist and offers of freed slots.
+func WithWaitlistRepository(repo ports.WaitlistRepository) Option {
+	return func(s *AppointmentService) { s.waitRepo = repo }
+}
+
+// WithPublicBaseURL sets the frontend origin used in links the service sends itself (waitlist offers).
+func WithPublicBaseURL(base string) Option {
+	return func(s *AppointmentService) { s.publicBaseURL = strings.TrimRight(base, "/") }
+}
+
+// WaitlistClaimLink builds the public page URL a waitlisted client opens to take an offer.
+func WaitlistClaimLink(publicBaseURL, token string) string {
+	return strings.TrimRight(publicBaseURL, "/") + "/waitlist/claim?token=" + url.QueryEscape(token)
+}
+
+// JoinWaitlist queues the customer for a fully booked local day (client: own cars; staff: on behalf).
+func (s *AppointmentService) JoinWaitlist(ctx context.Context, entry *domain.WaitlistEntry, requestingUserID uuid.UUID) (*domain.WaitlistEntry, error) {
+	if s.waitRepo == nil {
+		return nil, ErrWaitlistNotConfigured
+	}
+	u, err := s.userRepo.GetByID(ctx, requestingUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if u.IsClient() {
+		entry.CustomerID = requestingUserID
+	} else if !u.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	entry.ServiceType = strings.TrimSpace(entry.ServiceType)
+	if entry.CustomerID == uuid.Nil || entry.ServiceType == "" || entry.Day.IsZero() {
+		return nil, domain.ErrInvalidWaitlistData
+	}
+	car, err := s.carRepo.GetByID(ctx, entry.CarID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCarNotFound) {
+			return nil, domain.ErrInvalidWaitlistData
+		}
+		return nil, fmt.Errorf("failed to get car: %w", err)
+	}
+	if car == nil || car.OwnerID != entry.CustomerID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	day, dayEnd := dayRangeUTC(entry.Day)
+	if !dayEnd.After(time.Now()) {
+		return nil, domain.ErrInvalidWaitlistData
+	}
+	load, err := s.dayLoad(ctx, day, dayEnd, nil)
+	if err != nil {
+		return nil, err
+	}
+	if load < MaxAppointmentsPerDay {
+		return nil, domain.ErrWaitlistDayNotFull
+	}
+	mine, err := s.waitRepo.ListByCustomer(ctx, entry.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range mine {
+		if e.Day.Equal(day) {
+			return nil, domain.ErrWaitlistDuplicate
+		}
+	}
+
+	now := time.Now()
+	entry.ID = uuid.New()
+	entry.Day = day
+	entry.Status = domain.WaitlistStatusWaiting
+	entry.OfferedSlot, entry.OfferExpiresAt, entry.AppointmentID = nil, nil, nil
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	if err := s.waitRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListWaitlist returns the client's open entries; staff get every entry of the local day of `day`.
+func (s *AppointmentService) ListWaitlist(ctx context.Context, day time.Time, requestingUserID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	if s.waitRepo == nil {
+		return nil, ErrWaitlistNotConfigured
+	}
+	u, err := s.userRepo.GetByID(ctx, requestingUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if u.IsClient() {
+		return s.waitRepo.ListByCustomer(ctx, requestingUserID)
+	}
+	if !u.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	start, _ := dayRangeUTC(day)
+	return s.waitRepo.ListByDay(ctx, start)
+}
+
+// LeaveWaitlist cancels an entry (owner or staff). A pending offer passes to the next client.
+func (s *AppointmentService) LeaveWaitlist(ctx context.Context, entryID uuid.UUID, requestingUserID uuid.UUID) error {
+	if s.waitRepo == nil {
+		return ErrWaitlistNotConfigured
+	}
+	u, err := s.userRepo.GetByID(ctx, requestingUserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return domain.ErrUserNotFound
+	}
+	entry, err := s.waitRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if (u.IsClient() && entry.CustomerID != requestingUserID) || (!u.IsClient() && !u.IsEmployee()) {
+		return domain.ErrUnauthorizedAccess
+	}
+	switch entry.Status {
+	case domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered:
+	default:
+		return domain.ErrInvalidWaitlistData
+	}
+	ok, err := s.waitRepo.Transition(ctx, entry.ID, entry.Status, domain.WaitlistStatusCancelled, ports.WaitlistPatch{})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrInvalidWaitlistData
+	}
+	if entry.Status == domain.WaitlistStatusOffered && entry.OfferedSlot != nil {
+		s.offerSlot(ctx, entry.Day, *entry.OfferedSlot)
+	}
+	return nil
+}
+
+func (s *AppointmentService) offerFromToken(ctx context.Context, token string) (*domain.WaitlistEntry, error) {
+	if s.waitRepo == nil || s.signer == nil {
+		return nil, domain.ErrWaitlistOfferUnavailable
+	}
+	id, err := s.signer.Verify(WaitlistClaimPurpose, token)
+	if err != nil {
+		return nil, domain.ErrWaitlistOfferUnavailable
+	}
+	entry, err := s.waitRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrWaitlistEntryNotFound) {
+			return nil, domain.ErrWaitlistOfferUnavailable
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetWaitlistOffer resolves the entry behind a claim link (no login).
+func (s *AppointmentService) GetWaitlistOffer(ctx context.Context, token string) (*domain.WaitlistEntry, error) {
+	return s.offerFromToken(ctx, token)
+}
+
+// ClaimWaitlistOffer books the offered slot. The offered→claimed status change is conditional,
+// so when a link is opened twice at once only one request creates the appointment.
+func (s *AppointmentService) ClaimWaitlistOffer(ctx context.Context, token string) (*domain.Appointment, error) {
+	entry, err := s.offerFromToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if entry.Status != domain.WaitlistStatusOffered || entry.OfferedSlot == nil ||
+		entry.OfferExpiresAt == nil || !entry.OfferExpiresAt.After(now) {
+		return nil, domain.ErrWaitlistOfferUnavailable
+	}
+
+	apptID := uuid.New()
+	ok, err := s.waitRepo.Transition(ctx, entry.ID, domain.WaitlistStatusOffered, domain.WaitlistStatusClaimed,
+		ports.WaitlistPatch{AppointmentID: &apptID})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrWaitlistOfferUnavailable
+	}
+
+	appt := &domain.Appointment{
+		ID:          apptID,
+		CustomerID:  entry.CustomerID,
+		CarID:       entry.CarID,
+		ServiceType: entry.ServiceType,
+		Status:      domain.AppointmentStatusScheduled,
+		ScheduledAt: *entry.OfferedSlot,
+		Notes:       "Turno tomado desde la lista de espera.",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	dayStart, dayEnd := dayRangeUTC(appt.ScheduledAt)
+	limit, err := s.dayLimit(ctx, dayStart)
+	if err == nil {
+		err = s.repo.CreateWithinDayCap(ctx, appt, dayStart, dayEnd, limit)
+	}
+	if err != nil {
+		// Give the held slot back to this client so the offer can be retried until it expires.
+		if _, rerr := s.waitRepo.Transition(ctx, entry.ID, domain.WaitlistStatusClaimed, domain.WaitlistStatusOffered, ports.WaitlistPatch{}); rerr != nil {
+			log.Printf("waitlist: revert claim %s: %v", entry.ID, rerr)
+		}
+		return nil, err
+	}
+	return appt, nil
+}
+
+// ExpireWaitlistOffers lapses overdue offers and passes each slot to the next waiting client.
+func (s *AppointmentService) ExpireWaitlistOffers(ctx context.Context) (int, error) {
+	if s.waitRepo == nil {
+		return 0, nil
+	}
+	expired, err := s.waitRepo.ListExpiredOffers(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range expired {
+		ok, err := s.waitRepo.Transition(ctx, e.ID, domain.WaitlistStatusOffered, domain.WaitlistStatusExpired, ports.WaitlistPatch{})
+		if err != nil {
+			return n, err
+		}
+		if !ok {
+			continue // claimed or cancelled meanwhile
+		}
+		n++
+		if e.OfferedSlot != nil {
+			s.offerSlot(ctx, e.Day, *e.OfferedSlot)
+		}
+	}
+	return n, nil
+}
+
+// RunWaitlist calls ExpireWaitlistOffers every interval until ctx is done.
+func (s *AppointmentService) RunWaitlist(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := s.ExpireWaitlistOffers(ctx); err != nil {
+			log.Printf("appointment waitlist: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// slotFreed offers a cancelled (or moved) appointment's slot to the day's waitlist.
+func (s *AppointmentService) slotFreed(ctx context.Context, a *domain.Appointment) {
+	if s.waitRepo == nil || !a.ScheduledAt.After(time.Now()) {
+		return
+	}
+	day, _ := dayRangeUTC(a.ScheduledAt)
+	s.offerSlot(ctx, day, a.ScheduledAt)
+}
+
+// offerSlot holds slot for the oldest waiting client of day, if the day has room. Errors are
+// logged: the caller's cancellation already succeeded and the expiry sweep retries later.
+func (s *AppointmentService) offerSlot(ctx context.Context, day time.Time, slot time.Time) {
+	if !slot.After(time.Now()) {
+		return
+	}
+	_, dayEnd := dayRangeUTC(day)
+	for attempt := 0; attempt < maxOfferAttempts; attempt++ {
+		load, err := s.dayLoad(ctx, day, dayEnd, nil)
+		if err != nil {
+			log.Printf("waitlist: day load %s: %v", day.Format("2006-01-02"), err)
+			return
+		}
+		if load >= MaxAppointmentsPerDay {
+			return
+		}
+		next, err := s.waitRepo.NextWaiting(ctx, day)
+		if err != nil {
+			log.Printf("waitlist: next waiting %s: %v", day.Format("2006-01-02"), err)
+			return
+		}
+		if next == nil {
+			return
+		}
+		expires := time.Now().Add(WaitlistOfferTTL)
+		if slot.Before(expires) {
+			expires = slot
+		}
+		ok, err := s.waitRepo.Transition(ctx, next.ID, domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered,
+			ports.WaitlistPatch{OfferedSlot: &slot, OfferExpiresAt: &expires})
+		if err != nil {
+			log.Printf("waitlist: offer %s: %v", next.ID, err)
+			return
+		}
+		if ok {
+			s.notifyOffer(ctx, next, slot, expires)
+			return
+		}
+	}
+}
+
+func (s *AppointmentService) notifyOffer(ctx context.Context, e *domain.WaitlistEntry, slot, expires time.Time) {
+	if s.notifier == nil || s.signer == nil {
+		return
+	}
+	customer, err := s.userRepo.GetByID(ctx, e.CustomerID)
+	if err != nil || customer == nil {
+		log.Printf("waitlist: no recipient for entry %s: %v", e.ID, err)
+		return
+	}
+	channel, to := reminderRecipient(domain.NotificationChannelEmail, customer)
+	if to == "" {
+		return
+	}
+	token := s.signer.Sign(WaitlistClaimPurpose, e.ID, expires)
+	local := slot.In(s.calendarLoc)
+	msg := fmt.Sprintf("Se liberó un turno el %s a las %s para %s. Lo reservamos para vos hasta las %s: %s",
+		local.Format("02/01"), local.Format("15:04"), e.ServiceType, expires.In(s.calendarLoc).Format("15:04"),
+		WaitlistClaimLink(s.publicBaseURL, token))
+	err = s.notifier.QueueNotification(ctx, services.NotificationRequest{
+		Type:      channel,
+		To:        to,
+		Subject:   "Se liberó un turno",
+		Message:   msg,
+		DedupeKey: fmt.Sprintf("waitlist-offer:%s:%d", e.ID, slot.Unix()),
+	})
+	if err != nil {
+		log.Printf("waitlist: queue offer %s: %v", e.ID, err)
+	}
+}
+
+// dayLoad counts capacity used on [start, end): non-cancelled appointments plus live waitlist
+// offers, which hold their slot until claimed or expired.
+func (s *AppointmentService) dayLoad(ctx context.Context, start, end time.Time, excludeAppointment *uuid.UUID) (int64, error) {
+	n, err := s.repo.CountNonCancelledBetween(ctx, start, end, excludeAppointment)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count appointments for day: %w", err)
+	}
+	limit, err := s.dayLimit(ctx, start)
+	if err != nil {
+		return 0, err
+	}
+	return n + MaxAppointmentsPerDay - limit, nil
+}
+
+// dayLimit is how many appointments the day starting at start may hold: MaxAppointmentsPerDay
+// minus the slots held by live waitlist offers. It is the limit handed to the repository's
+// locked capacity check.
+func (s *AppointmentService) dayLimit(ctx context.Context, start time.Time) (int64, error) {
+	if s.waitRepo == nil {
+		return MaxAppointmentsPerDay, nil
+	}
+	entries, err := s.waitRepo.ListByDay(ctx, start)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list waitlist for day: %w", err)
+	}
+	limit := int64(MaxAppointmentsPerDay)
+	now := time.Now()
+	for _, e := range entries {
+		if e.Status == domain.WaitlistStatusOffered && e.OfferExpiresAt != nil && e.OfferExpiresAt.After(now) {
+			limit--
+		}
+	}
+	return limit, nil
+}
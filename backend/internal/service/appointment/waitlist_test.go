@@ -0,0 +1,289 @@
+package appointment
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memWaitlistRepo mirrors the conditional updates of the GORM repository under a mutex.
+type memWaitlistRepo struct {
+	mu   sync.Mutex
+	rows map[uuid.UUID]*domain.WaitlistEntry
+}
+
+func newMemWaitlistRepo() *memWaitlistRepo {
+	return &memWaitlistRepo{rows: map[uuid.UUID]*domain.WaitlistEntry{}}
+}
+
+func (m *memWaitlistRepo) Create(_ context.Context, e *domain.WaitlistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *e
+	m.rows[e.ID] = &cp
+	return nil
+}
+
+func (m *memWaitlistRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.WaitlistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.rows[id]
+	if !ok {
+		return nil, domain.ErrWaitlistEntryNotFound
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (m *memWaitlistRepo) filter(keep func(*domain.WaitlistEntry) bool) []*domain.WaitlistEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.WaitlistEntry{}
+	for _, e := range m.rows {
+		if keep(e) {
+			cp := *e
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+func (m *memWaitlistRepo) ListByCustomer(_ context.Context, customerID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	return m.filter(func(e *domain.WaitlistEntry) bool {
+		return e.CustomerID == customerID && (e.Status == domain.WaitlistStatusWaiting || e.Status == domain.WaitlistStatusOffered)
+	}), nil
+}
+
+func (m *memWaitlistRepo) ListByDay(_ context.Context, day time.Time) ([]*domain.WaitlistEntry, error) {
+	return m.filter(func(e *domain.WaitlistEntry) bool { return e.Day.Equal(day) }), nil
+}
+
+func (m *memWaitlistRepo) NextWaiting(_ context.Context, day time.Time) (*domain.WaitlistEntry, error) {
+	list := m.filter(func(e *domain.WaitlistEntry) bool {
+		return e.Day.Equal(day) && e.Status == domain.WaitlistStatusWaiting
+	})
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+func (m *memWaitlistRepo) Transition(_ context.Context, id uuid.UUID, from, to domain.WaitlistStatus, patch ports.WaitlistPatch) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.rows[id]
+	if !ok || e.Status != from {
+		return false, nil
+	}
+	e.Status = to
+	if patch.OfferedSlot != nil {
+		e.OfferedSlot = patch.OfferedSlot
+	}
+	if patch.OfferExpiresAt != nil {
+		e.OfferExpiresAt = patch.OfferExpiresAt
+	}
+	if patch.AppointmentID != nil {
+		e.AppointmentID = patch.AppointmentID
+	}
+	return true, nil
+}
+
+func (m *memWaitlistRepo) ListExpiredOffers(_ context.Context, now time.Time, _ int) ([]*domain.WaitlistEntry, error) {
+	return m.filter(func(e *domain.WaitlistEntry) bool {
+		return e.Status == domain.WaitlistStatusOffered && e.OfferExpiresAt != nil && e.OfferExpiresAt.Before(now)
+	}), nil
+}
+
+// syncApptRepo counts creates safely for the concurrent claim test.
+type syncApptRepo struct {
+	stubApptRepo
+	mu sync.Mutex
+}
+
+func (s *syncApptRepo) Create(ctx context.Context, a *domain.Appointment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stubApptRepo.Create(ctx, a)
+}
+
+func (s *syncApptRepo) CreateWithinDayCap(ctx context.Context, a *domain.Appointment, dayStart, dayEnd time.Time, limit int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stubApptRepo.CreateWithinDayCap(ctx, a, dayStart, dayEnd, limit)
+}
+
+type waitlistFixture struct {
+	svc      *AppointmentService
+	repo     *syncApptRepo
+	wait     *memWaitlistRepo
+	notifier *recordingNotifier
+	client   *domain.User
+	car      *domain.Car
+	day      time.Time
+}
+
+func newWaitlistFixture(t *testing.T) *waitlistFixture {
+	t.Helper()
+	client := calendarUser(domain.RoleClient)
+	car := &domain.Car{ID: uuid.New(), OwnerID: client.ID, LicensePlate: "AA-00-BB"}
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day()+3, 0, 0, 0, 0, time.Local)
+	f := &waitlistFixture{
+		repo:     &syncApptRepo{stubApptRepo: stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{}, countN: MaxAppointmentsPerDay}},
+		wait:     newMemWaitlistRepo(),
+		notifier: &recordingNotifier{},
+		client:   client,
+		car:      car,
+		day:      day,
+	}
+	f.svc = NewAppointmentService(f.repo, &apptTestUserRepo{users: map[uuid.UUID]*domain.User{client.ID: client}},
+		&stubCarRepo{byID: map[uuid.UUID]*domain.Car{car.ID: car}},
+		WithWaitlistRepository(f.wait), WithLinkSigner(signedlink.New("secret")),
+		WithNotifier(f.notifier), WithPublicBaseURL("https://app.example/"))
+	return f
+}
+
+func (f *waitlistFixture) join(t *testing.T, createdAt time.Time) *domain.WaitlistEntry {
+	t.Helper()
+	e, err := f.svc.JoinWaitlist(context.Background(), &domain.WaitlistEntry{CarID: f.car.ID, ServiceType: "inspection", Day: f.day}, f.client.ID)
+	require.NoError(t, err)
+	f.wait.mu.Lock()
+	f.wait.rows[e.ID].CreatedAt = createdAt
+	f.wait.mu.Unlock()
+	return e
+}
+
+func TestAppointmentService_JoinWaitlist(t *testing.T) {
+	t.Parallel()
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+
+	f.repo.countN = MaxAppointmentsPerDay - 1
+	_, err := f.svc.JoinWaitlist(ctx, &domain.WaitlistEntry{CarID: f.car.ID, ServiceType: "inspection", Day: f.day}, f.client.ID)
+	assert.ErrorIs(t, err, domain.ErrWaitlistDayNotFull)
+
+	f.repo.countN = MaxAppointmentsPerDay
+	e, err := f.svc.JoinWaitlist(ctx, &domain.WaitlistEntry{CarID: f.car.ID, ServiceType: "inspection", Day: f.day.Add(15 * time.Hour)}, f.client.ID)
+	require.NoError(t, err)
+	assert.True(t, e.Day.Equal(f.day), "day is normalised to local midnight")
+	assert.Equal(t, domain.WaitlistStatusWaiting, e.Status)
+
+	_, err = f.svc.JoinWaitlist(ctx, &domain.WaitlistEntry{CarID: f.car.ID, ServiceType: "oil", Day: f.day}, f.client.ID)
+	assert.ErrorIs(t, err, domain.ErrWaitlistDuplicate)
+
+	_, err = f.svc.JoinWaitlist(ctx, &domain.WaitlistEntry{CarID: uuid.New(), ServiceType: "oil", Day: f.day.AddDate(0, 0, 1)}, f.client.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWaitlistData)
+}
+
+func TestAppointmentService_WaitlistOffersFIFOAndPassesOnExpiry(t *testing.T) {
+	t.Parallel()
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+	base := time.Now()
+	first := f.join(t, base)
+	other := calendarUser(domain.RoleClient)
+	f.svc.userRepo.(*apptTestUserRepo).users[other.ID] = other
+	second := &domain.WaitlistEntry{ID: uuid.New(), CustomerID: other.ID, CarID: uuid.New(), ServiceType: "oil", Day: f.day,
+		Status: domain.WaitlistStatusWaiting, CreatedAt: base.Add(time.Minute)}
+	require.NoError(t, f.wait.Create(ctx, second))
+
+	slot := f.day.Add(10 * time.Hour)
+	appt := &domain.Appointment{ID: uuid.New(), CustomerID: f.client.ID, CarID: f.car.ID, ServiceType: "x",
+		Status: domain.AppointmentStatusScheduled, ScheduledAt: slot}
+	f.repo.byID[appt.ID] = appt
+	f.repo.countN = MaxAppointmentsPerDay - 1 // the cancellation frees one slot
+
+	_, err := f.svc.UpdateAppointment(ctx, &domain.Appointment{ID: appt.ID, Status: domain.AppointmentStatusCancelled}, f.client.ID)
+	require.NoError(t, err)
+
+	got, _ := f.wait.GetByID(ctx, first.ID)
+	require.Equal(t, domain.WaitlistStatusOffered, got.Status)
+	assert.True(t, slot.Equal(*got.OfferedSlot))
+	require.Len(t, f.notifier.reqs, 1)
+	assert.Contains(t, f.notifier.reqs[0].Message, "https://app.example/waitlist/claim?token=")
+	got, _ = f.wait.GetByID(ctx, second.ID)
+	assert.Equal(t, domain.WaitlistStatusWaiting, got.Status)
+
+	// The live offer holds the slot: the day stays full for direct bookings.
+	load, err := f.svc.dayLoad(ctx, f.day, f.day.AddDate(0, 0, 1), nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, MaxAppointmentsPerDay, load)
+
+	past := time.Now().Add(-time.Minute)
+	f.wait.rows[first.ID].OfferExpiresAt = &past
+	n, err := f.svc.ExpireWaitlistOffers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	got, _ = f.wait.GetByID(ctx, first.ID)
+	assert.Equal(t, domain.WaitlistStatusExpired, got.Status)
+	got, _ = f.wait.GetByID(ctx, second.ID)
+	assert.Equal(t, domain.WaitlistStatusOffered, got.Status)
+	assert.Len(t, f.notifier.reqs, 2)
+}
+
+func TestAppointmentService_ClaimWaitlistOffer_Concurrent(t *testing.T) {
+	t.Parallel()
+	f := newWaitlistFixture(t)
+	ctx := context.Background()
+	e := f.join(t, time.Now())
+	f.repo.countN = MaxAppointmentsPerDay - 1 // the freed slot the offer holds
+	slot := f.day.Add(10 * time.Hour)
+	expires := time.Now().Add(time.Hour)
+	ok, err := f.wait.Transition(ctx, e.ID, domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered,
+		ports.WaitlistPatch{OfferedSlot: &slot, OfferExpiresAt: &expires})
+	require.NoError(t, err)
+	require.True(t, ok)
+	token := f.svc.signer.Sign(WaitlistClaimPurpose, e.ID, expires)
+
+	const workers = 16
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		wins    int
+		refused int
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := f.svc.ClaimWaitlistOffer(ctx, token)
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				wins++
+			case domain.ErrWaitlistOfferUnavailable:
+				refused++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, 1, wins)
+	assert.Equal(t, workers-1, refused)
+	require.Len(t, f.repo.created, 1)
+	created := f.repo.created[0]
+	assert.True(t, slot.Equal(created.ScheduledAt))
+	assert.Equal(t, f.client.ID, created.CustomerID)
+	got, _ := f.wait.GetByID(ctx, e.ID)
+	assert.Equal(t, domain.WaitlistStatusClaimed, got.Status)
+	assert.Equal(t, created.ID, *got.AppointmentID)
+
+	_, err = f.svc.ClaimWaitlistOffer(ctx, "bogus")
+	assert.ErrorIs(t, err, domain.ErrWaitlistOfferUnavailable)
+}
@@ -9,6 +9,9 @@ import (
 // MaxAppointmentsPerDay is the workshop daily capacity (non-cancelled appointments).
 const MaxAppointmentsPerDay = 8
 
+// AppointmentSlotDuration is how long one appointment occupies its assigned technician.
+const AppointmentSlotDuration = time.Hour
+
 // validateWorkshopClock checks local wall-clock time is within:
 // 09:30–12:30 or 14:00–17:30 (inclusive endpoints).
 func validateWorkshopClock(t time.Time) error {
@@ -0,0 +1,153 @@
+package labour
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// ErrInvalidCatalogCSV is returned when an import has no usable header row.
+var ErrInvalidCatalogCSV = errors.New("catalog CSV needs a header with code, description and hours columns")
+
+// MaxCatalogCSVBytes bounds a catalog import upload.
+const MaxCatalogCSVBytes = 5 << 20
+
+// csvOperation is one data row of a catalog CSV; err is set when the row cannot be used.
+type csvOperation struct {
+	line  int
+	op    domain.LabourOperation
+	parts []csvKitPart
+	err   error
+}
+
+// csvKitPart is a kit entry as written in the CSV; ref is the part's barcode or reference.
+type csvKitPart struct {
+	ref       string
+	quantity  float64
+	unitPrice float64
+}
+
+// parseOperationsCSV reads a catalog export separated by "," or ";" (taken from the header). Columns, in any
+// order and case: code, description, hours (or flat_rate_hours), and optionally
+//
+//   - vehicles: "Make:Model|Make", a make alone covering all its models;
+//   - parts: "REF:quantity:unit_price|...", REF being the inventory barcode or reference.
+//
+// Decimals may use a comma.
+func parseOperationsCSV(data []byte) ([]csvOperation, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	header, _, _ := strings.Cut(strings.TrimLeft(string(data), "\r\n\t "), "\n")
+	rd := csv.NewReader(bytes.NewReader(data))
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		rd.Comma = ';'
+	}
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	names, err := rd.Read()
+	if err != nil {
+		return nil, ErrInvalidCatalogCSV
+	}
+	cols := map[string]int{}
+	for i, n := range names {
+		cols[strings.ToLower(strings.TrimSpace(n))] = i
+	}
+	if _, ok := cols["hours"]; !ok {
+		if i, ok := cols["flat_rate_hours"]; ok {
+			cols["hours"] = i
+		}
+	}
+	for _, c := range []string{"code", "description", "hours"} {
+		if _, ok := cols[c]; !ok {
+			return nil, ErrInvalidCatalogCSV
+		}
+	}
+	get := func(row []string, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var out []csvOperation
+	for {
+		row, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return nil, err
+			}
+			out = append(out, csvOperation{line: pe.StartLine, err: err})
+			continue
+		}
+		line, _ := rd.FieldPos(0)
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		r := csvOperation{line: line, op: domain.LabourOperation{
+			Code:        strings.ToUpper(get(row, "code")),
+			Description: get(row, "description"),
+		}}
+		if r.op.FlatRateHours, err = parseDecimal(get(row, "hours")); err != nil {
+			r.err = fmt.Errorf("invalid hours %q", get(row, "hours"))
+		} else if r.op.Vehicles, err = parseVehicles(get(row, "vehicles")); err != nil {
+			r.err = err
+		} else if r.parts, err = parseKit(get(row, "parts")); err != nil {
+			r.err = err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func parseVehicles(s string) ([]domain.LabourOperationVehicle, error) {
+	var out []domain.LabourOperationVehicle
+	for _, item := range splitList(s) {
+		mk, model, _ := strings.Cut(item, ":")
+		if strings.TrimSpace(mk) == "" {
+			return nil, fmt.Errorf("invalid vehicle %q", item)
+		}
+		out = append(out, domain.LabourOperationVehicle{Make: strings.TrimSpace(mk), Model: strings.TrimSpace(model)})
+	}
+	return out, nil
+}
+
+func parseKit(s string) ([]csvKitPart, error) {
+	var out []csvKitPart
+	for _, item := range splitList(s) {
+		f := strings.Split(item, ":")
+		if len(f) != 3 || strings.TrimSpace(f[0]) == "" {
+			return nil, fmt.Errorf("invalid kit part %q (want REF:quantity:unit_price)", item)
+		}
+		qty, err1 := parseDecimal(f[1])
+		price, err2 := parseDecimal(f[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid kit part %q (want REF:quantity:unit_price)", item)
+		}
+		out = append(out, csvKitPart{ref: strings.TrimSpace(f[0]), quantity: qty, unitPrice: price})
+	}
+	return out, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func parseDecimal(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+}
@@ -0,0 +1,229 @@
+package labour
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// LabourOperationService implements ports.LabourOperationService. Staff read the catalog; managers and admins
+// edit it, since flat-rate times set what customers pay.
+type LabourOperationService struct {
+	repo     ports.LabourOperationRepository
+	partRepo ports.PartItemRepository
+	userRepo ports.UserRepository
+}
+
+func NewLabourOperationService(repo ports.LabourOperationRepository, partRepo ports.PartItemRepository, userRepo ports.UserRepository) *LabourOperationService {
+	return &LabourOperationService{repo: repo, partRepo: partRepo, userRepo: userRepo}
+}
+
+var _ ports.LabourOperationService = (*LabourOperationService)(nil)
+
+func (s *LabourOperationService) requireUser(ctx context.Context, userID uuid.UUID, manager bool) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return domain.ErrUserNotFound
+	}
+	if !u.IsEmployee() || (manager && !u.IsManager()) {
+		return domain.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// Create adds an operation to the catalog (manager/admin only). Codes are unique.
+func (s *LabourOperationService) Create(ctx context.Context, op *domain.LabourOperation, requestingUserID uuid.UUID) (*domain.LabourOperation, error) {
+	if err := s.requireUser(ctx, requestingUserID, true); err != nil {
+		return nil, err
+	}
+	if err := s.prepare(ctx, op); err != nil {
+		return nil, err
+	}
+	if err := s.ensureCodeFree(ctx, op.Code, op.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, op); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, op.ID)
+}
+
+// Get returns one operation (staff).
+func (s *LabourOperationService) Get(ctx context.Context, id uuid.UUID, requestingUserID uuid.UUID) (*domain.LabourOperation, error) {
+	if err := s.requireUser(ctx, requestingUserID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// List searches the catalog (staff).
+func (s *LabourOperationService) List(ctx context.Context, filters ports.LabourOperationListFilters, requestingUserID uuid.UUID) ([]*domain.LabourOperation, int64, error) {
+	if err := s.requireUser(ctx, requestingUserID, false); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, filters)
+}
+
+// Update replaces an operation, its vehicles and kit (manager/admin only).
+func (s *LabourOperationService) Update(ctx context.Context, op *domain.LabourOperation, requestingUserID uuid.UUID) (*domain.LabourOperation, error) {
+	if err := s.requireUser(ctx, requestingUserID, true); err != nil {
+		return nil, err
+	}
+	if op == nil || op.ID == uuid.Nil {
+		return nil, domain.ErrInvalidLabourOperation
+	}
+	existing, err := s.repo.GetByID(ctx, op.ID)
+	if err != nil {
+		return nil, err
+	}
+	op.CreatedAt = existing.CreatedAt
+	if err := s.prepare(ctx, op); err != nil {
+		return nil, err
+	}
+	if err := s.ensureCodeFree(ctx, op.Code, op.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, op); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, op.ID)
+}
+
+// Delete removes an operation from the catalog (manager/admin only). Lines built from it keep their text and prices.
+func (s *LabourOperationService) Delete(ctx context.Context, id uuid.UUID, requestingUserID uuid.UUID) error {
+	if err := s.requireUser(ctx, requestingUserID, true); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ImportCSV creates or updates (by code) the operations of a catalog CSV laid out as parseOperationsCSV
+// describes. Bad rows are reported and skipped. Manager/admin only.
+func (s *LabourOperationService) ImportCSV(ctx context.Context, data []byte, requestingUserID uuid.UUID) (*ports.LabourOperationImport, error) {
+	if err := s.requireUser(ctx, requestingUserID, true); err != nil {
+		return nil, err
+	}
+	rows, err := parseOperationsCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	out := &ports.LabourOperationImport{Errors: []ports.LabourOperationImportError{}}
+	for _, row := range rows {
+		created, err := s.importRow(ctx, row)
+		switch {
+		case err != nil:
+			out.Errors = append(out.Errors, ports.LabourOperationImportError{Row: row.line, Code: row.op.Code, Error: err.Error()})
+		case created:
+			out.Created++
+		default:
+			out.Updated++
+		}
+	}
+	return out, nil
+}
+
+func (s *LabourOperationService) importRow(ctx context.Context, row csvOperation) (created bool, err error) {
+	if row.err != nil {
+		return false, row.err
+	}
+	op := row.op
+	for _, kp := range row.parts {
+		item, err := s.findPart(ctx, kp.ref)
+		if err != nil {
+			return false, err
+		}
+		op.Parts = append(op.Parts, domain.LabourOperationPart{PartItemID: item.ID, Quantity: kp.quantity, UnitPrice: kp.unitPrice})
+	}
+	existing, err := s.repo.GetByCode(ctx, op.Code)
+	if err != nil && !errors.Is(err, domain.ErrLabourOperationNotFound) {
+		return false, err
+	}
+	if existing != nil {
+		op.ID = existing.ID
+		op.CreatedAt = existing.CreatedAt
+	}
+	if err := s.prepare(ctx, &op); err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, s.repo.Update(ctx, &op)
+	}
+	return true, s.repo.Create(ctx, &op)
+}
+
+// findPart resolves a kit part named in the CSV by barcode, or else by exact reference.
+func (s *LabourOperationService) findPart(ctx context.Context, ref string) (*domain.PartItem, error) {
+	item, err := s.partRepo.GetByBarcode(ctx, ref)
+	if err == nil && item != nil {
+		return item, nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrPartItemNotFound) {
+		return nil, err
+	}
+	items, _, err := s.partRepo.List(ctx, ports.PartItemListFilters{Search: &ref, Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range items {
+		if strings.EqualFold(p.Reference, ref) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("part %q not found in inventory", ref)
+}
+
+// prepare validates op, stamps IDs and copies the part names into the kit.
+func (s *LabourOperationService) prepare(ctx context.Context, op *domain.LabourOperation) error {
+	if op == nil {
+		return domain.ErrInvalidLabourOperation
+	}
+	if err := op.Validate(); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if op.ID == uuid.Nil {
+		op.ID = uuid.New()
+	}
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = now
+	}
+	op.UpdatedAt = now
+	for i := range op.Vehicles {
+		op.Vehicles[i].ID = uuid.New()
+		op.Vehicles[i].OperationID = op.ID
+	}
+	for i := range op.Parts {
+		p := &op.Parts[i]
+		item, err := s.partRepo.GetByID(ctx, p.PartItemID)
+		if err != nil {
+			return err
+		}
+		p.ID = uuid.New()
+		p.OperationID = op.ID
+		p.Description = strings.TrimSpace(item.Brand + " " + item.Name)
+	}
+	return nil
+}
+
+func (s *LabourOperationService) ensureCodeFree(ctx context.Context, code string, id uuid.UUID) error {
+	existing, err := s.repo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, domain.ErrLabourOperationNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != id {
+		return domain.ErrLabourOperationCodeTaken
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package labour
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- test doubles ---
+
+type labourTestUserRepo struct {
+	users map[uuid.UUID]*domain.User
+}
+
+func (r *labourTestUserRepo) Create(ctx context.Context, user *domain.User) error { return nil }
+func (r *labourTestUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return nil, nil
+}
+func (r *labourTestUserRepo) GetByRole(ctx context.Context, role string, limit, offset int) ([]*domain.User, error) {
+	return nil, nil
+}
+func (r *labourTestUserRepo) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	return nil, nil
+}
+func (r *labourTestUserRepo) Update(ctx context.Context, user *domain.User) error { return nil }
+func (r *labourTestUserRepo) Delete(ctx context.Context, id uuid.UUID) error      { return nil }
+func (r *labourTestUserRepo) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	return nil
+}
+func (r *labourTestUserRepo) GetActiveUsers(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	return nil, nil
+}
+func (r *labourTestUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return u, nil
+}
+
+type labourTestPartRepo struct {
+	items []*domain.PartItem
+}
+
+func (r *labourTestPartRepo) Create(ctx context.Context, p *domain.PartItem) error { return nil }
+func (r *labourTestPartRepo) Update(ctx context.Context, p *domain.PartItem) error { return nil }
+func (r *labourTestPartRepo) Delete(ctx context.Context, id uuid.UUID) error       { return nil }
+func (r *labourTestPartRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.PartItem, error) {
+	for _, p := range r.items {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, domain.ErrPartItemNotFound
+}
+func (r *labourTestPartRepo) GetByBarcode(ctx context.Context, barcode string) (*domain.PartItem, error) {
+	for _, p := range r.items {
+		if p.Barcode != "" && p.Barcode == barcode {
+			return p, nil
+		}
+	}
+	return nil, domain.ErrPartItemNotFound
+}
+func (r *labourTestPartRepo) List(ctx context.Context, f ports.PartItemListFilters) ([]*domain.PartItem, int64, error) {
+	var out []*domain.PartItem
+	for _, p := range r.items {
+		if f.Search == nil || strings.Contains(strings.ToLower(p.Reference+" "+p.Name), strings.ToLower(*f.Search)) {
+			out = append(out, p)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+type labourTestOperationRepo struct {
+	byID map[uuid.UUID]*domain.LabourOperation
+}
+
+func newLabourTestOperationRepo() *labourTestOperationRepo {
+	return &labourTestOperationRepo{byID: map[uuid.UUID]*domain.LabourOperation{}}
+}
+
+func (r *labourTestOperationRepo) Create(ctx context.Context, op *domain.LabourOperation) error {
+	cp := *op
+	r.byID[op.ID] = &cp
+	return nil
+}
+func (r *labourTestOperationRepo) Update(ctx context.Context, op *domain.LabourOperation) error {
+	if _, ok := r.byID[op.ID]; !ok {
+		return domain.ErrLabourOperationNotFound
+	}
+	cp := *op
+	r.byID[op.ID] = &cp
+	return nil
+}
+func (r *labourTestOperationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := r.byID[id]; !ok {
+		return domain.ErrLabourOperationNotFound
+	}
+	delete(r.byID, id)
+	return nil
+}
+func (r *labourTestOperationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.LabourOperation, error) {
+	op, ok := r.byID[id]
+	if !ok {
+		return nil, domain.ErrLabourOperationNotFound
+	}
+	cp := *op
+	return &cp, nil
+}
+func (r *labourTestOperationRepo) GetByCode(ctx context.Context, code string) (*domain.LabourOperation, error) {
+	for _, op := range r.byID {
+		if op.Code == strings.ToUpper(strings.TrimSpace(code)) {
+			cp := *op
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrLabourOperationNotFound
+}
+func (r *labourTestOperationRepo) List(ctx context.Context, f ports.LabourOperationListFilters) ([]*domain.LabourOperation, int64, error) {
+	var out []*domain.LabourOperation
+	for _, op := range r.byID {
+		out = append(out, op)
+	}
+	return out, int64(len(out)), nil
+}
+
+type labourFixture struct {
+	svc      *LabourOperationService
+	ops      *labourTestOperationRepo
+	manager  uuid.UUID
+	employee uuid.UUID
+	filter   *domain.PartItem
+}
+
+func newLabourFixture() *labourFixture {
+	manager := &domain.User{ID: uuid.New(), Role: domain.RoleManager}
+	employee := &domain.User{ID: uuid.New(), Role: domain.RoleEmployee}
+	filter := &domain.PartItem{ID: uuid.New(), Reference: "FO-100", Brand: "Mann", Name: "Filtro aceite", Barcode: "8400001"}
+	users := &labourTestUserRepo{users: map[uuid.UUID]*domain.User{manager.ID: manager, employee.ID: employee}}
+	ops := newLabourTestOperationRepo()
+	return &labourFixture{
+		svc:      NewLabourOperationService(ops, &labourTestPartRepo{items: []*domain.PartItem{filter}}, users),
+		ops:      ops,
+		manager:  manager.ID,
+		employee: employee.ID,
+		filter:   filter,
+	}
+}
+
+// --- tests ---
+
+func TestLabourOperationService_CreateRequiresManagerAndUniqueCode(t *testing.T) {
+	ctx := context.Background()
+	f := newLabourFixture()
+	op := func() *domain.LabourOperation {
+		return &domain.LabourOperation{
+			Code:          " ace-01 ",
+			Description:   "Cambio de aceite",
+			FlatRateHours: 0.5,
+			Parts:         []domain.LabourOperationPart{{PartItemID: f.filter.ID, Quantity: 1, UnitPrice: 9.5}},
+		}
+	}
+
+	_, err := f.svc.Create(ctx, op(), f.employee)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	out, err := f.svc.Create(ctx, op(), f.manager)
+	require.NoError(t, err)
+	assert.Equal(t, "ACE-01", out.Code)
+	require.Len(t, out.Parts, 1)
+	assert.Equal(t, "Mann Filtro aceite", out.Parts[0].Description)
+
+	_, err = f.svc.Create(ctx, op(), f.manager)
+	assert.ErrorIs(t, err, domain.ErrLabourOperationCodeTaken)
+
+	_, err = f.svc.Create(ctx, &domain.LabourOperation{Code: "X", Description: "Sin tiempo"}, f.manager)
+	assert.ErrorIs(t, err, domain.ErrInvalidLabourOperation)
+
+	list, total, err := f.svc.List(ctx, ports.LabourOperationListFilters{}, f.employee)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, list, 1)
+}
+
+func TestLabourOperationService_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	f := newLabourFixture()
+	existing, err := f.svc.Create(ctx, &domain.LabourOperation{Code: "EMB-01", Description: "Embrague", FlatRateHours: 4}, f.manager)
+	require.NoError(t, err)
+
+	csv := "\xef\xbb\xbfCode;Description;Flat_Rate_Hours;Vehicles;Parts\n" +
+		"ace-01;Cambio de aceite;0,5;Seat:Ibiza|Renault;FO-100:1:9,50\n" +
+		"EMB-01;Cambio de embrague;4,2;;\n" +
+		"FRE-01;Pastillas;abc;;\n" +
+		"DIS-01;Distribución;3;;NOPE:1:10\n"
+
+	_, err = f.svc.ImportCSV(ctx, []byte(csv), f.employee)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	res, err := f.svc.ImportCSV(ctx, []byte(csv), f.manager)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, 1, res.Updated)
+	require.Len(t, res.Errors, 2)
+	assert.Equal(t, 4, res.Errors[0].Row)
+	assert.Equal(t, "FRE-01", res.Errors[0].Code)
+	assert.Contains(t, res.Errors[1].Error, "NOPE")
+
+	ace, err := f.ops.GetByCode(ctx, "ACE-01")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, ace.FlatRateHours)
+	require.Len(t, ace.Vehicles, 2)
+	assert.Equal(t, "", ace.Vehicles[1].Model)
+	require.Len(t, ace.Parts, 1)
+	assert.Equal(t, f.filter.ID, ace.Parts[0].PartItemID)
+	assert.Equal(t, 9.5, ace.Parts[0].UnitPrice)
+	assert.True(t, ace.AppliesTo("renault", "Clio"))
+	assert.False(t, ace.AppliesTo("Seat", "Leon"))
+
+	emb, err := f.ops.GetByID(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cambio de embrague", emb.Description)
+	assert.Equal(t, 4.2, emb.FlatRateHours)
+
+	_, err = f.svc.ImportCSV(ctx, []byte("codigo,nombre\nA,B\n"), f.manager)
+	assert.ErrorIs(t, err, ErrInvalidCatalogCSV)
+
+	// A malformed row is reported, not a crash.
+	res, err = f.svc.ImportCSV(ctx, []byte("code,description,hours\nA,desc,1\n\"unterminated,1,2\n"), f.manager)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Created)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, 3, res.Errors[0].Row)
+}
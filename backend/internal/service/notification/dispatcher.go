@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+)
+
+const (
+	// DefaultMaxAttempts is how many deliveries are tried before a row is marked failed.
+	DefaultMaxAttempts = 5
+	dispatchBatchSize  = 50
+	// unconfiguredDelay is how long rows of a channel without delivery settings wait before the next look.
+	unconfiguredDelay = time.Hour
+)
+
+// Dispatcher drains due outbox rows through the sender registered for each channel.
+// It assumes a single running instance; several API replicas would need row claiming.
+type Dispatcher struct {
+	outbox      ports.OutboundNotificationRepository
+	senders     map[string]external.NotificationSender
+	maxAttempts int
+	now         func() time.Time
+}
+
+func NewDispatcher(outbox ports.OutboundNotificationRepository, senders map[string]external.NotificationSender) *Dispatcher {
+	return &Dispatcher{outbox: outbox, senders: senders, maxAttempts: DefaultMaxAttempts, now: time.Now}
+}
+
+// RunOnce delivers one batch of due notifications and returns how many were sent.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	now := d.now().UTC()
+	due, err := d.outbox.ListDue(ctx, now, dispatchBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, n := range due {
+		sender, ok := d.senders[n.Channel]
+		if !ok {
+			if err := d.outbox.MarkFailed(ctx, n.ID, fmt.Sprintf("no sender for channel %q", n.Channel), nil); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sendErr := sender.Send(ctx, n)
+		if errors.Is(sendErr, ErrChannelNotConfigured) {
+			if err := d.outbox.Postpone(ctx, n.ID, now.Add(unconfiguredDelay), sendErr.Error()); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if sendErr != nil {
+			var retryAt *time.Time
+			if n.Attempts+1 < d.maxAttempts {
+				// Exponential backoff: 1, 2, 4, 8... minutes.
+				t := now.Add(time.Duration(1<<n.Attempts) * time.Minute)
+				retryAt = &t
+			}
+			if err := d.outbox.MarkFailed(ctx, n.ID, sendErr.Error(), retryAt); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if err := d.outbox.MarkSent(ctx, n.ID, d.now()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Run calls RunOnce every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if _, err := d.RunOnce(ctx); err != nil {
+			log.Printf("notification dispatcher: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Service queues outbound notifications in the outbox; the Dispatcher delivers them.
+type Service struct {
+	outbox ports.OutboundNotificationRepository
+	now    func() time.Time
+}
+
+func NewService(outbox ports.OutboundNotificationRepository) *Service {
+	return &Service{outbox: outbox, now: time.Now}
+}
+
+// QueueNotification validates the request and stores it for delivery (idempotent per DedupeKey).
+func (s *Service) QueueNotification(ctx context.Context, req services.NotificationRequest) error {
+	channel := strings.ToLower(strings.TrimSpace(req.Type))
+	to := strings.TrimSpace(req.To)
+	if !domain.ValidNotificationChannel(channel) || to == "" || strings.TrimSpace(req.Message) == "" {
+		return domain.ErrInvalidNotification
+	}
+	if channel == domain.NotificationChannelEmail && !strings.Contains(to, "@") {
+		return domain.ErrInvalidNotification
+	}
+	if len(req.Attachments) > 0 && channel != domain.NotificationChannelEmail {
+		return domain.ErrInvalidNotification
+	}
+	for _, a := range req.Attachments {
+		if strings.TrimSpace(a.Filename) == "" || len(a.Content) == 0 {
+			return domain.ErrInvalidNotification
+		}
+	}
+	now := s.now().UTC()
+	sendAfter := now
+	if req.SendAt != nil && req.SendAt.After(now) {
+		sendAfter = req.SendAt.UTC()
+	}
+	_, err := s.outbox.Enqueue(ctx, &domain.OutboundNotification{
+		ID:          uuid.New(),
+		Channel:     channel,
+		Recipient:   to,
+		Subject:     strings.TrimSpace(req.Subject),
+		Body:        req.Message,
+		DedupeKey:   strings.TrimSpace(req.DedupeKey),
+		Status:      domain.OutboundNotificationPending,
+		SendAfter:   sendAfter,
+		Attachments: req.Attachments,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	return err
+}
+
+// Workshop lifecycle notifications are not routed to anyone yet.
+
+func (s *Service) SendWorkshopCreatedNotification(ctx context.Context, workshop *domain.Workshop) error {
+	return nil
+}
+
+func (s *Service) SendWorkshopUpdatedNotification(ctx context.Context, workshop *domain.Workshop) error {
+	return nil
+}
+
+func (s *Service) SendWorkshopDeletedNotification(ctx context.Context, workshop *domain.Workshop) error {
+	return nil
+}
+
+var _ services.NotificationService = (*Service)(nil)
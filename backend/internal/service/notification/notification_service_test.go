@@ -0,0 +1,226 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memOutbox struct {
+	rows   []*domain.OutboundNotification
+	failed map[uuid.UUID]string
+}
+
+func (m *memOutbox) Enqueue(_ context.Context, n *domain.OutboundNotification) (bool, error) {
+	for _, r := range m.rows {
+		if n.DedupeKey != "" && r.DedupeKey == n.DedupeKey {
+			return false, nil
+		}
+	}
+	m.rows = append(m.rows, n)
+	return true, nil
+}
+
+func (m *memOutbox) ListDue(_ context.Context, now time.Time, limit int) ([]*domain.OutboundNotification, error) {
+	var out []*domain.OutboundNotification
+	for _, r := range m.rows {
+		if r.Status == domain.OutboundNotificationPending && !r.SendAfter.After(now) {
+			cp := *r
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memOutbox) find(id uuid.UUID) *domain.OutboundNotification {
+	for _, r := range m.rows {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (m *memOutbox) MarkSent(_ context.Context, id uuid.UUID, at time.Time) error {
+	r := m.find(id)
+	r.Status = domain.OutboundNotificationSent
+	r.Attempts++
+	r.SentAt = &at
+	return nil
+}
+
+func (m *memOutbox) MarkFailed(_ context.Context, id uuid.UUID, lastErr string, retryAt *time.Time) error {
+	r := m.find(id)
+	r.Attempts++
+	r.LastError = lastErr
+	if retryAt != nil {
+		r.SendAfter = *retryAt
+	} else {
+		r.Status = domain.OutboundNotificationFailed
+	}
+	return nil
+}
+
+func (m *memOutbox) Postpone(_ context.Context, id uuid.UUID, until time.Time, reason string) error {
+	r := m.find(id)
+	r.SendAfter = until
+	r.LastError = reason
+	return nil
+}
+
+type recordingSender struct {
+	sent []*domain.OutboundNotification
+	err  error
+}
+
+func (s *recordingSender) Send(_ context.Context, n *domain.OutboundNotification) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func TestService_QueueNotification_Validates(t *testing.T) {
+	t.Parallel()
+	svc := NewService(&memOutbox{})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "fax", To: "1", Message: "x"}), domain.ErrInvalidNotification)
+	assert.ErrorIs(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "sms", To: "", Message: "x"}), domain.ErrInvalidNotification)
+	assert.ErrorIs(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "email", To: "nope", Message: "x"}), domain.ErrInvalidNotification)
+	assert.NoError(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "WhatsApp", To: "+351900", Message: "x"}))
+
+	ics := []domain.NotificationAttachment{{Filename: "turno.ics", ContentType: "text/calendar", Content: []byte("BEGIN:VCALENDAR")}}
+	assert.ErrorIs(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "sms", To: "+351900", Message: "x", Attachments: ics}), domain.ErrInvalidNotification)
+	assert.NoError(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "email", To: "a@b.c", Message: "x", Attachments: ics}))
+}
+
+func TestBuildEmail_WithAttachment(t *testing.T) {
+	t.Parallel()
+	raw := buildEmail("taller@example.com", &domain.OutboundNotification{
+		Recipient: "c@example.com",
+		Subject:   "Turno confirmado",
+		Body:      "Hola\nNos vemos",
+		Attachments: []domain.NotificationAttachment{
+			{Filename: "turno.ics", ContentType: "text/calendar; charset=UTF-8; method=PUBLISH", Content: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+		},
+	})
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := mr.NextPart()
+	require.NoError(t, err)
+	body, _ := io.ReadAll(text)
+	assert.Equal(t, "Hola\r\nNos vemos\r\n", string(body))
+
+	att, err := mr.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "turno.ics", att.FileName())
+	enc, _ := io.ReadAll(att)
+	dec, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(enc), "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", string(dec))
+}
+
+func TestService_QueueNotification_DedupeAndDelay(t *testing.T) {
+	t.Parallel()
+	box := &memOutbox{}
+	svc := NewService(box)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	req := services.NotificationRequest{Type: "sms", To: "+351900", Message: "x", DedupeKey: "k", SendAt: &later}
+	require.NoError(t, svc.QueueNotification(ctx, req))
+	require.NoError(t, svc.QueueNotification(ctx, req))
+	require.Len(t, box.rows, 1)
+	assert.WithinDuration(t, later, box.rows[0].SendAfter, time.Second)
+}
+
+func TestDispatcher_RunOnce_SendsAndRetries(t *testing.T) {
+	t.Parallel()
+	box := &memOutbox{}
+	svc := NewService(box)
+	ctx := context.Background()
+	require.NoError(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "sms", To: "+351900", Message: "a"}))
+	require.NoError(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "email", To: "a@b.c", Message: "b"}))
+	require.NoError(t, svc.QueueNotification(ctx, services.NotificationRequest{Type: "whatsapp", To: "+351900", Message: "c"}))
+
+	sms := &recordingSender{}
+	email := &recordingSender{err: errors.New("smtp down")}
+	d := NewDispatcher(box, map[string]external.NotificationSender{
+		domain.NotificationChannelSMS:   sms,
+		domain.NotificationChannelEmail: email,
+	})
+	n, err := d.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, sms.sent, 1)
+
+	byChannel := map[string]*domain.OutboundNotification{}
+	for _, r := range box.rows {
+		byChannel[r.Channel] = r
+	}
+	assert.Equal(t, domain.OutboundNotificationSent, byChannel["sms"].Status)
+	assert.Equal(t, domain.OutboundNotificationPending, byChannel["email"].Status, "retried later")
+	assert.True(t, byChannel["email"].SendAfter.After(time.Now()))
+	assert.Equal(t, domain.OutboundNotificationFailed, byChannel["whatsapp"].Status, "no sender configured")
+}
+
+func TestDispatcher_RunOnce_UnconfiguredChannelStaysPending(t *testing.T) {
+	t.Parallel()
+	box := &memOutbox{}
+	ctx := context.Background()
+	require.NoError(t, NewService(box).QueueNotification(ctx, services.NotificationRequest{Type: "sms", To: "+351900", Message: "https://app/t/secret"}))
+
+	d := NewDispatcher(box, map[string]external.NotificationSender{domain.NotificationChannelSMS: UnconfiguredSender{}})
+	for i := 0; i < DefaultMaxAttempts+1; i++ {
+		box.rows[0].SendAfter = time.Now().Add(-time.Minute)
+		n, err := d.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	}
+	row := box.rows[0]
+	assert.Equal(t, domain.OutboundNotificationPending, row.Status)
+	assert.Zero(t, row.Attempts, "waiting for configuration is not a failed attempt")
+	assert.True(t, row.SendAfter.After(time.Now()))
+	assert.Equal(t, ErrChannelNotConfigured.Error(), row.LastError)
+}
+
+func TestWebhookSender_Status(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	n := &domain.OutboundNotification{ID: uuid.New(), Channel: "sms", Recipient: "+351900", Body: "x"}
+
+	assert.NoError(t, (&WebhookSender{URL: srv.URL + "/ok", Token: "tok"}).Send(context.Background(), n))
+	assert.Error(t, (&WebhookSender{URL: srv.URL + "/fail", Token: "tok"}).Send(context.Background(), n))
+}
@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+// ErrChannelNotConfigured is returned by UnconfiguredSender; the dispatcher keeps such rows pending.
+var ErrChannelNotConfigured = errors.New("notification channel not configured")
+
+// UnconfiguredSender stands in for a channel with no delivery settings. Nothing is sent or logged.
+type UnconfiguredSender struct{}
+
+func (UnconfiguredSender) Send(context.Context, *domain.OutboundNotification) error {
+	return ErrChannelNotConfigured
+}
+
+// LogSender marks messages as sent and only logs that they were (development only). The body is never
+// logged: it carries signed links that work as credentials.
+type LogSender struct{}
+
+func (LogSender) Send(_ context.Context, n *domain.OutboundNotification) error {
+	log.Printf("notification [%s] to=%s subject=%q body=%d bytes (log only, not delivered)", n.Channel, n.Recipient, n.Subject, len(n.Body))
+	return nil
+}
+
+// WebhookSender POSTs the message as JSON to an HTTP gateway (e.g. an SMS or WhatsApp provider bridge).
+// Any non-2xx response is treated as a delivery failure.
+type WebhookSender struct {
+	URL    string
+	Token  string // optional bearer token
+	Client *http.Client
+}
+
+type webhookPayload struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject,omitempty"`
+	Message string `json:"message"`
+	ID      string `json:"id"`
+}
+
+func (w *WebhookSender) Send(ctx context.Context, n *domain.OutboundNotification) error {
+	body, err := json.Marshal(webhookPayload{Channel: n.Channel, To: n.Recipient, Subject: n.Subject, Message: n.Body, ID: n.ID.String()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: status %d", n.Channel, resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPSender delivers email channel messages through an SMTP relay (PLAIN auth when Username is set).
+type SMTPSender struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(_ context.Context, n *domain.OutboundNotification) error {
+	host := s.Addr
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	return smtp.SendMail(s.Addr, auth, s.From, []string{n.Recipient}, buildEmail(s.From, n))
+}
+
+func buildEmail(from string, n *domain.OutboundNotification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", n.Recipient)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", n.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	body := strings.ReplaceAll(n.Body, "\n", "\r\n") + "\r\n"
+	if len(n.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+		b.WriteString(body)
+		return b.Bytes()
+	}
+
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+	part, _ := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	_, _ = part.Write([]byte(body))
+	for _, a := range n.Attachments {
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {ct},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		writeBase64Lines(part, a.Content)
+	}
+	_ = mw.Close()
+	return b.Bytes()
+}
+
+// writeBase64Lines writes base64 wrapped at 76 columns (RFC 2045).
+func writeBase64Lines(w io.Writer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		_, _ = io.WriteString(w, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	_, _ = io.WriteString(w, enc+"\r\n")
+}
+
+var (
+	_ external.NotificationSender = LogSender{}
+	_ external.NotificationSender = UnconfiguredSender{}
+	_ external.NotificationSender = (*WebhookSender)(nil)
+	_ external.NotificationSender = (*SMTPSender)(nil)
+)
@@ -0,0 +1,177 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrRepairLinesNotConfigured is returned by the line endpoints when the service has no line repository.
+var ErrRepairLinesNotConfigured = errors.New("repair lines not configured")
+
+// WithRepairLineRepository enables labour and parts lines; a repair with lines costs their sum.
+func WithRepairLineRepository(repo ports.RepairLineRepository) Option {
+	return func(uc *RepairService) { uc.lineRepo = repo }
+}
+
+// WithEmployeeRepository lets labour lines take the employee's hourly rate when no price is given.
+func WithEmployeeRepository(repo ports.EmployeeRepository) Option {
+	return func(uc *RepairService) { uc.employeeRepo = repo }
+}
+
+// WithPartItemRepository lets part lines default their description to the inventory item's name.
+func WithPartItemRepository(repo ports.PartItemRepository) Option {
+	return func(uc *RepairService) { uc.partRepo = repo }
+}
+
+// LineInput is a new repair line. Labour: Quantity hours at UnitPrice, or at EmployeeID's hourly rate when
+// UnitPrice is nil. Part: Quantity units of PartItemID at UnitPrice, taken from stock.
+type LineInput struct {
+	Kind        domain.RepairLineKind
+	Description string
+	Quantity    float64
+	UnitPrice   *float64
+	EmployeeID  *uuid.UUID
+	PartItemID  *uuid.UUID
+}
+
+// AddLine adds a line to an open repair and returns the repair with its lines and recomputed cost. Staff only.
+func (uc *RepairService) AddLine(ctx context.Context, repairID uuid.UUID, in LineInput, userID uuid.UUID) (*domain.Repair, error) {
+	if uc.lineRepo == nil {
+		return nil, ErrRepairLinesNotConfigured
+	}
+	repair, err := uc.editableRepair(ctx, repairID, userID)
+	if err != nil {
+		return nil, err
+	}
+	line, err := uc.newLine(ctx, repair.ID, in)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.lineRepo.Add(ctx, line); err != nil {
+		return nil, err
+	}
+	uc.publishVisitChange(repair.ServiceJobID)
+	return uc.withLines(ctx, repair.ID)
+}
+
+// RemoveLine deletes a line of an open repair (a part line's quantity goes back to stock). Staff only.
+func (uc *RepairService) RemoveLine(ctx context.Context, repairID, lineID uuid.UUID, userID uuid.UUID) (*domain.Repair, error) {
+	if uc.lineRepo == nil {
+		return nil, ErrRepairLinesNotConfigured
+	}
+	repair, err := uc.editableRepair(ctx, repairID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.lineRepo.Remove(ctx, repair.ID, lineID); err != nil {
+		return nil, err
+	}
+	uc.publishVisitChange(repair.ServiceJobID)
+	return uc.withLines(ctx, repair.ID)
+}
+
+// editableRepair loads a repair that is neither completed nor cancelled for a staff caller.
+func (uc *RepairService) editableRepair(ctx context.Context, repairID uuid.UUID, userID uuid.UUID) (*domain.Repair, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	repair, err := uc.repairRepo.GetByID(ctx, repairID)
+	if err != nil {
+		return nil, err
+	}
+	if repair.Status == domain.RepairStatusCompleted || repair.Status == domain.RepairStatusCancelled {
+		return nil, domain.ErrRepairNotEditable
+	}
+	return repair, nil
+}
+
+// newLine builds and prices a line from the input, ready to store.
+func (uc *RepairService) newLine(ctx context.Context, repairID uuid.UUID, in LineInput) (*domain.RepairLine, error) {
+	line := &domain.RepairLine{
+		ID:          uuid.New(),
+		RepairID:    repairID,
+		Kind:        in.Kind,
+		Description: strings.TrimSpace(in.Description),
+		Quantity:    in.Quantity,
+		EmployeeID:  in.EmployeeID,
+		PartItemID:  in.PartItemID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := uc.priceLine(ctx, line, in); err != nil {
+		return nil, err
+	}
+	if err := line.Validate(); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (uc *RepairService) priceLine(ctx context.Context, line *domain.RepairLine, in LineInput) error {
+	if in.UnitPrice != nil {
+		line.UnitPrice = *in.UnitPrice
+	}
+	switch line.Kind {
+	case domain.RepairLineLabour:
+		if in.UnitPrice != nil {
+			return nil
+		}
+		if in.EmployeeID == nil || uc.employeeRepo == nil {
+			return domain.ErrInvalidRepairLine
+		}
+		emp, err := uc.employeeRepo.FindByID(ctx, *in.EmployeeID)
+		if err != nil || emp == nil {
+			return domain.ErrInvalidRepairLine
+		}
+		line.UnitPrice = emp.HourlyRate
+		if line.Description == "" {
+			line.Description = "Mano de obra"
+		}
+	case domain.RepairLinePart:
+		if in.UnitPrice == nil || in.PartItemID == nil {
+			return domain.ErrInvalidRepairLine
+		}
+		if line.Description == "" && uc.partRepo != nil {
+			p, err := uc.partRepo.GetByID(ctx, *in.PartItemID)
+			if err != nil {
+				return err
+			}
+			line.Description = strings.TrimSpace(p.Brand + " " + p.Name)
+		}
+	}
+	return nil
+}
+
+// withLines loads the repair with its lines.
+func (uc *RepairService) withLines(ctx context.Context, repairID uuid.UUID) (*domain.Repair, error) {
+	repair, err := uc.repairRepo.GetByID(ctx, repairID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.attachLines(ctx, repair); err != nil {
+		return nil, err
+	}
+	return repair, nil
+}
+
+func (uc *RepairService) attachLines(ctx context.Context, repair *domain.Repair) error {
+	if uc.lineRepo == nil {
+		return nil
+	}
+	lines, err := uc.lineRepo.ListByRepair(ctx, repair.ID)
+	if err != nil {
+		return err
+	}
+	repair.Lines = lines
+	return nil
+}
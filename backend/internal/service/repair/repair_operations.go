@@ -0,0 +1,92 @@
+package repair
+
+import (
+	"context"
+	"log"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// WithLabourOperationRepository lets repairs take their lines from the labour-operation catalog.
+func WithLabourOperationRepository(repo ports.LabourOperationRepository) Option {
+	return func(uc *RepairService) { uc.opRepo = repo }
+}
+
+// OperationInput adds a catalog operation to a repair: its flat-rate hours become a labour line priced at
+// HourlyRate, or at EmployeeID's hourly rate, and its parts kit becomes parts lines unless WithoutParts.
+type OperationInput struct {
+	OperationID  uuid.UUID
+	EmployeeID   *uuid.UUID
+	HourlyRate   *float64
+	WithoutParts bool
+}
+
+// AddOperation adds the lines of a catalog operation to an open repair, all or none. The operation must apply
+// to the repair's car. Staff only.
+func (uc *RepairService) AddOperation(ctx context.Context, repairID uuid.UUID, in OperationInput, userID uuid.UUID) (*domain.Repair, error) {
+	if uc.lineRepo == nil || uc.opRepo == nil {
+		return nil, ErrRepairLinesNotConfigured
+	}
+	repair, err := uc.editableRepair(ctx, repairID, userID)
+	if err != nil {
+		return nil, err
+	}
+	op, err := uc.opRepo.GetByID(ctx, in.OperationID)
+	if err != nil {
+		return nil, err
+	}
+	car, err := uc.carRepo.GetByID(ctx, repair.CarID)
+	if err != nil {
+		return nil, err
+	}
+	if !op.AppliesTo(car.Make, car.Model) {
+		return nil, domain.ErrLabourOperationNotApplicable
+	}
+
+	inputs := []LineInput{{
+		Kind:        domain.RepairLineLabour,
+		Description: op.LineDescription(),
+		Quantity:    op.FlatRateHours,
+		UnitPrice:   in.HourlyRate,
+		EmployeeID:  in.EmployeeID,
+	}}
+	if !in.WithoutParts {
+		for _, p := range op.Parts {
+			partID, price := p.PartItemID, p.UnitPrice
+			inputs = append(inputs, LineInput{
+				Kind:        domain.RepairLinePart,
+				Description: p.Description,
+				Quantity:    p.Quantity,
+				UnitPrice:   &price,
+				PartItemID:  &partID,
+			})
+		}
+	}
+	lines := make([]*domain.RepairLine, 0, len(inputs))
+	for _, li := range inputs {
+		line, err := uc.newLine(ctx, repair.ID, li)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		if _, err := uc.lineRepo.Add(ctx, line); err != nil {
+			uc.removeLines(ctx, repair.ID, lines[:i])
+			return nil, err
+		}
+	}
+	uc.publishVisitChange(repair.ServiceJobID)
+	return uc.withLines(ctx, repair.ID)
+}
+
+// removeLines undoes lines already added when a later one fails (e.g. a kit part is out of stock).
+func (uc *RepairService) removeLines(ctx context.Context, repairID uuid.UUID, lines []*domain.RepairLine) {
+	for _, l := range lines {
+		if _, err := uc.lineRepo.Remove(ctx, repairID, l.ID); err != nil {
+			log.Printf("repair %s: undo line %s: %v", repairID, l.ID, err)
+		}
+	}
+}
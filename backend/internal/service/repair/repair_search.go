@@ -0,0 +1,141 @@
+package repair
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MaxRepairExportRows bounds a CSV export; wider searches must be narrowed first.
+const MaxRepairExportRows = 10000
+
+// ErrRepairExportTooLarge is returned when a CSV export would exceed MaxRepairExportRows.
+var ErrRepairExportTooLarge = fmt.Errorf("more than %d repairs match; narrow the filters", MaxRepairExportRows)
+
+// ListRepairs searches every repair in the workshop, newest first, and returns the page with the total
+// match count. Managers and admins only.
+func (uc *RepairService) ListRepairs(ctx context.Context, filters ports.RepairFilters, userID uuid.UUID) ([]*domain.Repair, int64, error) {
+	if err := uc.requireManager(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	if filters.Status != nil && !domain.ValidateRepairStatus(*filters.Status) {
+		return nil, 0, fmt.Errorf("invalid repair status")
+	}
+	return uc.repairRepo.List(ctx, &filters)
+}
+
+// ExportRepairsCSV renders every repair matching filters (Limit and Offset are ignored) as a CSV with the
+// car and lead technician spelled out. Managers and admins only.
+func (uc *RepairService) ExportRepairsCSV(ctx context.Context, filters ports.RepairFilters, userID uuid.UUID) ([]byte, error) {
+	if err := uc.requireManager(ctx, userID); err != nil {
+		return nil, err
+	}
+	if filters.Status != nil && !domain.ValidateRepairStatus(*filters.Status) {
+		return nil, fmt.Errorf("invalid repair status")
+	}
+
+	const page = 500
+	var repairs []*domain.Repair
+	for filters.Offset = 0; ; filters.Offset += page {
+		filters.Limit = page
+		rows, total, err := uc.repairRepo.List(ctx, &filters)
+		if err != nil {
+			return nil, err
+		}
+		if total > MaxRepairExportRows {
+			return nil, ErrRepairExportTooLarge
+		}
+		repairs = append(repairs, rows...)
+		if len(rows) < page {
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"id", "created_at", "status", "license_plate", "vehicle", "description", "lead_technician",
+		"service_job_id", "started_at", "completed_at", "cost", "rework_of_repair_id"})
+	cars := map[uuid.UUID]*domain.Car{}
+	names := map[uuid.UUID]string{}
+	for _, r := range repairs {
+		car, ok := cars[r.CarID]
+		if !ok {
+			c, err := uc.carRepo.GetByID(ctx, r.CarID)
+			if err != nil && !errors.Is(err, domain.ErrCarNotFound) {
+				return nil, err
+			}
+			car, cars[r.CarID] = c, c
+		}
+		name, ok := names[r.TechnicianID]
+		if !ok {
+			if u, err := uc.userRepo.GetByID(ctx, r.TechnicianID); err == nil && u != nil {
+				name = u.FullName()
+			}
+			names[r.TechnicianID] = name
+		}
+		var plate, vehicle, jobID, reworkOf string
+		if car != nil {
+			plate, vehicle = car.LicensePlate, car.Make+" "+car.Model
+		}
+		if r.ServiceJobID != nil {
+			jobID = r.ServiceJobID.String()
+		}
+		if r.ReworkOfRepairID != nil {
+			reworkOf = r.ReworkOfRepairID.String()
+		}
+		_ = w.Write([]string{
+			r.ID.String(),
+			csvTime(&r.CreatedAt),
+			string(r.Status),
+			csvText(plate),
+			csvText(vehicle),
+			csvText(r.Description),
+			csvText(name),
+			jobID,
+			csvTime(r.StartedAt),
+			csvTime(r.CompletedAt),
+			strconv.FormatFloat(r.Cost, 'f', 2, 64),
+			reworkOf,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (uc *RepairService) requireManager(ctx context.Context, userID uuid.UUID) error {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsManager() {
+		return domain.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// csvText neutralises free text that a spreadsheet would otherwise run as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -7,21 +7,47 @@ import (
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
 	"github.com/google/uuid"
 )
 
 type RepairService struct {
-	repairRepo ports.RepairRepository
-	carRepo    ports.CarRepository
-	userRepo   ports.UserRepository
+	repairRepo   ports.RepairRepository
+	carRepo      ports.CarRepository
+	userRepo     ports.UserRepository
+	estimateRepo ports.EstimateRepository         // optional: nil disables the estimate approval gate
+	boardHub     *pubsub.Hub                      // optional: nil disables live board updates
+	lineRepo     ports.RepairLineRepository       // optional: required for labour and parts lines
+	employeeRepo ports.EmployeeRepository         // optional: hourly rates for labour lines
+	partRepo     ports.PartItemRepository         // optional: default part line descriptions
+	techRepo     ports.RepairTechnicianRepository // optional: required for shared repairs
+	opRepo       ports.LabourOperationRepository  // optional: required for catalog operations
+	warrantyRepo ports.RepairWarrantyRepository   // optional: required for warranties and warranty rework
 }
 
-func NewRepairService(repairRepo ports.RepairRepository, carRepo ports.CarRepository, userRepo ports.UserRepository) *RepairService {
-	return &RepairService{
+// Option configures optional collaborators of RepairService.
+type Option func(*RepairService)
+
+// WithEstimateRepository requires an approved estimate line before a visit's repair starts.
+func WithEstimateRepository(repo ports.EstimateRepository) Option {
+	return func(uc *RepairService) { uc.estimateRepo = repo }
+}
+
+// WithBoardHub announces changes to a visit's repairs on hub (the workshop board reloads the visit).
+func WithBoardHub(hub *pubsub.Hub) Option {
+	return func(uc *RepairService) { uc.boardHub = hub }
+}
+
+func NewRepairService(repairRepo ports.RepairRepository, carRepo ports.CarRepository, userRepo ports.UserRepository, opts ...Option) *RepairService {
+	uc := &RepairService{
 		repairRepo: repairRepo,
 		carRepo:    carRepo,
 		userRepo:   userRepo,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 func (uc *RepairService) CreateRepair(ctx context.Context, repair *domain.Repair, userID uuid.UUID) (*domain.Repair, error) {
@@ -45,25 +71,46 @@ func (uc *RepairService) CreateRepair(ctx context.Context, repair *domain.Repair
 		return nil, fmt.Errorf("car not found")
 	}
 
-	// Check if repair already exists for the same car with same description and start date
+	// Check if the same repair is already waiting to start on this car
 	existingRepairs, err := uc.repairRepo.GetByCarID(ctx, repair.CarID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing repairs: %w", err)
 	}
 	for _, r := range existingRepairs {
-		if r.Description == repair.Description && r.StartedAt == repair.StartedAt {
-			return nil, fmt.Errorf("repair already exists for the same car with same description and start date")
+		if r.Description == repair.Description && r.Status == domain.RepairStatusPending {
+			return nil, fmt.Errorf("a pending repair with the same description already exists for this car")
 		}
 	}
 
 	// Staff creates repairs on the customer's car (car.OwnerID is the client, not the technician).
 
+	// Warranty rework must point at a completed repair of this car that is still under warranty.
+	if repair.ReworkOfRepairID != nil {
+		if err := uc.checkRework(ctx, repair, car); err != nil {
+			return nil, err
+		}
+	}
+
 	// Set metadata
 	repair.ID = uuid.New()
-	repair.TechnicianID = userID
 	repair.CreatedAt = time.Now()
 	repair.UpdatedAt = time.Now()
 
+	// The creator leads the repair unless the technicians are named.
+	techs := repair.Technicians
+	if len(techs) > 0 && uc.techRepo == nil {
+		return nil, ErrRepairTechniciansNotConfigured
+	}
+	if len(techs) == 0 {
+		techs = []domain.RepairTechnician{{UserID: userID, Role: domain.RepairTechnicianLead}}
+	}
+	repair.TechnicianID = domain.LeadTechnician(techs)
+	if uc.techRepo != nil {
+		if techs, err = uc.technicianRows(ctx, repair.ID, techs); err != nil {
+			return nil, err
+		}
+	}
+
 	if repair.Status == "" {
 		repair.Status = domain.RepairStatusPending
 	}
@@ -72,13 +119,35 @@ func (uc *RepairService) CreateRepair(ctx context.Context, repair *domain.Repair
 	if err := uc.validateRepair(repair); err != nil {
 		return nil, err
 	}
-
-	// Create repair
-	if err := uc.repairRepo.Create(ctx, repair); err != nil {
+	// A repair starts pending; asking for in_progress starts it right away through the workflow.
+	// Timestamps are the server's, never the client's.
+	start := repair.Status == domain.RepairStatusInProgress
+	if !start && repair.Status != domain.RepairStatusPending {
+		return nil, domain.ErrRepairTransitionNotAllowed
+	}
+	if start {
+		if err := uc.requireApprovedEstimate(ctx, repair); err != nil {
+			return nil, err
+		}
+	}
+	repair.Status = domain.RepairStatusPending
+	repair.StartedAt = nil
+	repair.CompletedAt = nil
+
+	// Create the repair together with its technicians, so a failure leaves neither behind.
+	if uc.techRepo != nil {
+		if err := uc.techRepo.CreateRepair(ctx, repair, techs); err != nil {
+			return nil, err
+		}
+		repair.Technicians = techs
+	} else if err := uc.repairRepo.Create(ctx, repair); err != nil {
 		return nil, fmt.Errorf("failed to create repair: %w", err)
 	}
-
-	return repair, nil
+	if !start {
+		uc.publishVisitChange(repair.ServiceJobID)
+		return repair, nil
+	}
+	return uc.transition(ctx, repair, domain.RepairStatusInProgress, "", userID)
 }
 
 func (uc *RepairService) GetRepair(ctx context.Context, repairID uuid.UUID, userID uuid.UUID) (*domain.Repair, error) {
@@ -103,6 +172,15 @@ func (uc *RepairService) GetRepair(ctx context.Context, repairID uuid.UUID, user
 			return nil, domain.ErrUnauthorizedAccess
 		}
 	}
+	if err := uc.attachLines(ctx, repair); err != nil {
+		return nil, err
+	}
+	if err := uc.attachTechnicians(ctx, repair); err != nil {
+		return nil, err
+	}
+	if err := uc.attachWarranties(ctx, repair); err != nil {
+		return nil, err
+	}
 
 	return repair, nil
 }
@@ -146,30 +224,62 @@ func (uc *RepairService) UpdateRepair(ctx context.Context, repair *domain.Repair
 		return nil, fmt.Errorf("failed to get existing repair: %w", err)
 	}
 
+	// Completed and cancelled repairs are frozen until a manager reopens them.
+	if existingRepair.Status == domain.RepairStatusCompleted || existingRepair.Status == domain.RepairStatusCancelled {
+		return nil, domain.ErrRepairNotEditable
+	}
+
 	// Validate repair data
 	if err := uc.validateRepair(repair); err != nil {
 		return nil, err
 	}
+	// A status change goes through the workflow after the other fields are saved; timestamps stay the server's.
+	to := repair.Status
+	if to != existingRepair.Status && !domain.CanTransitionRepair(existingRepair.Status, to) {
+		return nil, domain.ErrRepairTransitionNotAllowed
+	}
+	if to == domain.RepairStatusInProgress && existingRepair.Status != domain.RepairStatusInProgress {
+		if err := uc.requireApprovedEstimate(ctx, existingRepair); err != nil {
+			return nil, err
+		}
+	}
+	repair.Status = existingRepair.Status
+	repair.StartedAt = existingRepair.StartedAt
+	repair.CompletedAt = existingRepair.CompletedAt
+
+	// A repair with lines costs their sum; the typed-in cost only applies to repairs without lines.
+	if err := uc.attachLines(ctx, repair); err != nil {
+		return nil, err
+	}
+	if len(repair.Lines) > 0 {
+		repair.Cost = domain.RepairLinesTotal(repair.Lines)
+	}
 
 	// Update metadata
 	repair.UpdatedAt = time.Now()
 	repair.CreatedAt = existingRepair.CreatedAt // Preserve original creation time
 
-	// If marking as completed, set end date
-	if repair.Status == domain.RepairStatusCompleted && repair.CompletedAt == nil {
-		now := time.Now()
-		repair.CompletedAt = &now
-	}
-
 	// Update repair
 	if err := uc.repairRepo.Update(ctx, repair); err != nil {
 		return nil, fmt.Errorf("failed to update repair: %w", err)
 	}
+	uc.publishVisitChange(existingRepair.ServiceJobID)
+	if repair.ServiceJobID != nil && (existingRepair.ServiceJobID == nil || *repair.ServiceJobID != *existingRepair.ServiceJobID) {
+		uc.publishVisitChange(repair.ServiceJobID)
+	}
+	if to == repair.Status {
+		return repair, nil
+	}
 
-	return repair, nil
+	out, err := uc.transition(ctx, repair, to, "", userID)
+	if err != nil {
+		return nil, err
+	}
+	out.Lines = repair.Lines
+	return out, nil
 }
 
-// DeleteRepair soft-deletes a repair (staff only).
+// DeleteRepair soft-deletes a repair and puts its parts back in stock (staff only).
 func (uc *RepairService) DeleteRepair(ctx context.Context, repairID uuid.UUID, userID uuid.UUID) error {
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -178,10 +288,44 @@ func (uc *RepairService) DeleteRepair(ctx context.Context, repairID uuid.UUID, u
 	if !user.IsEmployee() {
 		return domain.ErrUnauthorizedAccess
 	}
-	if _, err := uc.repairRepo.GetByID(ctx, repairID); err != nil {
+	existing, err := uc.repairRepo.GetByID(ctx, repairID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repairRepo.Delete(ctx, repairID); err != nil {
 		return err
 	}
-	return uc.repairRepo.Delete(ctx, repairID)
+	uc.publishVisitChange(existing.ServiceJobID)
+	return nil
+}
+
+// publishVisitChange tells board viewers that the repairs of a visit changed. Repairs without a visit are ignored.
+func (uc *RepairService) publishVisitChange(serviceJobID *uuid.UUID) {
+	if serviceJobID == nil {
+		return
+	}
+	uc.boardHub.Publish(pubsub.Event{Type: domain.ServiceJobChangeEvent, Data: domain.ServiceJobChange{
+		ServiceJobID: *serviceJobID,
+		Kind:         domain.ServiceJobChangeRepairs,
+		At:           time.Now().UTC(),
+	}})
+}
+
+// requireApprovedEstimate blocks starting work on a visit's repair until the customer has approved
+// an estimate line for it. Repairs not tied to a visit keep the old behaviour, and warranty rework is
+// never billed so it needs no approval.
+func (uc *RepairService) requireApprovedEstimate(ctx context.Context, repair *domain.Repair) error {
+	if uc.estimateRepo == nil || repair.ServiceJobID == nil || repair.ReworkOfRepairID != nil {
+		return nil
+	}
+	ok, err := uc.estimateRepo.HasApprovedLine(ctx, repair.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrRepairEstimateNotApproved
+	}
+	return nil
 }
 
 func (uc *RepairService) validateRepair(repair *domain.Repair) error {
@@ -3,10 +3,14 @@ package repair
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +51,7 @@ type stubRepairRepo struct {
 	byID    map[uuid.UUID]*domain.Repair
 	byCar   map[uuid.UUID][]*domain.Repair
 	deleted []uuid.UUID
+	events  []*domain.RepairStatusEvent
 }
 
 func (s *stubRepairRepo) Create(ctx context.Context, repair *domain.Repair) error {
@@ -88,6 +93,58 @@ func (s *stubRepairRepo) ListIDsByServiceJobID(_ context.Context, _ uuid.UUID) (
 	return nil, nil
 }
 
+func (s *stubRepairRepo) ListByServiceJobIDs(context.Context, []uuid.UUID) ([]*domain.Repair, error) {
+	return []*domain.Repair{}, nil
+}
+
+func (s *stubRepairRepo) ChangeStatus(_ context.Context, r *domain.Repair, ev *domain.RepairStatusEvent) error {
+	if stored, ok := s.byID[r.ID]; ok && stored != r && stored.Status != ev.FromStatus {
+		return domain.ErrRepairStatusConflict
+	}
+	if s.byID == nil {
+		s.byID = make(map[uuid.UUID]*domain.Repair)
+	}
+	s.byID[r.ID] = r
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *stubRepairRepo) ListStatusEvents(_ context.Context, repairID uuid.UUID) ([]*domain.RepairStatusEvent, error) {
+	out := []*domain.RepairStatusEvent{}
+	for _, ev := range s.events {
+		if ev.RepairID == repairID {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubRepairRepo) ListByTechnician(_ context.Context, userID uuid.UUID, status domain.RepairStatus, _, _ int) ([]*domain.Repair, error) {
+	out := []*domain.Repair{}
+	for _, r := range s.byID {
+		if r.TechnicianID == userID && (status == "" || r.Status == status) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (s *stubRepairRepo) List(_ context.Context, f *ports.RepairFilters) ([]*domain.Repair, int64, error) {
+	var match []*domain.Repair
+	for _, r := range s.byID {
+		if (f.Status == nil || r.Status == *f.Status) &&
+			(f.Search == "" || strings.Contains(strings.ToLower(r.Description), strings.ToLower(f.Search))) &&
+			(f.Rework == nil || *f.Rework == (r.ReworkOfRepairID != nil)) {
+			match = append(match, r)
+		}
+	}
+	sort.Slice(match, func(i, j int) bool { return match[i].CreatedAt.After(match[j].CreatedAt) })
+	page := []*domain.Repair{}
+	for i := f.Offset; i < len(match) && len(page) < f.Limit; i++ {
+		page = append(page, match[i])
+	}
+	return page, int64(len(match)), nil
+}
+
 type repairStubCarRepo struct {
 	byID map[uuid.UUID]*domain.Car
 }
@@ -311,3 +368,589 @@ func TestRepairService_DeleteRepair_EmployeeOK(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, repairRepo.deleted, repairID)
 }
+
+type stubEstimateRepo struct {
+	approved map[uuid.UUID]bool
+}
+
+func (s *stubEstimateRepo) Create(context.Context, *domain.Estimate) error { return nil }
+func (s *stubEstimateRepo) GetByID(context.Context, uuid.UUID) (*domain.Estimate, error) {
+	return nil, domain.ErrEstimateNotFound
+}
+func (s *stubEstimateRepo) ListByServiceJob(context.Context, uuid.UUID) ([]*domain.Estimate, error) {
+	return nil, nil
+}
+func (s *stubEstimateRepo) Save(context.Context, *domain.Estimate, domain.EstimateStatus) error {
+	return nil
+}
+func (s *stubEstimateRepo) HasApprovedLine(_ context.Context, repairID uuid.UUID) (bool, error) {
+	return s.approved[repairID], nil
+}
+
+func TestRepairService_UpdateRepair_InProgressNeedsApprovedEstimate(t *testing.T) {
+	t.Parallel()
+	empID := uuid.New()
+	emp, err := domain.NewUser("e@example.com", "pw", "E", "L", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = empID
+	jobID := uuid.New()
+	visitRepair := &domain.Repair{ID: uuid.New(), CarID: uuid.New(), ServiceJobID: &jobID, Description: "Frenos", Status: domain.RepairStatusPending}
+	walkIn := &domain.Repair{ID: uuid.New(), CarID: uuid.New(), Description: "Lámpara", Status: domain.RepairStatusPending}
+	estimates := &stubEstimateRepo{approved: map[uuid.UUID]bool{}}
+	svc := NewRepairService(
+		&stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{visitRepair.ID: visitRepair, walkIn.ID: walkIn}},
+		&repairStubCarRepo{},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{empID: emp}},
+		WithEstimateRepository(estimates),
+	)
+	ctx := context.Background()
+
+	start := *visitRepair
+	start.Status = domain.RepairStatusInProgress
+	_, err = svc.UpdateRepair(ctx, &start, empID)
+	assert.ErrorIs(t, err, domain.ErrRepairEstimateNotApproved)
+
+	// Other edits are not gated.
+	edit := *visitRepair
+	edit.Description = "Frenos delanteros"
+	_, err = svc.UpdateRepair(ctx, &edit, empID)
+	require.NoError(t, err)
+
+	estimates.approved[visitRepair.ID] = true
+	_, err = svc.UpdateRepair(ctx, &start, empID)
+	require.NoError(t, err)
+
+	// Repairs outside a visit keep working as before.
+	startWalkIn := *walkIn
+	startWalkIn.Status = domain.RepairStatusInProgress
+	_, err = svc.UpdateRepair(ctx, &startWalkIn, empID)
+	require.NoError(t, err)
+}
+
+func TestRepairService_PublishesVisitRepairChanges(t *testing.T) {
+	t.Parallel()
+	empID := uuid.New()
+	emp, err := domain.NewUser("e@example.com", "pw", "E", "L", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = empID
+	jobID := uuid.New()
+	visitRepair := &domain.Repair{ID: uuid.New(), CarID: uuid.New(), ServiceJobID: &jobID, Description: "Frenos", Status: domain.RepairStatusPending}
+	walkIn := &domain.Repair{ID: uuid.New(), CarID: uuid.New(), Description: "Lámpara", Status: domain.RepairStatusPending}
+	hub := pubsub.New()
+	events, cancel := hub.Subscribe(8)
+	defer cancel()
+	svc := NewRepairService(
+		&stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{visitRepair.ID: visitRepair, walkIn.ID: walkIn}},
+		&repairStubCarRepo{},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{empID: emp}},
+		WithBoardHub(hub),
+	)
+	ctx := context.Background()
+
+	require.NoError(t, svc.DeleteRepair(ctx, walkIn.ID, empID))
+	assert.Empty(t, events, "repairs outside a visit are not on the board")
+
+	require.NoError(t, svc.DeleteRepair(ctx, visitRepair.ID, empID))
+	require.Len(t, events, 1)
+	ev := <-events
+	assert.Equal(t, domain.ServiceJobChangeEvent, ev.Type)
+	assert.Equal(t, domain.ServiceJobChange{ServiceJobID: jobID, Kind: domain.ServiceJobChangeRepairs, At: ev.Data.(domain.ServiceJobChange).At}, ev.Data)
+}
+
+type memLineRepo struct {
+	lines []domain.RepairLine
+	stock map[uuid.UUID]float64
+}
+
+func (m *memLineRepo) Add(_ context.Context, l *domain.RepairLine) (float64, error) {
+	if l.PartItemID != nil {
+		if m.stock[*l.PartItemID] < l.Quantity {
+			return 0, domain.ErrInsufficientStock
+		}
+		m.stock[*l.PartItemID] -= l.Quantity
+	}
+	m.lines = append(m.lines, *l)
+	return domain.RepairLinesTotal(m.lines), nil
+}
+
+func (m *memLineRepo) Remove(_ context.Context, _, lineID uuid.UUID) (float64, error) {
+	for i, l := range m.lines {
+		if l.ID == lineID {
+			if l.PartItemID != nil {
+				m.stock[*l.PartItemID] += l.Quantity
+			}
+			m.lines = append(m.lines[:i], m.lines[i+1:]...)
+			return domain.RepairLinesTotal(m.lines), nil
+		}
+	}
+	return 0, domain.ErrRepairLineNotFound
+}
+
+func (m *memLineRepo) ListByRepair(_ context.Context, repairID uuid.UUID) ([]domain.RepairLine, error) {
+	out := []domain.RepairLine{}
+	for _, l := range m.lines {
+		if l.RepairID == repairID {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+type stubEmployeeRepo struct {
+	byID map[uuid.UUID]*domain.Employee
+}
+
+func (s *stubEmployeeRepo) Create(context.Context, *domain.Employee) error { return nil }
+func (s *stubEmployeeRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.Employee, error) {
+	if e, ok := s.byID[id]; ok {
+		return e, nil
+	}
+	return nil, domain.ErrEmployeeNotFound
+}
+func (s *stubEmployeeRepo) Update(context.Context, *domain.Employee) error { return nil }
+func (s *stubEmployeeRepo) Delete(context.Context, uuid.UUID) error        { return nil }
+func (s *stubEmployeeRepo) List(context.Context, *ports.EmployeeFilters) ([]*domain.Employee, int64, error) {
+	return nil, 0, nil
+}
+
+func TestRepairService_Lines_PricedAndCostDerived(t *testing.T) {
+	t.Parallel()
+	empID := uuid.New()
+	emp, err := domain.NewUser("e@example.com", "pw", "E", "L", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = empID
+	client, err := domain.NewUser("c@example.com", "pw", "C", "L", domain.RoleClient)
+	require.NoError(t, err)
+	client.ID = uuid.New()
+	tech := &domain.Employee{ID: uuid.New(), UserID: empID, HourlyRate: 42}
+	partID := uuid.New()
+	r := &domain.Repair{ID: uuid.New(), CarID: uuid.New(), Description: "Frenos", Status: domain.RepairStatusInProgress, Cost: 500}
+	lines := &memLineRepo{stock: map[uuid.UUID]float64{partID: 2}}
+	svc := NewRepairService(
+		&stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{r.ID: r}},
+		&repairStubCarRepo{},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{empID: emp, client.ID: client}},
+	)
+	ctx := context.Background()
+	_, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLineLabour, Quantity: 1}, empID)
+	assert.ErrorIs(t, err, ErrRepairLinesNotConfigured)
+	WithRepairLineRepository(lines)(svc)
+	WithEmployeeRepository(&stubEmployeeRepo{byID: map[uuid.UUID]*domain.Employee{tech.ID: tech}})(svc)
+
+	_, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLineLabour, Quantity: 1, EmployeeID: &tech.ID}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLineLabour, Quantity: 1}, empID)
+	assert.ErrorIs(t, err, domain.ErrInvalidRepairLine, "labour needs a price or an employee rate")
+
+	got, err := svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLineLabour, Quantity: 1.5, EmployeeID: &tech.ID}, empID)
+	require.NoError(t, err)
+	require.Len(t, got.Lines, 1)
+	assert.Equal(t, 42.0, got.Lines[0].UnitPrice)
+	assert.Equal(t, "Mano de obra", got.Lines[0].Description)
+
+	price := 30.0
+	_, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLinePart, Description: "Pastillas", Quantity: 3, UnitPrice: &price, PartItemID: &partID}, empID)
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	got, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLinePart, Description: "Pastillas", Quantity: 2, UnitPrice: &price, PartItemID: &partID}, empID)
+	require.NoError(t, err)
+	require.Len(t, got.Lines, 2)
+	assert.Zero(t, lines.stock[partID])
+
+	// A typed-in cost no longer wins once the repair has lines.
+	edit := *r
+	edit.Cost = 1
+	updated, err := svc.UpdateRepair(ctx, &edit, empID)
+	require.NoError(t, err)
+	assert.InDelta(t, 123.0, updated.Cost, 0.001)
+
+	got, err = svc.RemoveLine(ctx, r.ID, got.Lines[1].ID, empID)
+	require.NoError(t, err)
+	assert.Len(t, got.Lines, 1)
+	assert.Equal(t, 2.0, lines.stock[partID])
+
+	r.Status = domain.RepairStatusCompleted
+	_, err = svc.AddLine(ctx, r.ID, LineInput{Kind: domain.RepairLineLabour, Quantity: 1, EmployeeID: &tech.ID}, empID)
+	assert.ErrorIs(t, err, domain.ErrRepairNotEditable)
+}
+
+func TestRepairService_StatusWorkflow(t *testing.T) {
+	t.Parallel()
+	emp, err := domain.NewUser("e@example.com", "pw", "E", "L", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = uuid.New()
+	mgr, err := domain.NewUser("m@example.com", "pw", "M", "L", domain.RoleManager)
+	require.NoError(t, err)
+	mgr.ID = uuid.New()
+	carID := uuid.New()
+	repairs := &stubRepairRepo{byCar: map[uuid.UUID][]*domain.Repair{}}
+	svc := NewRepairService(
+		repairs,
+		&repairStubCarRepo{byID: map[uuid.UUID]*domain.Car{carID: {ID: carID, OwnerID: uuid.New()}}},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{emp.ID: emp, mgr.ID: mgr}},
+	)
+	ctx := context.Background()
+
+	_, err = svc.CreateRepair(ctx, &domain.Repair{CarID: carID, Description: "Embrague", Status: domain.RepairStatusCompleted}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrRepairTransitionNotAllowed)
+
+	past := time.Now().Add(-72 * time.Hour)
+	r, err := svc.CreateRepair(ctx, &domain.Repair{CarID: carID, Description: "Embrague", Status: domain.RepairStatusInProgress, StartedAt: &past}, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RepairStatusInProgress, r.Status)
+	require.NotNil(t, r.StartedAt)
+	assert.WithinDuration(t, time.Now(), *r.StartedAt, time.Minute, "client timestamps are ignored")
+	startedAt := *r.StartedAt
+
+	_, err = svc.TransitionRepair(ctx, r.ID, domain.RepairStatusPending, "", emp.ID)
+	assert.ErrorIs(t, err, domain.ErrRepairTransitionNotAllowed)
+
+	done, err := svc.TransitionRepair(ctx, r.ID, domain.RepairStatusCompleted, "", emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, done.CompletedAt)
+
+	edit := *done
+	edit.Description = "Embrague y volante"
+	_, err = svc.UpdateRepair(ctx, &edit, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrRepairNotEditable)
+
+	_, err = svc.TransitionRepair(ctx, r.ID, domain.RepairStatusInProgress, "vuelve a patinar", emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess, "only managers reopen")
+	_, err = svc.TransitionRepair(ctx, r.ID, domain.RepairStatusInProgress, " ", mgr.ID)
+	assert.ErrorIs(t, err, domain.ErrRepairReasonRequired)
+	reopened, err := svc.TransitionRepair(ctx, r.ID, domain.RepairStatusInProgress, "vuelve a patinar", mgr.ID)
+	require.NoError(t, err)
+	assert.Nil(t, reopened.CompletedAt)
+	assert.Equal(t, startedAt, *reopened.StartedAt)
+
+	edit = *reopened
+	edit.Description = "Embrague y volante"
+	edit.Status = domain.RepairStatusCancelled
+	cancelled, err := svc.UpdateRepair(ctx, &edit, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RepairStatusCancelled, cancelled.Status)
+	assert.Equal(t, "Embrague y volante", cancelled.Description)
+
+	history, err := svc.ListRepairStatusHistory(ctx, r.ID, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Equal(t, domain.RepairStatusPending, history[0].FromStatus)
+	assert.Equal(t, "vuelve a patinar", history[2].Reason)
+	assert.Equal(t, mgr.ID, history[2].ChangedByUserID)
+	assert.Equal(t, domain.RepairStatusCancelled, history[3].ToStatus)
+}
+
+type memTechRepo struct {
+	byRepair map[uuid.UUID][]domain.RepairTechnician
+	repairs  *stubRepairRepo
+}
+
+func (m *memTechRepo) CreateRepair(ctx context.Context, repair *domain.Repair, ts []domain.RepairTechnician) error {
+	if m.repairs != nil {
+		if err := m.repairs.Create(ctx, repair); err != nil {
+			return err
+		}
+	}
+	m.byRepair[repair.ID] = ts
+	return nil
+}
+
+func (m *memTechRepo) Replace(_ context.Context, repairID uuid.UUID, ts []domain.RepairTechnician) error {
+	m.byRepair[repairID] = ts
+	return nil
+}
+
+func (m *memTechRepo) ListByRepair(_ context.Context, repairID uuid.UUID) ([]domain.RepairTechnician, error) {
+	return m.byRepair[repairID], nil
+}
+
+func TestRepairService_SharedTechnicians(t *testing.T) {
+	t.Parallel()
+	users := map[uuid.UUID]*domain.User{}
+	newUser := func(email string, role string) *domain.User {
+		u, err := domain.NewUser(email, "pw", "U", "L", role)
+		require.NoError(t, err)
+		u.ID = uuid.New()
+		users[u.ID] = u
+		return u
+	}
+	emp := newUser("e@example.com", domain.RoleEmployee)
+	apprentice := newUser("a@example.com", domain.RoleEmployee)
+	client := newUser("c@example.com", domain.RoleClient)
+	carID := uuid.New()
+	repairs := &stubRepairRepo{byCar: map[uuid.UUID][]*domain.Repair{}}
+	techs := &memTechRepo{byRepair: map[uuid.UUID][]domain.RepairTechnician{}, repairs: repairs}
+	svc := NewRepairService(repairs,
+		&repairStubCarRepo{byID: map[uuid.UUID]*domain.Car{carID: {ID: carID, OwnerID: client.ID}}},
+		&repairTestUserRepo{users: users},
+	)
+	ctx := context.Background()
+
+	_, err := svc.CreateRepair(ctx, &domain.Repair{CarID: carID, Description: "Embrague", Technicians: []domain.RepairTechnician{{UserID: apprentice.ID, Role: domain.RepairTechnicianLead}}}, emp.ID)
+	assert.ErrorIs(t, err, ErrRepairTechniciansNotConfigured)
+	WithRepairTechnicianRepository(techs)(svc)
+
+	r, err := svc.CreateRepair(ctx, &domain.Repair{CarID: carID, Description: "Embrague"}, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, emp.ID, r.TechnicianID)
+	require.Len(t, r.Technicians, 1)
+	assert.Equal(t, domain.RepairTechnicianLead, r.Technicians[0].Role)
+	repairs.byID = map[uuid.UUID]*domain.Repair{r.ID: r}
+
+	_, err = svc.AssignTechnicians(ctx, r.ID, []domain.RepairTechnician{
+		{UserID: emp.ID, Role: domain.RepairTechnicianLead, Hours: 3},
+		{UserID: client.ID, Role: domain.RepairTechnicianAssistant},
+	}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidRepairTechnicians, "clients cannot be technicians")
+	_, err = svc.AssignTechnicians(ctx, r.ID, []domain.RepairTechnician{
+		{UserID: emp.ID, Role: domain.RepairTechnicianAssistant},
+		{UserID: apprentice.ID, Role: domain.RepairTechnicianAssistant},
+	}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidRepairTechnicians, "a lead is required")
+
+	got, err := svc.AssignTechnicians(ctx, r.ID, []domain.RepairTechnician{
+		{UserID: emp.ID, Role: domain.RepairTechnicianLead, Hours: 3},
+		{UserID: apprentice.ID, Role: domain.RepairTechnicianAssistant, Hours: 1.5},
+	}, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Technicians, 2)
+	assert.Equal(t, 1.5, got.Technicians[1].Hours)
+
+	mine, err := svc.ListMyRepairs(ctx, emp.ID, domain.RepairStatusPending, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	assert.Len(t, mine[0].Technicians, 2)
+	_, err = svc.ListMyRepairs(ctx, client.ID, "", 0, 0)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+type memOpRepo struct {
+	byID map[uuid.UUID]*domain.LabourOperation
+}
+
+func (m *memOpRepo) Create(context.Context, *domain.LabourOperation) error { return nil }
+func (m *memOpRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.LabourOperation, error) {
+	if op, ok := m.byID[id]; ok {
+		return op, nil
+	}
+	return nil, domain.ErrLabourOperationNotFound
+}
+func (m *memOpRepo) GetByCode(context.Context, string) (*domain.LabourOperation, error) {
+	return nil, domain.ErrLabourOperationNotFound
+}
+func (m *memOpRepo) Update(context.Context, *domain.LabourOperation) error { return nil }
+func (m *memOpRepo) Delete(context.Context, uuid.UUID) error               { return nil }
+func (m *memOpRepo) List(context.Context, ports.LabourOperationListFilters) ([]*domain.LabourOperation, int64, error) {
+	return nil, 0, nil
+}
+
+func TestRepairService_AddOperation_FlatRateAndKit(t *testing.T) {
+	t.Parallel()
+	emp, err := domain.NewUser("e@example.com", "pw", "E", "L", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = uuid.New()
+	car := &domain.Car{ID: uuid.New(), Make: "Seat", Model: "Ibiza"}
+	r := &domain.Repair{ID: uuid.New(), CarID: car.ID, Description: "Revisión", Status: domain.RepairStatusInProgress}
+	filterID, oilID := uuid.New(), uuid.New()
+	op := &domain.LabourOperation{ID: uuid.New(), Code: "ACE-01", Description: "Cambio de aceite", FlatRateHours: 0.5,
+		Vehicles: []domain.LabourOperationVehicle{{Make: "Seat"}},
+		Parts: []domain.LabourOperationPart{
+			{PartItemID: filterID, Description: "Filtro", Quantity: 1, UnitPrice: 10},
+			{PartItemID: oilID, Description: "Aceite", Quantity: 4, UnitPrice: 8},
+		}}
+	clio := &domain.LabourOperation{ID: uuid.New(), Code: "EMB-01", Description: "Embrague", FlatRateHours: 4,
+		Vehicles: []domain.LabourOperationVehicle{{Make: "Renault", Model: "Clio"}}}
+	lines := &memLineRepo{stock: map[uuid.UUID]float64{filterID: 1, oilID: 2}}
+	svc := NewRepairService(
+		&stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{r.ID: r}},
+		&repairStubCarRepo{byID: map[uuid.UUID]*domain.Car{car.ID: car}},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{emp.ID: emp}},
+		WithRepairLineRepository(lines),
+		WithLabourOperationRepository(&memOpRepo{byID: map[uuid.UUID]*domain.LabourOperation{op.ID: op, clio.ID: clio}}),
+	)
+	ctx := context.Background()
+	rate := 40.0
+
+	_, err = svc.AddOperation(ctx, r.ID, OperationInput{OperationID: clio.ID, HourlyRate: &rate}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrLabourOperationNotApplicable)
+
+	// Not enough oil: the labour and filter lines already added are undone.
+	_, err = svc.AddOperation(ctx, r.ID, OperationInput{OperationID: op.ID, HourlyRate: &rate}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Empty(t, lines.lines)
+	assert.Equal(t, 1.0, lines.stock[filterID])
+
+	lines.stock[oilID] = 4
+	got, err := svc.AddOperation(ctx, r.ID, OperationInput{OperationID: op.ID, HourlyRate: &rate}, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Lines, 3)
+	assert.Equal(t, "ACE-01 · Cambio de aceite", got.Lines[0].Description)
+	assert.Equal(t, 0.5, got.Lines[0].Quantity)
+	assert.InDelta(t, 20+10+32, domain.RepairLinesTotal(got.Lines), 0.001)
+
+	got, err = svc.AddOperation(ctx, r.ID, OperationInput{OperationID: op.ID, HourlyRate: &rate, WithoutParts: true}, emp.ID)
+	require.NoError(t, err)
+	assert.Len(t, got.Lines, 4)
+}
+
+func TestRepairService_ListAndExportRepairs_ManagersOnly(t *testing.T) {
+	t.Parallel()
+	mgr, err := domain.NewUser("m@example.com", "pw", "Marta", "Gil", domain.RoleManager)
+	require.NoError(t, err)
+	mgr.ID = uuid.New()
+	emp, err := domain.NewUser("e@example.com", "pw", "Luis", "Pardo", domain.RoleEmployee)
+	require.NoError(t, err)
+	emp.ID = uuid.New()
+	car := &domain.Car{ID: uuid.New(), Make: "Seat", Model: "Ibiza", LicensePlate: "1234ABC"}
+	started := time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)
+	a := &domain.Repair{ID: uuid.New(), CarID: car.ID, TechnicianID: emp.ID, Description: "Frenos, delanteros", Status: domain.RepairStatusInProgress,
+		Cost: 120.5, StartedAt: &started, CreatedAt: started}
+	b := &domain.Repair{ID: uuid.New(), CarID: car.ID, TechnicianID: emp.ID, Description: "=HYPERLINK(\"http://x\")", Status: domain.RepairStatusPending,
+		CreatedAt: started.Add(time.Hour)}
+	svc := NewRepairService(
+		&stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{a.ID: a, b.ID: b}},
+		&repairStubCarRepo{byID: map[uuid.UUID]*domain.Car{car.ID: car}},
+		&repairTestUserRepo{users: map[uuid.UUID]*domain.User{mgr.ID: mgr, emp.ID: emp}},
+	)
+	ctx := context.Background()
+
+	_, _, err = svc.ListRepairs(ctx, ports.RepairFilters{Limit: 10}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = svc.ExportRepairsCSV(ctx, ports.RepairFilters{}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	list, total, err := svc.ListRepairs(ctx, ports.RepairFilters{Limit: 1}, mgr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, b.ID, list[0].ID)
+
+	inProgress := domain.RepairStatusInProgress
+	data, err := svc.ExportRepairsCSV(ctx, ports.RepairFilters{Status: &inProgress, Limit: 1, Offset: 5}, mgr.ID)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2, "header and the one in-progress repair; paging is ignored")
+	assert.True(t, strings.HasPrefix(lines[0], "id,created_at,status,license_plate"))
+	assert.Equal(t, a.ID.String()+",2026-03-02T08:30:00Z,in_progress,1234ABC,Seat Ibiza,\"Frenos, delanteros\",Luis Pardo,,2026-03-02T08:30:00Z,,120.50,", lines[1])
+
+	// Free text that starts like a formula is exported as plain text.
+	pending := domain.RepairStatusPending
+	data, err = svc.ExportRepairsCSV(ctx, ports.RepairFilters{Status: &pending}, mgr.ID)
+	require.NoError(t, err)
+	lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `,"'=HYPERLINK(""http://x"")",`)
+}
+
+type memWarrantyRepo struct {
+	byRepair map[uuid.UUID][]domain.RepairWarranty
+	repairs  *stubRepairRepo
+}
+
+func (m *memWarrantyRepo) Replace(_ context.Context, repairID uuid.UUID, ws []domain.RepairWarranty) error {
+	m.byRepair[repairID] = ws
+	return nil
+}
+
+func (m *memWarrantyRepo) ListByRepair(_ context.Context, repairID uuid.UUID) ([]domain.RepairWarranty, error) {
+	return m.byRepair[repairID], nil
+}
+
+func (m *memWarrantyRepo) ListByCar(_ context.Context, carID uuid.UUID) ([]domain.RepairWarranty, error) {
+	out := []domain.RepairWarranty{}
+	for id, ws := range m.byRepair {
+		if r, ok := m.repairs.byID[id]; ok && r.CarID == carID {
+			out = append(out, ws...)
+		}
+	}
+	return out, nil
+}
+
+func TestRepairService_WarrantiesAndRework(t *testing.T) {
+	t.Parallel()
+	users := map[uuid.UUID]*domain.User{}
+	newUser := func(email, first, role string) *domain.User {
+		u, err := domain.NewUser(email, "pw", first, "Pardo", role)
+		require.NoError(t, err)
+		u.ID = uuid.New()
+		users[u.ID] = u
+		return u
+	}
+	mgr := newUser("m@example.com", "Marta", domain.RoleManager)
+	emp := newUser("e@example.com", "Luis", domain.RoleEmployee)
+	client := newUser("c@example.com", "Ana", domain.RoleClient)
+	car := &domain.Car{ID: uuid.New(), OwnerID: client.ID, Mileage: 61000}
+	other := &domain.Car{ID: uuid.New(), OwnerID: client.ID}
+	completed := time.Now().UTC().AddDate(0, -2, 0)
+	original := &domain.Repair{ID: uuid.New(), CarID: car.ID, TechnicianID: emp.ID, Description: "Embrague",
+		Status: domain.RepairStatusCompleted, CompletedAt: &completed, CreatedAt: completed}
+	pending := &domain.Repair{ID: uuid.New(), CarID: car.ID, TechnicianID: emp.ID, Description: "Luces",
+		Status: domain.RepairStatusPending, CreatedAt: completed}
+	kit := domain.RepairLine{ID: uuid.New(), RepairID: original.ID, Kind: domain.RepairLinePart, Description: "Kit embrague", Quantity: 1}
+	labour := domain.RepairLine{ID: uuid.New(), RepairID: original.ID, Kind: domain.RepairLineLabour, Description: "Mano de obra", Quantity: 4}
+	repairs := &stubRepairRepo{byID: map[uuid.UUID]*domain.Repair{original.ID: original, pending.ID: pending}}
+	warranties := &memWarrantyRepo{byRepair: map[uuid.UUID][]domain.RepairWarranty{}, repairs: repairs}
+	svc := NewRepairService(repairs,
+		&repairStubCarRepo{byID: map[uuid.UUID]*domain.Car{car.ID: car, other.ID: other}},
+		&repairTestUserRepo{users: users},
+		WithRepairLineRepository(&memLineRepo{lines: []domain.RepairLine{kit, labour}}),
+	)
+	ctx := context.Background()
+
+	_, err := svc.SetWarranties(ctx, original.ID, WarrantyInput{Months: 12}, emp.ID)
+	assert.ErrorIs(t, err, ErrRepairWarrantiesNotConfigured)
+	WithRepairWarrantyRepository(warranties)(svc)
+
+	_, err = svc.SetWarranties(ctx, original.ID, WarrantyInput{Months: 12}, client.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = svc.SetWarranties(ctx, pending.ID, WarrantyInput{Months: 12}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrRepairNotCompleted)
+	_, err = svc.SetWarranties(ctx, original.ID, WarrantyInput{Parts: []PartWarrantyInput{{LineID: labour.ID, Months: 6}}}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidRepairWarranty, "only part lines carry a part warranty")
+
+	// Work: 3 months or 20000 km; the kit: 24 months or 5000 km from 60000 km.
+	odometer := 60000
+	got, err := svc.SetWarranties(ctx, original.ID, WarrantyInput{Months: 3, KM: 20000, OdometerKM: &odometer,
+		Parts: []PartWarrantyInput{{LineID: kit.ID, Months: 24, KM: 5000}}}, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Warranties, 2)
+	assert.Equal(t, completed, got.Warranties[0].StartsAt)
+
+	active, err := svc.ActiveWarranties(ctx, car.ID, nil, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	active, err = svc.ActiveWarranties(ctx, car.ID, ptrInt(66000), emp.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 1, "the kit's 5000 km are used up")
+	assert.Nil(t, active[0].RepairLineID)
+	assert.Equal(t, "Embrague", active[0].RepairDescription)
+	require.NotNil(t, active[0].RemainingKM)
+	assert.Equal(t, 14000, *active[0].RemainingKM)
+
+	_, err = svc.CreateRepair(ctx, &domain.Repair{CarID: other.ID, Description: "Embrague patina", ReworkOfRepairID: &original.ID}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrNoActiveWarranty, "the original repair is on another car")
+	rework, err := svc.CreateRepair(ctx, &domain.Repair{CarID: car.ID, Description: "Embrague patina", Cost: 80, ReworkOfRepairID: &original.ID}, emp.ID)
+	require.NoError(t, err)
+	repairs.byID[rework.ID] = rework
+
+	_, err = svc.ReworkStats(ctx, nil, nil, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	stats, err := svc.ReworkStats(ctx, nil, nil, mgr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Repairs)
+	assert.Equal(t, int64(1), stats.Rework)
+	assert.InDelta(t, 1.0/3, stats.ReworkRate, 0.0001)
+	assert.Equal(t, 80.0, stats.ReworkCost)
+	require.Len(t, stats.ByTechnician, 1)
+	assert.Equal(t, emp.ID, stats.ByTechnician[0].TechnicianID)
+	assert.Equal(t, "Luis Pardo", stats.ByTechnician[0].Name)
+
+	// Once the work's warranty has run out, new rework is refused.
+	expired := time.Now().UTC().AddDate(0, -4, 0)
+	original.CompletedAt = &expired
+	_, err = svc.SetWarranties(ctx, original.ID, WarrantyInput{Months: 3}, emp.ID)
+	require.NoError(t, err)
+	_, err = svc.CreateRepair(ctx, &domain.Repair{CarID: car.ID, Description: "Embrague ruido", ReworkOfRepairID: &original.ID}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrNoActiveWarranty)
+}
+
+func ptrInt(v int) *int { return &v }
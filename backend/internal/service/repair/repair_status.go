@@ -0,0 +1,82 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// TransitionRepair moves a repair along the allowed transitions (see domain.CanTransitionRepair), stamps
+// StartedAt/CompletedAt and records the change in the repair's history. Staff only; reopening a completed
+// or cancelled repair is for managers and needs a reason.
+func (uc *RepairService) TransitionRepair(ctx context.Context, repairID uuid.UUID, to domain.RepairStatus, reason string, userID uuid.UUID) (*domain.Repair, error) {
+	if !domain.ValidateRepairStatus(to) {
+		return nil, fmt.Errorf("invalid repair status")
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	repair, err := uc.repairRepo.GetByID(ctx, repairID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.RepairTransitionIsReopen(repair.Status) && !user.IsManager() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	out, err := uc.transition(ctx, repair, to, reason, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.attachLines(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRepairStatusHistory returns the repair's status changes, oldest first (staff, or the client who owns the car).
+func (uc *RepairService) ListRepairStatusHistory(ctx context.Context, repairID uuid.UUID, userID uuid.UUID) ([]*domain.RepairStatusEvent, error) {
+	if _, err := uc.GetRepair(ctx, repairID, userID); err != nil {
+		return nil, err
+	}
+	return uc.repairRepo.ListStatusEvents(ctx, repairID)
+}
+
+// transition checks and stores one status change of repair; the caller has checked who may make it.
+func (uc *RepairService) transition(ctx context.Context, repair *domain.Repair, to domain.RepairStatus, reason string, userID uuid.UUID) (*domain.Repair, error) {
+	if !domain.CanTransitionRepair(repair.Status, to) {
+		return nil, domain.ErrRepairTransitionNotAllowed
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" && domain.RepairTransitionIsReopen(repair.Status) {
+		return nil, domain.ErrRepairReasonRequired
+	}
+	if to == domain.RepairStatusInProgress {
+		if err := uc.requireApprovedEstimate(ctx, repair); err != nil {
+			return nil, err
+		}
+	}
+	ev := &domain.RepairStatusEvent{
+		ID:              uuid.New(),
+		RepairID:        repair.ID,
+		FromStatus:      repair.Status,
+		ToStatus:        to,
+		Reason:          reason,
+		ChangedByUserID: userID,
+		ChangedAt:       time.Now().UTC(),
+	}
+	out := *repair
+	out.Apply(ev)
+	if err := uc.repairRepo.ChangeStatus(ctx, &out, ev); err != nil {
+		return nil, err
+	}
+	uc.publishVisitChange(out.ServiceJobID)
+	return &out, nil
+}
@@ -0,0 +1,102 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrRepairTechniciansNotConfigured is returned when technicians are assigned but the service has no repository for them.
+var ErrRepairTechniciansNotConfigured = errors.New("repair technicians not configured")
+
+// WithRepairTechnicianRepository lets several technicians share a repair, each with a role and hours.
+func WithRepairTechnicianRepository(repo ports.RepairTechnicianRepository) Option {
+	return func(uc *RepairService) { uc.techRepo = repo }
+}
+
+// AssignTechnicians replaces who works on an open repair: exactly one lead (who becomes Repair.TechnicianID)
+// and any assistants, each staff member once with the hours they put in. Staff only.
+func (uc *RepairService) AssignTechnicians(ctx context.Context, repairID uuid.UUID, ts []domain.RepairTechnician, userID uuid.UUID) (*domain.Repair, error) {
+	if uc.techRepo == nil {
+		return nil, ErrRepairTechniciansNotConfigured
+	}
+	repair, err := uc.editableRepair(ctx, repairID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := uc.technicianRows(ctx, repair.ID, ts)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.techRepo.Replace(ctx, repair.ID, rows); err != nil {
+		return nil, err
+	}
+	uc.publishVisitChange(repair.ServiceJobID)
+	out, err := uc.withLines(ctx, repair.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.attachTechnicians(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMyRepairs returns the repairs the caller leads or assists on, across all cars, newest first;
+// status narrows the list when not empty. Staff only.
+func (uc *RepairService) ListMyRepairs(ctx context.Context, userID uuid.UUID, status domain.RepairStatus, limit, offset int) ([]*domain.Repair, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	if status != "" && !domain.ValidateRepairStatus(status) {
+		return nil, fmt.Errorf("invalid repair status")
+	}
+	repairs, err := uc.repairRepo.ListByTechnician(ctx, userID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range repairs {
+		if err := uc.attachTechnicians(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return repairs, nil
+}
+
+// technicianRows checks a technician set (every technician must be a staff member) and stamps it for the repair.
+func (uc *RepairService) technicianRows(ctx context.Context, repairID uuid.UUID, ts []domain.RepairTechnician) ([]domain.RepairTechnician, error) {
+	if err := domain.ValidateRepairTechnicians(ts); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	rows := make([]domain.RepairTechnician, len(ts))
+	for i, t := range ts {
+		u, err := uc.userRepo.GetByID(ctx, t.UserID)
+		if err != nil || u == nil || !u.IsEmployee() {
+			return nil, domain.ErrInvalidRepairTechnicians
+		}
+		rows[i] = domain.RepairTechnician{RepairID: repairID, UserID: t.UserID, Role: t.Role, Hours: t.Hours, AssignedAt: now}
+	}
+	return rows, nil
+}
+
+func (uc *RepairService) attachTechnicians(ctx context.Context, repair *domain.Repair) error {
+	if uc.techRepo == nil {
+		return nil
+	}
+	ts, err := uc.techRepo.ListByRepair(ctx, repair.ID)
+	if err != nil {
+		return err
+	}
+	repair.Technicians = ts
+	return nil
+}
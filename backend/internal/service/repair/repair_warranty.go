@@ -0,0 +1,312 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrRepairWarrantiesNotConfigured is returned by the warranty endpoints when the service has no warranty repository.
+var ErrRepairWarrantiesNotConfigured = errors.New("repair warranties not configured")
+
+// WithRepairWarrantyRepository enables warranties on completed repairs and warranty rework.
+func WithRepairWarrantyRepository(repo ports.RepairWarrantyRepository) Option {
+	return func(uc *RepairService) { uc.warrantyRepo = repo }
+}
+
+// WarrantyInput sets a completed repair's warranties. Months and KM cover the work (both zero: no warranty
+// on the work); Parts cover fitted parts. OdometerKM is the reading when the car left, the car's mileage when nil.
+type WarrantyInput struct {
+	Months     int
+	KM         int
+	OdometerKM *int
+	Parts      []PartWarrantyInput
+}
+
+// PartWarrantyInput is the warranty on one part line of the repair.
+type PartWarrantyInput struct {
+	LineID uuid.UUID
+	Months int
+	KM     int
+}
+
+// SetWarranties replaces the warranties of a completed repair. They run from its completion. Staff only.
+func (uc *RepairService) SetWarranties(ctx context.Context, repairID uuid.UUID, in WarrantyInput, userID uuid.UUID) (*domain.Repair, error) {
+	if uc.warrantyRepo == nil {
+		return nil, ErrRepairWarrantiesNotConfigured
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	repair, err := uc.repairRepo.GetByID(ctx, repairID)
+	if err != nil {
+		return nil, err
+	}
+	if repair.Status != domain.RepairStatusCompleted || repair.CompletedAt == nil {
+		return nil, domain.ErrRepairNotCompleted
+	}
+	odometer := 0
+	if in.OdometerKM != nil {
+		odometer = *in.OdometerKM
+	} else {
+		car, err := uc.carRepo.GetByID(ctx, repair.CarID)
+		if err != nil {
+			return nil, err
+		}
+		odometer = car.Mileage
+	}
+	if err := uc.attachLines(ctx, repair); err != nil {
+		return nil, err
+	}
+	partLines := map[uuid.UUID]bool{}
+	for _, l := range repair.Lines {
+		if l.Kind == domain.RepairLinePart {
+			partLines[l.ID] = true
+		}
+	}
+
+	now := time.Now().UTC()
+	newWarranty := func(lineID *uuid.UUID, months, km int) (domain.RepairWarranty, error) {
+		w := domain.RepairWarranty{ID: uuid.New(), RepairID: repair.ID, RepairLineID: lineID, Months: months, KM: km,
+			StartsAt: *repair.CompletedAt, StartOdometerKM: odometer, CreatedAt: now}
+		return w, w.Validate()
+	}
+	var ws []domain.RepairWarranty
+	if in.Months != 0 || in.KM != 0 {
+		w, err := newWarranty(nil, in.Months, in.KM)
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, p := range in.Parts {
+		if !partLines[p.LineID] || seen[p.LineID] {
+			return nil, domain.ErrInvalidRepairWarranty
+		}
+		seen[p.LineID] = true
+		lineID := p.LineID
+		w, err := newWarranty(&lineID, p.Months, p.KM)
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	if err := uc.warrantyRepo.Replace(ctx, repair.ID, ws); err != nil {
+		return nil, err
+	}
+	if err := uc.attachTechnicians(ctx, repair); err != nil {
+		return nil, err
+	}
+	if err := uc.attachWarranties(ctx, repair); err != nil {
+		return nil, err
+	}
+	return repair, nil
+}
+
+// ActiveWarranty is a warranty still covering the car, with what it covers and what is left of it.
+type ActiveWarranty struct {
+	domain.RepairWarranty
+	RepairDescription string     `json:"repair_description"`
+	PartDescription   string     `json:"part_description,omitempty"` // fitted part; empty for the repair's work
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	ExpiresAtKM       *int       `json:"expires_at_km,omitempty"`
+	RemainingDays     *int       `json:"remaining_days,omitempty"`
+	RemainingKM       *int       `json:"remaining_km,omitempty"`
+}
+
+// ActiveWarranties lists the warranties of the car's completed repairs that still cover it now at odometerKM
+// (the car's recorded mileage when nil), newest first. Staff only.
+func (uc *RepairService) ActiveWarranties(ctx context.Context, carID uuid.UUID, odometerKM *int, userID uuid.UUID) ([]ActiveWarranty, error) {
+	if uc.warrantyRepo == nil {
+		return nil, ErrRepairWarrantiesNotConfigured
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !user.IsEmployee() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	car, err := uc.carRepo.GetByID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	odometer := car.Mileage
+	if odometerKM != nil {
+		odometer = *odometerKM
+	}
+	ws, err := uc.warrantyRepo.ListByCar(ctx, car.ID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.activeWarranties(ctx, ws, time.Now().UTC(), odometer)
+}
+
+func (uc *RepairService) activeWarranties(ctx context.Context, ws []domain.RepairWarranty, now time.Time, odometer int) ([]ActiveWarranty, error) {
+	out := []ActiveWarranty{}
+	repairs := map[uuid.UUID]*domain.Repair{}
+	for _, w := range ws {
+		if !w.ActiveAt(now, odometer) {
+			continue
+		}
+		r, ok := repairs[w.RepairID]
+		if !ok {
+			var err error
+			if r, err = uc.repairRepo.GetByID(ctx, w.RepairID); err != nil {
+				return nil, err
+			}
+			if err := uc.attachLines(ctx, r); err != nil {
+				return nil, err
+			}
+			repairs[w.RepairID] = r
+		}
+		// A reopened repair is back in the workshop; its warranty counts again once it is completed.
+		if r.Status != domain.RepairStatusCompleted {
+			continue
+		}
+		a := ActiveWarranty{RepairWarranty: w, RepairDescription: r.Description, ExpiresAt: w.ExpiresAt(), ExpiresAtKM: w.ExpiresAtKM()}
+		if w.RepairLineID != nil {
+			for _, l := range r.Lines {
+				if l.ID == *w.RepairLineID {
+					a.PartDescription = l.Description
+				}
+			}
+		}
+		if a.ExpiresAt != nil {
+			days := int(math.Ceil(a.ExpiresAt.Sub(now).Hours() / 24))
+			a.RemainingDays = &days
+		}
+		if a.ExpiresAtKM != nil {
+			km := *a.ExpiresAtKM - odometer
+			a.RemainingKM = &km
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (uc *RepairService) attachWarranties(ctx context.Context, repair *domain.Repair) error {
+	if uc.warrantyRepo == nil {
+		return nil
+	}
+	ws, err := uc.warrantyRepo.ListByRepair(ctx, repair.ID)
+	if err != nil {
+		return err
+	}
+	repair.Warranties = ws
+	return nil
+}
+
+// checkRework accepts a new repair as warranty rework when the original is a completed repair of the same car
+// still under an active warranty at the car's recorded mileage.
+func (uc *RepairService) checkRework(ctx context.Context, repair *domain.Repair, car *domain.Car) error {
+	if uc.warrantyRepo == nil {
+		return ErrRepairWarrantiesNotConfigured
+	}
+	original, err := uc.repairRepo.GetByID(ctx, *repair.ReworkOfRepairID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRepairNotFound) {
+			return domain.ErrNoActiveWarranty
+		}
+		return err
+	}
+	if original.CarID != car.ID || original.Status != domain.RepairStatusCompleted {
+		return domain.ErrNoActiveWarranty
+	}
+	ws, err := uc.warrantyRepo.ListByRepair(ctx, original.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, w := range ws {
+		if w.ActiveAt(now, car.Mileage) {
+			return nil
+		}
+	}
+	return domain.ErrNoActiveWarranty
+}
+
+// ReworkStats are warranty rework figures for repairs created in a period.
+type ReworkStats struct {
+	Repairs      int64              `json:"repairs"`     // all repairs, rework included
+	Rework       int64              `json:"rework"`      // warranty rework
+	ReworkRate   float64            `json:"rework_rate"` // Rework / Repairs
+	ReworkCost   float64            `json:"rework_cost"` // cost absorbed by the workshop, not billed
+	ByTechnician []TechnicianRework `json:"by_technician"`
+}
+
+// TechnicianRework counts the rework of repairs a technician led, most rework first.
+type TechnicianRework struct {
+	TechnicianID uuid.UUID `json:"technician_id"`
+	Name         string    `json:"name"`
+	Rework       int64     `json:"rework"`
+}
+
+// ReworkStats computes the warranty rework KPIs over repairs created in [from, to); nil bounds are open.
+// Rework is attributed to the lead technician of the original repair. Managers and admins only.
+func (uc *RepairService) ReworkStats(ctx context.Context, from, to *time.Time, userID uuid.UUID) (*ReworkStats, error) {
+	if err := uc.requireManager(ctx, userID); err != nil {
+		return nil, err
+	}
+	_, total, err := uc.repairRepo.List(ctx, &ports.RepairFilters{CreatedFrom: from, CreatedTo: to, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	stats := &ReworkStats{Repairs: total, ByTechnician: []TechnicianRework{}}
+
+	const page = 500
+	rework := true
+	filters := ports.RepairFilters{CreatedFrom: from, CreatedTo: to, Rework: &rework, Limit: page}
+	byLead := map[uuid.UUID]int64{}
+	for ; ; filters.Offset += page {
+		rows, _, err := uc.repairRepo.List(ctx, &filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			stats.Rework++
+			stats.ReworkCost += r.Cost
+			original, err := uc.repairRepo.GetByID(ctx, *r.ReworkOfRepairID)
+			if err != nil {
+				if errors.Is(err, domain.ErrRepairNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			byLead[original.TechnicianID]++
+		}
+		if len(rows) < page {
+			break
+		}
+	}
+	if stats.Repairs > 0 {
+		stats.ReworkRate = float64(stats.Rework) / float64(stats.Repairs)
+	}
+	for id, n := range byLead {
+		tr := TechnicianRework{TechnicianID: id, Rework: n}
+		if u, err := uc.userRepo.GetByID(ctx, id); err == nil && u != nil {
+			tr.Name = u.FullName()
+		}
+		stats.ByTechnician = append(stats.ByTechnician, tr)
+	}
+	sort.Slice(stats.ByTechnician, func(i, j int) bool {
+		a, b := stats.ByTechnician[i], stats.ByTechnician[j]
+		if a.Rework != b.Rework {
+			return a.Rework > b.Rework
+		}
+		return a.Name < b.Name
+	})
+	return stats, nil
+}
@@ -0,0 +1,169 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
+	"github.com/google/uuid"
+)
+
+// ErrBoardUpdatesNotConfigured is returned by SubscribeBoard when the service has no hub (see WithBoardHub).
+var ErrBoardUpdatesNotConfigured = errors.New("live board updates not configured")
+
+// WithBoardHub publishes visit changes to hub so board screens can update live.
+func WithBoardHub(hub *pubsub.Hub) Option {
+	return func(s *Service) { s.boardHub = hub }
+}
+
+// Board is every visit still in the workshop, one column per status.
+type Board struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Columns     []BoardColumn `json:"columns"`
+}
+
+// BoardColumn holds the visits in one status, longest in the shop first.
+type BoardColumn struct {
+	Status domain.ServiceJobStatus `json:"status"`
+	Jobs   []BoardItem             `json:"jobs"`
+}
+
+// BoardItem is one visit card on the board.
+type BoardItem struct {
+	ServiceJobID uuid.UUID               `json:"service_job_id"`
+	Status       domain.ServiceJobStatus `json:"status"`
+	OpenedAt     time.Time               `json:"opened_at"`
+	AgeMinutes   int                     `json:"age_minutes"` // time in the shop so far
+	PromisedAt   *time.Time              `json:"promised_at,omitempty"`
+	PromiseState domain.PromiseState     `json:"promise_state,omitempty"` // on_track | at_risk | overdue
+	Car          *BoardCar               `json:"car,omitempty"`
+	Owner        *BoardPerson            `json:"owner,omitempty"`
+	Technicians  []BoardPerson           `json:"technicians"` // technicians of the visit's repairs
+	Repairs      []BoardRepair           `json:"repairs"`
+}
+
+// BoardCar identifies the vehicle on a card.
+type BoardCar struct {
+	ID           uuid.UUID `json:"id"`
+	LicensePlate string    `json:"license_plate"`
+	Make         string    `json:"make"`
+	Model        string    `json:"model"`
+	Year         int       `json:"year"`
+}
+
+// BoardPerson is an owner or technician shown on a card.
+type BoardPerson struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Phone string    `json:"phone,omitempty"`
+}
+
+// BoardRepair is a repair linked to the visit.
+type BoardRepair struct {
+	ID           uuid.UUID           `json:"id"`
+	Description  string              `json:"description"`
+	Status       domain.RepairStatus `json:"status"`
+	TechnicianID uuid.UUID           `json:"technician_id"`
+}
+
+// Board returns every open or in-progress visit with its car, owner, technicians and repairs. Staff only.
+func (s *Service) Board(ctx context.Context, userID uuid.UUID) (*Board, error) {
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	jobs, err := s.jobRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repairsByJob := map[uuid.UUID][]*domain.Repair{}
+	if s.repairRepo != nil && len(jobs) > 0 {
+		ids := make([]uuid.UUID, len(jobs))
+		for i, j := range jobs {
+			ids[i] = j.ID
+		}
+		repairs, err := s.repairRepo.ListByServiceJobIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repairs {
+			repairsByJob[*r.ServiceJobID] = append(repairsByJob[*r.ServiceJobID], r)
+		}
+	}
+
+	// Several cards usually share technicians; look each user up once.
+	people := map[uuid.UUID]*BoardPerson{}
+	person := func(id uuid.UUID) *BoardPerson {
+		if p, ok := people[id]; ok {
+			return p
+		}
+		var p *BoardPerson
+		if u, err := s.userRepo.GetByID(ctx, id); err == nil && u != nil {
+			p = &BoardPerson{ID: u.ID, Name: u.FullName(), Phone: u.Phone}
+		}
+		people[id] = p
+		return p
+	}
+
+	now := time.Now().UTC()
+	board := &Board{GeneratedAt: now, Columns: []BoardColumn{
+		{Status: domain.ServiceJobStatusOpen, Jobs: []BoardItem{}},
+		{Status: domain.ServiceJobStatusInProgress, Jobs: []BoardItem{}},
+	}}
+	for _, j := range jobs {
+		item := BoardItem{
+			ServiceJobID: j.ID,
+			Status:       j.Status,
+			OpenedAt:     j.OpenedAt,
+			AgeMinutes:   int(now.Sub(j.OpenedAt).Minutes()),
+			PromisedAt:   j.PromisedAt,
+			PromiseState: j.PromiseState(now, s.promiseAtRiskWindow()),
+			Technicians:  []BoardPerson{},
+			Repairs:      []BoardRepair{},
+		}
+		if car, err := s.carRepo.GetByID(ctx, j.CarID); err == nil && car != nil {
+			item.Car = &BoardCar{ID: car.ID, LicensePlate: car.LicensePlate, Make: car.Make, Model: car.Model, Year: car.Year}
+			item.Owner = person(car.OwnerID)
+		}
+		seen := map[uuid.UUID]bool{}
+		for _, r := range repairsByJob[j.ID] {
+			item.Repairs = append(item.Repairs, BoardRepair{ID: r.ID, Description: r.Description, Status: r.Status, TechnicianID: r.TechnicianID})
+			if r.Status == domain.RepairStatusCancelled || seen[r.TechnicianID] {
+				continue
+			}
+			seen[r.TechnicianID] = true
+			if p := person(r.TechnicianID); p != nil {
+				item.Technicians = append(item.Technicians, BoardPerson{ID: p.ID, Name: p.Name})
+			}
+		}
+		for i := range board.Columns {
+			if board.Columns[i].Status == j.Status {
+				board.Columns[i].Jobs = append(board.Columns[i].Jobs, item)
+			}
+		}
+	}
+	return board, nil
+}
+
+// SubscribeBoard returns the live feed of visit changes for a staff caller, and its cancel function.
+func (s *Service) SubscribeBoard(ctx context.Context, userID uuid.UUID) (<-chan pubsub.Event, func(), error) {
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+	if s.boardHub == nil {
+		return nil, nil, ErrBoardUpdatesNotConfigured
+	}
+	ch, cancel := s.boardHub.Subscribe(64)
+	return ch, cancel, nil
+}
+
+// publishChange tells board viewers that a visit changed. No-op without a hub.
+func (s *Service) publishChange(jobID uuid.UUID, status domain.ServiceJobStatus, kind string) {
+	s.boardHub.Publish(pubsub.Event{Type: domain.ServiceJobChangeEvent, Data: domain.ServiceJobChange{
+		ServiceJobID: jobID,
+		Kind:         kind,
+		Status:       status,
+		At:           time.Now().UTC(),
+	}})
+}
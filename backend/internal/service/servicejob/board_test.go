@@ -0,0 +1,107 @@
+package servicejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Board_GroupsActiveVisits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	emp, _ := domain.NewUser("e@t", "p", "Ana", "Taller", domain.RoleEmployee)
+	emp.ID = uuid.New()
+	tech, _ := domain.NewUser("t@t", "p", "Luis", "Mecánico", domain.RoleEmployee)
+	tech.ID = uuid.New()
+	owner, _ := domain.NewUser("o@t", "p", "Olga", "Cliente", domain.RoleClient)
+	owner.ID = uuid.New()
+	owner.Phone = "600111222"
+	carID := uuid.New()
+	now := time.Now().UTC()
+	promised := now.Add(3 * time.Hour)
+	open := &domain.ServiceJob{ID: uuid.New(), CarID: carID, Status: domain.ServiceJobStatusOpen, OpenedAt: now.Add(-30 * time.Minute), PromisedAt: &promised}
+	working := &domain.ServiceJob{ID: uuid.New(), CarID: carID, Status: domain.ServiceJobStatusInProgress, OpenedAt: now.Add(-2 * time.Hour)}
+	closed := &domain.ServiceJob{ID: uuid.New(), CarID: carID, Status: domain.ServiceJobStatusClosed, OpenedAt: now.Add(-5 * time.Hour)}
+	jobs := &stubJobRepo{byID: map[uuid.UUID]*domain.ServiceJob{open.ID: open, working.ID: working, closed.ID: closed}}
+	repairs := &memRepairRepo{created: []*domain.Repair{
+		{ID: uuid.New(), ServiceJobID: &working.ID, TechnicianID: tech.ID, Description: "Pastillas", Status: domain.RepairStatusInProgress},
+		{ID: uuid.New(), ServiceJobID: &working.ID, TechnicianID: tech.ID, Description: "Discos", Status: domain.RepairStatusPending},
+		{ID: uuid.New(), ServiceJobID: &working.ID, TechnicianID: emp.ID, Description: "Aceite", Status: domain.RepairStatusCancelled},
+		{ID: uuid.New(), ServiceJobID: &closed.ID, TechnicianID: emp.ID, Description: "ITV", Status: domain.RepairStatusCompleted},
+	}}
+	car := &domain.Car{ID: carID, OwnerID: owner.ID, LicensePlate: "1234ABC", Make: "Seat", Model: "Ibiza", Year: 2018}
+	s := NewService(jobs, tCar{carID: car}, tUser{emp.ID: emp, tech.ID: tech, owner.ID: owner}, repairs)
+
+	_, err := s.Board(ctx, owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	b, err := s.Board(ctx, emp.ID)
+	require.NoError(t, err)
+	require.Len(t, b.Columns, 2)
+	assert.Equal(t, domain.ServiceJobStatusOpen, b.Columns[0].Status)
+	require.Len(t, b.Columns[0].Jobs, 1)
+	card := b.Columns[0].Jobs[0]
+	assert.Equal(t, open.ID, card.ServiceJobID)
+	assert.Equal(t, &promised, card.PromisedAt)
+	assert.InDelta(t, 30, card.AgeMinutes, 1)
+	require.NotNil(t, card.Car)
+	assert.Equal(t, "1234ABC", card.Car.LicensePlate)
+	require.NotNil(t, card.Owner)
+	assert.Equal(t, "Olga Cliente", card.Owner.Name)
+	assert.Equal(t, "600111222", card.Owner.Phone)
+	assert.Empty(t, card.Repairs)
+
+	require.Len(t, b.Columns[1].Jobs, 1)
+	card = b.Columns[1].Jobs[0]
+	assert.Equal(t, working.ID, card.ServiceJobID)
+	assert.Len(t, card.Repairs, 3)
+	require.Len(t, card.Technicians, 1, "one entry per technician; cancelled repairs don't count")
+	assert.Equal(t, tech.ID, card.Technicians[0].ID)
+	assert.Equal(t, "Luis Mecánico", card.Technicians[0].Name)
+}
+
+func TestService_Board_PublishesChanges(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	hub := pubsub.New()
+	WithBoardHub(hub)(fx.svc)
+
+	_, _, err := fx.svc.SubscribeBoard(ctx, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	events, cancel, err := fx.svc.SubscribeBoard(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	defer cancel()
+
+	_, err = fx.svc.CancelServiceJob(ctx, fx.jobID, "El cliente se lo lleva", fx.emp.ID)
+	require.NoError(t, err)
+	select {
+	case ev := <-events:
+		assert.Equal(t, domain.ServiceJobChangeEvent, ev.Type)
+		ch, ok := ev.Data.(domain.ServiceJobChange)
+		require.True(t, ok)
+		assert.Equal(t, fx.jobID, ch.ServiceJobID)
+		assert.Equal(t, domain.ServiceJobChangeStatus, ch.Kind)
+		assert.Equal(t, domain.ServiceJobStatusCancelled, ch.Status)
+	default:
+		t.Fatal("expected a change event")
+	}
+
+	// A rejected change publishes nothing.
+	_, err = fx.svc.CancelServiceJob(ctx, fx.jobID, "otra vez", fx.emp.ID)
+	require.Error(t, err)
+	assert.Empty(t, events)
+}
+
+func TestService_SubscribeBoard_WithoutHub(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	_, _, err := fx.svc.SubscribeBoard(context.Background(), fx.emp.ID)
+	assert.ErrorIs(t, err, ErrBoardUpdatesNotConfigured)
+}
@@ -0,0 +1,140 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrAppointmentsNotConfigured is returned when check-in is used without WithAppointmentRepository.
+var ErrAppointmentsNotConfigured = errors.New("appointment repository not configured")
+
+// maxArrivalsPerDay bounds the appointments loaded for one arrivals view.
+const maxArrivalsPerDay = 500
+
+// CheckInAppointment opens a visit for the appointment's car when the customer arrives.
+// The appointment moves to checked_in; a second check-in returns ErrAppointmentAlreadyCheckedIn. Staff only.
+func (s *Service) CheckInAppointment(ctx context.Context, appointmentID uuid.UUID, userID uuid.UUID) (*domain.ServiceJob, error) {
+	if s.apptRepo == nil {
+		return nil, ErrAppointmentsNotConfigured
+	}
+	u, err := s.requireWorkshopUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	appt, err := s.apptRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	switch appt.Status {
+	case domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed:
+	case domain.AppointmentStatusCheckedIn:
+		return nil, domain.ErrAppointmentAlreadyCheckedIn
+	default:
+		return nil, domain.ErrAppointmentNotCheckInable
+	}
+	existing, err := s.jobRepo.GetByAppointmentID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrAppointmentAlreadyCheckedIn
+	}
+	if _, err := s.canAccessCar(ctx, u, appt.CarID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	apptID := appt.ID
+	job := &domain.ServiceJob{
+		ID:             uuid.New(),
+		CarID:          appt.CarID,
+		Status:         domain.ServiceJobStatusOpen,
+		OpenedByUserID: userID,
+		OpenedAt:       now,
+		AppointmentID:  &apptID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	// The visit and the appointment's checked_in status are written together; a concurrent second
+	// check-in finds the appointment already checked in.
+	if err := s.jobRepo.CreateForAppointment(ctx, job); err != nil {
+		return nil, err
+	}
+	s.publishChange(job.ID, job.Status, domain.ServiceJobChangeCreated)
+	return job, nil
+}
+
+// Arrival pairs an expected appointment with the visit opened at check-in (nil while not arrived).
+type Arrival struct {
+	Appointment *domain.Appointment `json:"appointment"`
+	ServiceJob  *domain.ServiceJob  `json:"service_job,omitempty"`
+	Arrived     bool                `json:"arrived"`
+}
+
+// ArrivalsSummary is the "expected vs arrived" view for one workshop day.
+type ArrivalsSummary struct {
+	Day      string     `json:"day"`
+	Expected int        `json:"expected"`
+	Arrived  int        `json:"arrived"`
+	Pending  int        `json:"pending"`
+	Items    []*Arrival `json:"items"`
+}
+
+// ArrivalsOn lists non-cancelled appointments scheduled on the local calendar day of `day`
+// and whether each one has been checked in. Staff only.
+func (s *Service) ArrivalsOn(ctx context.Context, day time.Time, userID uuid.UUID) (*ArrivalsSummary, error) {
+	if s.apptRepo == nil {
+		return nil, ErrAppointmentsNotConfigured
+	}
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	d := day.In(time.Local)
+	startLocal := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+	start := startLocal.UTC()
+	end := startLocal.AddDate(0, 0, 1).UTC()
+	appts, _, err := s.apptRepo.List(ctx, &ports.AppointmentFilters{
+		ScheduledFrom: &start,
+		ScheduledTo:   &end,
+		SortBy:        "scheduled_at",
+		SortOrder:     "ASC",
+		Limit:         maxArrivalsPerDay,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(appts))
+	for _, a := range appts {
+		ids = append(ids, a.ID)
+	}
+	jobs, err := s.jobRepo.ListByAppointmentIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byAppt := make(map[uuid.UUID]*domain.ServiceJob, len(jobs))
+	for _, j := range jobs {
+		byAppt[*j.AppointmentID] = j
+	}
+
+	out := &ArrivalsSummary{Day: startLocal.Format("2006-01-02"), Items: []*Arrival{}}
+	for _, a := range appts {
+		if a.Status == domain.AppointmentStatusCancelled {
+			continue
+		}
+		job := byAppt[a.ID]
+		item := &Arrival{Appointment: a, ServiceJob: job, Arrived: job != nil}
+		out.Expected++
+		if item.Arrived {
+			out.Arrived++
+		}
+		out.Items = append(out.Items, item)
+	}
+	out.Pending = out.Expected - out.Arrived
+	return out, nil
+}
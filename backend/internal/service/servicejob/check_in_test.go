@@ -0,0 +1,142 @@
+package servicejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubApptRepo struct {
+	byID map[uuid.UUID]*domain.Appointment
+}
+
+func (r *stubApptRepo) Create(_ context.Context, a *domain.Appointment) error {
+	r.byID[a.ID] = a
+	return nil
+}
+
+func (r *stubApptRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.Appointment, error) {
+	a, ok := r.byID[id]
+	if !ok {
+		return nil, domain.ErrAppointmentNotFound
+	}
+	return a, nil
+}
+
+func (r *stubApptRepo) Update(_ context.Context, a *domain.Appointment) error {
+	r.byID[a.ID] = a
+	return nil
+}
+
+func (r *stubApptRepo) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *stubApptRepo) List(_ context.Context, f *ports.AppointmentFilters) ([]*domain.Appointment, int64, error) {
+	var out []*domain.Appointment
+	for _, a := range r.byID {
+		if f != nil && f.ScheduledFrom != nil && a.ScheduledAt.Before(*f.ScheduledFrom) {
+			continue
+		}
+		if f != nil && f.ScheduledTo != nil && !a.ScheduledAt.Before(*f.ScheduledTo) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, int64(len(out)), nil
+}
+
+func (r *stubApptRepo) CountNonCancelledBetween(context.Context, time.Time, time.Time, *uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (r *stubApptRepo) CreateWithinDayCap(ctx context.Context, a *domain.Appointment, _, _ time.Time, _ int64) error {
+	return r.Create(ctx, a)
+}
+
+func (r *stubApptRepo) UpdateWithinDayCap(ctx context.Context, a *domain.Appointment, _, _ time.Time, _ int64) error {
+	return r.Update(ctx, a)
+}
+
+func checkInFixture(t *testing.T) (*Service, *stubJobRepo, *stubApptRepo, uuid.UUID, *domain.Appointment) {
+	t.Helper()
+	empID := uuid.New()
+	carID := uuid.New()
+	emp, _ := domain.NewUser("e@t", "p", "E", "E", domain.RoleEmployee)
+	emp.ID = empID
+	appt := &domain.Appointment{
+		ID:          uuid.New(),
+		CustomerID:  uuid.New(),
+		CarID:       carID,
+		ServiceType: "oil",
+		Status:      domain.AppointmentStatusConfirmed,
+		ScheduledAt: time.Now().Add(time.Hour),
+	}
+	ar := &stubApptRepo{byID: map[uuid.UUID]*domain.Appointment{appt.ID: appt}}
+	je := &stubJobRepo{byID: map[uuid.UUID]*domain.ServiceJob{}, appts: ar}
+	s := NewService(je, tCar{carID: {ID: carID, OwnerID: appt.CustomerID}}, tUser{empID: emp}, nil, WithAppointmentRepository(ar))
+	return s, je, ar, empID, appt
+}
+
+func TestService_CheckInAppointment_OpensJob(t *testing.T) {
+	t.Parallel()
+	s, je, ar, empID, appt := checkInFixture(t)
+
+	job, err := s.CheckInAppointment(context.Background(), appt.ID, empID)
+	require.NoError(t, err)
+	require.NotNil(t, job.AppointmentID)
+	assert.Equal(t, appt.ID, *job.AppointmentID)
+	assert.Equal(t, appt.CarID, job.CarID)
+	assert.Equal(t, domain.ServiceJobStatusOpen, job.Status)
+	require.Len(t, je.created, 1)
+	assert.Equal(t, domain.AppointmentStatusCheckedIn, ar.byID[appt.ID].Status)
+}
+
+func TestService_CheckInAppointment_Twice(t *testing.T) {
+	t.Parallel()
+	s, je, _, empID, appt := checkInFixture(t)
+
+	_, err := s.CheckInAppointment(context.Background(), appt.ID, empID)
+	require.NoError(t, err)
+	_, err = s.CheckInAppointment(context.Background(), appt.ID, empID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentAlreadyCheckedIn)
+	assert.Len(t, je.created, 1)
+}
+
+func TestService_CheckInAppointment_Cancelled(t *testing.T) {
+	t.Parallel()
+	s, _, _, empID, appt := checkInFixture(t)
+	appt.Status = domain.AppointmentStatusCancelled
+
+	_, err := s.CheckInAppointment(context.Background(), appt.ID, empID)
+	assert.ErrorIs(t, err, domain.ErrAppointmentNotCheckInable)
+}
+
+func TestService_ArrivalsOn_ExpectedVsArrived(t *testing.T) {
+	t.Parallel()
+	s, _, ar, empID, appt := checkInFixture(t)
+	day := time.Date(2024, 6, 10, 0, 0, 0, 0, time.Local)
+	appt.ScheduledAt = day.Add(10 * time.Hour)
+	other := &domain.Appointment{ID: uuid.New(), CarID: appt.CarID, Status: domain.AppointmentStatusScheduled, ScheduledAt: day.Add(15 * time.Hour)}
+	cancelled := &domain.Appointment{ID: uuid.New(), CarID: appt.CarID, Status: domain.AppointmentStatusCancelled, ScheduledAt: day.Add(11 * time.Hour)}
+	nextDay := &domain.Appointment{ID: uuid.New(), CarID: appt.CarID, Status: domain.AppointmentStatusScheduled, ScheduledAt: day.Add(34 * time.Hour)}
+	for _, a := range []*domain.Appointment{other, cancelled, nextDay} {
+		ar.byID[a.ID] = a
+	}
+	_, err := s.CheckInAppointment(context.Background(), appt.ID, empID)
+	require.NoError(t, err)
+
+	out, err := s.ArrivalsOn(context.Background(), day, empID)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-10", out.Day)
+	assert.Equal(t, 2, out.Expected)
+	assert.Equal(t, 1, out.Arrived)
+	assert.Equal(t, 1, out.Pending)
+}
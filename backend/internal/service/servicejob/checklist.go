@@ -0,0 +1,143 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrChecklistTemplatesNotConfigured is returned by SaveChecklistTemplate when the service has no template repository.
+var ErrChecklistTemplatesNotConfigured = errors.New("checklist templates not configured")
+
+// WithChecklistTemplateRepository enables template-based reception and handover checklists.
+func WithChecklistTemplateRepository(repo ports.ChecklistTemplateRepository) Option {
+	return func(s *Service) { s.templateRepo = repo }
+}
+
+// ChecklistTemplateInput defines a new template, or the next version when Code and Kind already exist.
+type ChecklistTemplateInput struct {
+	Code  string
+	Kind  domain.ChecklistKind
+	Name  string
+	Items []domain.ChecklistItem
+}
+
+// ChecklistAnswersInput selects a template version and its answers on reception / handover.
+type ChecklistAnswersInput struct {
+	TemplateID *uuid.UUID
+	Answers    []domain.ChecklistAnswer
+}
+
+// SaveChecklistTemplate stores in as a new immutable version (1 for a new code). Admin / manager only.
+func (s *Service) SaveChecklistTemplate(ctx context.Context, in ChecklistTemplateInput, userID uuid.UUID) (*domain.ChecklistTemplate, error) {
+	u, err := s.requireWorkshopUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.CanManageUsers() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	if s.templateRepo == nil {
+		return nil, ErrChecklistTemplatesNotConfigured
+	}
+	t := &domain.ChecklistTemplate{
+		ID:              uuid.New(),
+		Code:            strings.ToLower(strings.TrimSpace(in.Code)),
+		Kind:            in.Kind,
+		Name:            strings.TrimSpace(in.Name),
+		Items:           in.Items,
+		CreatedByUserID: userID,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	latest, err := s.templateRepo.GetLatest(ctx, t.Code, t.Kind)
+	if err != nil {
+		return nil, err
+	}
+	t.Version = 1
+	if latest != nil {
+		t.Version = latest.Version + 1
+	}
+	if err := s.templateRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListChecklistTemplates returns the current version of each template; kind "" lists both steps. Staff only.
+func (s *Service) ListChecklistTemplates(ctx context.Context, kind domain.ChecklistKind, userID uuid.UUID) ([]*domain.ChecklistTemplate, error) {
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if kind != "" && !domain.ValidateChecklistKind(kind) {
+		return nil, domain.ErrInvalidChecklistTemplate
+	}
+	if s.templateRepo == nil {
+		return []*domain.ChecklistTemplate{}, nil
+	}
+	return s.templateRepo.ListLatest(ctx, kind)
+}
+
+// ListChecklistTemplateVersions returns every version of a template, newest first. Staff only.
+func (s *Service) ListChecklistTemplateVersions(ctx context.Context, code string, kind domain.ChecklistKind, userID uuid.UUID) ([]*domain.ChecklistTemplate, error) {
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if !domain.ValidateChecklistKind(kind) {
+		return nil, domain.ErrInvalidChecklistTemplate
+	}
+	if s.templateRepo == nil {
+		return []*domain.ChecklistTemplate{}, nil
+	}
+	return s.templateRepo.ListVersions(ctx, strings.ToLower(strings.TrimSpace(code)), kind)
+}
+
+// GetChecklistTemplate returns one template version. Any signed-in user: clients need it to render
+// the checklist of their own visit.
+func (s *Service) GetChecklistTemplate(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.ChecklistTemplate, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	if s.templateRepo == nil {
+		return nil, domain.ErrChecklistTemplateNotFound
+	}
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// resolveChecklist validates answers against the chosen template version and returns the values to
+// store plus the row's schema version. No template means the legacy fixed-column checklist.
+func (s *Service) resolveChecklist(ctx context.Context, kind domain.ChecklistKind, in ChecklistAnswersInput) (*uuid.UUID, []domain.ChecklistAnswer, int, error) {
+	if in.TemplateID == nil {
+		if len(in.Answers) > 0 {
+			return nil, nil, 0, fmt.Errorf("%w: answers need a checklist_template_id", domain.ErrInvalidChecklistAnswers)
+		}
+		return nil, nil, domain.ChecklistSchemaFixed, nil
+	}
+	if s.templateRepo == nil {
+		return nil, nil, 0, domain.ErrChecklistTemplateNotFound
+	}
+	t, err := s.templateRepo.GetByID(ctx, *in.TemplateID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if t.Kind != kind {
+		return nil, nil, 0, domain.ErrChecklistTemplateKindMismatch
+	}
+	if err := t.ValidateAnswers(in.Answers); err != nil {
+		return nil, nil, 0, err
+	}
+	id := t.ID
+	return &id, in.Answers, domain.ChecklistSchemaTemplate, nil
+}
@@ -0,0 +1,136 @@
+package servicejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memTemplateRepo struct {
+	rows map[uuid.UUID]*domain.ChecklistTemplate
+}
+
+func (m *memTemplateRepo) Create(_ context.Context, t *domain.ChecklistTemplate) error {
+	for _, r := range m.rows {
+		if r.Code == t.Code && r.Kind == t.Kind && r.Version == t.Version {
+			return domain.ErrChecklistVersionConflict
+		}
+	}
+	m.rows[t.ID] = t
+	return nil
+}
+
+func (m *memTemplateRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.ChecklistTemplate, error) {
+	t, ok := m.rows[id]
+	if !ok {
+		return nil, domain.ErrChecklistTemplateNotFound
+	}
+	return t, nil
+}
+
+func (m *memTemplateRepo) GetLatest(_ context.Context, code string, kind domain.ChecklistKind) (*domain.ChecklistTemplate, error) {
+	var latest *domain.ChecklistTemplate
+	for _, r := range m.rows {
+		if r.Code == code && r.Kind == kind && (latest == nil || r.Version > latest.Version) {
+			latest = r
+		}
+	}
+	return latest, nil
+}
+
+func (m *memTemplateRepo) ListLatest(context.Context, domain.ChecklistKind) ([]*domain.ChecklistTemplate, error) {
+	return nil, nil
+}
+
+func (m *memTemplateRepo) ListVersions(context.Context, string, domain.ChecklistKind) ([]*domain.ChecklistTemplate, error) {
+	return nil, nil
+}
+
+func checklistFixture(t *testing.T) (*Service, *stubJobRepo, *domain.User, *domain.User, uuid.UUID) {
+	t.Helper()
+	manager, _ := domain.NewUser("m@t", "p", "M", "M", domain.RoleManager)
+	manager.ID = uuid.New()
+	emp, _ := domain.NewUser("e@t", "p", "E", "E", domain.RoleEmployee)
+	emp.ID = uuid.New()
+	jobID, carID := uuid.New(), uuid.New()
+	j := &domain.ServiceJob{ID: jobID, CarID: carID, Status: domain.ServiceJobStatusOpen, OpenedByUserID: emp.ID, OpenedAt: time.Now().UTC()}
+	jobs := &stubJobRepo{byID: map[uuid.UUID]*domain.ServiceJob{jobID: j}}
+	s := NewService(jobs, tCar{carID: {ID: carID, OwnerID: uuid.New()}}, tUser{manager.ID: manager, emp.ID: emp}, nil,
+		WithChecklistTemplateRepository(&memTemplateRepo{rows: map[uuid.UUID]*domain.ChecklistTemplate{}}))
+	return s, jobs, manager, emp, jobID
+}
+
+func motorbikeInput(items ...domain.ChecklistItem) ChecklistTemplateInput {
+	return ChecklistTemplateInput{Code: " Motorbike ", Kind: domain.ChecklistKindReception, Name: "Moto", Items: items}
+}
+
+func TestService_SaveChecklistTemplate_Versions(t *testing.T) {
+	t.Parallel()
+	s, _, manager, emp, _ := checklistFixture(t)
+	ctx := context.Background()
+	chain := domain.ChecklistItem{Key: "chain", Label: "Cadena", Type: domain.ChecklistItemLevel, Levels: []string{"loose", "ok", "tight"}}
+
+	_, err := s.SaveChecklistTemplate(ctx, motorbikeInput(chain), emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	v1, err := s.SaveChecklistTemplate(ctx, motorbikeInput(chain), manager.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "motorbike", v1.Code)
+	assert.Equal(t, 1, v1.Version)
+
+	helmet := domain.ChecklistItem{Key: "helmet", Label: "Casco", Type: domain.ChecklistItemBoolean}
+	v2, err := s.SaveChecklistTemplate(ctx, motorbikeInput(chain, helmet), manager.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, v2.Version)
+	assert.NotEqual(t, v1.ID, v2.ID)
+
+	old, err := s.GetChecklistTemplate(ctx, v1.ID, emp.ID)
+	require.NoError(t, err)
+	assert.Len(t, old.Items, 1, "earlier versions are never rewritten")
+
+	_, err = s.SaveChecklistTemplate(ctx, motorbikeInput(), manager.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidChecklistTemplate)
+
+	unconfigured := NewService(&stubJobRepo{}, tCar{}, tUser{manager.ID: manager}, nil)
+	_, err = unconfigured.SaveChecklistTemplate(ctx, motorbikeInput(chain), manager.ID)
+	assert.ErrorIs(t, err, ErrChecklistTemplatesNotConfigured)
+}
+
+func TestService_SaveReception_WithTemplate(t *testing.T) {
+	t.Parallel()
+	s, jobs, manager, emp, jobID := checklistFixture(t)
+	ctx := context.Background()
+	tmpl, err := s.SaveChecklistTemplate(ctx, motorbikeInput(
+		domain.ChecklistItem{Key: "helmet", Label: "Casco", Type: domain.ChecklistItemBoolean, Required: true}), manager.ID)
+	require.NoError(t, err)
+
+	_, err = s.SaveReception(ctx, jobID, SaveReceptionInput{OdometerKM: 10, Checklist: ChecklistAnswersInput{TemplateID: &tmpl.ID}}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidChecklistAnswers)
+
+	yes := true
+	rec, err := s.SaveReception(ctx, jobID, SaveReceptionInput{OdometerKM: 10, Checklist: ChecklistAnswersInput{
+		TemplateID: &tmpl.ID,
+		Answers:    []domain.ChecklistAnswer{{Key: "helmet", Bool: &yes}},
+	}}, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ChecklistSchemaTemplate, rec.SchemaVersion)
+	assert.Equal(t, tmpl.ID, *rec.ChecklistTemplateID)
+	assert.Same(t, rec, jobs.rec[jobID])
+
+	// A reception template cannot be used at handover.
+	_, err = s.SaveHandover(ctx, jobID, SaveHandoverInput{OdometerKM: 12, Checklist: ChecklistAnswersInput{
+		TemplateID: &tmpl.ID,
+		Answers:    []domain.ChecklistAnswer{{Key: "helmet", Bool: &yes}},
+	}}, emp.ID)
+	assert.ErrorIs(t, err, domain.ErrChecklistTemplateKindMismatch)
+
+	h, err := s.SaveHandover(ctx, jobID, SaveHandoverInput{OdometerKM: 12}, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ChecklistSchemaFixed, h.SchemaVersion)
+	assert.Nil(t, h.ChecklistTemplateID)
+}
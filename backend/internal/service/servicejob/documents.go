@@ -0,0 +1,487 @@
+package servicejob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pdf"
+	"github.com/google/uuid"
+)
+
+// Next-service recommendation printed on the handover report: whichever comes first.
+const (
+	ServiceIntervalKM     = 15000
+	ServiceIntervalMonths = 12
+)
+
+// Branding is the workshop identity printed on job cards and handover reports.
+type Branding struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	TaxID   string
+	Color   pdf.Color // accent colour of the header band and headings
+}
+
+// DefaultBranding is used until WithBranding is given.
+var DefaultBranding = Branding{Name: "GonsGarage", Color: pdf.Color{R: 31, G: 78, B: 121}}
+
+// WithBranding sets the workshop identity printed on documents; empty Name keeps the default.
+func WithBranding(b Branding) Option {
+	return func(s *Service) {
+		if strings.TrimSpace(b.Name) == "" {
+			b.Name = DefaultBranding.Name
+		}
+		s.branding = &b
+	}
+}
+
+// visitDocument is everything the printed documents show about a visit.
+type visitDocument struct {
+	job               *domain.ServiceJob
+	car               *domain.Car
+	owner             *domain.User
+	appointment       *domain.Appointment
+	reception         *domain.ServiceJobReception
+	receptionTemplate *domain.ChecklistTemplate
+	handover          *domain.ServiceJobHandover
+	handoverTemplate  *domain.ChecklistTemplate
+	repairs           []*domain.Repair
+	parts             []domain.EstimateLine       // approved part lines of the visit's estimates
+	openFindings      []*domain.InspectionFinding // red and amber findings not turned into a repair
+}
+
+// JobCardPDF renders the job card printed at reception: vehicle, client, requested work, reception
+// checklist and odometer, and boxes for signatures. Staff, or the client who owns the car.
+func (s *Service) JobCardPDF(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]byte, error) {
+	v, err := s.loadVisitDocument(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderJobCard(v), nil
+}
+
+// HandoverPDF renders the report given to the client at handover: work done, parts used, odometer in
+// and out and the next-service recommendation. Needs a recorded handover. Staff, or the car's owner.
+func (s *Service) HandoverPDF(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]byte, error) {
+	v, err := s.loadVisitDocument(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if v.handover == nil {
+		return nil, domain.ErrHandoverNotRecorded
+	}
+	return s.renderHandover(v), nil
+}
+
+func (s *Service) loadVisitDocument(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*visitDocument, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	car, err := s.canAccessCar(ctx, u, j.CarID)
+	if err != nil {
+		return nil, err
+	}
+	v := &visitDocument{job: j, car: car}
+	if owner, err := s.userRepo.GetByID(ctx, car.OwnerID); err == nil {
+		v.owner = owner
+	}
+	if s.apptRepo != nil && j.AppointmentID != nil {
+		if a, err := s.apptRepo.GetByID(ctx, *j.AppointmentID); err == nil {
+			v.appointment = a
+		}
+	}
+	if v.reception, err = s.jobRepo.GetReception(ctx, jobID); err != nil {
+		return nil, err
+	}
+	if v.handover, err = s.jobRepo.GetHandover(ctx, jobID); err != nil {
+		return nil, err
+	}
+	if v.reception != nil {
+		v.receptionTemplate = s.checklistTemplate(ctx, v.reception.ChecklistTemplateID)
+	}
+	if v.handover != nil {
+		v.handoverTemplate = s.checklistTemplate(ctx, v.handover.ChecklistTemplateID)
+	}
+	if s.repairRepo != nil {
+		if v.repairs, err = s.repairRepo.ListByServiceJobIDs(ctx, []uuid.UUID{jobID}); err != nil {
+			return nil, err
+		}
+	}
+	if s.estimateRepo != nil {
+		estimates, err := s.estimateRepo.ListByServiceJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range estimates {
+			for _, l := range e.Lines {
+				if l.Kind == domain.EstimateLinePart && l.Approved != nil && *l.Approved {
+					v.parts = append(v.parts, l)
+				}
+			}
+		}
+	}
+	if s.findingRepo != nil {
+		findings, err := s.findingRepo.ListByServiceJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range findings {
+			if f.Severity != domain.FindingSeverityGreen && f.RepairID == nil {
+				v.openFindings = append(v.openFindings, f)
+			}
+		}
+	}
+	return v, nil
+}
+
+func (s *Service) checklistTemplate(ctx context.Context, id *uuid.UUID) *domain.ChecklistTemplate {
+	if id == nil || s.templateRepo == nil {
+		return nil
+	}
+	t, err := s.templateRepo.GetByID(ctx, *id)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+func (s *Service) brand() Branding {
+	if s.branding != nil {
+		return *s.branding
+	}
+	return DefaultBranding
+}
+
+// newDocumentFlow starts a document whose pages carry the workshop header band.
+func (s *Service) newDocumentFlow(title string, v *visitDocument) (*pdf.Document, *pdf.Flow) {
+	b := s.brand()
+	doc := pdf.New(fmt.Sprintf("%s %s", title, v.car.LicensePlate))
+	flow := pdf.NewFlow(doc, 40, func(f *pdf.Flow) float64 {
+		d := f.Doc
+		d.FillRect(0, 0, pdf.PageWidth, 64, b.Color)
+		d.Text(f.Left(), 38, pdf.Bold, 18, pdf.White, b.Name)
+		d.TextRight(f.Right(), 30, pdf.Bold, 11, pdf.White, strings.ToUpper(title))
+		d.TextRight(f.Right(), 46, pdf.Regular, 9, pdf.White, "Visita "+shortID(v.job.ID))
+		var contact []string
+		for _, part := range []string{b.Address, b.Phone, b.Email, taxID(b.TaxID)} {
+			if strings.TrimSpace(part) != "" {
+				contact = append(contact, part)
+			}
+		}
+		d.Text(f.Left(), 80, pdf.Regular, 8, pdf.Gray, strings.Join(contact, "  ·  "))
+		return 92
+	})
+	return doc, flow
+}
+
+// finishDocument numbers the pages and serializes the document.
+func (s *Service) finishDocument(doc *pdf.Document) []byte {
+	generated := "Generado el " + s.formatDateTime(time.Now().UTC())
+	for i, n := 0, doc.PageCount(); i < n; i++ {
+		doc.SetPage(i)
+		doc.Text(40, pdf.PageHeight-22, pdf.Regular, 7, pdf.Gray, generated)
+		doc.TextRight(pdf.PageWidth-40, pdf.PageHeight-22, pdf.Regular, 7, pdf.Gray, fmt.Sprintf("Página %d de %d", i+1, n))
+	}
+	return doc.Bytes()
+}
+
+func (s *Service) renderJobCard(v *visitDocument) []byte {
+	b := s.brand()
+	doc, f := s.newDocumentFlow("Orden de trabajo", v)
+	promised := ""
+	if v.job.PromisedAt != nil {
+		promised = s.formatDateTime(*v.job.PromisedAt)
+	}
+	f.Fields([]pdf.Field{
+		{Label: "Entrada", Value: s.formatDateTime(v.job.OpenedAt)},
+		{Label: "Entrega prevista", Value: promised},
+		{Label: "Estado", Value: serviceJobStatusLabel(v.job.Status)},
+	}, 3)
+	s.vehicleAndClient(f, v, b)
+
+	f.Heading("Trabajo solicitado", b.Color)
+	if v.appointment != nil {
+		f.Paragraph(strings.TrimSpace(v.appointment.ServiceType+". "+v.appointment.Notes), pdf.Regular, 10, pdf.Black)
+		f.Space(4)
+	}
+	var rows [][]string
+	for _, r := range v.repairs {
+		if r.Status != domain.RepairStatusCancelled {
+			rows = append(rows, []string{r.Description, repairStatusLabel(r.Status)})
+		}
+	}
+	if len(rows) > 0 {
+		f.Table([]pdf.Column{{Title: "Trabajo", Width: 0.75}, {Title: "Estado", Width: 0.25}}, rows, b.Color)
+	} else if v.appointment == nil {
+		// Nothing recorded yet: leave ruled lines to write on.
+		for i := 0; i < 4; i++ {
+			f.Space(20)
+			f.Doc.Line(f.Left(), f.Y(), f.Right(), f.Y(), 0.4, pdf.LightGray)
+		}
+		f.Space(6)
+	}
+
+	f.Heading("Recepción", b.Color)
+	if v.reception == nil {
+		f.Fields([]pdf.Field{{Label: "Kilometraje"}, {Label: "Nivel de aceite"}, {Label: "Refrigerante"}, {Label: "Neumáticos"}}, 4)
+	} else {
+		r := v.reception
+		f.Fields([]pdf.Field{
+			{Label: "Kilometraje", Value: fmt.Sprintf("%d km", r.OdometerKM)},
+			{Label: "Nivel de aceite", Value: r.OilLevel},
+			{Label: "Refrigerante", Value: r.CoolantLevel},
+			{Label: "Neumáticos", Value: r.TiresNote},
+		}, 4)
+		if r.GeneralNotes != "" {
+			f.Paragraph("Observaciones: "+r.GeneralNotes, pdf.Regular, 10, pdf.Black)
+		}
+		checklistTable(f, v.receptionTemplate, r.ChecklistAnswers, b.Color)
+	}
+
+	f.Space(12)
+	f.Paragraph("El cliente autoriza los trabajos indicados y la prueba del vehículo cuando sea necesaria.", pdf.Regular, 8, pdf.Gray)
+	f.Space(6)
+	var signed *domain.CustomerSignature
+	if v.reception != nil {
+		signed = v.reception.Signature
+	}
+	f.SignatureBoxes(s.signatureCaption("Firma del cliente", signed), "Recibido por "+b.Name)
+	return s.finishDocument(doc)
+}
+
+func (s *Service) renderHandover(v *visitDocument) []byte {
+	b := s.brand()
+	doc, f := s.newDocumentFlow("Informe de entrega", v)
+	h := v.handover
+	odoIn := ""
+	driven := ""
+	if v.reception != nil {
+		odoIn = fmt.Sprintf("%d km", v.reception.OdometerKM)
+		driven = fmt.Sprintf("%d km", h.OdometerKM-v.reception.OdometerKM)
+	}
+	f.Fields([]pdf.Field{
+		{Label: "Entrada", Value: s.formatDateTime(v.job.OpenedAt)},
+		{Label: "Entrega", Value: s.formatDateTime(h.RecordedAt)},
+		{Label: "Km entrada", Value: odoIn},
+		{Label: "Km salida", Value: fmt.Sprintf("%d km", h.OdometerKM)},
+		{Label: "Km recorridos en taller", Value: driven},
+	}, 5)
+	s.vehicleAndClient(f, v, b)
+
+	work, parts := handoverRows(v)
+	f.Heading("Trabajos realizados", b.Color)
+	if len(work) == 0 {
+		f.Paragraph("Sin trabajos registrados.", pdf.Regular, 10, pdf.Gray)
+	} else {
+		f.Table([]pdf.Column{{Title: "Trabajo", Width: 0.8}, {Title: "Importe", Width: 0.2, Right: true}}, work, b.Color)
+	}
+
+	f.Heading("Piezas utilizadas", b.Color)
+	if len(parts) == 0 {
+		f.Paragraph("Sin piezas registradas.", pdf.Regular, 10, pdf.Gray)
+	} else {
+		f.Table([]pdf.Column{
+			{Title: "Pieza", Width: 0.55},
+			{Title: "Cant.", Width: 0.1, Right: true},
+			{Title: "Precio", Width: 0.15, Right: true},
+			{Title: "Importe", Width: 0.2, Right: true},
+		}, parts, b.Color)
+	}
+
+	f.Heading("Entrega", b.Color)
+	f.Fields([]pdf.Field{{Label: "Neumáticos", Value: h.TiresNote}, {Label: "Observaciones", Value: h.GeneralNotes}}, 2)
+	checklistTable(f, v.handoverTemplate, h.ChecklistAnswers, b.Color)
+
+	f.Heading("Próximo servicio", b.Color)
+	next := h.RecordedAt.AddDate(0, ServiceIntervalMonths, 0)
+	f.Paragraph(fmt.Sprintf("Recomendamos el próximo mantenimiento a los %d km o el %s, lo que ocurra primero.",
+		h.OdometerKM+ServiceIntervalKM, s.formatDate(next)), pdf.Regular, 10, pdf.Black)
+	if len(v.openFindings) > 0 {
+		f.Space(6)
+		f.Paragraph("Pendiente según la inspección:", pdf.Bold, 10, pdf.Black)
+		for _, fd := range v.openFindings {
+			line := fmt.Sprintf("• [%s] %s", findingSeverityLabel(fd.Severity), fd.Description)
+			if fd.EstimatedCost != nil {
+				line += " (aprox. " + money(*fd.EstimatedCost) + ")"
+			}
+			f.Paragraph(line, pdf.Regular, 10, pdf.Black)
+		}
+	}
+
+	f.Space(12)
+	f.SignatureBoxes(s.signatureCaption("Firma del cliente — conforme con la entrega", h.Signature), "Entregado por "+b.Name)
+	return s.finishDocument(doc)
+}
+
+// handoverRows lists the completed work and the parts used with their amounts. Warranty rework is not billed:
+// it and its parts show at 0 and are labelled as warranty.
+func handoverRows(v *visitDocument) (work, parts [][]string) {
+	rework := map[uuid.UUID]bool{}
+	for _, r := range v.repairs {
+		if r.ReworkOfRepairID != nil {
+			rework[r.ID] = true
+		}
+		if r.Status != domain.RepairStatusCompleted {
+			continue
+		}
+		if rework[r.ID] {
+			work = append(work, []string{r.Description + " (Garantía)", money(0)})
+		} else {
+			work = append(work, []string{r.Description, money(r.Cost)})
+		}
+	}
+	for _, l := range v.parts {
+		if l.RepairID != nil && rework[*l.RepairID] {
+			parts = append(parts, []string{l.Description + " (Garantía)", trimFloat(l.Quantity), money(0), money(0)})
+		} else {
+			parts = append(parts, []string{l.Description, trimFloat(l.Quantity), money(l.UnitPrice), money(l.Amount())})
+		}
+	}
+	return work, parts
+}
+
+func (s *Service) vehicleAndClient(f *pdf.Flow, v *visitDocument, b Branding) {
+	f.Heading("Vehículo", b.Color)
+	year := ""
+	if v.car.Year > 0 {
+		year = fmt.Sprint(v.car.Year)
+	}
+	f.Fields([]pdf.Field{
+		{Label: "Matrícula", Value: v.car.LicensePlate},
+		{Label: "Marca y modelo", Value: strings.TrimSpace(v.car.Make + " " + v.car.Model)},
+		{Label: "Año", Value: year},
+		{Label: "Bastidor (VIN)", Value: v.car.VIN},
+	}, 4)
+	f.Heading("Cliente", b.Color)
+	if v.owner == nil {
+		f.Fields([]pdf.Field{{Label: "Nombre"}, {Label: "Teléfono"}, {Label: "Email"}}, 3)
+		return
+	}
+	f.Fields([]pdf.Field{
+		{Label: "Nombre", Value: v.owner.FullName()},
+		{Label: "Teléfono", Value: v.owner.Phone},
+		{Label: "Email", Value: v.owner.Email},
+	}, 3)
+}
+
+// checklistTable prints template-based checklist answers with the template's labels and units.
+func checklistTable(f *pdf.Flow, t *domain.ChecklistTemplate, answers []domain.ChecklistAnswer, accent pdf.Color) {
+	if len(answers) == 0 {
+		return
+	}
+	items := map[string]domain.ChecklistItem{}
+	if t != nil {
+		for _, it := range t.Items {
+			items[it.Key] = it
+		}
+	}
+	rows := make([][]string, 0, len(answers))
+	for _, a := range answers {
+		it, ok := items[a.Key]
+		label := it.Label
+		if !ok || label == "" {
+			label = a.Key
+		}
+		rows = append(rows, []string{label, checklistAnswerText(it, a)})
+	}
+	f.Space(4)
+	f.Table([]pdf.Column{{Title: "Punto de control", Width: 0.6}, {Title: "Resultado", Width: 0.4}}, rows, accent)
+}
+
+func checklistAnswerText(it domain.ChecklistItem, a domain.ChecklistAnswer) string {
+	switch {
+	case a.Bool != nil && *a.Bool:
+		return "Sí"
+	case a.Bool != nil:
+		return "No"
+	case a.Number != nil:
+		return strings.TrimSpace(trimFloat(*a.Number) + " " + it.Unit)
+	case it.Type == domain.ChecklistItemPhoto && a.Text != "":
+		return "Foto adjunta"
+	default:
+		return a.Text
+	}
+}
+
+func serviceJobStatusLabel(st domain.ServiceJobStatus) string {
+	switch st {
+	case domain.ServiceJobStatusOpen:
+		return "Abierta"
+	case domain.ServiceJobStatusInProgress:
+		return "En curso"
+	case domain.ServiceJobStatusClosed:
+		return "Entregada"
+	case domain.ServiceJobStatusCancelled:
+		return "Cancelada"
+	}
+	return string(st)
+}
+
+func repairStatusLabel(st domain.RepairStatus) string {
+	switch st {
+	case domain.RepairStatusPending:
+		return "Pendiente"
+	case domain.RepairStatusInProgress:
+		return "En curso"
+	case domain.RepairStatusCompleted:
+		return "Terminado"
+	case domain.RepairStatusCancelled:
+		return "Cancelado"
+	}
+	return string(st)
+}
+
+func findingSeverityLabel(sev domain.FindingSeverity) string {
+	if sev == domain.FindingSeverityRed {
+		return "Urgente"
+	}
+	return "A vigilar"
+}
+
+func shortID(id uuid.UUID) string {
+	return strings.ToUpper(id.String()[:8])
+}
+
+func taxID(id string) string {
+	if strings.TrimSpace(id) == "" {
+		return ""
+	}
+	return "NIF " + id
+}
+
+func money(v float64) string {
+	return fmt.Sprintf("%.2f €", v)
+}
+
+func trimFloat(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+// signatureCaption notes under the client's box who signed on the tablet, and when.
+func (s *Service) signatureCaption(caption string, sig *domain.CustomerSignature) string {
+	if sig == nil {
+		return caption
+	}
+	return fmt.Sprintf("%s — firmado digitalmente por %s el %s", caption, sig.SignerName, s.formatDateTime(sig.SignedAt))
+}
+
+func (s *Service) formatDate(t time.Time) string {
+	return t.In(s.location()).Format("02/01/2006")
+}
+
+func (s *Service) formatDateTime(t time.Time) string {
+	return t.In(s.location()).Format("02/01/2006 15:04")
+}
@@ -0,0 +1,59 @@
+package servicejob
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+func TestService_VisitPDFs(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+
+	card, err := fx.svc.JobCardPDF(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(card, []byte("%PDF-")))
+	_, err = fx.svc.JobCardPDF(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	_, err = fx.svc.HandoverPDF(ctx, fx.jobID, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrHandoverNotRecorded)
+
+	_, err = fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 42000, GeneralNotes: "Ruido al frenar"}, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.SaveHandover(ctx, fx.jobID, SaveHandoverInput{OdometerKM: 42012}, fx.emp.ID)
+	require.NoError(t, err)
+	report, err := fx.svc.HandoverPDF(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(report, []byte("%PDF-")))
+	_, err = fx.svc.HandoverPDF(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestHandoverRows_WarrantyReworkNotBilled(t *testing.T) {
+	t.Parallel()
+	original := uuid.New()
+	paid := &domain.Repair{ID: uuid.New(), Description: "Frenos", Status: domain.RepairStatusCompleted, Cost: 120}
+	rework := &domain.Repair{ID: uuid.New(), Description: "Embrague", Status: domain.RepairStatusCompleted, Cost: 80, ReworkOfRepairID: &original}
+	v := &visitDocument{
+		repairs: []*domain.Repair{paid, rework},
+		parts: []domain.EstimateLine{
+			{Description: "Pastillas", Quantity: 2, UnitPrice: 25, RepairID: &paid.ID},
+			{Description: "Disco", Quantity: 1, UnitPrice: 60, RepairID: &rework.ID},
+		},
+	}
+
+	work, parts := handoverRows(v)
+	assert.Equal(t, [][]string{{"Frenos", "120.00 €"}, {"Embrague (Garantía)", "0.00 €"}}, work)
+	assert.Equal(t, [][]string{
+		{"Pastillas", "2", "25.00 €", "50.00 €"},
+		{"Disco (Garantía)", "1", "0.00 €", "0.00 €"},
+	}, parts)
+}
@@ -0,0 +1,382 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+	"github.com/google/uuid"
+)
+
+// EstimateApprovalPurpose binds approval tokens to estimates.
+const EstimateApprovalPurpose = "estimate-approval"
+
+// EstimateLinkTTL is how long an approval link stays valid after the estimate is sent.
+const EstimateLinkTTL = 14 * 24 * time.Hour
+
+// ErrEstimatesNotConfigured is returned when estimates are drafted without an estimate repository, or sent
+// without a link signer.
+var ErrEstimatesNotConfigured = errors.New("estimates not configured")
+
+// WithEstimateRepository enables estimates and the approval gate on repairs.
+func WithEstimateRepository(repo ports.EstimateRepository) Option {
+	return func(s *Service) { s.estimateRepo = repo }
+}
+
+// WithLinkSigner enables signed estimate approval links.
+func WithLinkSigner(signer *signedlink.Signer) Option {
+	return func(s *Service) { s.signer = signer }
+}
+
+// WithNotifier messages the client: estimate approval links, promised-time changes and the car being ready.
+func WithNotifier(n services.NotificationService) Option {
+	return func(s *Service) { s.notifier = n }
+}
+
+// WithPublicBaseURL sets the frontend origin used in estimate approval links.
+func WithPublicBaseURL(base string) Option {
+	return func(s *Service) { s.publicBaseURL = strings.TrimRight(base, "/") }
+}
+
+// WithLabourOperationRepository lets estimate lines come from the labour-operation catalog.
+func WithLabourOperationRepository(repo ports.LabourOperationRepository) Option {
+	return func(s *Service) { s.opRepo = repo }
+}
+
+// EstimateApprovalLink builds the public page URL the client opens to approve an estimate.
+func EstimateApprovalLink(publicBaseURL, token string) string {
+	return strings.TrimRight(publicBaseURL, "/") + "/estimates/approve?token=" + url.QueryEscape(token)
+}
+
+// EstimateLineInput is one labour or parts line entered by staff. With OperationID the line is a catalog
+// operation instead: its flat-rate hours at UnitPrice per hour, followed by its parts kit unless WithoutParts.
+type EstimateLineInput struct {
+	Kind         domain.EstimateLineKind
+	Description  string
+	Quantity     float64
+	UnitPrice    float64
+	RepairID     *uuid.UUID
+	OperationID  *uuid.UUID
+	WithoutParts bool
+}
+
+// EstimateInput is the editable content of a draft estimate.
+type EstimateInput struct {
+	Notes string
+	Lines []EstimateLineInput
+}
+
+// EstimateDecision lists the lines the customer approves; every other line is rejected.
+type EstimateDecision struct {
+	ApprovedLineIDs []uuid.UUID
+	IP              string
+}
+
+// CreateEstimate drafts an estimate on an open or in-progress visit. Staff only.
+func (s *Service) CreateEstimate(ctx context.Context, jobID uuid.UUID, in EstimateInput, userID uuid.UUID) (*domain.Estimate, error) {
+	if s.estimateRepo == nil {
+		return nil, ErrEstimatesNotConfigured
+	}
+	j, err := s.openJobForStaff(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	e := &domain.Estimate{
+		ID:              uuid.New(),
+		ServiceJobID:    j.ID,
+		Status:          domain.EstimateStatusDraft,
+		CreatedByUserID: userID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.fillEstimate(ctx, e, in); err != nil {
+		return nil, err
+	}
+	if err := s.estimateRepo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// UpdateEstimate replaces notes and lines of a draft estimate. Staff only.
+func (s *Service) UpdateEstimate(ctx context.Context, estimateID uuid.UUID, in EstimateInput, userID uuid.UUID) (*domain.Estimate, error) {
+	e, err := s.draftEstimate(ctx, estimateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillEstimate(ctx, e, in); err != nil {
+		return nil, err
+	}
+	if err := s.estimateRepo.Save(ctx, e, domain.EstimateStatusDraft); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SendEstimate freezes a draft and notifies the client with an approval link. Staff only.
+// The returned token backs the same link, for staff to share by other means.
+func (s *Service) SendEstimate(ctx context.Context, estimateID uuid.UUID, userID uuid.UUID) (*domain.Estimate, string, error) {
+	if s.signer == nil {
+		return nil, "", ErrEstimatesNotConfigured
+	}
+	e, err := s.draftEstimate(ctx, estimateID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	e.Status = domain.EstimateStatusSent
+	e.SentAt = &now
+	if err := s.estimateRepo.Save(ctx, e, domain.EstimateStatusDraft); err != nil {
+		return nil, "", err
+	}
+	token := s.signer.Sign(EstimateApprovalPurpose, e.ID, now.Add(EstimateLinkTTL))
+	s.notifyEstimate(ctx, e, token)
+	return e, token, nil
+}
+
+// GetEstimate returns one estimate (staff, or the car's owner once it has been sent).
+func (s *Service) GetEstimate(ctx context.Context, estimateID uuid.UUID, userID uuid.UUID) (*domain.Estimate, error) {
+	u, err := s.estimateViewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.jobRepo.GetByID(ctx, e.ServiceJobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	if u.IsClient() && e.Status == domain.EstimateStatusDraft {
+		return nil, domain.ErrEstimateNotFound
+	}
+	return e, nil
+}
+
+// ListEstimates returns a visit's estimates, newest first; clients do not see drafts.
+func (s *Service) ListEstimates(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.Estimate, error) {
+	u, err := s.estimateViewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	list, err := s.estimateRepo.ListByServiceJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsClient() {
+		return list, nil
+	}
+	out := make([]*domain.Estimate, 0, len(list))
+	for _, e := range list {
+		if e.Status != domain.EstimateStatusDraft {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// DecideEstimate records the logged-in client's decision on a sent estimate. Car owner only.
+func (s *Service) DecideEstimate(ctx context.Context, estimateID uuid.UUID, d EstimateDecision, userID uuid.UUID) (*domain.Estimate, error) {
+	u, err := s.estimateViewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsClient() {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	e, err := s.GetEstimate(ctx, estimateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	by := u.ID
+	return s.decide(ctx, e, d, &by, domain.EstimateApprovedViaClient)
+}
+
+// GetEstimateByToken resolves the estimate behind an approval link (no login).
+func (s *Service) GetEstimateByToken(ctx context.Context, token string) (*domain.Estimate, error) {
+	if s.signer == nil || s.estimateRepo == nil {
+		return nil, domain.ErrEstimateLinkInvalid
+	}
+	id, err := s.signer.Verify(EstimateApprovalPurpose, token)
+	if err != nil {
+		return nil, domain.ErrEstimateLinkInvalid
+	}
+	e, err := s.estimateRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEstimateNotFound) {
+			return nil, domain.ErrEstimateLinkInvalid
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// DecideEstimateByToken records the decision made from an approval link.
+func (s *Service) DecideEstimateByToken(ctx context.Context, token string, d EstimateDecision) (*domain.Estimate, error) {
+	e, err := s.GetEstimateByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, e, d, nil, domain.EstimateApprovedViaSignedLink)
+}
+
+func (s *Service) decide(ctx context.Context, e *domain.Estimate, d EstimateDecision, by *uuid.UUID, via string) (*domain.Estimate, error) {
+	if e.Status != domain.EstimateStatusSent {
+		return nil, domain.ErrEstimateNotDecidable
+	}
+	if err := e.ApplyDecision(d.ApprovedLineIDs); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	e.DecidedAt = &now
+	e.DecidedByUserID = by
+	e.DecidedVia = via
+	e.DecisionIP = strings.TrimSpace(d.IP)
+	if err := s.estimateRepo.Save(ctx, e, domain.EstimateStatusSent); err != nil {
+		if errors.Is(err, domain.ErrEstimateStatusConflict) {
+			return nil, domain.ErrEstimateNotDecidable
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (s *Service) draftEstimate(ctx context.Context, estimateID uuid.UUID, userID uuid.UUID) (*domain.Estimate, error) {
+	if s.estimateRepo == nil {
+		return nil, domain.ErrEstimateNotFound
+	}
+	e, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.openJobForStaff(ctx, e.ServiceJobID, userID); err != nil {
+		return nil, err
+	}
+	if e.Status != domain.EstimateStatusDraft {
+		return nil, domain.ErrEstimateNotEditable
+	}
+	return e, nil
+}
+
+func (s *Service) estimateViewer(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	if s.estimateRepo == nil {
+		return nil, domain.ErrEstimateNotFound
+	}
+	return u, nil
+}
+
+// fillEstimate copies in onto e; lines tied to a repair must point at a repair of the same visit that is not
+// warranty rework (rework is never billed).
+func (s *Service) fillEstimate(ctx context.Context, e *domain.Estimate, in EstimateInput) error {
+	e.Notes = strings.TrimSpace(in.Notes)
+	e.Lines = make([]domain.EstimateLine, 0, len(in.Lines))
+	var car *domain.Car
+	add := func(kind domain.EstimateLineKind, description string, quantity, unitPrice float64, repairID *uuid.UUID) {
+		e.Lines = append(e.Lines, domain.EstimateLine{
+			ID:          uuid.New(),
+			EstimateID:  e.ID,
+			Position:    len(e.Lines) + 1,
+			Kind:        kind,
+			Description: strings.TrimSpace(description),
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			RepairID:    repairID,
+		})
+	}
+	for i, l := range in.Lines {
+		if l.RepairID != nil {
+			if s.repairRepo == nil {
+				return domain.ErrInvalidEstimate
+			}
+			rep, err := s.repairRepo.GetByID(ctx, *l.RepairID)
+			if err != nil || rep.ServiceJobID == nil || *rep.ServiceJobID != e.ServiceJobID {
+				return fmt.Errorf("%w: line %d repair is not on this visit", domain.ErrInvalidEstimate, i+1)
+			}
+			if rep.ReworkOfRepairID != nil {
+				return fmt.Errorf("%w: line %d repair is warranty rework and not billed", domain.ErrInvalidEstimate, i+1)
+			}
+		}
+		if l.OperationID == nil {
+			add(l.Kind, l.Description, l.Quantity, l.UnitPrice, l.RepairID)
+			continue
+		}
+		op, err := s.estimateOperation(ctx, e, *l.OperationID, &car)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		add(domain.EstimateLineLabour, op.LineDescription(), op.FlatRateHours, l.UnitPrice, l.RepairID)
+		if !l.WithoutParts {
+			for _, p := range op.Parts {
+				add(domain.EstimateLinePart, p.Description, p.Quantity, p.UnitPrice, l.RepairID)
+			}
+		}
+	}
+	return e.Validate()
+}
+
+// estimateOperation loads a catalog operation for an estimate line and checks it applies to the visit's car,
+// which is loaded once into *car.
+func (s *Service) estimateOperation(ctx context.Context, e *domain.Estimate, opID uuid.UUID, car **domain.Car) (*domain.LabourOperation, error) {
+	if s.opRepo == nil {
+		return nil, domain.ErrInvalidEstimate
+	}
+	op, err := s.opRepo.GetByID(ctx, opID)
+	if err != nil {
+		return nil, err
+	}
+	if *car == nil {
+		j, err := s.jobRepo.GetByID(ctx, e.ServiceJobID)
+		if err != nil {
+			return nil, err
+		}
+		if *car, err = s.carRepo.GetByID(ctx, j.CarID); err != nil {
+			return nil, err
+		}
+	}
+	if !op.AppliesTo((*car).Make, (*car).Model) {
+		return nil, domain.ErrLabourOperationNotApplicable
+	}
+	return op, nil
+}
+
+func (s *Service) notifyEstimate(ctx context.Context, e *domain.Estimate, token string) {
+	if s.notifier == nil {
+		return
+	}
+	j, err := s.jobRepo.GetByID(ctx, e.ServiceJobID)
+	if err != nil {
+		log.Printf("estimate %s: load visit: %v", e.ID, err)
+		return
+	}
+	s.notifyCarOwner(ctx, j, "Presupuesto para aprobar", "estimate-sent:"+e.ID.String(), func(car *domain.Car) string {
+		return fmt.Sprintf("Tenés un presupuesto de %.2f € para tu %s %s (%s). Revisalo y aprobalo acá: %s",
+			e.Total(), car.Make, car.Model, car.LicensePlate, EstimateApprovalLink(s.publicBaseURL, token))
+	})
+}
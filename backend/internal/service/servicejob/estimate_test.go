@@ -0,0 +1,283 @@
+package servicejob
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memEstimateRepo struct {
+	mu   sync.Mutex
+	rows map[uuid.UUID]*domain.Estimate
+}
+
+func cloneEstimate(e *domain.Estimate) *domain.Estimate {
+	cp := *e
+	cp.Lines = append([]domain.EstimateLine(nil), e.Lines...)
+	return &cp
+}
+
+func (m *memEstimateRepo) Create(_ context.Context, e *domain.Estimate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows[e.ID] = cloneEstimate(e)
+	return nil
+}
+
+func (m *memEstimateRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.Estimate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.rows[id]
+	if !ok {
+		return nil, domain.ErrEstimateNotFound
+	}
+	return cloneEstimate(e), nil
+}
+
+func (m *memEstimateRepo) ListByServiceJob(_ context.Context, jobID uuid.UUID) ([]*domain.Estimate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.Estimate{}
+	for _, e := range m.rows {
+		if e.ServiceJobID == jobID {
+			out = append(out, cloneEstimate(e))
+		}
+	}
+	return out, nil
+}
+
+func (m *memEstimateRepo) Save(_ context.Context, e *domain.Estimate, from domain.EstimateStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, ok := m.rows[e.ID]
+	if !ok {
+		return domain.ErrEstimateNotFound
+	}
+	if cur.Status != from {
+		return domain.ErrEstimateStatusConflict
+	}
+	m.rows[e.ID] = cloneEstimate(e)
+	return nil
+}
+
+func (m *memEstimateRepo) HasApprovedLine(_ context.Context, repairID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+type estimateNotifier struct {
+	reqs []services.NotificationRequest
+}
+
+func (n *estimateNotifier) SendWorkshopCreatedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *estimateNotifier) SendWorkshopUpdatedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *estimateNotifier) SendWorkshopDeletedNotification(context.Context, *domain.Workshop) error {
+	return nil
+}
+func (n *estimateNotifier) QueueNotification(_ context.Context, req services.NotificationRequest) error {
+	n.reqs = append(n.reqs, req)
+	return nil
+}
+
+type estimateFixtureT struct {
+	findingFixtureT
+	notifier *estimateNotifier
+}
+
+func estimateFixture(t *testing.T) estimateFixtureT {
+	t.Helper()
+	fx := findingFixture(t)
+	n := &estimateNotifier{}
+	WithEstimateRepository(&memEstimateRepo{rows: map[uuid.UUID]*domain.Estimate{}})(fx.svc)
+	WithLinkSigner(signedlink.New("test-secret"))(fx.svc)
+	WithNotifier(n)(fx.svc)
+	WithPublicBaseURL("https://taller.example/")(fx.svc)
+	return estimateFixtureT{findingFixtureT: fx, notifier: n}
+}
+
+func brakeInput() EstimateInput {
+	return EstimateInput{Notes: "Frenos delanteros", Lines: []EstimateLineInput{
+		{Kind: domain.EstimateLineLabour, Description: "Cambio de pastillas", Quantity: 1, UnitPrice: 45},
+		{Kind: domain.EstimateLinePart, Description: "Pastillas", Quantity: 1, UnitPrice: 60},
+	}}
+}
+
+func TestService_Estimate_NotConfigured(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.CreateEstimate(ctx, fx.jobID, brakeInput(), fx.emp.ID)
+	assert.ErrorIs(t, err, ErrEstimatesNotConfigured)
+	_, _, err = fx.svc.SendEstimate(ctx, uuid.New(), fx.emp.ID)
+	assert.ErrorIs(t, err, ErrEstimatesNotConfigured)
+}
+
+func TestService_Estimate_DraftSendApproveByClient(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.CreateEstimate(ctx, fx.jobID, brakeInput(), fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	e, err := fx.svc.CreateEstimate(ctx, fx.jobID, brakeInput(), fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EstimateStatusDraft, e.Status)
+	assert.InDelta(t, 105.0, e.Total(), 0.001)
+
+	// Drafts are invisible to the client and cannot be decided.
+	list, err := fx.svc.ListEstimates(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrEstimateNotFound)
+
+	sent, token, err := fx.svc.SendEstimate(ctx, e.ID, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EstimateStatusSent, sent.Status)
+	assert.NotEmpty(t, token)
+	require.Len(t, fx.notifier.reqs, 1)
+	assert.Equal(t, "o@t", fx.notifier.reqs[0].To)
+	assert.True(t, strings.Contains(fx.notifier.reqs[0].Message, "https://taller.example/estimates/approve?token="))
+
+	_, err = fx.svc.UpdateEstimate(ctx, e.ID, brakeInput(), fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrEstimateNotEditable)
+	_, err = fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{}, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	decided, err := fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{ApprovedLineIDs: []uuid.UUID{sent.Lines[1].ID}, IP: "203.0.113.7"}, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EstimateStatusPartiallyApproved, decided.Status)
+	require.NotNil(t, decided.DecidedByUserID)
+	assert.Equal(t, fx.owner.ID, *decided.DecidedByUserID)
+	assert.Equal(t, domain.EstimateApprovedViaClient, decided.DecidedVia)
+	assert.Equal(t, "203.0.113.7", decided.DecisionIP)
+	require.NotNil(t, decided.DecidedAt)
+
+	_, err = fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrEstimateNotDecidable)
+}
+
+func TestService_Estimate_DecideBySignedLink(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+	e, err := fx.svc.CreateEstimate(ctx, fx.jobID, brakeInput(), fx.emp.ID)
+	require.NoError(t, err)
+	_, token, err := fx.svc.SendEstimate(ctx, e.ID, fx.emp.ID)
+	require.NoError(t, err)
+
+	_, err = fx.svc.GetEstimateByToken(ctx, token+"x")
+	assert.ErrorIs(t, err, domain.ErrEstimateLinkInvalid)
+	other := signedlink.New("test-secret").Sign("appointment-rsvp", e.ID, time.Now().Add(time.Hour))
+	_, err = fx.svc.GetEstimateByToken(ctx, other)
+	assert.ErrorIs(t, err, domain.ErrEstimateLinkInvalid)
+
+	got, err := fx.svc.GetEstimateByToken(ctx, token)
+	require.NoError(t, err)
+	decided, err := fx.svc.DecideEstimateByToken(ctx, token, EstimateDecision{
+		ApprovedLineIDs: []uuid.UUID{got.Lines[0].ID, got.Lines[1].ID},
+		IP:              "198.51.100.2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.EstimateStatusApproved, decided.Status)
+	assert.Nil(t, decided.DecidedByUserID)
+	assert.Equal(t, domain.EstimateApprovedViaSignedLink, decided.DecidedVia)
+	assert.Equal(t, "198.51.100.2", decided.DecisionIP)
+
+	_, err = fx.svc.DecideEstimateByToken(ctx, token, EstimateDecision{})
+	assert.ErrorIs(t, err, domain.ErrEstimateNotDecidable)
+}
+
+type memOperationRepo map[uuid.UUID]*domain.LabourOperation
+
+func (m memOperationRepo) Create(context.Context, *domain.LabourOperation) error { return nil }
+func (m memOperationRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.LabourOperation, error) {
+	if op, ok := m[id]; ok {
+		return op, nil
+	}
+	return nil, domain.ErrLabourOperationNotFound
+}
+func (m memOperationRepo) GetByCode(context.Context, string) (*domain.LabourOperation, error) {
+	return nil, domain.ErrLabourOperationNotFound
+}
+func (m memOperationRepo) Update(context.Context, *domain.LabourOperation) error { return nil }
+func (m memOperationRepo) Delete(context.Context, uuid.UUID) error               { return nil }
+func (m memOperationRepo) List(context.Context, ports.LabourOperationListFilters) ([]*domain.LabourOperation, int64, error) {
+	return nil, 0, nil
+}
+
+func TestService_Estimate_OperationLinesFromCatalog(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+	oil := &domain.LabourOperation{ID: uuid.New(), Code: "ACE-01", Description: "Cambio de aceite", FlatRateHours: 0.5,
+		Parts: []domain.LabourOperationPart{{PartItemID: uuid.New(), Description: "Filtro", Quantity: 1, UnitPrice: 10}}}
+	clutch := &domain.LabourOperation{ID: uuid.New(), Code: "EMB-01", Description: "Embrague", FlatRateHours: 4,
+		Vehicles: []domain.LabourOperationVehicle{{Make: "Renault"}}}
+	WithLabourOperationRepository(memOperationRepo{oil.ID: oil, clutch.ID: clutch})(fx.svc)
+
+	in := EstimateInput{Lines: []EstimateLineInput{
+		{OperationID: &oil.ID, UnitPrice: 40},
+		{Kind: domain.EstimateLinePart, Description: "Escobillas", Quantity: 2, UnitPrice: 12},
+	}}
+	e, err := fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, e.Lines, 3)
+	assert.Equal(t, domain.EstimateLineLabour, e.Lines[0].Kind)
+	assert.Equal(t, "ACE-01 · Cambio de aceite", e.Lines[0].Description)
+	assert.Equal(t, 0.5, e.Lines[0].Quantity)
+	assert.Equal(t, "Filtro", e.Lines[1].Description)
+	assert.Equal(t, 3, e.Lines[2].Position)
+
+	in.Lines[0].WithoutParts = true
+	e, err = fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Len(t, e.Lines, 2)
+
+	in.Lines[0].OperationID = &clutch.ID
+	_, err = fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrLabourOperationNotApplicable)
+}
+
+func TestService_Estimate_LineRepairMustBeOnVisit(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Pastillas"}, fx.emp.ID)
+	require.NoError(t, err)
+	rep, err := fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	require.NoError(t, err)
+
+	in := brakeInput()
+	in.Lines[0].RepairID = &rep.ID
+	e, err := fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, e.Lines[0].RepairID)
+
+	stranger := uuid.New()
+	in.Lines[0].RepairID = &stranger
+	_, err = fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidEstimate)
+
+	// Warranty rework is not billed.
+	rep.ReworkOfRepairID = &stranger
+	in.Lines[0].RepairID = &rep.ID
+	_, err = fx.svc.CreateEstimate(ctx, fx.jobID, in, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidEstimate)
+}
@@ -0,0 +1,184 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrInspectionFindingsNotConfigured is returned by the finding endpoints when the service has no finding repository.
+var ErrInspectionFindingsNotConfigured = errors.New("inspection findings not configured")
+
+// ErrRepairsNotConfigured is returned by ProposeRepairFromFinding when the service has no repair repository.
+var ErrRepairsNotConfigured = errors.New("repairs not configured")
+
+// WithInspectionFindingRepository enables digital vehicle inspection (DVI) findings on visits.
+func WithInspectionFindingRepository(repo ports.InspectionFindingRepository) Option {
+	return func(s *Service) { s.findingRepo = repo }
+}
+
+// FindingInput is what a technician records for one finding.
+type FindingInput struct {
+	Severity      domain.FindingSeverity
+	Description   string
+	PhotoURL      string
+	EstimatedCost *float64
+}
+
+// AddFinding records a finding on an open or in-progress visit. Staff only.
+func (s *Service) AddFinding(ctx context.Context, jobID uuid.UUID, in FindingInput, userID uuid.UUID) (*domain.InspectionFinding, error) {
+	if _, err := s.findingJob(ctx, jobID, userID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	f := &domain.InspectionFinding{
+		ID:               uuid.New(),
+		ServiceJobID:     jobID,
+		Severity:         in.Severity,
+		Description:      strings.TrimSpace(in.Description),
+		PhotoURL:         strings.TrimSpace(in.PhotoURL),
+		EstimatedCost:    in.EstimatedCost,
+		RecordedByUserID: userID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.findingRepo.Create(ctx, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// UpdateFinding edits a finding that has not been turned into a repair yet. Staff only.
+func (s *Service) UpdateFinding(ctx context.Context, jobID, findingID uuid.UUID, in FindingInput, userID uuid.UUID) (*domain.InspectionFinding, error) {
+	f, err := s.editableFinding(ctx, jobID, findingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	f.Severity = in.Severity
+	f.Description = strings.TrimSpace(in.Description)
+	f.PhotoURL = strings.TrimSpace(in.PhotoURL)
+	f.EstimatedCost = in.EstimatedCost
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.findingRepo.Update(ctx, f); err != nil {
+		return nil, err
+	}
+	f.UpdatedAt = time.Now().UTC()
+	return f, nil
+}
+
+// DeleteFinding removes a finding that has not been turned into a repair yet. Staff only.
+func (s *Service) DeleteFinding(ctx context.Context, jobID, findingID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.editableFinding(ctx, jobID, findingID, userID); err != nil {
+		return err
+	}
+	return s.findingRepo.Delete(ctx, findingID)
+}
+
+// ListFindings returns a visit's findings, most severe first (staff, or the client who owns the car).
+func (s *Service) ListFindings(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.InspectionFinding, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	if s.findingRepo == nil {
+		return []*domain.InspectionFinding{}, nil
+	}
+	return s.findingRepo.ListByServiceJob(ctx, jobID)
+}
+
+// ProposeRepairFromFinding creates a pending Repair on the visit's car from the finding (description and
+// estimated cost), assigned to the calling technician, and links it back. A finding yields one repair:
+// the link is claimed first so two clicks cannot create two repairs. Staff only.
+func (s *Service) ProposeRepairFromFinding(ctx context.Context, jobID, findingID uuid.UUID, userID uuid.UUID) (*domain.Repair, error) {
+	if s.repairRepo == nil {
+		return nil, ErrRepairsNotConfigured
+	}
+	j, err := s.findingJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.findingRepo.GetByID(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if f.ServiceJobID != jobID {
+		return nil, domain.ErrInspectionFindingNotFound
+	}
+	if f.RepairID != nil {
+		return nil, domain.ErrFindingAlreadyConverted
+	}
+	now := time.Now().UTC()
+	visit := j.ID
+	rep := &domain.Repair{
+		ID:           uuid.New(),
+		CarID:        j.CarID,
+		TechnicianID: userID,
+		ServiceJobID: &visit,
+		Description:  fmt.Sprintf("[%s] %s", f.Severity, f.Description),
+		Status:       domain.RepairStatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if f.EstimatedCost != nil {
+		rep.Cost = *f.EstimatedCost
+	}
+	linked, err := s.findingRepo.SetRepair(ctx, findingID, &rep.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, domain.ErrFindingAlreadyConverted
+	}
+	if err := s.repairRepo.Create(ctx, rep); err != nil {
+		_, _ = s.findingRepo.SetRepair(ctx, findingID, nil, false) // release the claim so the call can be retried
+		return nil, err
+	}
+	s.publishChange(j.ID, j.Status, domain.ServiceJobChangeRepairs)
+	return rep, nil
+}
+
+// findingJob loads a visit that still accepts findings, for a staff caller.
+func (s *Service) findingJob(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*domain.ServiceJob, error) {
+	if s.findingRepo == nil {
+		return nil, ErrInspectionFindingsNotConfigured
+	}
+	return s.openJobForStaff(ctx, jobID, userID)
+}
+
+func (s *Service) editableFinding(ctx context.Context, jobID, findingID uuid.UUID, userID uuid.UUID) (*domain.InspectionFinding, error) {
+	if _, err := s.findingJob(ctx, jobID, userID); err != nil {
+		return nil, err
+	}
+	f, err := s.findingRepo.GetByID(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if f.ServiceJobID != jobID {
+		return nil, domain.ErrInspectionFindingNotFound
+	}
+	if f.RepairID != nil {
+		return nil, domain.ErrFindingAlreadyConverted
+	}
+	return f, nil
+}
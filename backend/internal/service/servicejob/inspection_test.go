@@ -0,0 +1,302 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memFindingRepo struct {
+	mu   sync.Mutex
+	rows map[uuid.UUID]*domain.InspectionFinding
+}
+
+func (m *memFindingRepo) Create(_ context.Context, f *domain.InspectionFinding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *f
+	m.rows[f.ID] = &cp
+	return nil
+}
+
+func (m *memFindingRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.InspectionFinding, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.rows[id]
+	if !ok {
+		return nil, domain.ErrInspectionFindingNotFound
+	}
+	cp := *f
+	return &cp, nil
+}
+
+func (m *memFindingRepo) ListByServiceJob(_ context.Context, jobID uuid.UUID) ([]*domain.InspectionFinding, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.InspectionFinding{}
+	for _, f := range m.rows {
+		if f.ServiceJobID == jobID {
+			cp := *f
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memFindingRepo) Update(_ context.Context, f *domain.InspectionFinding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rows[f.ID]; !ok {
+		return domain.ErrInspectionFindingNotFound
+	}
+	cp := *f
+	m.rows[f.ID] = &cp
+	return nil
+}
+
+func (m *memFindingRepo) Delete(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rows[id]; !ok {
+		return domain.ErrInspectionFindingNotFound
+	}
+	delete(m.rows, id)
+	return nil
+}
+
+func (m *memFindingRepo) SetRepair(_ context.Context, id uuid.UUID, repairID *uuid.UUID, onlyIfUnset bool) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.rows[id]
+	if !ok || (onlyIfUnset && f.RepairID != nil) {
+		return false, nil
+	}
+	f.RepairID = repairID
+	return true, nil
+}
+
+type memRepairRepo struct {
+	mu      sync.Mutex
+	created []*domain.Repair
+	failErr error
+}
+
+func (m *memRepairRepo) Create(_ context.Context, r *domain.Repair) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failErr != nil {
+		return m.failErr
+	}
+	m.created = append(m.created, r)
+	return nil
+}
+
+func (m *memRepairRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.Repair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.created {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, domain.ErrRepairNotFound
+}
+func (m *memRepairRepo) Update(context.Context, *domain.Repair) error { return nil }
+func (m *memRepairRepo) Delete(context.Context, uuid.UUID) error      { return nil }
+func (m *memRepairRepo) GetByCarID(context.Context, uuid.UUID) ([]*domain.Repair, error) {
+	return nil, nil
+}
+func (m *memRepairRepo) ListIDsByServiceJobID(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+func (m *memRepairRepo) ListByServiceJobIDs(_ context.Context, ids []uuid.UUID) ([]*domain.Repair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.Repair{}
+	for _, r := range m.created {
+		for _, id := range ids {
+			if r.ServiceJobID != nil && *r.ServiceJobID == id {
+				out = append(out, r)
+			}
+		}
+	}
+	return out, nil
+}
+func (m *memRepairRepo) ChangeStatus(context.Context, *domain.Repair, *domain.RepairStatusEvent) error {
+	return nil
+}
+func (m *memRepairRepo) ListStatusEvents(context.Context, uuid.UUID) ([]*domain.RepairStatusEvent, error) {
+	return nil, nil
+}
+func (m *memRepairRepo) ListByTechnician(context.Context, uuid.UUID, domain.RepairStatus, int, int) ([]*domain.Repair, error) {
+	return nil, nil
+}
+func (m *memRepairRepo) List(context.Context, *ports.RepairFilters) ([]*domain.Repair, int64, error) {
+	return nil, 0, nil
+}
+
+type findingFixtureT struct {
+	svc     *Service
+	jobs    *stubJobRepo
+	repairs *memRepairRepo
+	emp     *domain.User
+	owner   *domain.User
+	other   *domain.User
+	jobID   uuid.UUID
+	carID   uuid.UUID
+}
+
+func findingFixture(t *testing.T) findingFixtureT {
+	t.Helper()
+	emp, _ := domain.NewUser("e@t", "p", "E", "E", domain.RoleEmployee)
+	emp.ID = uuid.New()
+	owner, _ := domain.NewUser("o@t", "p", "O", "O", domain.RoleClient)
+	owner.ID = uuid.New()
+	other, _ := domain.NewUser("x@t", "p", "X", "X", domain.RoleClient)
+	other.ID = uuid.New()
+	jobID, carID := uuid.New(), uuid.New()
+	j := &domain.ServiceJob{ID: jobID, CarID: carID, Status: domain.ServiceJobStatusInProgress, OpenedByUserID: emp.ID, OpenedAt: time.Now().UTC()}
+	jobs := &stubJobRepo{byID: map[uuid.UUID]*domain.ServiceJob{jobID: j}}
+	repairs := &memRepairRepo{}
+	s := NewService(jobs, tCar{carID: {ID: carID, OwnerID: owner.ID}}, tUser{emp.ID: emp, owner.ID: owner, other.ID: other}, repairs,
+		WithInspectionFindingRepository(&memFindingRepo{rows: map[uuid.UUID]*domain.InspectionFinding{}}))
+	return findingFixtureT{svc: s, jobs: jobs, repairs: repairs, emp: emp, owner: owner, other: other, jobID: jobID, carID: carID}
+}
+
+func TestService_AddFinding_ValidationAndAccess(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Frenos"}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: "purple", Description: "Frenos"}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidInspectionFinding)
+	_, err = fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityAmber, Description: "  "}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidInspectionFinding)
+	neg := -1.0
+	_, err = fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityAmber, Description: "x", EstimatedCost: &neg}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidInspectionFinding)
+
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityGreen, Description: " Neumáticos OK ", PhotoURL: "photos/1.jpg"}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Neumáticos OK", f.Description)
+	assert.Equal(t, fx.emp.ID, f.RecordedByUserID)
+
+	fx.jobs.byID[fx.jobID].Status = domain.ServiceJobStatusClosed
+	_, err = fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Tarde"}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidServiceJobData)
+}
+
+func TestService_Findings_NotConfigured(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	s := NewService(fx.jobs, tCar{fx.carID: {ID: fx.carID, OwnerID: fx.owner.ID}}, tUser{fx.emp.ID: fx.emp}, nil)
+
+	_, err := s.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Frenos"}, fx.emp.ID)
+	assert.ErrorIs(t, err, ErrInspectionFindingsNotConfigured)
+	_, err = s.ProposeRepairFromFinding(ctx, fx.jobID, uuid.New(), fx.emp.ID)
+	assert.ErrorIs(t, err, ErrRepairsNotConfigured)
+}
+
+func TestService_ListFindings_OwnerOnly(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	_, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Frenos"}, fx.emp.ID)
+	require.NoError(t, err)
+
+	list, err := fx.svc.ListFindings(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	_, err = fx.svc.ListFindings(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestService_ProposeRepairFromFinding(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	cost := 180.0
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Pastillas delanteras", EstimatedCost: &cost}, fx.emp.ID)
+	require.NoError(t, err)
+
+	rep, err := fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fx.carID, rep.CarID)
+	assert.Equal(t, fx.emp.ID, rep.TechnicianID)
+	require.NotNil(t, rep.ServiceJobID)
+	assert.Equal(t, fx.jobID, *rep.ServiceJobID)
+	assert.Equal(t, domain.RepairStatusPending, rep.Status)
+	assert.Equal(t, 180.0, rep.Cost)
+	assert.Contains(t, rep.Description, "Pastillas delanteras")
+
+	list, err := fx.svc.ListFindings(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	require.NotNil(t, list[0].RepairID)
+	assert.Equal(t, rep.ID, *list[0].RepairID)
+
+	_, err = fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrFindingAlreadyConverted)
+	_, err = fx.svc.UpdateFinding(ctx, fx.jobID, f.ID, FindingInput{Severity: domain.FindingSeverityAmber, Description: "x"}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrFindingAlreadyConverted)
+	assert.ErrorIs(t, fx.svc.DeleteFinding(ctx, fx.jobID, f.ID, fx.emp.ID), domain.ErrFindingAlreadyConverted)
+	assert.Len(t, fx.repairs.created, 1)
+}
+
+func TestService_ProposeRepairFromFinding_ConcurrentOnce(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityAmber, Description: "Escobillas"}, fx.emp.ID)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, fx.repairs.created, 1)
+}
+
+func TestService_ProposeRepairFromFinding_ReleasesOnFailure(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Fuga"}, fx.emp.ID)
+	require.NoError(t, err)
+
+	fx.repairs.failErr = errors.New("db down")
+	_, err = fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	require.Error(t, err)
+
+	fx.repairs.failErr = nil
+	_, err = fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	require.NoError(t, err)
+}
+
+func TestService_Finding_WrongJob(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Fuga"}, fx.emp.ID)
+	require.NoError(t, err)
+
+	otherJob := uuid.New()
+	fx.jobs.byID[otherJob] = &domain.ServiceJob{ID: otherJob, CarID: fx.carID, Status: domain.ServiceJobStatusOpen}
+	_, err = fx.svc.ProposeRepairFromFinding(ctx, otherJob, f.ID, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInspectionFindingNotFound)
+}
@@ -0,0 +1,275 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrMessagingNotConfigured is returned by the thread endpoints when the service has no message repository,
+// or when a message carries attachments and no file storage is wired.
+var ErrMessagingNotConfigured = errors.New("visit messaging not configured")
+
+// Message limits: body length in characters, attachments per message and bytes per attachment.
+const (
+	MaxMessageLength          = 4000
+	MaxMessageAttachments     = 5
+	MaxMessageAttachmentBytes = 10 << 20
+)
+
+// messageContentTypes are the attachment types clients and staff can exchange: photos and documents.
+var messageContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"image/gif":       true,
+	"application/pdf": true,
+}
+
+// WithMessageRepository enables the message thread of each visit.
+func WithMessageRepository(repo ports.ServiceJobMessageRepository) Option {
+	return func(s *Service) { s.messageRepo = repo }
+}
+
+// MessageInput is a new message; it needs a body, attachments, or both.
+type MessageInput struct {
+	Body        string
+	Attachments []MessageAttachmentInput
+}
+
+// MessageAttachmentInput is an uploaded file; its type is sniffed from the content, not trusted from the client.
+type MessageAttachmentInput struct {
+	FileName string
+	Data     []byte
+}
+
+// PostMessage adds a message to the visit's thread (staff, or the client who owns the car) and tells the
+// other side. The thread stays open after the visit closes, for questions about the work done.
+func (s *Service) PostMessage(ctx context.Context, jobID uuid.UUID, in MessageInput, userID uuid.UUID) (*domain.ServiceJobMessage, error) {
+	if s.messageRepo == nil {
+		return nil, ErrMessagingNotConfigured
+	}
+	u, j, err := s.threadAccess(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(in.Body)
+	if (body == "" && len(in.Attachments) == 0) || utf8.RuneCountInString(body) > MaxMessageLength || len(in.Attachments) > MaxMessageAttachments {
+		return nil, domain.ErrInvalidMessage
+	}
+	if len(in.Attachments) > 0 && s.files == nil {
+		return nil, ErrMessagingNotConfigured
+	}
+	now := time.Now().UTC()
+	m := &domain.ServiceJobMessage{
+		ID:           uuid.New(),
+		ServiceJobID: j.ID,
+		SenderUserID: u.ID,
+		SenderName:   strings.TrimSpace(u.FullName()),
+		SenderSide:   messageSide(u),
+		Body:         body,
+		CreatedAt:    now,
+		Attachments:  []domain.ServiceJobMessageAttachment{},
+	}
+	for _, a := range in.Attachments {
+		att, err := messageAttachment(m.ID, a)
+		if err != nil {
+			return nil, err
+		}
+		m.Attachments = append(m.Attachments, att)
+	}
+	// Validate every attachment before storing any, so a bad file leaves nothing behind.
+	for i := range m.Attachments {
+		a := &m.Attachments[i]
+		err := s.files.UploadFile(ctx, &domain.File{ID: a.FileID, Name: a.FileName, ContentType: a.ContentType, Data: in.Attachments[i].Data, CreatedAt: now})
+		if err != nil {
+			s.discardAttachments(ctx, m.Attachments[:i])
+			return nil, fmt.Errorf("store attachment: %w", err)
+		}
+	}
+	if err := s.messageRepo.Create(ctx, m); err != nil {
+		s.discardAttachments(ctx, m.Attachments)
+		return nil, err
+	}
+	if m.SenderSide == domain.MessageSideClient {
+		s.publishChange(j.ID, j.Status, domain.ServiceJobChangeMessage)
+	}
+	s.notifyMessage(ctx, j, m)
+	return m, nil
+}
+
+// ListMessages returns the visit's thread, oldest first (staff, or the client who owns the car).
+func (s *Service) ListMessages(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.ServiceJobMessage, error) {
+	if s.messageRepo == nil {
+		return nil, ErrMessagingNotConfigured
+	}
+	if _, _, err := s.threadAccess(ctx, jobID, userID); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.ListByServiceJob(ctx, jobID)
+}
+
+// MarkMessagesRead records that the caller has seen the other side's messages so far; it returns how many
+// messages were newly marked.
+func (s *Service) MarkMessagesRead(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (int64, error) {
+	if s.messageRepo == nil {
+		return 0, ErrMessagingNotConfigured
+	}
+	u, j, err := s.threadAccess(ctx, jobID, userID)
+	if err != nil {
+		return 0, err
+	}
+	other := domain.MessageSideClient
+	if messageSide(u) == domain.MessageSideClient {
+		other = domain.MessageSideStaff
+	}
+	return s.messageRepo.MarkRead(ctx, j.ID, other, u.ID, time.Now().UTC())
+}
+
+// MessageAttachment returns an attachment of the visit's thread with its content.
+func (s *Service) MessageAttachment(ctx context.Context, jobID, attachmentID uuid.UUID, userID uuid.UUID) (*domain.ServiceJobMessageAttachment, []byte, error) {
+	if s.messageRepo == nil || s.files == nil {
+		return nil, nil, ErrMessagingNotConfigured
+	}
+	if _, _, err := s.threadAccess(ctx, jobID, userID); err != nil {
+		return nil, nil, err
+	}
+	a, err := s.messageRepo.GetAttachment(ctx, jobID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := s.files.DownloadFile(ctx, a.FileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load attachment: %w", err)
+	}
+	return a, f.Data, nil
+}
+
+func (s *Service) threadAccess(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*domain.User, *domain.ServiceJob, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil || (!u.IsEmployee() && !u.IsClient()) {
+		return nil, nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, nil, err
+	}
+	return u, j, nil
+}
+
+func messageSide(u *domain.User) domain.MessageSide {
+	if u.IsEmployee() {
+		return domain.MessageSideStaff
+	}
+	return domain.MessageSideClient
+}
+
+func messageAttachment(messageID uuid.UUID, in MessageAttachmentInput) (domain.ServiceJobMessageAttachment, error) {
+	if len(in.Data) == 0 || len(in.Data) > MaxMessageAttachmentBytes {
+		return domain.ServiceJobMessageAttachment{}, domain.ErrInvalidMessage
+	}
+	contentType := http.DetectContentType(in.Data)
+	if !messageContentTypes[contentType] {
+		return domain.ServiceJobMessageAttachment{}, domain.ErrInvalidMessage
+	}
+	name := strings.TrimSpace(filepath.Base(strings.ReplaceAll(in.FileName, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "adjunto"
+	}
+	name = strings.ToValidUTF8(name, "")
+	if len(name) > 200 {
+		// Keep the tail (the extension), cut on a character boundary.
+		cut := len(name) - 200
+		for cut < len(name) && !utf8.RuneStart(name[cut]) {
+			cut++
+		}
+		name = name[cut:]
+	}
+	id := uuid.New()
+	return domain.ServiceJobMessageAttachment{
+		ID:          id,
+		MessageID:   messageID,
+		FileID:      fmt.Sprintf("message-%s-%s%s", messageID, id, strings.ToLower(filepath.Ext(name))),
+		FileName:    name,
+		ContentType: contentType,
+		Size:        int64(len(in.Data)),
+	}, nil
+}
+
+func (s *Service) discardAttachments(ctx context.Context, atts []domain.ServiceJobMessageAttachment) {
+	for _, a := range atts {
+		if err := s.files.DeleteFile(ctx, a.FileID); err != nil {
+			log.Printf("discard message attachment %s: %v", a.FileID, err)
+		}
+	}
+}
+
+// notifyMessage tells the other side a message arrived: staff messages go to the car owner; client messages
+// to the staff already on the thread, or to whoever opened the visit.
+func (s *Service) notifyMessage(ctx context.Context, j *domain.ServiceJob, m *domain.ServiceJobMessage) {
+	preview := m.Body
+	if utf8.RuneCountInString(preview) > 280 {
+		preview = string([]rune(preview)[:280]) + "…"
+	}
+	if preview == "" {
+		preview = fmt.Sprintf("(%d adjunto(s))", len(m.Attachments))
+	}
+	if m.SenderSide == domain.MessageSideStaff {
+		s.notifyCarOwner(ctx, j, "Nuevo mensaje del taller", "message:"+m.ID.String(), func(car *domain.Car) string {
+			return fmt.Sprintf("%s te escribió sobre tu %s %s (%s): %s", s.brand().Name, car.Make, car.Model, car.LicensePlate, preview)
+		})
+		return
+	}
+	if s.notifier == nil {
+		return
+	}
+	thread, err := s.messageRepo.ListByServiceJob(ctx, j.ID)
+	if err != nil {
+		log.Printf("service job %s: list messages: %v", j.ID, err)
+		return
+	}
+	recipients := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, msg := range thread {
+		if msg.SenderSide == domain.MessageSideStaff && !seen[msg.SenderUserID] {
+			seen[msg.SenderUserID] = true
+			recipients = append(recipients, msg.SenderUserID)
+		}
+	}
+	if len(recipients) == 0 {
+		recipients = append(recipients, j.OpenedByUserID)
+	}
+	for _, id := range recipients {
+		staff, err := s.userRepo.GetByID(ctx, id)
+		if err != nil || staff == nil || !staff.IsEmployee() || strings.TrimSpace(staff.Email) == "" {
+			continue
+		}
+		err = s.notifier.QueueNotification(ctx, services.NotificationRequest{
+			Type:      domain.NotificationChannelEmail,
+			To:        strings.TrimSpace(staff.Email),
+			Subject:   "Mensaje de cliente en la visita " + j.ID.String()[:8],
+			Message:   fmt.Sprintf("%s escribió: %s", m.SenderName, preview),
+			DedupeKey: "message:" + m.ID.String() + ":" + id.String(),
+		})
+		if err != nil {
+			log.Printf("service job %s: queue notification: %v", j.ID, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package servicejob
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type memMessageRepo struct {
+	mu   sync.Mutex
+	rows []*domain.ServiceJobMessage
+}
+
+func (m *memMessageRepo) Create(_ context.Context, msg *domain.ServiceJobMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *msg
+	m.rows = append(m.rows, &cp)
+	return nil
+}
+
+func (m *memMessageRepo) ListByServiceJob(_ context.Context, jobID uuid.UUID) ([]*domain.ServiceJobMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.ServiceJobMessage{}
+	for _, msg := range m.rows {
+		if msg.ServiceJobID == jobID {
+			cp := *msg
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memMessageRepo) MarkRead(_ context.Context, jobID uuid.UUID, sentBy domain.MessageSide, readerID uuid.UUID, at time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for _, msg := range m.rows {
+		if msg.ServiceJobID == jobID && msg.SenderSide == sentBy && msg.ReadAt == nil {
+			msg.ReadAt, msg.ReadByUserID = &at, &readerID
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memMessageRepo) GetAttachment(_ context.Context, jobID, id uuid.UUID) (*domain.ServiceJobMessageAttachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, msg := range m.rows {
+		for _, a := range msg.Attachments {
+			if a.ID == id && msg.ServiceJobID == jobID {
+				cp := a
+				return &cp, nil
+			}
+		}
+	}
+	return nil, domain.ErrMessageAttachmentNotFound
+}
+
+var jpegBytes = append([]byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}, make([]byte, 32)...)
+
+func TestService_Messages_ThreadNotifiesOtherSide(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+	files := &memFiles{}
+	_, err := fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "hola"}, fx.owner.ID)
+	assert.ErrorIs(t, err, ErrMessagingNotConfigured)
+	WithMessageRepository(&memMessageRepo{})(fx.svc)
+
+	_, err = fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "hola"}, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "  "}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	_, err = fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Attachments: []MessageAttachmentInput{{FileName: "a.jpg", Data: jpegBytes}}}, fx.owner.ID)
+	assert.ErrorIs(t, err, ErrMessagingNotConfigured)
+	WithFileStorage(files)(fx.svc)
+	_, err = fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Attachments: []MessageAttachmentInput{{FileName: "x.exe", Data: []byte("MZ\x90\x00")}}}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	assert.Empty(t, files.byID)
+
+	// The client writes first: nobody from staff is on the thread yet, so whoever opened the visit hears of it.
+	q, err := fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "¿Cuándo está?", Attachments: []MessageAttachmentInput{{FileName: "../ruido.jpg", Data: jpegBytes}}}, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageSideClient, q.SenderSide)
+	require.Len(t, q.Attachments, 1)
+	assert.Equal(t, "ruido.jpg", q.Attachments[0].FileName)
+	assert.Equal(t, "image/jpeg", q.Attachments[0].ContentType)
+	require.Len(t, fx.notifier.reqs, 1)
+	assert.Equal(t, "e@t", fx.notifier.reqs[0].To)
+
+	reply, err := fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "Mañana a las 10"}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MessageSideStaff, reply.SenderSide)
+	require.Len(t, fx.notifier.reqs, 2)
+	assert.Equal(t, "o@t", fx.notifier.reqs[1].To)
+	assert.Contains(t, fx.notifier.reqs[1].Message, "Mañana a las 10")
+
+	// Read receipts: each side marks the other side's messages.
+	n, err := fx.svc.MarkMessagesRead(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	thread, err := fx.svc.ListMessages(ctx, fx.jobID, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	assert.Nil(t, thread[0].ReadAt)
+	require.NotNil(t, thread[1].ReadAt)
+	assert.Equal(t, fx.owner.ID, *thread[1].ReadByUserID)
+
+	a, data, err := fx.svc.MessageAttachment(ctx, fx.jobID, q.Attachments[0].ID, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ruido.jpg", a.FileName)
+	assert.Equal(t, jpegBytes, data)
+	_, _, err = fx.svc.MessageAttachment(ctx, fx.jobID, q.Attachments[0].ID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	// The thread stays with the visit after it closes.
+	fx.jobs.byID[fx.jobID].Status = domain.ServiceJobStatusClosed
+	_, err = fx.svc.PostMessage(ctx, fx.jobID, MessageInput{Body: "Gracias"}, fx.owner.ID)
+	require.NoError(t, err)
+	require.Len(t, fx.notifier.reqs, 3)
+	assert.Equal(t, "e@t", fx.notifier.reqs[2].To)
+}
+
+func TestMessageAttachment_LongNameCutOnCharacterBoundary(t *testing.T) {
+	t.Parallel()
+	name := strings.Repeat("ñ", 150) + ".jpeg" // 305 bytes: a plain byte cut would split an ñ
+	a, err := messageAttachment(uuid.New(), MessageAttachmentInput{FileName: name, Data: jpegBytes})
+	require.NoError(t, err)
+	assert.True(t, utf8.ValidString(a.FileName))
+	assert.LessOrEqual(t, len(a.FileName), 200)
+	assert.True(t, strings.HasSuffix(a.FileName, ".jpeg"))
+}
@@ -0,0 +1,103 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/obd"
+	"github.com/google/uuid"
+)
+
+// MaxOBDLogSize bounds an uploaded scanner session; ELM327 logs of a full session are a few KiB.
+const MaxOBDLogSize = 1 << 20
+
+// ErrOBDSessionsNotConfigured is returned by UploadOBDSession when the service has no OBD session repository.
+var ErrOBDSessionsNotConfigured = errors.New("obd sessions not configured")
+
+// WithOBDSessionRepository enables OBD-II scanner session uploads on visits.
+func WithOBDSessionRepository(repo ports.OBDSessionRepository) Option {
+	return func(s *Service) { s.obdRepo = repo }
+}
+
+// UploadOBDSession parses an ELM327 log or CSV export and stores it, with the codes, freeze frames
+// and PIDs read from it, on an open or in-progress visit. Staff only.
+func (s *Service) UploadOBDSession(ctx context.Context, jobID uuid.UUID, fileName string, data []byte, userID uuid.UUID) (*domain.OBDSession, error) {
+	if s.obdRepo == nil {
+		return nil, ErrOBDSessionsNotConfigured
+	}
+	if len(data) > MaxOBDLogSize {
+		return nil, domain.ErrOBDLogTooLarge
+	}
+	if _, err := s.openJobForStaff(ctx, jobID, userID); err != nil {
+		return nil, err
+	}
+	rep, err := obd.Parse(data)
+	if err != nil {
+		if errors.Is(err, obd.ErrEmptyLog) || errors.Is(err, obd.ErrUnrecognisedLog) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidOBDLog, err)
+		}
+		return nil, err
+	}
+	sess := &domain.OBDSession{
+		ID:               uuid.New(),
+		ServiceJobID:     jobID,
+		FileName:         filepath.Base(strings.TrimSpace(fileName)),
+		Format:           rep.Format,
+		MILOn:            rep.MILOn,
+		TroubleCodes:     make([]domain.OBDTroubleCode, 0, len(rep.DTCs)),
+		FreezeFrames:     make([]domain.OBDFreezeFrame, 0, len(rep.FreezeFrames)),
+		Readings:         obdReadings(rep.Readings),
+		RawLog:           string(data),
+		UploadedByUserID: userID,
+		CreatedAt:        time.Now().UTC(),
+	}
+	for _, d := range rep.DTCs {
+		sess.TroubleCodes = append(sess.TroubleCodes, domain.OBDTroubleCode{Code: d.Code, Status: d.Status, Description: d.Description})
+	}
+	for _, f := range rep.FreezeFrames {
+		sess.FreezeFrames = append(sess.FreezeFrames, domain.OBDFreezeFrame{Frame: f.Frame, DTC: f.DTC, Readings: obdReadings(f.Readings)})
+	}
+	if sess.FileName == "." {
+		sess.FileName = ""
+	}
+	if err := s.obdRepo.Create(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// ListOBDSessions returns a visit's scanner sessions, oldest first (staff, or the client who owns the car).
+func (s *Service) ListOBDSessions(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.OBDSession, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	if s.obdRepo == nil {
+		return []*domain.OBDSession{}, nil
+	}
+	return s.obdRepo.ListByServiceJob(ctx, jobID)
+}
+
+func obdReadings(in []obd.Reading) []domain.OBDReading {
+	out := make([]domain.OBDReading, 0, len(in))
+	for _, r := range in {
+		out = append(out, domain.OBDReading{PID: r.PID, Name: r.Name, Value: r.Value, Unit: r.Unit})
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package servicejob
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memOBDRepo struct {
+	mu   sync.Mutex
+	rows []*domain.OBDSession
+}
+
+func (m *memOBDRepo) Create(_ context.Context, s *domain.OBDSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *s
+	m.rows = append(m.rows, &cp)
+	return nil
+}
+
+func (m *memOBDRepo) ListByServiceJob(_ context.Context, jobID uuid.UUID) ([]*domain.OBDSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.OBDSession{}
+	for _, s := range m.rows {
+		if s.ServiceJobID == jobID {
+			cp := *s
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+const scannerLog = `>0101
+41 01 82 07 65 04
+>03
+43 01 71 03 00 00 00
+>07
+47 04 20 00 00 00 00
+>04
+44
+>0105
+41 05 7B
+`
+
+func TestService_UploadOBDSession_StoresParsedCodes(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	_, err := fx.svc.UploadOBDSession(ctx, fx.jobID, "scan.txt", []byte(scannerLog), fx.emp.ID)
+	assert.ErrorIs(t, err, ErrOBDSessionsNotConfigured)
+	WithOBDSessionRepository(&memOBDRepo{})(fx.svc)
+
+	_, err = fx.svc.UploadOBDSession(ctx, fx.jobID, "scan.txt", []byte(scannerLog), fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.UploadOBDSession(ctx, fx.jobID, "scan.txt", []byte("ATZ\nOK\n"), fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidOBDLog)
+	_, err = fx.svc.UploadOBDSession(ctx, fx.jobID, "scan.txt", []byte(strings.Repeat("4", MaxOBDLogSize+1)), fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrOBDLogTooLarge)
+
+	sess, err := fx.svc.UploadOBDSession(ctx, fx.jobID, "../logs/scan.txt", []byte(scannerLog), fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "scan.txt", sess.FileName)
+	assert.Equal(t, "elm327", sess.Format)
+	require.NotNil(t, sess.MILOn)
+	assert.True(t, *sess.MILOn)
+	assert.Equal(t, []domain.OBDTroubleCode{
+		{Code: "P0171", Status: "cleared", Description: "System Too Lean (Bank 1)"},
+		{Code: "P0300", Status: "cleared", Description: "Random/Multiple Cylinder Misfire Detected"},
+		{Code: "P0420", Status: "cleared", Description: "Catalyst System Efficiency Below Threshold (Bank 1)"},
+	}, sess.TroubleCodes)
+	assert.Equal(t, []domain.OBDReading{{PID: "05", Name: "Engine coolant temperature", Value: 83, Unit: "°C"}}, sess.Readings)
+	assert.Equal(t, scannerLog, sess.RawLog)
+
+	list, err := fx.svc.ListOBDSessions(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	_, err = fx.svc.ListOBDSessions(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	fx.jobs.byID[fx.jobID].Status = domain.ServiceJobStatusClosed
+	_, err = fx.svc.UploadOBDSession(ctx, fx.jobID, "late.txt", []byte(scannerLog), fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidServiceJobData)
+}
@@ -0,0 +1,170 @@
+package servicejob
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultPromiseAtRiskWindow is how long before the promised time an active visit is flagged at risk.
+const DefaultPromiseAtRiskWindow = 2 * time.Hour
+
+// WithPromiseAtRiskWindow overrides DefaultPromiseAtRiskWindow.
+func WithPromiseAtRiskWindow(d time.Duration) Option {
+	return func(s *Service) { s.promiseAtRisk = d }
+}
+
+// WithLocation sets the workshop time zone for times in client messages and documents (default UTC).
+func WithLocation(loc *time.Location) Option {
+	return func(s *Service) { s.loc = loc }
+}
+
+// SetPromisedTime sets or revises when an open or in-progress visit will be ready, keeps the revision in the
+// visit's history and tells the client. Setting the current promise again changes nothing. Staff only.
+func (s *Service) SetPromisedTime(ctx context.Context, jobID uuid.UUID, at time.Time, reason string, userID uuid.UUID) (*domain.ServiceJob, error) {
+	j, err := s.openJobForStaff(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	at = at.UTC()
+	if !at.After(now) {
+		return nil, domain.ErrInvalidPromisedTime
+	}
+	if j.PromisedAt != nil && j.PromisedAt.Equal(at) {
+		return j, nil
+	}
+	rev := &domain.ServiceJobPromiseRevision{
+		ID:              uuid.New(),
+		ServiceJobID:    j.ID,
+		PreviousAt:      j.PromisedAt,
+		PromisedAt:      at,
+		Reason:          strings.TrimSpace(reason),
+		ChangedByUserID: userID,
+		ChangedAt:       now,
+	}
+	if err := s.jobRepo.SetPromise(ctx, rev); err != nil {
+		return nil, err
+	}
+	out := *j
+	out.PromisedAt = &at
+	out.UpdatedAt = now
+	s.publishChange(out.ID, out.Status, domain.ServiceJobChangePromise)
+	s.notifyPromise(ctx, &out, rev)
+	return &out, nil
+}
+
+// ListPromiseRevisions returns the visit's promised-time changes, oldest first (staff, or the client who owns the car).
+func (s *Service) ListPromiseRevisions(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.ServiceJobPromiseRevision, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	return s.jobRepo.ListPromiseRevisions(ctx, jobID)
+}
+
+// PromiseState flags the visit against its promised time, using the service's at-risk window.
+func (s *Service) PromiseState(j *domain.ServiceJob) domain.PromiseState {
+	return j.PromiseState(time.Now().UTC(), s.promiseAtRiskWindow())
+}
+
+// PromiseAlerts returns the board cards of visits that are overdue or at risk, most urgent first. Staff only.
+func (s *Service) PromiseAlerts(ctx context.Context, userID uuid.UUID) ([]BoardItem, error) {
+	b, err := s.Board(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := []BoardItem{}
+	for _, col := range b.Columns {
+		for _, item := range col.Jobs {
+			if item.PromiseState == domain.PromiseStateOverdue || item.PromiseState == domain.PromiseStateAtRisk {
+				out = append(out, item)
+			}
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].PromisedAt.Before(*out[b].PromisedAt) })
+	return out, nil
+}
+
+func (s *Service) promiseAtRiskWindow() time.Duration {
+	if s.promiseAtRisk > 0 {
+		return s.promiseAtRisk
+	}
+	return DefaultPromiseAtRiskWindow
+}
+
+func (s *Service) notifyPromise(ctx context.Context, j *domain.ServiceJob, rev *domain.ServiceJobPromiseRevision) {
+	s.notifyCarOwner(ctx, j, "Hora de entrega de tu vehículo", "promise:"+rev.ID.String(), func(car *domain.Car) string {
+		if rev.PreviousAt == nil {
+			return fmt.Sprintf("Tu %s %s (%s) va a estar listo el %s.", car.Make, car.Model, car.LicensePlate, s.formatLocal(rev.PromisedAt))
+		}
+		msg := fmt.Sprintf("Cambió la hora de entrega de tu %s %s (%s): ahora va a estar listo el %s (antes: %s).",
+			car.Make, car.Model, car.LicensePlate, s.formatLocal(rev.PromisedAt), s.formatLocal(*rev.PreviousAt))
+		if rev.Reason != "" {
+			msg += " Motivo: " + rev.Reason
+		}
+		return msg
+	})
+}
+
+// notifyReady tells the client their car is ready, once per visit.
+func (s *Service) notifyReady(ctx context.Context, j *domain.ServiceJob) {
+	s.notifyCarOwner(ctx, j, "Tu vehículo está listo", "visit-ready:"+j.ID.String(), func(car *domain.Car) string {
+		return fmt.Sprintf("Tu %s %s (%s) está listo. ¡Gracias por confiar en nosotros!", car.Make, car.Model, car.LicensePlate)
+	})
+}
+
+// notifyCarOwner queues an email to the client who owns the visit's car. Failures are logged, not returned:
+// the workshop action that triggered the message has already happened.
+func (s *Service) notifyCarOwner(ctx context.Context, j *domain.ServiceJob, subject, dedupeKey string, message func(*domain.Car) string) {
+	if s.notifier == nil {
+		return
+	}
+	car, err := s.carRepo.GetByID(ctx, j.CarID)
+	if err != nil || car == nil {
+		log.Printf("service job %s: load car: %v", j.ID, err)
+		return
+	}
+	owner, err := s.userRepo.GetByID(ctx, car.OwnerID)
+	if err != nil || owner == nil || strings.TrimSpace(owner.Email) == "" {
+		return
+	}
+	err = s.notifier.QueueNotification(ctx, services.NotificationRequest{
+		Type:      domain.NotificationChannelEmail,
+		To:        strings.TrimSpace(owner.Email),
+		Subject:   subject,
+		Message:   message(car),
+		DedupeKey: dedupeKey,
+	})
+	if err != nil {
+		log.Printf("service job %s: queue notification: %v", j.ID, err)
+	}
+}
+
+func (s *Service) formatLocal(t time.Time) string {
+	return t.In(s.location()).Format("02/01 a las 15:04")
+}
+
+func (s *Service) location() *time.Location {
+	if s.loc == nil {
+		return time.UTC
+	}
+	return s.loc
+}
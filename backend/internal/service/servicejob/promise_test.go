@@ -0,0 +1,105 @@
+package servicejob
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SetPromisedTime_RevisesAndNotifies(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	notifier := &estimateNotifier{}
+	WithNotifier(notifier)(fx.svc)
+	first := time.Now().UTC().Add(4 * time.Hour).Truncate(time.Minute)
+
+	_, err := fx.svc.SetPromisedTime(ctx, fx.jobID, first, "", fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.SetPromisedTime(ctx, fx.jobID, time.Now().Add(-time.Minute), "", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidPromisedTime)
+
+	j, err := fx.svc.SetPromisedTime(ctx, fx.jobID, first, "", fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, j.PromisedAt)
+	assert.True(t, first.Equal(*j.PromisedAt))
+	assert.Equal(t, domain.PromiseStateOnTrack, fx.svc.PromiseState(j))
+
+	// Same time again: nothing recorded, nobody notified.
+	_, err = fx.svc.SetPromisedTime(ctx, fx.jobID, first, "", fx.emp.ID)
+	require.NoError(t, err)
+
+	later := first.Add(24 * time.Hour)
+	_, err = fx.svc.SetPromisedTime(ctx, fx.jobID, later, " Falta un repuesto ", fx.emp.ID)
+	require.NoError(t, err)
+
+	history, err := fx.svc.ListPromiseRevisions(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Nil(t, history[0].PreviousAt)
+	require.NotNil(t, history[1].PreviousAt)
+	assert.True(t, first.Equal(*history[1].PreviousAt))
+	assert.Equal(t, "Falta un repuesto", history[1].Reason)
+	_, err = fx.svc.ListPromiseRevisions(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	require.Len(t, notifier.reqs, 2)
+	assert.Equal(t, "o@t", notifier.reqs[0].To)
+	assert.NotEqual(t, notifier.reqs[0].DedupeKey, notifier.reqs[1].DedupeKey)
+	assert.True(t, strings.Contains(notifier.reqs[1].Message, "Falta un repuesto"), notifier.reqs[1].Message)
+
+	_, err = fx.svc.CancelServiceJob(ctx, fx.jobID, "No vuelve", fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.SetPromisedTime(ctx, fx.jobID, later.Add(time.Hour), "", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidServiceJobData)
+}
+
+func TestService_PromiseAlerts(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	WithPromiseAtRiskWindow(time.Hour)(fx.svc)
+
+	alerts, err := fx.svc.PromiseAlerts(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Empty(t, alerts, "no promise, no alert")
+
+	soon := time.Now().UTC().Add(30 * time.Minute)
+	fx.jobs.byID[fx.jobID].PromisedAt = &soon
+	alerts, err = fx.svc.PromiseAlerts(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, domain.PromiseStateAtRisk, alerts[0].PromiseState)
+
+	past := time.Now().UTC().Add(-time.Minute)
+	fx.jobs.byID[fx.jobID].PromisedAt = &past
+	alerts, err = fx.svc.PromiseAlerts(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, domain.PromiseStateOverdue, alerts[0].PromiseState)
+
+	_, err = fx.svc.PromiseAlerts(ctx, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestService_SaveHandover_NotifiesCarReady(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	notifier := &estimateNotifier{}
+	WithNotifier(notifier)(fx.svc)
+
+	_, err := fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 1000}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Empty(t, notifier.reqs)
+	_, err = fx.svc.SaveHandover(ctx, fx.jobID, SaveHandoverInput{OdometerKM: 1001}, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, notifier.reqs, 1)
+	assert.Equal(t, "visit-ready:"+fx.jobID.String(), notifier.reqs[0].DedupeKey)
+	assert.Equal(t, "o@t", notifier.reqs[0].To)
+}
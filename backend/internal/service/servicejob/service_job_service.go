@@ -6,7 +6,11 @@ import (
 	"time"
 
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
 	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/pubsub"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
 	"github.com/google/uuid"
 )
 
@@ -15,11 +19,45 @@ type Service struct {
 	jobRepo    ports.ServiceJobRepository
 	carRepo    ports.CarRepository
 	userRepo   ports.UserRepository
-	repairRepo ports.RepairRepository // optional: nil yields empty repair_ids in detail
+	repairRepo ports.RepairRepository      // optional: nil yields empty repair_ids in detail
+	apptRepo   ports.AppointmentRepository // optional: required for check-in and arrivals
+
+	templateRepo ports.ChecklistTemplateRepository // optional: required for template-based checklists
+	findingRepo  ports.InspectionFindingRepository // optional: required for DVI findings
+	estimateRepo ports.EstimateRepository          // optional: required for estimates
+	obdRepo      ports.OBDSessionRepository        // optional: required for OBD-II session uploads
+	workRepo     ports.WorkHourRepository          // optional: required for technician clock-in/out
+	messageRepo  ports.ServiceJobMessageRepository // optional: required for the visit message thread
+	opRepo       ports.LabourOperationRepository   // optional: required for estimate lines from the labour catalog
+
+	boardHub *pubsub.Hub // optional: nil disables live board updates
+
+	files                    external.FileStorage // optional: required for client signatures and message attachments
+	requireHandoverSignature bool                 // handover must carry the client's signature
+
+	signer        *signedlink.Signer           // optional: required for estimate approval and visit tracking links
+	notifier      services.NotificationService // optional: nil skips notifications (estimates, promised time, car ready, thread messages)
+	publicBaseURL string                       // frontend origin for signed client links
+
+	promiseAtRisk time.Duration  // zero means DefaultPromiseAtRiskWindow
+	loc           *time.Location // workshop time zone for client messages and documents; nil means UTC
+	branding      *Branding      // nil means DefaultBranding
 }
 
-func NewService(jobRepo ports.ServiceJobRepository, carRepo ports.CarRepository, userRepo ports.UserRepository, repairRepo ports.RepairRepository) *Service {
-	return &Service{jobRepo: jobRepo, carRepo: carRepo, userRepo: userRepo, repairRepo: repairRepo}
+// Option configures optional collaborators of Service.
+type Option func(*Service)
+
+// WithAppointmentRepository enables appointment check-in and the daily arrivals view.
+func WithAppointmentRepository(repo ports.AppointmentRepository) Option {
+	return func(s *Service) { s.apptRepo = repo }
+}
+
+func NewService(jobRepo ports.ServiceJobRepository, carRepo ports.CarRepository, userRepo ports.UserRepository, repairRepo ports.RepairRepository, opts ...Option) *Service {
+	s := &Service{jobRepo: jobRepo, carRepo: carRepo, userRepo: userRepo, repairRepo: repairRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Service) requireWorkshopUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
@@ -47,6 +85,18 @@ func (s *Service) canAccessCar(ctx context.Context, user *domain.User, carID uui
 	return car, nil
 }
 
+// openJobForStaff loads a visit that is neither closed nor cancelled, for a staff caller.
+func (s *Service) openJobForStaff(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*domain.ServiceJob, error) {
+	j, err := s.staffJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status == domain.ServiceJobStatusClosed || j.Status == domain.ServiceJobStatusCancelled {
+		return nil, domain.ErrInvalidServiceJobData
+	}
+	return j, nil
+}
+
 // CreateServiceJob starts a new visit; only workshop staff.
 func (s *Service) CreateServiceJob(ctx context.Context, carID uuid.UUID, userID uuid.UUID) (*domain.ServiceJob, error) {
 	u, err := s.requireWorkshopUser(ctx, userID)
@@ -69,6 +119,7 @@ func (s *Service) CreateServiceJob(ctx context.Context, carID uuid.UUID, userID
 	if err := s.jobRepo.Create(ctx, job); err != nil {
 		return nil, err
 	}
+	s.publishChange(job.ID, job.Status, domain.ServiceJobChangeCreated)
 	return job, nil
 }
 
@@ -149,6 +200,8 @@ type SaveReceptionInput struct {
 	CoolantLevel string
 	TiresNote    string
 	GeneralNotes string
+	Checklist    ChecklistAnswersInput
+	Signature    *SignatureInput // optional
 }
 
 func (s *Service) SaveReception(ctx context.Context, jobID uuid.UUID, in SaveReceptionInput, userID uuid.UUID) (*domain.ServiceJobReception, error) {
@@ -169,24 +222,42 @@ func (s *Service) SaveReception(ctx context.Context, jobID uuid.UUID, in SaveRec
 	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
 		return nil, err
 	}
+	templateID, answers, schema, err := s.resolveChecklist(ctx, domain.ChecklistKindReception, in.Checklist)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
+	sig, err := s.storeSignature(ctx, jobID, domain.SignatureStageReception, in.Signature, now)
+	if err != nil {
+		return nil, err
+	}
 	r := &domain.ServiceJobReception{
-		ServiceJobID:     jobID,
-		OdometerKM:       in.OdometerKM,
-		OilLevel:         in.OilLevel,
-		CoolantLevel:     in.CoolantLevel,
-		TiresNote:        in.TiresNote,
-		GeneralNotes:     in.GeneralNotes,
-		RecordedByUserID: userID,
-		RecordedAt:       now,
-		SchemaVersion:    1,
-	}
-	if err := s.jobRepo.SaveReception(ctx, r); err != nil {
-		return nil, err
-	}
-	j.Status = domain.ServiceJobStatusInProgress
-	j.UpdatedAt = now
-	_ = s.jobRepo.Update(ctx, j) // best-effort status
+		ServiceJobID:        jobID,
+		OdometerKM:          in.OdometerKM,
+		OilLevel:            in.OilLevel,
+		CoolantLevel:        in.CoolantLevel,
+		TiresNote:           in.TiresNote,
+		GeneralNotes:        in.GeneralNotes,
+		ChecklistTemplateID: templateID,
+		ChecklistAnswers:    answers,
+		RecordedByUserID:    userID,
+		RecordedAt:          now,
+		SchemaVersion:       schema,
+		Signature:           sig,
+	}
+	var ev *domain.ServiceJobStatusEvent
+	if j.Status == domain.ServiceJobStatusOpen {
+		ev = newStatusEvent(j, domain.ServiceJobStatusInProgress, "reception recorded", userID, now)
+	}
+	if err := s.jobRepo.SaveReception(ctx, r, ev); err != nil {
+		s.discardSignature(ctx, sig)
+		return nil, err
+	}
+	if ev != nil {
+		s.publishChange(jobID, ev.ToStatus, domain.ServiceJobChangeStatus)
+	} else {
+		s.publishChange(jobID, j.Status, domain.ServiceJobChangeReception)
+	}
 	return r, nil
 }
 
@@ -195,6 +266,8 @@ type SaveHandoverInput struct {
 	OdometerKM   int
 	TiresNote    string
 	GeneralNotes string
+	Checklist    ChecklistAnswersInput
+	Signature    *SignatureInput // optional
 }
 
 func (s *Service) SaveHandover(ctx context.Context, jobID uuid.UUID, in SaveHandoverInput, userID uuid.UUID) (*domain.ServiceJobHandover, error) {
@@ -215,7 +288,7 @@ func (s *Service) SaveHandover(ctx context.Context, jobID uuid.UUID, in SaveHand
 			return existing, nil
 		}
 	}
-	if j.Status == domain.ServiceJobStatusCancelled {
+	if j.Status == domain.ServiceJobStatusClosed || j.Status == domain.ServiceJobStatusCancelled {
 		return nil, domain.ErrInvalidServiceJobData
 	}
 	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
@@ -228,24 +301,36 @@ func (s *Service) SaveHandover(ctx context.Context, jobID uuid.UUID, in SaveHand
 	if prev == nil {
 		return nil, domain.ErrReceptionRequiredBeforeHandover
 	}
-	now := time.Now().UTC()
-	h := &domain.ServiceJobHandover{
-		ServiceJobID:     jobID,
-		OdometerKM:       in.OdometerKM,
-		TiresNote:        in.TiresNote,
-		GeneralNotes:     in.GeneralNotes,
-		RecordedByUserID: userID,
-		RecordedAt:       now,
-		SchemaVersion:    1,
+	if s.requireHandoverSignature && in.Signature == nil {
+		return nil, domain.ErrSignatureRequired
+	}
+	templateID, answers, schema, err := s.resolveChecklist(ctx, domain.ChecklistKindHandover, in.Checklist)
+	if err != nil {
+		return nil, err
 	}
-	if err := s.jobRepo.SaveHandover(ctx, h); err != nil {
+	now := time.Now().UTC()
+	sig, err := s.storeSignature(ctx, jobID, domain.SignatureStageHandover, in.Signature, now)
+	if err != nil {
 		return nil, err
 	}
-	j.Status = domain.ServiceJobStatusClosed
-	j.ClosedAt = &now
-	j.UpdatedAt = now
-	if err := s.jobRepo.Update(ctx, j); err != nil {
+	h := &domain.ServiceJobHandover{
+		ServiceJobID:        jobID,
+		OdometerKM:          in.OdometerKM,
+		TiresNote:           in.TiresNote,
+		GeneralNotes:        in.GeneralNotes,
+		ChecklistTemplateID: templateID,
+		ChecklistAnswers:    answers,
+		RecordedByUserID:    userID,
+		RecordedAt:          now,
+		SchemaVersion:       schema,
+		Signature:           sig,
+	}
+	ev := newStatusEvent(j, domain.ServiceJobStatusClosed, "handover recorded", userID, now)
+	if err := s.jobRepo.SaveHandover(ctx, h, ev); err != nil {
+		s.discardSignature(ctx, sig)
 		return nil, err
 	}
+	s.publishChange(jobID, ev.ToStatus, domain.ServiceJobChangeStatus)
+	s.notifyReady(ctx, j)
 	return h, nil
 }
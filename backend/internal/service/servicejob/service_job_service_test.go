@@ -3,6 +3,7 @@ package servicejob
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
 	"time"
 
@@ -61,6 +62,9 @@ type stubJobRepo struct {
 	byCar    map[uuid.UUID][]*domain.ServiceJob
 	rec      map[uuid.UUID]*domain.ServiceJobReception
 	handover map[uuid.UUID]*domain.ServiceJobHandover
+	events   []*domain.ServiceJobStatusEvent
+	promises []*domain.ServiceJobPromiseRevision
+	appts    *stubApptRepo // check-in moves these appointments to checked_in
 }
 
 func (s *stubJobRepo) Create(_ context.Context, j *domain.ServiceJob) error {
@@ -119,12 +123,62 @@ func (s *stubJobRepo) ListByOpenedOn(_ context.Context, day time.Time) ([]*domai
 	return out, nil
 }
 
-func (s *stubJobRepo) SaveReception(_ context.Context, r *domain.ServiceJobReception) error {
+func (s *stubJobRepo) ListActive(context.Context) ([]*domain.ServiceJob, error) {
+	out := []*domain.ServiceJob{}
+	for _, j := range s.byID {
+		if j.Status == domain.ServiceJobStatusOpen || j.Status == domain.ServiceJobStatusInProgress {
+			out = append(out, j)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].OpenedAt.Before(out[b].OpenedAt) })
+	return out, nil
+}
+
+func (s *stubJobRepo) GetByAppointmentID(_ context.Context, appointmentID uuid.UUID) (*domain.ServiceJob, error) {
+	for _, j := range s.byID {
+		if j.AppointmentID != nil && *j.AppointmentID == appointmentID {
+			return j, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubJobRepo) ListByAppointmentIDs(_ context.Context, ids []uuid.UUID) ([]*domain.ServiceJob, error) {
+	out := []*domain.ServiceJob{}
+	for _, j := range s.byID {
+		for _, id := range ids {
+			if j.AppointmentID != nil && *j.AppointmentID == id {
+				out = append(out, j)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *stubJobRepo) CreateForAppointment(ctx context.Context, j *domain.ServiceJob) error {
+	if s.appts != nil {
+		a, ok := s.appts.byID[*j.AppointmentID]
+		if !ok {
+			return domain.ErrAppointmentNotFound
+		}
+		switch a.Status {
+		case domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed:
+		case domain.AppointmentStatusCheckedIn:
+			return domain.ErrAppointmentAlreadyCheckedIn
+		default:
+			return domain.ErrAppointmentNotCheckInable
+		}
+		a.Status = domain.AppointmentStatusCheckedIn
+	}
+	return s.Create(ctx, j)
+}
+
+func (s *stubJobRepo) SaveReception(ctx context.Context, r *domain.ServiceJobReception, ev *domain.ServiceJobStatusEvent) error {
 	if s.rec == nil {
 		s.rec = make(map[uuid.UUID]*domain.ServiceJobReception)
 	}
 	s.rec[r.ServiceJobID] = r
-	return nil
+	return s.ChangeStatus(ctx, ev)
 }
 
 func (s *stubJobRepo) GetReception(_ context.Context, id uuid.UUID) (*domain.ServiceJobReception, error) {
@@ -134,12 +188,12 @@ func (s *stubJobRepo) GetReception(_ context.Context, id uuid.UUID) (*domain.Ser
 	return s.rec[id], nil
 }
 
-func (s *stubJobRepo) SaveHandover(_ context.Context, h *domain.ServiceJobHandover) error {
+func (s *stubJobRepo) SaveHandover(ctx context.Context, h *domain.ServiceJobHandover, ev *domain.ServiceJobStatusEvent) error {
 	if s.handover == nil {
 		s.handover = make(map[uuid.UUID]*domain.ServiceJobHandover)
 	}
 	s.handover[h.ServiceJobID] = h
-	return nil
+	return s.ChangeStatus(ctx, ev)
 }
 
 func (s *stubJobRepo) GetHandover(_ context.Context, id uuid.UUID) (*domain.ServiceJobHandover, error) {
@@ -149,6 +203,50 @@ func (s *stubJobRepo) GetHandover(_ context.Context, id uuid.UUID) (*domain.Serv
 	return s.handover[id], nil
 }
 
+func (s *stubJobRepo) ChangeStatus(_ context.Context, ev *domain.ServiceJobStatusEvent) error {
+	if ev == nil {
+		return nil
+	}
+	j, ok := s.byID[ev.ServiceJobID]
+	if !ok || j.Status != ev.FromStatus {
+		return domain.ErrServiceJobStatusConflict
+	}
+	j.Apply(ev)
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *stubJobRepo) ListStatusEvents(_ context.Context, id uuid.UUID) ([]*domain.ServiceJobStatusEvent, error) {
+	out := []*domain.ServiceJobStatusEvent{}
+	for _, ev := range s.events {
+		if ev.ServiceJobID == id {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubJobRepo) SetPromise(_ context.Context, rev *domain.ServiceJobPromiseRevision) error {
+	j, ok := s.byID[rev.ServiceJobID]
+	if !ok || (j.Status != domain.ServiceJobStatusOpen && j.Status != domain.ServiceJobStatusInProgress) {
+		return domain.ErrServiceJobStatusConflict
+	}
+	at := rev.PromisedAt
+	j.PromisedAt = &at
+	s.promises = append(s.promises, rev)
+	return nil
+}
+
+func (s *stubJobRepo) ListPromiseRevisions(_ context.Context, id uuid.UUID) ([]*domain.ServiceJobPromiseRevision, error) {
+	out := []*domain.ServiceJobPromiseRevision{}
+	for _, rev := range s.promises {
+		if rev.ServiceJobID == id {
+			out = append(out, rev)
+		}
+	}
+	return out, nil
+}
+
 func TestService_CreateServiceJob_ClientDenied(t *testing.T) {
 	t.Parallel()
 	carID := uuid.New()
@@ -0,0 +1,199 @@
+package servicejob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/external"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrSignatureStorageNotConfigured is returned when a signature is sent but no file storage is wired.
+var ErrSignatureStorageNotConfigured = errors.New("signature storage is not configured")
+
+// ErrSignatureHashMismatch means the stored image no longer matches the hash recorded when it was signed.
+var ErrSignatureHashMismatch = errors.New("stored signature does not match its recorded hash")
+
+// Signature limits: image size, signer name length, and how far ahead of the server clock a signing
+// device may be.
+const (
+	MaxSignatureBytes      = 512 << 10
+	maxSignerNameLen       = 200
+	signatureClockSkew     = 5 * time.Minute
+	defaultSignatureWidth  = 400
+	defaultSignatureHeight = 150
+	maxSignatureSide       = 4000
+)
+
+var (
+	pngMagic    = []byte("\x89PNG\r\n\x1a\n")
+	svgPathData = regexp.MustCompile(`^[Mm][MmLlHhVvCcSsQqTtAaZz0-9eE.,+\-\s]*$`)
+)
+
+// WithFileStorage enables signature capture at reception and handover.
+func WithFileStorage(fs external.FileStorage) Option {
+	return func(s *Service) { s.files = fs }
+}
+
+// WithHandoverSignatureRequired makes handover refuse to close a visit without the client's signature.
+func WithHandoverSignatureRequired(required bool) Option {
+	return func(s *Service) { s.requireHandoverSignature = required }
+}
+
+// SignatureInput is a signature captured on a pad: either SVG path data drawn on a Width×Height canvas
+// (defaults 400×150) or a PNG image.
+type SignatureInput struct {
+	SignerName    string
+	SignedAt      time.Time // zero means now
+	SVGPath       string
+	Width, Height int
+	PNG           []byte
+}
+
+// storeSignature validates the signature, saves its image and returns the record to keep on the
+// reception or handover. A nil input yields nil.
+func (s *Service) storeSignature(ctx context.Context, jobID uuid.UUID, stage string, in *SignatureInput, now time.Time) (*domain.CustomerSignature, error) {
+	if in == nil {
+		return nil, nil
+	}
+	name := strings.TrimSpace(in.SignerName)
+	if name == "" || len([]rune(name)) > maxSignerNameLen {
+		return nil, domain.ErrInvalidSignature
+	}
+	signedAt := in.SignedAt.UTC()
+	if signedAt.IsZero() {
+		signedAt = now
+	}
+	if signedAt.After(now.Add(signatureClockSkew)) {
+		return nil, domain.ErrInvalidSignature
+	}
+	data, contentType, ext, err := signatureImage(in)
+	if err != nil {
+		return nil, err
+	}
+	if s.files == nil {
+		return nil, ErrSignatureStorageNotConfigured
+	}
+	sum := sha256.Sum256(data)
+	file := &domain.File{
+		ID:          fmt.Sprintf("signature-%s-%s-%s%s", jobID, stage, uuid.New(), ext),
+		Name:        stage + "-signature" + ext,
+		ContentType: contentType,
+		Data:        data,
+		CreatedAt:   now,
+	}
+	if err := s.files.UploadFile(ctx, file); err != nil {
+		return nil, fmt.Errorf("store signature: %w", err)
+	}
+	return &domain.CustomerSignature{
+		FileID:      file.ID,
+		ContentType: contentType,
+		SHA256:      hex.EncodeToString(sum[:]),
+		SignerName:  name,
+		SignedAt:    signedAt,
+	}, nil
+}
+
+// discardSignature removes an image whose reception or handover was not saved.
+func (s *Service) discardSignature(ctx context.Context, sig *domain.CustomerSignature) {
+	if sig == nil {
+		return
+	}
+	if err := s.files.DeleteFile(ctx, sig.FileID); err != nil {
+		log.Printf("discard signature %s: %v", sig.FileID, err)
+	}
+}
+
+func signatureImage(in *SignatureInput) (data []byte, contentType, ext string, err error) {
+	path := strings.TrimSpace(in.SVGPath)
+	switch {
+	case path != "" && len(in.PNG) > 0, path == "" && len(in.PNG) == 0:
+		return nil, "", "", domain.ErrInvalidSignature
+	case len(in.PNG) > 0:
+		if len(in.PNG) > MaxSignatureBytes || !bytes.HasPrefix(in.PNG, pngMagic) {
+			return nil, "", "", domain.ErrInvalidSignature
+		}
+		return in.PNG, domain.SignatureContentTypePNG, ".png", nil
+	}
+	w, h := in.Width, in.Height
+	if w == 0 {
+		w = defaultSignatureWidth
+	}
+	if h == 0 {
+		h = defaultSignatureHeight
+	}
+	if len(path) > MaxSignatureBytes || !svgPathData.MatchString(path) || w < 1 || h < 1 || w > maxSignatureSide || h > maxSignatureSide {
+		return nil, "", "", domain.ErrInvalidSignature
+	}
+	// The path is checked against the SVG path grammar's alphabet above, so it can go into the
+	// attribute as is.
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+
+		`<path d="%s" fill="none" stroke="#000" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/></svg>`,
+		w, h, w, h, path)
+	return []byte(svg), domain.SignatureContentTypeSVG, ".svg", nil
+}
+
+// SignatureImage returns the client's signature from the visit's reception or handover, after checking
+// it against its recorded hash. Staff, or the client who owns the car.
+func (s *Service) SignatureImage(ctx context.Context, jobID uuid.UUID, stage string, userID uuid.UUID) (*domain.File, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	var sig *domain.CustomerSignature
+	switch stage {
+	case domain.SignatureStageReception:
+		r, err := s.jobRepo.GetReception(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			sig = r.Signature
+		}
+	case domain.SignatureStageHandover:
+		h, err := s.jobRepo.GetHandover(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if h != nil {
+			sig = h.Signature
+		}
+	default:
+		return nil, domain.ErrInvalidSignature
+	}
+	if sig == nil {
+		return nil, domain.ErrFileNotFound
+	}
+	if s.files == nil {
+		return nil, ErrSignatureStorageNotConfigured
+	}
+	f, err := s.files.DownloadFile(ctx, sig.FileID)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(f.Data)
+	if hex.EncodeToString(sum[:]) != sig.SHA256 {
+		return nil, ErrSignatureHashMismatch
+	}
+	f.ContentType = sig.ContentType
+	return f, nil
+}
@@ -0,0 +1,127 @@
+package servicejob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type memFiles struct{ byID map[string]*domain.File }
+
+func (m *memFiles) UploadFile(_ context.Context, f *domain.File) error {
+	if m.byID == nil {
+		m.byID = map[string]*domain.File{}
+	}
+	cp := *f
+	m.byID[f.ID] = &cp
+	return nil
+}
+
+func (m *memFiles) DownloadFile(_ context.Context, id string) (*domain.File, error) {
+	f, ok := m.byID[id]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	cp := *f
+	return &cp, nil
+}
+
+func (m *memFiles) DeleteFile(_ context.Context, id string) error {
+	delete(m.byID, id)
+	return nil
+}
+
+func (m *memFiles) ListFiles(context.Context, int, int) ([]*domain.File, int64, error) {
+	return nil, int64(len(m.byID)), nil
+}
+
+func TestService_SaveReception_StoresSignature(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	files := &memFiles{}
+	signed := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+
+	in := SaveReceptionInput{OdometerKM: 1000, Signature: &SignatureInput{SignerName: " Ana Pérez ", SignedAt: signed, SVGPath: "M10 10 L50 40 C60 50, 70 50, 80 40"}}
+	_, err := fx.svc.SaveReception(ctx, fx.jobID, in, fx.emp.ID)
+	assert.ErrorIs(t, err, ErrSignatureStorageNotConfigured)
+
+	WithFileStorage(files)(fx.svc)
+	r, err := fx.svc.SaveReception(ctx, fx.jobID, in, fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, r.Signature)
+	assert.Equal(t, "Ana Pérez", r.Signature.SignerName)
+	assert.Equal(t, signed, r.Signature.SignedAt)
+	assert.Equal(t, domain.SignatureContentTypeSVG, r.Signature.ContentType)
+	stored := files.byID[r.Signature.FileID]
+	require.NotNil(t, stored)
+	assert.True(t, strings.Contains(string(stored.Data), `d="M10 10 L50 40 C60 50, 70 50, 80 40"`))
+	sum := sha256.Sum256(stored.Data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), r.Signature.SHA256)
+
+	img, err := fx.svc.SignatureImage(ctx, fx.jobID, domain.SignatureStageReception, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Data, img.Data)
+	_, err = fx.svc.SignatureImage(ctx, fx.jobID, domain.SignatureStageReception, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.SignatureImage(ctx, fx.jobID, domain.SignatureStageHandover, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+
+	stored.Data = append(stored.Data, ' ')
+	_, err = fx.svc.SignatureImage(ctx, fx.jobID, domain.SignatureStageReception, fx.emp.ID)
+	assert.ErrorIs(t, err, ErrSignatureHashMismatch)
+}
+
+func TestService_SaveReception_RejectsBadSignatures(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	files := &memFiles{}
+	WithFileStorage(files)(fx.svc)
+
+	for name, sig := range map[string]*SignatureInput{
+		"no signer":    {SVGPath: "M0 0 L1 1"},
+		"no image":     {SignerName: "Ana"},
+		"both images":  {SignerName: "Ana", SVGPath: "M0 0", PNG: append([]byte{}, pngMagic...)},
+		"script":       {SignerName: "Ana", SVGPath: `M0 0"/><script>alert(1)</script>`},
+		"not a png":    {SignerName: "Ana", PNG: []byte("GIF89a")},
+		"future stamp": {SignerName: "Ana", SVGPath: "M0 0", SignedAt: time.Now().Add(time.Hour)},
+	} {
+		_, err := fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 1, Signature: sig}, fx.emp.ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidSignature, name)
+	}
+	assert.Empty(t, files.byID)
+
+	png := append(append([]byte{}, pngMagic...), 0, 0, 0, 13)
+	r, err := fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 1, Signature: &SignatureInput{SignerName: "Ana", PNG: png}}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SignatureContentTypePNG, r.Signature.ContentType)
+	assert.True(t, strings.HasSuffix(r.Signature.FileID, ".png"))
+}
+
+func TestService_SaveHandover_SignatureRequired(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	WithFileStorage(&memFiles{})(fx.svc)
+	WithHandoverSignatureRequired(true)(fx.svc)
+
+	_, err := fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 1000}, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.SaveHandover(ctx, fx.jobID, SaveHandoverInput{OdometerKM: 1001}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrSignatureRequired)
+	assert.Equal(t, domain.ServiceJobStatusInProgress, fx.jobs.byID[fx.jobID].Status)
+
+	h, err := fx.svc.SaveHandover(ctx, fx.jobID, SaveHandoverInput{OdometerKM: 1001, Signature: &SignatureInput{SignerName: "Ana", SVGPath: "M0 0 L10 10"}}, fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, h.Signature)
+	assert.Equal(t, domain.ServiceJobStatusClosed, fx.jobs.byID[fx.jobID].Status)
+}
@@ -0,0 +1,120 @@
+package servicejob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CancelServiceJob cancels an open or in-progress visit. A reason is required. Staff only.
+func (s *Service) CancelServiceJob(ctx context.Context, jobID uuid.UUID, reason string, userID uuid.UUID) (*domain.ServiceJob, error) {
+	return s.TransitionServiceJob(ctx, jobID, domain.ServiceJobStatusCancelled, reason, userID)
+}
+
+// ReopenServiceJob reopens a closed visit (back to in progress, e.g. the client returns the same day with
+// the same issue) or a cancelled one (back to open). A reason is required. Staff only.
+func (s *Service) ReopenServiceJob(ctx context.Context, jobID uuid.UUID, reason string, userID uuid.UUID) (*domain.ServiceJob, error) {
+	j, err := s.staffJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch j.Status {
+	case domain.ServiceJobStatusClosed:
+		return s.transition(ctx, j, domain.ServiceJobStatusInProgress, reason, userID)
+	case domain.ServiceJobStatusCancelled:
+		return s.transition(ctx, j, domain.ServiceJobStatusOpen, reason, userID)
+	default:
+		return nil, domain.ErrServiceJobTransitionNotAllowed
+	}
+}
+
+// TransitionServiceJob moves a visit to status along the allowed transitions (see domain.CanTransitionServiceJob)
+// and records it in the visit's history. Closing by hand needs a recorded handover. Staff only.
+func (s *Service) TransitionServiceJob(ctx context.Context, jobID uuid.UUID, to domain.ServiceJobStatus, reason string, userID uuid.UUID) (*domain.ServiceJob, error) {
+	if !domain.ValidateServiceJobStatus(to) {
+		return nil, domain.ErrInvalidServiceJobData
+	}
+	j, err := s.staffJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, j, to, reason, userID)
+}
+
+// ListStatusHistory returns the visit's status changes, oldest first (staff, or the client who owns the car).
+func (s *Service) ListStatusHistory(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) ([]*domain.ServiceJobStatusEvent, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	return s.jobRepo.ListStatusEvents(ctx, jobID)
+}
+
+func (s *Service) transition(ctx context.Context, j *domain.ServiceJob, to domain.ServiceJobStatus, reason string, userID uuid.UUID) (*domain.ServiceJob, error) {
+	if !domain.CanTransitionServiceJob(j.Status, to) {
+		return nil, domain.ErrServiceJobTransitionNotAllowed
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" && domain.ServiceJobTransitionNeedsReason(j.Status, to) {
+		return nil, domain.ErrServiceJobReasonRequired
+	}
+	if to == domain.ServiceJobStatusClosed {
+		h, err := s.jobRepo.GetHandover(ctx, j.ID)
+		if err != nil {
+			return nil, err
+		}
+		if h == nil {
+			return nil, domain.ErrServiceJobTransitionNotAllowed
+		}
+	}
+	ev := newStatusEvent(j, to, reason, userID, time.Now().UTC())
+	if err := s.jobRepo.ChangeStatus(ctx, ev); err != nil {
+		return nil, err
+	}
+	out := *j
+	out.Apply(ev)
+	s.publishChange(out.ID, out.Status, domain.ServiceJobChangeStatus)
+	return &out, nil
+}
+
+// staffJob loads a visit in any status for a staff caller.
+func (s *Service) staffJob(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*domain.ServiceJob, error) {
+	u, err := s.requireWorkshopUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.canAccessCar(ctx, u, j.CarID); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func newStatusEvent(j *domain.ServiceJob, to domain.ServiceJobStatus, reason string, userID uuid.UUID, at time.Time) *domain.ServiceJobStatusEvent {
+	return &domain.ServiceJobStatusEvent{
+		ID:              uuid.New(),
+		ServiceJobID:    j.ID,
+		FromStatus:      j.Status,
+		ToStatus:        to,
+		Reason:          reason,
+		ChangedByUserID: userID,
+		ChangedAt:       at,
+	}
+}
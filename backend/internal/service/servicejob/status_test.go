@@ -0,0 +1,92 @@
+package servicejob
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CancelAndReopen(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.CancelServiceJob(ctx, fx.jobID, "El cliente no vino", fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.CancelServiceJob(ctx, fx.jobID, "  ", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrServiceJobReasonRequired)
+
+	j, err := fx.svc.CancelServiceJob(ctx, fx.jobID, "El cliente retira el coche", fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ServiceJobStatusCancelled, j.Status)
+	_, err = fx.svc.CancelServiceJob(ctx, fx.jobID, "otra vez", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrServiceJobTransitionNotAllowed)
+
+	j, err = fx.svc.ReopenServiceJob(ctx, fx.jobID, "Vuelve por la tarde", fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ServiceJobStatusOpen, j.Status)
+	_, err = fx.svc.ReopenServiceJob(ctx, fx.jobID, "x", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrServiceJobTransitionNotAllowed)
+
+	history, err := fx.svc.ListStatusHistory(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.ServiceJobStatusInProgress, history[0].FromStatus)
+	assert.Equal(t, "El cliente retira el coche", history[0].Reason)
+	assert.Equal(t, domain.ServiceJobStatusOpen, history[1].ToStatus)
+	_, err = fx.svc.ListStatusHistory(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+}
+
+func TestService_ReopenClosedVisit(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.TransitionServiceJob(ctx, fx.jobID, domain.ServiceJobStatusClosed, "", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrServiceJobTransitionNotAllowed, "closing by hand needs a handover")
+
+	_, err = fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 1000}, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.SaveHandover(ctx, fx.jobID, SaveHandoverInput{OdometerKM: 1002}, fx.emp.ID)
+	require.NoError(t, err)
+	closed, _ := fx.jobs.GetByID(ctx, fx.jobID)
+	require.Equal(t, domain.ServiceJobStatusClosed, closed.Status)
+	require.NotNil(t, closed.ClosedAt)
+
+	_, err = fx.svc.ReopenServiceJob(ctx, fx.jobID, "", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrServiceJobReasonRequired)
+	j, err := fx.svc.ReopenServiceJob(ctx, fx.jobID, "Mismo ruido en la dirección", fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ServiceJobStatusInProgress, j.Status)
+	assert.Nil(t, j.ClosedAt)
+
+	// The handover is kept, so the visit can be closed again once the extra work is done.
+	j, err = fx.svc.TransitionServiceJob(ctx, fx.jobID, domain.ServiceJobStatusClosed, "", fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ServiceJobStatusClosed, j.Status)
+
+	_, err = fx.svc.TransitionServiceJob(ctx, fx.jobID, "archived", "", fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidServiceJobData)
+}
+
+func TestService_SaveReception_RecordsStatusChangeOnce(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	fx.jobs.byID[fx.jobID].Status = domain.ServiceJobStatusOpen
+
+	_, err := fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 10}, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.SaveReception(ctx, fx.jobID, SaveReceptionInput{OdometerKM: 11}, fx.emp.ID)
+	require.NoError(t, err)
+
+	history, err := fx.svc.ListStatusHistory(ctx, fx.jobID, fx.emp.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, domain.ServiceJobStatusOpen, history[0].FromStatus)
+	assert.Equal(t, domain.ServiceJobStatusInProgress, history[0].ToStatus)
+}
@@ -0,0 +1,310 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports/services"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrTrackerLinksNotConfigured is returned by CreateTrackerLink when the service has no link signer (see WithLinkSigner).
+var ErrTrackerLinksNotConfigured = errors.New("visit tracking links not configured")
+
+// ErrNoTrackerRecipient is returned when a tracking link should be texted but neither a phone was given
+// nor does the car's owner have a phone or email on file.
+var ErrNoTrackerRecipient = errors.New("no phone or email to send the tracking link to")
+
+// VisitTrackerPurpose binds tracking-link tokens to visits.
+const VisitTrackerPurpose = "visit-tracker"
+
+// VisitTrackerLinkTTL is how long a tracking link stays valid after staff create it.
+const VisitTrackerLinkTTL = 7 * 24 * time.Hour
+
+// Client-facing stage of a visit on the tracker.
+const (
+	TrackerStageReceived   = "received"    // open, not started
+	TrackerStageInProgress = "in_progress" // being worked on
+	TrackerStageReady      = "ready"       // all work done, waiting for pickup
+	TrackerStageDelivered  = "delivered"   // handed over
+	TrackerStageCancelled  = "cancelled"
+)
+
+// VisitTrackerLink builds the public page URL a client without an account opens to follow a visit.
+func VisitTrackerLink(publicBaseURL, token string) string {
+	return strings.TrimRight(publicBaseURL, "/") + "/visits/track?token=" + url.QueryEscape(token)
+}
+
+// VisitTracker is what the client sees of a visit: no staff names, internal notes or costs beyond what
+// they approved.
+type VisitTracker struct {
+	ServiceJobID   uuid.UUID               `json:"service_job_id"`
+	Status         domain.ServiceJobStatus `json:"status"`
+	Stage          string                  `json:"stage"` // received | in_progress | ready | delivered | cancelled
+	ReadyForPickup bool                    `json:"ready_for_pickup"`
+	OpenedAt       time.Time               `json:"opened_at"`
+	PromisedAt     *time.Time              `json:"promised_at,omitempty"`
+	ClosedAt       *time.Time              `json:"closed_at,omitempty"`
+	Car            TrackerCar              `json:"car"`
+	Workshop       TrackerWorkshop         `json:"workshop"`
+	Timeline       []TrackerEvent          `json:"timeline"`
+	Work           []TrackerWork           `json:"work"`
+	ApprovedLines  []TrackerLine           `json:"approved_lines"`
+	ApprovedTotal  float64                 `json:"approved_total"`
+	Findings       []TrackerFinding        `json:"findings"`
+}
+
+// TrackerCar identifies the vehicle.
+type TrackerCar struct {
+	Make         string `json:"make"`
+	Model        string `json:"model"`
+	LicensePlate string `json:"license_plate"`
+}
+
+// TrackerWorkshop is who to call about the visit.
+type TrackerWorkshop struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// TrackerEvent is one step of the visit's status timeline.
+type TrackerEvent struct {
+	Status domain.ServiceJobStatus `json:"status"`
+	At     time.Time               `json:"at"`
+}
+
+// TrackerWork is a repair on the visit.
+type TrackerWork struct {
+	Description string              `json:"description"`
+	Status      domain.RepairStatus `json:"status"`
+}
+
+// TrackerLine is an estimate line the client approved.
+type TrackerLine struct {
+	Kind        domain.EstimateLineKind `json:"kind"`
+	Description string                  `json:"description"`
+	Quantity    float64                 `json:"quantity"`
+	Amount      float64                 `json:"amount"`
+}
+
+// TrackerFinding is an inspection result shown to the client.
+type TrackerFinding struct {
+	Severity      domain.FindingSeverity `json:"severity"`
+	Description   string                 `json:"description"`
+	PhotoURL      string                 `json:"photo_url,omitempty"`
+	EstimatedCost *float64               `json:"estimated_cost,omitempty"`
+	RepairQuoted  bool                   `json:"repair_quoted"` // a repair was proposed for it
+}
+
+// VisitTracker returns the client view of a visit (the car's owner, or staff checking what the client sees).
+func (s *Service) VisitTracker(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*VisitTracker, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	j, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	car, err := s.canAccessCar(ctx, u, j.CarID)
+	if err != nil {
+		return nil, err
+	}
+	return s.visitTracker(ctx, j, car)
+}
+
+// VisitTrackerByToken resolves the visit behind a tracking link (no login).
+func (s *Service) VisitTrackerByToken(ctx context.Context, token string) (*VisitTracker, error) {
+	if s.signer == nil {
+		return nil, domain.ErrVisitLinkInvalid
+	}
+	id, err := s.signer.Verify(VisitTrackerPurpose, token)
+	if err != nil {
+		return nil, domain.ErrVisitLinkInvalid
+	}
+	j, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrServiceJobNotFound) {
+			return nil, domain.ErrVisitLinkInvalid
+		}
+		return nil, err
+	}
+	car, err := s.carRepo.GetByID(ctx, j.CarID)
+	if err != nil {
+		return nil, fmt.Errorf("car: %w", err)
+	}
+	if car == nil {
+		return nil, domain.ErrVisitLinkInvalid
+	}
+	return s.visitTracker(ctx, j, car)
+}
+
+// TrackerLinkInput says where to text a tracking link. Phone empty means the car owner's phone; with
+// Send false the link is only returned, for staff to share by other means.
+type TrackerLinkInput struct {
+	Phone string
+	Send  bool
+}
+
+// TrackerLink is a signed tracking link; SentTo is the phone or email it was texted to, if any.
+type TrackerLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	SentTo    string    `json:"sent_to,omitempty"`
+}
+
+// CreateTrackerLink mints a signed tracking link for the visit and, when asked, texts it to the client
+// (by SMS, or by email when the owner has no phone). Staff only.
+func (s *Service) CreateTrackerLink(ctx context.Context, jobID uuid.UUID, in TrackerLinkInput, userID uuid.UUID) (*TrackerLink, error) {
+	if s.signer == nil {
+		return nil, ErrTrackerLinksNotConfigured
+	}
+	j, err := s.staffJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := &TrackerLink{ExpiresAt: time.Now().UTC().Add(VisitTrackerLinkTTL)}
+	out.URL = VisitTrackerLink(s.publicBaseURL, s.signer.Sign(VisitTrackerPurpose, j.ID, out.ExpiresAt))
+	if in.Send && s.notifier != nil {
+		if out.SentTo, err = s.sendTrackerLink(ctx, j, strings.TrimSpace(in.Phone), out.URL); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) sendTrackerLink(ctx context.Context, j *domain.ServiceJob, phone, link string) (string, error) {
+	car, err := s.carRepo.GetByID(ctx, j.CarID)
+	if err != nil {
+		return "", fmt.Errorf("car: %w", err)
+	}
+	if car == nil {
+		return "", domain.ErrCarNotFound
+	}
+	req := services.NotificationRequest{
+		Type:    domain.NotificationChannelSMS,
+		To:      phone,
+		Subject: "Seguimiento de tu vehículo",
+		Message: fmt.Sprintf("Seguí el estado de tu %s %s (%s) en %s: %s", car.Make, car.Model, car.LicensePlate, s.brand().Name, link),
+	}
+	if req.To == "" {
+		owner, err := s.userRepo.GetByID(ctx, car.OwnerID)
+		if err != nil {
+			return "", fmt.Errorf("get owner: %w", err)
+		}
+		if owner != nil {
+			req.To = strings.TrimSpace(owner.Phone)
+			if req.To == "" {
+				req.Type, req.To = domain.NotificationChannelEmail, strings.TrimSpace(owner.Email)
+			}
+		}
+	}
+	if req.To == "" {
+		return "", ErrNoTrackerRecipient
+	}
+	if err := s.notifier.QueueNotification(ctx, req); err != nil {
+		return "", fmt.Errorf("queue tracker link: %w", err)
+	}
+	return req.To, nil
+}
+
+func (s *Service) visitTracker(ctx context.Context, j *domain.ServiceJob, car *domain.Car) (*VisitTracker, error) {
+	b := s.brand()
+	out := &VisitTracker{
+		ServiceJobID:  j.ID,
+		Status:        j.Status,
+		OpenedAt:      j.OpenedAt,
+		PromisedAt:    j.PromisedAt,
+		ClosedAt:      j.ClosedAt,
+		Car:           TrackerCar{Make: car.Make, Model: car.Model, LicensePlate: car.LicensePlate},
+		Workshop:      TrackerWorkshop{Name: b.Name, Phone: b.Phone},
+		Timeline:      []TrackerEvent{{Status: domain.ServiceJobStatusOpen, At: j.OpenedAt}},
+		Work:          []TrackerWork{},
+		ApprovedLines: []TrackerLine{},
+		Findings:      []TrackerFinding{},
+	}
+	events, err := s.jobRepo.ListStatusEvents(ctx, j.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		out.Timeline = append(out.Timeline, TrackerEvent{Status: ev.ToStatus, At: ev.ChangedAt})
+	}
+	var repairs []*domain.Repair
+	if s.repairRepo != nil {
+		if repairs, err = s.repairRepo.ListByServiceJobIDs(ctx, []uuid.UUID{j.ID}); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range repairs {
+		out.Work = append(out.Work, TrackerWork{Description: r.Description, Status: r.Status})
+	}
+	if s.estimateRepo != nil {
+		estimates, err := s.estimateRepo.ListByServiceJob(ctx, j.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range estimates {
+			for _, l := range e.Lines {
+				if l.Approved != nil && *l.Approved {
+					out.ApprovedLines = append(out.ApprovedLines, TrackerLine{Kind: l.Kind, Description: l.Description, Quantity: l.Quantity, Amount: l.Amount()})
+					out.ApprovedTotal += l.Amount()
+				}
+			}
+		}
+		out.ApprovedTotal = math.Round(out.ApprovedTotal*100) / 100
+	}
+	if s.findingRepo != nil {
+		findings, err := s.findingRepo.ListByServiceJob(ctx, j.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range findings {
+			out.Findings = append(out.Findings, TrackerFinding{Severity: f.Severity, Description: f.Description,
+				PhotoURL: f.PhotoURL, EstimatedCost: f.EstimatedCost, RepairQuoted: f.RepairID != nil})
+		}
+	}
+	out.Stage = trackerStage(j.Status, repairs)
+	out.ReadyForPickup = out.Stage == TrackerStageReady
+	return out, nil
+}
+
+// trackerStage maps the visit to what the client cares about: a visit still in the shop whose repairs
+// are all finished (at least one completed) is ready for pickup.
+func trackerStage(status domain.ServiceJobStatus, repairs []*domain.Repair) string {
+	switch status {
+	case domain.ServiceJobStatusClosed:
+		return TrackerStageDelivered
+	case domain.ServiceJobStatusCancelled:
+		return TrackerStageCancelled
+	}
+	completed := 0
+	for _, r := range repairs {
+		switch r.Status {
+		case domain.RepairStatusCompleted:
+			completed++
+		case domain.RepairStatusCancelled:
+		default:
+			if status == domain.ServiceJobStatusOpen {
+				return TrackerStageReceived
+			}
+			return TrackerStageInProgress
+		}
+	}
+	if completed > 0 {
+		return TrackerStageReady
+	}
+	if status == domain.ServiceJobStatusOpen {
+		return TrackerStageReceived
+	}
+	return TrackerStageInProgress
+}
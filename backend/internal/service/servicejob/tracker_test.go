@@ -0,0 +1,118 @@
+package servicejob
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/platform/signedlink"
+)
+
+func TestTrackerStage(t *testing.T) {
+	t.Parallel()
+	done := &domain.Repair{Status: domain.RepairStatusCompleted}
+	dropped := &domain.Repair{Status: domain.RepairStatusCancelled}
+	working := &domain.Repair{Status: domain.RepairStatusInProgress}
+	cases := []struct {
+		status  domain.ServiceJobStatus
+		repairs []*domain.Repair
+		want    string
+	}{
+		{domain.ServiceJobStatusOpen, nil, TrackerStageReceived},
+		{domain.ServiceJobStatusInProgress, nil, TrackerStageInProgress},
+		{domain.ServiceJobStatusInProgress, []*domain.Repair{done, working}, TrackerStageInProgress},
+		{domain.ServiceJobStatusInProgress, []*domain.Repair{done, dropped}, TrackerStageReady},
+		{domain.ServiceJobStatusInProgress, []*domain.Repair{dropped}, TrackerStageInProgress},
+		{domain.ServiceJobStatusClosed, []*domain.Repair{done}, TrackerStageDelivered},
+		{domain.ServiceJobStatusCancelled, nil, TrackerStageCancelled},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, trackerStage(c.status, c.repairs), "%s %d repairs", c.status, len(c.repairs))
+	}
+}
+
+func TestService_VisitTracker_ClientView(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+	promised := time.Now().UTC().Add(3 * time.Hour).Truncate(time.Second)
+	fx.jobs.byID[fx.jobID].PromisedAt = &promised
+
+	cost := 60.0
+	f, err := fx.svc.AddFinding(ctx, fx.jobID, FindingInput{Severity: domain.FindingSeverityRed, Description: "Pastillas gastadas", EstimatedCost: &cost}, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.ProposeRepairFromFinding(ctx, fx.jobID, f.ID, fx.emp.ID)
+	require.NoError(t, err)
+	e, err := fx.svc.CreateEstimate(ctx, fx.jobID, brakeInput(), fx.emp.ID)
+	require.NoError(t, err)
+	sent, _, err := fx.svc.SendEstimate(ctx, e.ID, fx.emp.ID)
+	require.NoError(t, err)
+	_, err = fx.svc.DecideEstimate(ctx, e.ID, EstimateDecision{ApprovedLineIDs: []uuid.UUID{sent.Lines[1].ID}}, fx.owner.ID)
+	require.NoError(t, err)
+
+	_, err = fx.svc.VisitTracker(ctx, fx.jobID, fx.other.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	v, err := fx.svc.VisitTracker(ctx, fx.jobID, fx.owner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TrackerStageInProgress, v.Stage)
+	assert.False(t, v.ReadyForPickup)
+	require.NotNil(t, v.PromisedAt)
+	assert.True(t, promised.Equal(*v.PromisedAt))
+	require.Len(t, v.ApprovedLines, 1)
+	assert.Equal(t, "Pastillas", v.ApprovedLines[0].Description)
+	assert.InDelta(t, 60.0, v.ApprovedTotal, 0.001)
+	require.Len(t, v.Findings, 1)
+	assert.True(t, v.Findings[0].RepairQuoted)
+	require.Len(t, v.Work, 1)
+	require.NotEmpty(t, v.Timeline)
+	assert.Equal(t, domain.ServiceJobStatusOpen, v.Timeline[0].Status)
+
+	fx.repairs.created[0].Status = domain.RepairStatusCompleted
+	v, err = fx.svc.VisitTracker(ctx, fx.jobID, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TrackerStageReady, v.Stage)
+	assert.True(t, v.ReadyForPickup)
+}
+
+func TestService_TrackerLink_SignedAndTexted(t *testing.T) {
+	t.Parallel()
+	fx := estimateFixture(t)
+	ctx := context.Background()
+
+	_, err := fx.svc.CreateTrackerLink(ctx, fx.jobID, TrackerLinkInput{}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+
+	// No phone on file: falls back to the owner's email.
+	link, err := fx.svc.CreateTrackerLink(ctx, fx.jobID, TrackerLinkInput{Send: true}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "o@t", link.SentTo)
+	require.Len(t, fx.notifier.reqs, 1)
+	assert.Equal(t, domain.NotificationChannelEmail, fx.notifier.reqs[0].Type)
+	assert.True(t, strings.HasPrefix(link.URL, "https://taller.example/visits/track?token="))
+	assert.Contains(t, fx.notifier.reqs[0].Message, link.URL)
+
+	link, err = fx.svc.CreateTrackerLink(ctx, fx.jobID, TrackerLinkInput{Send: true, Phone: " +34600000000 "}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "+34600000000", link.SentTo)
+	assert.Equal(t, domain.NotificationChannelSMS, fx.notifier.reqs[1].Type)
+
+	token := strings.TrimPrefix(link.URL, "https://taller.example/visits/track?token=")
+	v, err := fx.svc.VisitTrackerByToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, fx.jobID, v.ServiceJobID)
+
+	_, err = fx.svc.VisitTrackerByToken(ctx, token+"x")
+	assert.ErrorIs(t, err, domain.ErrVisitLinkInvalid)
+	expired := signedlink.New("test-secret").Sign(VisitTrackerPurpose, fx.jobID, time.Now().Add(-time.Minute))
+	_, err = fx.svc.VisitTrackerByToken(ctx, expired)
+	assert.ErrorIs(t, err, domain.ErrVisitLinkInvalid)
+	estimateToken := signedlink.New("test-secret").Sign(EstimateApprovalPurpose, fx.jobID, time.Now().Add(time.Hour))
+	_, err = fx.svc.VisitTrackerByToken(ctx, estimateToken)
+	assert.ErrorIs(t, err, domain.ErrVisitLinkInvalid)
+}
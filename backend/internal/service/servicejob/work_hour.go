@@ -0,0 +1,246 @@
+package servicejob
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/core/ports"
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrWorkTimeNotConfigured is returned by the clock endpoints when the service has no work-hour repository.
+var ErrWorkTimeNotConfigured = errors.New("work time tracking not configured")
+
+// WithWorkHourRepository enables technician clock-in/clock-out and the labour summary of visits.
+func WithWorkHourRepository(repo ports.WorkHourRepository) Option {
+	return func(s *Service) { s.workRepo = repo }
+}
+
+// ClockInInput is what a technician starts a timer on: a visit, or one of its repairs.
+type ClockInInput struct {
+	ServiceJobID *uuid.UUID
+	RepairID     *uuid.UUID
+	Description  string
+}
+
+// ClockIn starts the technician's timer on an open visit or an active repair. A technician runs one timer
+// at a time: clock out first to switch work.
+func (s *Service) ClockIn(ctx context.Context, in ClockInInput, userID uuid.UUID) (*domain.WorkHour, error) {
+	if s.workRepo == nil {
+		return nil, ErrWorkTimeNotConfigured
+	}
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if (in.ServiceJobID == nil) == (in.RepairID == nil) {
+		return nil, domain.ErrInvalidWorkTarget
+	}
+	jobID := in.ServiceJobID
+	if in.RepairID != nil {
+		if s.repairRepo == nil {
+			return nil, domain.ErrInvalidWorkTarget
+		}
+		rep, err := s.repairRepo.GetByID(ctx, *in.RepairID)
+		if err != nil {
+			return nil, err
+		}
+		if rep.Status == domain.RepairStatusCompleted || rep.Status == domain.RepairStatusCancelled {
+			return nil, domain.ErrInvalidWorkTarget
+		}
+		jobID = rep.ServiceJobID // nil for repairs outside a visit
+	}
+	if jobID != nil {
+		if _, err := s.openJobForStaff(ctx, *jobID, userID); err != nil {
+			if errors.Is(err, domain.ErrInvalidServiceJobData) {
+				return nil, domain.ErrInvalidWorkTarget
+			}
+			return nil, err
+		}
+	}
+	now := time.Now().UTC()
+	local := now.In(s.location())
+	w := domain.NewWorkHour(userID, time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), now)
+	w.ServiceJobID = jobID
+	w.RepairID = in.RepairID
+	w.Description = strings.TrimSpace(in.Description)
+	if err := s.workRepo.Start(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ClockOut stops the technician's running timer and books its hours.
+func (s *Service) ClockOut(ctx context.Context, userID uuid.UUID) (*domain.WorkHour, error) {
+	if s.workRepo == nil {
+		return nil, ErrWorkTimeNotConfigured
+	}
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.workRepo.Stop(ctx, userID, time.Now().UTC())
+}
+
+// RunningTimer returns the technician's running timer, or nil when they are not clocked in.
+func (s *Service) RunningTimer(ctx context.Context, userID uuid.UUID) (*domain.WorkHour, error) {
+	if s.workRepo == nil {
+		return nil, ErrWorkTimeNotConfigured
+	}
+	if _, err := s.requireWorkshopUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.workRepo.GetRunning(ctx, userID)
+}
+
+// LabourSummary compares the hours clocked on a visit with the labour hours the client approved.
+type LabourSummary struct {
+	ServiceJobID  uuid.UUID          `json:"service_job_id"`
+	ActualHours   float64            `json:"actual_hours"`   // clocked; running timers count up to now
+	BilledHours   float64            `json:"billed_hours"`   // approved labour lines of the visit's estimates
+	BilledAmount  float64            `json:"billed_amount"`  // their total price
+	VarianceHours float64            `json:"variance_hours"` // actual − billed: positive means unbilled time
+	RunningTimers int                `json:"running_timers"`
+	Repairs       []RepairLabour     `json:"repairs"`
+	Technicians   []TechnicianLabour `json:"technicians"`
+	Entries       []*domain.WorkHour `json:"entries"`
+}
+
+// RepairLabour is actual vs billed time for one repair; RepairID nil holds time and lines on the visit itself.
+type RepairLabour struct {
+	RepairID    *uuid.UUID `json:"repair_id,omitempty"`
+	Description string     `json:"description"`
+	ActualHours float64    `json:"actual_hours"`
+	BilledHours float64    `json:"billed_hours"`
+}
+
+// TechnicianLabour is the time one technician clocked on the visit.
+type TechnicianLabour struct {
+	TechnicianID uuid.UUID `json:"technician_id"`
+	Name         string    `json:"name"`
+	ActualHours  float64   `json:"actual_hours"`
+}
+
+// Labour returns the visit's actual vs billed labour, per repair and per technician. Staff only.
+func (s *Service) Labour(ctx context.Context, jobID uuid.UUID, userID uuid.UUID) (*LabourSummary, error) {
+	if s.workRepo == nil {
+		return nil, ErrWorkTimeNotConfigured
+	}
+	if _, err := s.staffJob(ctx, jobID, userID); err != nil {
+		return nil, err
+	}
+	entries, err := s.workRepo.ListByServiceJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	var repairs []*domain.Repair
+	if s.repairRepo != nil {
+		if repairs, err = s.repairRepo.ListByServiceJobIDs(ctx, []uuid.UUID{jobID}); err != nil {
+			return nil, err
+		}
+	}
+	var lines []domain.EstimateLine
+	if s.estimateRepo != nil {
+		estimates, err := s.estimateRepo.ListByServiceJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range estimates {
+			for _, l := range e.Lines {
+				if l.Kind == domain.EstimateLineLabour && l.Approved != nil && *l.Approved {
+					lines = append(lines, l)
+				}
+			}
+		}
+	}
+	return s.labourSummary(ctx, jobID, entries, repairs, lines, time.Now().UTC()), nil
+}
+
+func (s *Service) labourSummary(ctx context.Context, jobID uuid.UUID, entries []*domain.WorkHour, repairs []*domain.Repair, lines []domain.EstimateLine, now time.Time) *LabourSummary {
+	out := &LabourSummary{ServiceJobID: jobID, Repairs: []RepairLabour{}, Technicians: []TechnicianLabour{}, Entries: entries}
+
+	byRepair := map[uuid.UUID]*RepairLabour{}
+	var visit *RepairLabour // time and lines not tied to a repair
+	bucket := func(id *uuid.UUID) *RepairLabour {
+		if id == nil {
+			if visit == nil {
+				visit = &RepairLabour{Description: "Visita"}
+			}
+			return visit
+		}
+		if r, ok := byRepair[*id]; ok {
+			return r
+		}
+		r := &RepairLabour{RepairID: id}
+		byRepair[*id] = r
+		return r
+	}
+	for _, r := range repairs {
+		id := r.ID
+		bucket(&id).Description = r.Description
+	}
+
+	byTech := map[uuid.UUID]*TechnicianLabour{}
+	var techOrder []uuid.UUID
+	for _, w := range entries {
+		h := w.HoursAt(now)
+		if w.Running() {
+			out.RunningTimers++
+		}
+		out.ActualHours += h
+		bucket(w.RepairID).ActualHours += h
+		t, ok := byTech[w.TechnicianID]
+		if !ok {
+			t = &TechnicianLabour{TechnicianID: w.TechnicianID}
+			byTech[w.TechnicianID] = t
+			techOrder = append(techOrder, w.TechnicianID)
+		}
+		t.ActualHours += h
+	}
+	for _, l := range lines {
+		out.BilledHours += l.Quantity
+		out.BilledAmount += l.Amount()
+		bucket(l.RepairID).BilledHours += l.Quantity
+	}
+
+	for _, r := range repairs {
+		b := byRepair[r.ID]
+		out.Repairs = append(out.Repairs, RepairLabour{RepairID: b.RepairID, Description: b.Description,
+			ActualHours: roundHours(b.ActualHours), BilledHours: roundHours(b.BilledHours)})
+		delete(byRepair, r.ID)
+	}
+	// Time on repairs no longer linked to the visit still counts, after the visit's own repairs.
+	rest := make([]*RepairLabour, 0, len(byRepair))
+	for _, b := range byRepair {
+		rest = append(rest, b)
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].RepairID.String() < rest[j].RepairID.String() })
+	if visit != nil {
+		rest = append(rest, visit)
+	}
+	for _, b := range rest {
+		out.Repairs = append(out.Repairs, RepairLabour{RepairID: b.RepairID, Description: b.Description,
+			ActualHours: roundHours(b.ActualHours), BilledHours: roundHours(b.BilledHours)})
+	}
+
+	for _, id := range techOrder {
+		t := byTech[id]
+		if u, err := s.userRepo.GetByID(ctx, id); err == nil && u != nil {
+			t.Name = u.FullName()
+		}
+		t.ActualHours = roundHours(t.ActualHours)
+		out.Technicians = append(out.Technicians, *t)
+	}
+	out.ActualHours = roundHours(out.ActualHours)
+	out.BilledHours = roundHours(out.BilledHours)
+	out.BilledAmount = math.Round(out.BilledAmount*100) / 100
+	out.VarianceHours = roundHours(out.ActualHours - out.BilledHours)
+	return out
+}
+
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
@@ -0,0 +1,166 @@
+package servicejob
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gaston-garcia-cegid/gonsgarage/internal/domain"
+)
+
+type memWorkRepo struct {
+	mu   sync.Mutex
+	rows []*domain.WorkHour
+}
+
+func (m *memWorkRepo) Start(_ context.Context, w *domain.WorkHour) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.rows {
+		if r.TechnicianID == w.TechnicianID && r.Running() {
+			return domain.ErrWorkTimerRunning
+		}
+	}
+	cp := *w
+	m.rows = append(m.rows, &cp)
+	return nil
+}
+
+func (m *memWorkRepo) Stop(_ context.Context, techID uuid.UUID, end time.Time) (*domain.WorkHour, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.rows {
+		if r.TechnicianID == techID && r.Running() {
+			r.EndTime = &end
+			r.Hours = r.CalculateHours()
+			cp := *r
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrNoWorkTimerRunning
+}
+
+func (m *memWorkRepo) GetRunning(_ context.Context, techID uuid.UUID) (*domain.WorkHour, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.rows {
+		if r.TechnicianID == techID && r.Running() {
+			cp := *r
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memWorkRepo) ListByServiceJob(_ context.Context, jobID uuid.UUID) ([]*domain.WorkHour, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []*domain.WorkHour{}
+	for _, r := range m.rows {
+		if r.ServiceJobID != nil && *r.ServiceJobID == jobID {
+			cp := *r
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func TestService_ClockInOut(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	work := &memWorkRepo{}
+
+	_, err := fx.svc.ClockIn(ctx, ClockInInput{ServiceJobID: &fx.jobID}, fx.emp.ID)
+	assert.ErrorIs(t, err, ErrWorkTimeNotConfigured)
+	WithWorkHourRepository(work)(fx.svc)
+
+	_, err = fx.svc.ClockIn(ctx, ClockInInput{ServiceJobID: &fx.jobID}, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	_, err = fx.svc.ClockIn(ctx, ClockInInput{}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWorkTarget)
+
+	w, err := fx.svc.ClockIn(ctx, ClockInInput{ServiceJobID: &fx.jobID, Description: " Diagnóstico "}, fx.emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Diagnóstico", w.Description)
+	assert.True(t, w.Running())
+	_, err = fx.svc.ClockIn(ctx, ClockInInput{ServiceJobID: &fx.jobID}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrWorkTimerRunning)
+
+	running, err := fx.svc.RunningTimer(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, running)
+	assert.Equal(t, w.ID, running.ID)
+
+	stopped, err := fx.svc.ClockOut(ctx, fx.emp.ID)
+	require.NoError(t, err)
+	assert.False(t, stopped.Running())
+	_, err = fx.svc.ClockOut(ctx, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrNoWorkTimerRunning)
+
+	// A repair's time is booked on its visit.
+	rep := &domain.Repair{ID: uuid.New(), CarID: fx.carID, ServiceJobID: &fx.jobID, Status: domain.RepairStatusInProgress}
+	require.NoError(t, fx.repairs.Create(ctx, rep))
+	onRepair, err := fx.svc.ClockIn(ctx, ClockInInput{RepairID: &rep.ID}, fx.emp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, onRepair.ServiceJobID)
+	assert.Equal(t, fx.jobID, *onRepair.ServiceJobID)
+	_, err = fx.svc.ClockOut(ctx, fx.emp.ID)
+	require.NoError(t, err)
+
+	fx.jobs.byID[fx.jobID].Status = domain.ServiceJobStatusClosed
+	_, err = fx.svc.ClockIn(ctx, ClockInInput{ServiceJobID: &fx.jobID}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWorkTarget)
+	_, err = fx.svc.ClockIn(ctx, ClockInInput{RepairID: &rep.ID}, fx.emp.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWorkTarget)
+}
+
+func TestService_Labour_ActualVsBilled(t *testing.T) {
+	t.Parallel()
+	fx := findingFixture(t)
+	ctx := context.Background()
+	work := &memWorkRepo{}
+	estimates := &memEstimateRepo{rows: map[uuid.UUID]*domain.Estimate{}}
+	WithWorkHourRepository(work)(fx.svc)
+	WithEstimateRepository(estimates)(fx.svc)
+
+	rep := &domain.Repair{ID: uuid.New(), CarID: fx.carID, ServiceJobID: &fx.jobID, Description: "Frenos", Status: domain.RepairStatusInProgress}
+	require.NoError(t, fx.repairs.Create(ctx, rep))
+	yes, no := true, false
+	require.NoError(t, estimates.Create(ctx, &domain.Estimate{ID: uuid.New(), ServiceJobID: fx.jobID, Status: domain.EstimateStatusPartiallyApproved, Lines: []domain.EstimateLine{
+		{Kind: domain.EstimateLineLabour, Quantity: 2, UnitPrice: 40, RepairID: &rep.ID, Approved: &yes},
+		{Kind: domain.EstimateLineLabour, Quantity: 0.5, UnitPrice: 40, Approved: &yes},
+		{Kind: domain.EstimateLineLabour, Quantity: 3, UnitPrice: 40, Approved: &no},
+		{Kind: domain.EstimateLinePart, Quantity: 4, UnitPrice: 10, RepairID: &rep.ID, Approved: &yes},
+	}}))
+
+	now := time.Now().UTC()
+	end := now.Add(-time.Hour)
+	work.rows = []*domain.WorkHour{
+		{ID: uuid.New(), TechnicianID: fx.emp.ID, ServiceJobID: &fx.jobID, RepairID: &rep.ID, StartTime: end.Add(-150 * time.Minute), EndTime: &end},
+		{ID: uuid.New(), TechnicianID: fx.emp.ID, ServiceJobID: &fx.jobID, StartTime: now.Add(-30 * time.Minute)},
+	}
+
+	_, err := fx.svc.Labour(ctx, fx.jobID, fx.owner.ID)
+	assert.ErrorIs(t, err, domain.ErrUnauthorizedAccess)
+	sum, err := fx.svc.Labour(ctx, fx.jobID, fx.emp.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, sum.ActualHours, 0.02)
+	assert.Equal(t, 2.5, sum.BilledHours)
+	assert.Equal(t, 100.0, sum.BilledAmount)
+	assert.InDelta(t, 0.5, sum.VarianceHours, 0.02)
+	assert.Equal(t, 1, sum.RunningTimers)
+	require.Len(t, sum.Repairs, 2)
+	assert.Equal(t, "Frenos", sum.Repairs[0].Description)
+	assert.Equal(t, 2.5, sum.Repairs[0].ActualHours)
+	assert.Equal(t, 2.0, sum.Repairs[0].BilledHours)
+	assert.Nil(t, sum.Repairs[1].RepairID)
+	assert.Equal(t, 0.5, sum.Repairs[1].BilledHours)
+	require.Len(t, sum.Technicians, 1)
+	assert.Equal(t, fx.emp.FullName(), sum.Technicians[0].Name)
+}